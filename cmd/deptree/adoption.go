@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func runAdoption(args []string) error {
+	fs := flag.NewFlagSet("adoption", flag.ContinueOnError)
+	projectPath := fs.String("path", ".", "Path to the Go project, which must be in a git repository")
+	githubToken := fs.String("token", "", "GitHub personal access token (or use GITHUB_TOKEN env var)")
+	caCert := fs.String("ca-cert", "", "Path to a PEM CA bundle to trust for outbound HTTPS")
+	vulnDB := fs.String("vuln-db", deptree.VulnDBOSV, "Vulnerability database: osv, github or the path of an offline OSV bundle")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree adoption [flags]")
+		fmt.Fprintln(fs.Output(), "Reports the requirements of go.mod that were added at a version affected by an already published advisory, from the git history of go.mod.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("adoption takes no arguments, got %d", fs.NArg())
+	}
+
+	if *githubToken == "" {
+		*githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+	client, err := deptree.NewHTTPClient(*caCert)
+	if err != nil {
+		return err
+	}
+	db, err := deptree.NewVulnDB(*vulnDB, deptree.Options{HTTPClient: client}, *githubToken)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := readModFileHistory(*projectPath)
+	if err != nil {
+		return err
+	}
+	adoptions := deptree.FindAdoptions(revisions)
+	vulnerable, err := deptree.CheckAdoptions(adoptions, db)
+	if err != nil {
+		return fmt.Errorf("failed to check vulnerabilities: %w", err)
+	}
+
+	_, err = os.Stdout.Write(deptree.RenderAdoptions(vulnerable, len(adoptions)))
+	return err
+}
+
+// readModFileHistory reads every committed version of the project's go.mod
+// from git, oldest first.
+func readModFileHistory(projectPath string) ([]deptree.ModFileRevision, error) {
+	prefix, err := git(projectPath, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository: %w", projectPath, err)
+	}
+	file := strings.TrimSpace(prefix) + "go.mod"
+
+	log, err := git(projectPath, "log", "--reverse", "--format=%H %cI", "--", "go.mod")
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []deptree.ModFileRevision
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		commit, date, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		committed, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the date of commit %s: %w", commit, err)
+		}
+		mf := &deptree.ModFile{}
+		// A commit that deleted go.mod requires nothing
+		if content, err := git(projectPath, "show", commit+":"+file); err == nil {
+			if mf, err = deptree.ParseModFile([]byte(content)); err != nil {
+				return nil, fmt.Errorf("failed to parse go.mod at %s: %w", commit, err)
+			}
+		}
+		revisions = append(revisions, deptree.ModFileRevision{Commit: commit, Date: committed, ModFile: mf})
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("go.mod at %s has no git history", projectPath)
+	}
+	return revisions, nil
+}
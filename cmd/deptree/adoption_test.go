@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadModFileHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "service")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "-A"}, {"commit", "-q", "-m", "update"}} {
+			if _, err := git(repo, args...); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := git(repo, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+	commit("module example.com/service\n\ngo 1.21\n")
+	commit("module example.com/service\n\ngo 1.21\n\nrequire golang.org/x/text v0.3.5\n")
+
+	revisions, err := readModFileHistory(dir)
+	if err != nil {
+		t.Fatalf("readModFileHistory failed: %v", err)
+	}
+	if len(revisions) != 2 || len(revisions[0].ModFile.Require) != 0 || len(revisions[1].ModFile.Require) != 1 {
+		t.Fatalf("Expected two revisions, the second requiring golang.org/x/text, got %+v", revisions)
+	}
+	if revisions[1].Date.Before(revisions[0].Date) {
+		t.Errorf("Expected revisions oldest first, got %v then %v", revisions[0].Date, revisions[1].Date)
+	}
+}
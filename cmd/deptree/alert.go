@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// licenseChange is a module whose detected license differs from the
+// baseline's.
+type licenseChange struct {
+	Path     string
+	Old, New string
+}
+
+// alertReport is what changed in the project since the baseline that is
+// worth a notification.
+type alertReport struct {
+	Module  string
+	NewDeps []deptree.ModuleChange
+	// NewVulns maps modules to the advisories affecting them that the
+	// baseline did not list.
+	NewVulns       map[string][]string
+	LicenseChanges []licenseChange
+}
+
+func (r *alertReport) empty() bool {
+	return len(r.NewDeps) == 0 && len(r.NewVulns) == 0 && len(r.LicenseChanges) == 0
+}
+
+func runAlert(args []string) error {
+	fs := flag.NewFlagSet("alert", flag.ContinueOnError)
+	projectPath := fs.String("path", ".", "Path to the Go project to check")
+	baselinePath := fs.String("baseline", "", "Snapshot, directory or git ref to compare against (required)")
+	notify := fs.String("notify", "", "Slack-compatible incoming webhook URL to post the alert to; without it the alert is printed")
+	githubToken := fs.String("token", "", "GitHub personal access token (or use GITHUB_TOKEN env var)")
+	caCert := fs.String("ca-cert", "", "Path to a PEM CA bundle to trust for outbound HTTPS")
+	vulnDB := fs.String("vuln-db", deptree.VulnDBOSV, "Vulnerability database: osv, github or the path of an offline OSV bundle")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree alert -baseline <snapshot|directory|git-ref> [flags]")
+		fmt.Fprintln(fs.Output(), "Reports new dependencies, new vulnerabilities and license changes since the baseline, posting them to a webhook if -notify is set.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("alert takes no arguments, got %d", fs.NArg())
+	}
+	if *baselinePath == "" {
+		fs.Usage()
+		return fmt.Errorf("alert needs -baseline")
+	}
+
+	if *githubToken == "" {
+		*githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+	client, err := deptree.NewHTTPClient(*caCert)
+	if err != nil {
+		return err
+	}
+
+	baseline, current, err := loadDiffGraphs(*projectPath, *baselinePath)
+	if err != nil {
+		return err
+	}
+
+	db, err := deptree.NewVulnDB(*vulnDB, deptree.Options{HTTPClient: client}, *githubToken)
+	if err != nil {
+		return err
+	}
+	// A directory or git ref baseline, or a snapshot saved without -vuln
+	// or -license, records nothing to compare with, so it is checked the
+	// same way as the project
+	for _, g := range []*deptree.Graph{baseline, current} {
+		if g == current || g.Licenses == nil {
+			deptree.DetectLicenses(g, deptree.Options{HTTPClient: client}, *githubToken)
+		}
+		if g == current || g.Vulnerabilities == nil {
+			if err := deptree.DetectVulnerabilities(g, db, deptree.SeverityUnknown); err != nil {
+				// New dependencies and licenses are still worth reporting
+				fmt.Fprintf(os.Stderr, "Warning: failed to check vulnerabilities: %v\n", err)
+			}
+		}
+	}
+
+	report := buildAlert(baseline, current)
+	if report.empty() {
+		fmt.Printf("No new dependencies, vulnerabilities or license changes since %s\n", *baselinePath)
+		return nil
+	}
+
+	message := renderAlert(report, *baselinePath)
+	if *notify == "" {
+		_, err = os.Stdout.Write(message)
+		return err
+	}
+	if err := postAlert(client, *notify, message); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Posted alert for %s to the webhook\n", report.Module)
+	return nil
+}
+
+// buildAlert compares current with baseline. Vulnerabilities and licenses
+// are compared as detected or recorded on both graphs; licenses are not
+// compared if the baseline has none. A change to or from UnknownLicense is
+// not reported when detecting the license failed on either side, e.g. on the
+// GitHub rate limit, since the license may well be unchanged.
+func buildAlert(baseline, current *deptree.Graph) *alertReport {
+	report := &alertReport{NewVulns: make(map[string][]string)}
+	if current.Root != nil {
+		report.Module = current.Root.Name
+	}
+
+	changes := deptree.DiffGraphs(baseline, current)
+	deptree.AttributeChanges(baseline, current, changes)
+	for _, c := range changes {
+		if c.Kind == deptree.ChangeAdded {
+			report.NewDeps = append(report.NewDeps, c)
+		}
+	}
+
+	known := make(map[string]bool)
+	for name, ids := range baseline.Vulnerabilities {
+		path, _ := deptree.SplitModule(name)
+		for _, id := range ids {
+			known[path+" "+id] = true
+		}
+	}
+	for name, ids := range current.Vulnerabilities {
+		path, _ := deptree.SplitModule(name)
+		for _, id := range ids {
+			if !known[path+" "+id] {
+				report.NewVulns[name] = append(report.NewVulns[name], id)
+			}
+		}
+	}
+
+	if baseline.Licenses != nil {
+		before, beforeFailed := licensesByPath(baseline)
+		after, afterFailed := licensesByPath(current)
+		for _, path := range slices.Sorted(maps.Keys(after)) {
+			old, ok := before[path]
+			if !ok || old == after[path] {
+				continue
+			}
+			unknown := old == deptree.UnknownLicense || after[path] == deptree.UnknownLicense
+			if unknown && (beforeFailed[path] || afterFailed[path]) {
+				continue
+			}
+			report.LicenseChanges = append(report.LicenseChanges, licenseChange{Path: path, Old: old, New: after[path]})
+		}
+	}
+	return report
+}
+
+// licensesByPath maps the module paths of g to their detected license, and
+// reports the paths whose detection failed. A path at several versions
+// takes the license of the highest.
+func licensesByPath(g *deptree.Graph) (map[string]string, map[string]bool) {
+	licenses := make(map[string]string)
+	failed := make(map[string]bool)
+	versions := make(map[string]string)
+	for name, license := range g.Licenses {
+		path, version := deptree.SplitModule(name)
+		if v, ok := versions[path]; !ok || deptree.CompareVersions(version, v) > 0 {
+			versions[path] = version
+			licenses[path] = license
+			failed[path] = g.LicenseErrors[name] != nil
+		}
+	}
+	return licenses, failed
+}
+
+// renderAlert formats the report in the mrkdwn syntax Slack-compatible
+// webhooks render.
+func renderAlert(r *alertReport, baseline string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*deptree: %s changed since %s*\n", r.Module, baseline)
+
+	if len(r.NewDeps) > 0 {
+		fmt.Fprintf(&buf, "\n*New dependencies (%d)*\n", len(r.NewDeps))
+		for _, c := range r.NewDeps {
+			line := "• `" + c.String() + "`"
+			if attribution := c.Attribution(); attribution != "" {
+				line += " " + attribution
+			}
+			fmt.Fprintln(&buf, line)
+		}
+	}
+
+	if len(r.NewVulns) > 0 {
+		fmt.Fprintf(&buf, "\n*New vulnerabilities (%d module(s))*\n", len(r.NewVulns))
+		for _, module := range slices.Sorted(maps.Keys(r.NewVulns)) {
+			fmt.Fprintf(&buf, "• `%s`: %s\n", module, strings.Join(r.NewVulns[module], ", "))
+		}
+	}
+
+	if len(r.LicenseChanges) > 0 {
+		fmt.Fprintf(&buf, "\n*License changes (%d)*\n", len(r.LicenseChanges))
+		for _, c := range r.LicenseChanges {
+			fmt.Fprintf(&buf, "• `%s`: %s → %s\n", c.Path, c.Old, c.New)
+		}
+	}
+	return buf.Bytes()
+}
+
+// postAlert posts message as the text of a Slack-compatible incoming
+// webhook payload, which Mattermost, Rocket.Chat and others accept too.
+func postAlert(client *http.Client, webhook string, message []byte) error {
+	payload, err := json.Marshal(map[string]string{"text": string(message)})
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(payload))
+	if urlErr, ok := err.(*url.Error); ok {
+		// Webhook URLs embed their secret, so keep them out of errors
+		err = urlErr.Err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", deptree.DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to post alert: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
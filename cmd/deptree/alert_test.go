@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestBuildAlert(t *testing.T) {
+	baseline := &deptree.Graph{
+		Root: deptree.NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/a/lib@v1.0.0", "github.com/b/lib@v1.0.0"},
+		},
+		Licenses:        map[string]string{"github.com/a/lib@v1.0.0": "MIT", "github.com/b/lib@v1.0.0": "Apache-2.0"},
+		Vulnerabilities: map[string][]string{"github.com/a/lib@v1.0.0": {"GO-2024-0001"}},
+	}
+	current := &deptree.Graph{
+		Root: deptree.NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/a/lib@v1.1.0", "github.com/b/lib@v1.0.0", "github.com/c/lib@v0.2.0"},
+		},
+		Licenses:        map[string]string{"github.com/a/lib@v1.1.0": "BUSL-1.1", "github.com/b/lib@v1.0.0": "Apache-2.0", "github.com/c/lib@v0.2.0": "MIT"},
+		Vulnerabilities: map[string][]string{"github.com/a/lib@v1.1.0": {"GO-2024-0001", "GO-2025-0002"}},
+	}
+
+	report := buildAlert(baseline, current)
+	if len(report.NewDeps) != 1 || report.NewDeps[0].Path != "github.com/c/lib" {
+		t.Errorf("NewDeps = %v, want github.com/c/lib", report.NewDeps)
+	}
+	if ids := report.NewVulns["github.com/a/lib@v1.1.0"]; len(report.NewVulns) != 1 || len(ids) != 1 || ids[0] != "GO-2025-0002" {
+		t.Errorf("NewVulns = %v, want only GO-2025-0002", report.NewVulns)
+	}
+	if len(report.LicenseChanges) != 1 || report.LicenseChanges[0] != (licenseChange{Path: "github.com/a/lib", Old: "MIT", New: "BUSL-1.1"}) {
+		t.Errorf("LicenseChanges = %v", report.LicenseChanges)
+	}
+
+	message := string(renderAlert(report, "main"))
+	for _, line := range []string{
+		"*deptree: example.com/app changed since main*\n",
+		"• `github.com/c/lib@v0.2.0`\n",
+		"• `github.com/a/lib@v1.1.0`: GO-2025-0002\n",
+		"• `github.com/a/lib`: MIT → BUSL-1.1\n",
+	} {
+		if !strings.Contains(message, line) {
+			t.Errorf("message missing %q:\n%s", line, message)
+		}
+	}
+
+	if report := buildAlert(current, current); !report.empty() {
+		t.Errorf("Expected no alert for an unchanged graph, got %+v", report)
+	}
+}
+
+func TestBuildAlertFailedLicenseLookup(t *testing.T) {
+	graph := func(licenses map[string]string, errs map[string]error) *deptree.Graph {
+		return &deptree.Graph{
+			Root: deptree.NewNode("example.com/app"),
+			Deps: map[string][]string{
+				"example.com/app": {"github.com/a/lib@v1.0.0", "github.com/b/lib@v1.0.0", "github.com/c/lib@v1.0.0"},
+			},
+			Licenses:      licenses,
+			LicenseErrors: errs,
+		}
+	}
+	limited := errors.New("GitHub API rate limit exceeded")
+	baseline := graph(
+		map[string]string{"github.com/a/lib@v1.0.0": "MIT", "github.com/b/lib@v1.0.0": deptree.UnknownLicense, "github.com/c/lib@v1.0.0": "MIT"},
+		map[string]error{"github.com/b/lib@v1.0.0": limited},
+	)
+	current := graph(
+		map[string]string{"github.com/a/lib@v1.0.0": deptree.UnknownLicense, "github.com/b/lib@v1.0.0": "Apache-2.0", "github.com/c/lib@v1.0.0": deptree.UnknownLicense},
+		map[string]error{"github.com/a/lib@v1.0.0": limited},
+	)
+
+	// a and b failed on one side; c really lost its license file
+	report := buildAlert(baseline, current)
+	want := licenseChange{Path: "github.com/c/lib", Old: "MIT", New: deptree.UnknownLicense}
+	if len(report.LicenseChanges) != 1 || report.LicenseChanges[0] != want {
+		t.Errorf("LicenseChanges = %v, want only %v", report.LicenseChanges, want)
+	}
+}
+
+func TestPostAlert(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hooks/secret" {
+			json.NewDecoder(r.Body).Decode(&got)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	if err := postAlert(server.Client(), server.URL+"/hooks/secret", []byte("*hello*\n")); err != nil {
+		t.Fatalf("postAlert failed: %v", err)
+	}
+	if got["text"] != "*hello*\n" {
+		t.Errorf("webhook received %v", got)
+	}
+
+	err := postAlert(server.Client(), server.URL+"/hooks/wrong", []byte("x"))
+	if err == nil || strings.Contains(err.Error(), "/hooks/") {
+		t.Errorf("Expected an error without the webhook URL, got %v", err)
+	}
+}
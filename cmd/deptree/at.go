@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runAt(args []string) error {
+	fs := flag.NewFlagSet("at", flag.ContinueOnError)
+	var opts options
+	defineFlags(fs, &opts)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree at <git-ref> [flags]")
+		fmt.Fprintln(fs.Output(), "Analyzes the project at -path as of a git ref, such as a release tag, reading its go.mod and go.sum with git instead of checking the ref out. Takes the flags of the tree command.")
+		fs.PrintDefaults()
+	}
+
+	// Flags may follow the ref, which flag parsing stops at
+	var ref string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		ref, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if ref == "" && fs.NArg() > 0 {
+		ref = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return err
+		}
+	}
+	if ref == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("at needs exactly one git ref")
+	}
+	if err := applyProfile(fs, opts.profile); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.packageName != "" || opts.loadFile != "" || opts.recursive:
+		return fmt.Errorf("at analyzes the project at -path, so it cannot be combined with -package, -load or -recursive")
+	case !opts.buildConfig().IsZero() || opts.tests || opts.noTests || opts.unused:
+		return fmt.Errorf("at reads only go.mod and go.sum, so -tags, -GOOS, -GOARCH, -tests, -no-tests and -unused, which need the packages of the module, are not available")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "deptree-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractGitRef(opts.packagePath, ref, tmpDir); err != nil {
+		return err
+	}
+	if !opts.quiet {
+		commit, err := git(opts.packagePath, "log", "-1", "--format=%h, %cs", ref)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Dependencies as of %s (%s)\n", ref, strings.TrimSpace(commit))
+	}
+
+	// A git ref given to -baseline names a commit of the project at -path,
+	// not of the checkout of ref that run analyzes
+	if opts.baseline != "" {
+		if _, err := os.Stat(opts.baseline); err != nil {
+			baselineDir, err := os.MkdirTemp("", "deptree-*")
+			if err != nil {
+				return fmt.Errorf("failed to create temp directory: %w", err)
+			}
+			defer os.RemoveAll(baselineDir)
+
+			if err := extractGitRef(opts.packagePath, opts.baseline, baselineDir); err != nil {
+				return fmt.Errorf("failed to load -baseline: %w", err)
+			}
+			opts.baseline = baselineDir
+		}
+	}
+
+	if opts.githubToken == "" {
+		opts.githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+	opts.packagePath = tmpDir
+	return runToOutput(opts)
+}
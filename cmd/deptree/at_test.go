@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "service")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git(repo, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+	for i, content := range []string{"module example.com/service\n\ngo 1.21\n", "module example.com/service/v2\n\ngo 1.22\n"} {
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := git(repo, "add", "-A"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := git(repo, "commit", "-q", "-m", "update"); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if _, err := git(repo, "tag", "v1.0.0"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	out := t.TempDir()
+	if err := extractGitRef(dir, "v1.0.0", out); err != nil {
+		t.Fatalf("extractGitRef failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(out, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "module example.com/service\n") {
+		t.Errorf("Expected the go.mod of v1.0.0, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(out, "go.sum")); !os.IsNotExist(err) {
+		t.Errorf("Expected no go.sum for a module without dependencies, got %v", err)
+	}
+
+	if err := extractGitRef(dir, "v9.9.9", t.TempDir()); err == nil {
+		t.Error("Expected an error for an unknown ref")
+	}
+}
+
+func TestRunAtArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"-depth", "2"},
+		{"v1.0.0", "v2.0.0"},
+		{"v1.0.0", "-package", "example.com/m"},
+		{"-tags", "integration", "v1.0.0"},
+	} {
+		if err := runAt(args); err == nil {
+			t.Errorf("Expected runAt %q to fail", args)
+		}
+	}
+}
+
+func TestRunAtRelativeReplace(t *testing.T) {
+	dir := initReplaceRepo(t)
+	output := filepath.Join(t.TempDir(), "tree.txt")
+
+	if err := runAt([]string{"v1.0.0", "-path", dir, "-q", "-o", output}); err != nil {
+		t.Fatalf("runAt failed: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "example.com/lib@v1.0.0") {
+		t.Errorf("Expected example.com/lib from ../lib in the tree, got %q", got)
+	}
+}
+
+func TestRunAtBaselineRef(t *testing.T) {
+	dir := initReplaceRepo(t)
+	output := filepath.Join(t.TempDir(), "tree.txt")
+
+	// The ref is looked up in the repository at -path, not the checkout of v1.0.0
+	if err := runAt([]string{"v1.0.0", "-path", dir, "-q", "-o", output, "-fail-on", "new-dep", "-baseline", "v1.0.0"}); err != nil {
+		t.Fatalf("runAt with a -baseline ref failed: %v", err)
+	}
+	if err := runAt([]string{"v1.0.0", "-path", dir, "-q", "-o", output, "-fail-on", "new-dep", "-baseline", "v9.9.9"}); err == nil {
+		t.Error("Expected an error for an unknown -baseline ref")
+	}
+}
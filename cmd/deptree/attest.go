@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// attestationReport lists how the modules embedded in a binary differ from
+// the modules an SBOM claims it contains.
+type attestationReport struct {
+	Binary string
+	SBOM   string
+
+	// Missing are binary modules the SBOM does not list at all.
+	Missing []string
+	// VersionMismatch maps binary modules to the versions the SBOM lists
+	// for their path instead.
+	VersionMismatch map[string][]string
+	// HashMismatch are binary modules whose go.sum hash differs from the
+	// digest recorded in the SBOM.
+	HashMismatch []string
+	// Matched counts binary modules the SBOM lists correctly.
+	Matched int
+	// Unlinked counts SBOM modules that are not part of the binary.
+	Unlinked int
+}
+
+func (r *attestationReport) discrepancies() int {
+	return len(r.Missing) + len(r.VersionMismatch) + len(r.HashMismatch)
+}
+
+func runVerifyAttestation(args []string) error {
+	fs := flag.NewFlagSet("verify-attestation", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree verify-attestation <binary> <sbom>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("verify-attestation needs a binary and an SBOM, got %d arguments", fs.NArg())
+	}
+	binary, sbomPath := fs.Arg(0), fs.Arg(1)
+
+	info, err := buildinfo.ReadFile(binary)
+	if err != nil {
+		return fmt.Errorf("failed to read build info: %w", err)
+	}
+
+	data, err := os.ReadFile(sbomPath)
+	if err != nil {
+		return fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	components, err := deptree.ReadSBOM(data)
+	if err != nil {
+		return err
+	}
+
+	report := compareAttestation(info, components)
+	report.Binary, report.SBOM = binary, sbomPath
+
+	if _, err := os.Stdout.Write(renderAttestation(report)); err != nil {
+		return err
+	}
+	if n := report.discrepancies(); n > 0 {
+		return fmt.Errorf("%d discrepancy(ies) between binary and SBOM", n)
+	}
+	return nil
+}
+
+// compareAttestation checks every module dependency recorded in the build
+// info against the SBOM components. The main module is not checked, since
+// its version in the binary is usually "(devel)".
+func compareAttestation(info *debug.BuildInfo, components []deptree.SBOMComponent) *attestationReport {
+	report := &attestationReport{VersionMismatch: make(map[string][]string)}
+
+	byPath := make(map[string][]deptree.SBOMComponent)
+	for _, c := range components {
+		byPath[c.Path] = append(byPath[c.Path], c)
+	}
+
+	linked := make(map[deptree.SBOMComponent]bool)
+	for _, dep := range info.Deps {
+		name := dep.Path + "@" + dep.Version
+
+		listed, ok := byPath[dep.Path]
+		if !ok {
+			report.Missing = append(report.Missing, name)
+			continue
+		}
+
+		var match *deptree.SBOMComponent
+		var versions []string
+		for i, c := range listed {
+			if c.Version == dep.Version {
+				match = &listed[i]
+			}
+			versions = append(versions, c.Version)
+		}
+		if match == nil {
+			report.VersionMismatch[name] = versions
+			continue
+		}
+		linked[*match] = true
+
+		// A replaced module's code comes from the replacement, whose hash the
+		// SBOM records under a different path
+		if dep.Replace == nil && match.SHA256 != "" && deptree.H1ToSHA256(dep.Sum) != match.SHA256 {
+			report.HashMismatch = append(report.HashMismatch, name)
+			continue
+		}
+		report.Matched++
+	}
+
+	for _, c := range components {
+		if c.Path != info.Main.Path && !linked[c] {
+			report.Unlinked++
+		}
+	}
+
+	return report
+}
+
+func renderAttestation(r *attestationReport) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Attestation check: %s against %s\n\n", r.Binary, r.SBOM)
+
+	for _, name := range r.Missing {
+		fmt.Fprintf(&buf, "[!!] %s: in binary but not in SBOM\n", name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.VersionMismatch)) {
+		path, version := deptree.SplitModule(name)
+		fmt.Fprintf(&buf, "[!!] %s: binary has %s, SBOM lists %s\n", path, version, strings.Join(r.VersionMismatch[name], ", "))
+	}
+	for _, name := range r.HashMismatch {
+		fmt.Fprintf(&buf, "[!!] %s: hash differs from SBOM\n", name)
+	}
+	if r.discrepancies() == 0 {
+		fmt.Fprintln(&buf, "[ok] Every module in the binary is listed in the SBOM")
+	}
+
+	fmt.Fprintf(&buf, "\n%d module(s) match, %d SBOM module(s) are not linked into the binary\n", r.Matched, r.Unlinked)
+	return buf.Bytes()
+}
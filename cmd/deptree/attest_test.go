@@ -0,0 +1,53 @@
+package main
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestCompareAttestation(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/spf13/pflag", Version: "v1.0.5", Sum: "h1:iy+VFUOCP1a+8yFto/drg2CJ5u0yRoB7fZw3DKv/JXA="},
+			{Path: "golang.org/x/sys", Version: "v0.20.0"},
+			{Path: "golang.org/x/text", Version: "v0.14.0", Sum: "h1:iy+VFUOCP1a+8yFto/drg2CJ5u0yRoB7fZw3DKv/JXA="},
+			{Path: "github.com/missing/mod", Version: "v1.0.0"},
+		},
+	}
+	components := []deptree.SBOMComponent{
+		{Path: "example.com/app"},
+		{Path: "github.com/spf13/pflag", Version: "v1.0.5", SHA256: "8b2f951543823f56bef3216da3f76b836089e6ed3246807b7d9c370cabff2570"},
+		{Path: "golang.org/x/sys", Version: "v0.19.0"},
+		{Path: "golang.org/x/text", Version: "v0.14.0", SHA256: "0000"},
+		{Path: "gopkg.in/yaml.v3", Version: "v3.0.1"},
+	}
+
+	report := compareAttestation(info, components)
+	report.Binary, report.SBOM = "app", "sbom.json"
+
+	if report.Matched != 1 {
+		t.Errorf("Expected 1 matching module, got %d", report.Matched)
+	}
+	if report.Unlinked != 2 {
+		t.Errorf("Expected 2 unlinked SBOM modules, got %d", report.Unlinked)
+	}
+	if report.discrepancies() != 3 {
+		t.Errorf("Expected 3 discrepancies, got %d", report.discrepancies())
+	}
+
+	output := string(renderAttestation(report))
+	for _, want := range []string{
+		"[!!] github.com/missing/mod@v1.0.0: in binary but not in SBOM",
+		"[!!] golang.org/x/sys: binary has v0.20.0, SBOM lists v0.19.0",
+		"[!!] golang.org/x/text@v0.14.0: hash differs from SBOM",
+		"1 module(s) match, 2 SBOM module(s) are not linked into the binary",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
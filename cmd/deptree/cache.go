@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// runCache inspects and manages the on-disk description cache.
+func runCache(args []string) error {
+	fs := flag.NewFlagSet("cache", flag.ContinueOnError)
+	cacheTTL := fs.Duration("cache-ttl", deptree.DefaultCacheTTL, "How long cached descriptions stay valid, to tell expired entries apart")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree cache ls [pattern] | clear [pattern] | stats")
+		fmt.Fprintln(fs.Output(), "Patterns are globs such as github.com/spf13/* or paths ending in /..., e.g. golang.org/x/...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	action, pattern := fs.Arg(0), fs.Arg(1)
+	maxArgs := map[string]int{"ls": 2, "clear": 2, "stats": 1}[action]
+	if maxArgs == 0 || fs.NArg() > maxArgs {
+		fs.Usage()
+		return fmt.Errorf("cache needs ls, clear or stats, got %q", fs.Args())
+	}
+
+	path, err := deptree.DefaultDescriptionCachePath()
+	if err != nil {
+		return fmt.Errorf("no cache directory: %w", err)
+	}
+	ttl := *cacheTTL
+	if action == "clear" {
+		// Save drops expired entries; keep those the pattern doesn't match
+		ttl = math.MaxInt64
+	}
+	cache, err := deptree.OpenDescriptionCache(path, ttl)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	switch action {
+	case "ls":
+		output = renderCacheEntries(cache.Entries(pattern), time.Now())
+	case "clear":
+		removed := cache.Remove(pattern)
+		if err := cache.Save(); err != nil {
+			return err
+		}
+		if pattern == "" {
+			if err := deptree.ResetCacheStats(path); err != nil {
+				return err
+			}
+		}
+		output = fmt.Appendf(nil, "Removed %d cache entry(ies)\n", removed)
+	case "stats":
+		stats, err := deptree.ReadCacheStats(path)
+		if err != nil {
+			return err
+		}
+		output = renderCacheStats(path, cache.Entries(""), stats, time.Now())
+	}
+
+	_, err = os.Stdout.Write(output)
+	return err
+}
+
+// renderCacheEntries lists cache entries with their age and size, one per
+// line.
+func renderCacheEntries(entries []deptree.CacheEntry, now time.Time) []byte {
+	var buf bytes.Buffer
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Module))
+	}
+	for _, e := range entries {
+		age := formatAge(now.Sub(e.Fetched))
+		if e.Expired {
+			age += " (expired)"
+		}
+		desc := e.Description
+		if desc == "" {
+			desc = "(no description)"
+		}
+		fmt.Fprintf(&buf, "%-*s  %-16s  %5d B  %s\n", width, e.Module, age, e.Size, desc)
+	}
+	return buf.Bytes()
+}
+
+// renderCacheStats summarizes the size and age of the cache and its hit
+// rate.
+func renderCacheStats(path string, entries []deptree.CacheEntry, stats deptree.CacheStats, now time.Time) []byte {
+	var buf bytes.Buffer
+	size, expired := 0, 0
+	var oldest time.Time
+	for _, e := range entries {
+		size += e.Size
+		if e.Expired {
+			expired++
+		}
+		if oldest.IsZero() || e.Fetched.Before(oldest) {
+			oldest = e.Fetched
+		}
+	}
+
+	fmt.Fprintf(&buf, "Cache file:  %s\n", path)
+	fmt.Fprintf(&buf, "Entries:     %d (%d expired)\n", len(entries), expired)
+	fmt.Fprintf(&buf, "Size:        %d B\n", size)
+	if !oldest.IsZero() {
+		fmt.Fprintf(&buf, "Oldest:      %s ago\n", formatAge(now.Sub(oldest)))
+	}
+	fmt.Fprintf(&buf, "Hits:        %d\n", stats.Hits)
+	fmt.Fprintf(&buf, "Misses:      %d\n", stats.Misses)
+	if stats.Hits+stats.Misses > 0 {
+		fmt.Fprintf(&buf, "Hit rate:    %.1f%% since %s\n", 100*stats.HitRate(), stats.Since.Format(time.DateOnly))
+	}
+	return buf.Bytes()
+}
+
+// formatAge rounds d to a short human-readable age such as "3h12m" or
+// "5d4h".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestRenderCacheStats(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	entries := []deptree.CacheEntry{
+		{Module: "github.com/spf13/cobra", Fetched: now.Add(-3 * time.Hour), Size: 80},
+		{Module: "golang.org/x/text", Fetched: now.Add(-50 * time.Hour), Size: 70, Expired: true},
+	}
+	stats := deptree.CacheStats{Hits: 3, Misses: 1, Since: now.Add(-72 * time.Hour)}
+
+	out := string(renderCacheStats("/cache/descriptions.json", entries, stats, now))
+	for _, want := range []string{"Entries:     2 (1 expired)\n", "Size:        150 B\n", "Oldest:      2d2h ago\n", "Hit rate:    75.0% since 2026-10-13\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+
+	out = string(renderCacheEntries(entries, now))
+	if !strings.Contains(out, "golang.org/x/text       2d2h (expired)") || !strings.Contains(out, "(no description)") {
+		t.Errorf("Unexpected listing:\n%s", out)
+	}
+}
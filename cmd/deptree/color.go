@@ -0,0 +1,31 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// Values of the -color flag.
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// useColor resolves the -color mode for output written to out. In auto mode
+// color is used only on a terminal, and never when the NO_COLOR environment
+// variable is set (https://no-color.org) or TERM is "dumb".
+func useColor(mode string, out io.Writer) bool {
+	switch mode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	return ok && isTerminal(f)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestUseColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if useColor(colorAuto, f) {
+		t.Error("Expected no color in auto mode when output is a file")
+	}
+	if !useColor(colorAlways, f) {
+		t.Error("Expected color in always mode")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if useColor(colorAuto, os.Stdout) {
+		t.Error("Expected NO_COLOR to disable color in auto mode")
+	}
+	if useColor(colorNever, os.Stdout) {
+		t.Error("Expected no color in never mode")
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+// subcommands maps the first command-line argument to its handler. Other
+// commands run a plugin from PATH (see runPlugin), and invocations starting
+// with a flag are parsed as flags of the default tree command.
+var subcommands = map[string]func(args []string) error{
+	"adoption":           runAdoption,
+	"alert":              runAlert,
+	"at":                 runAt,
+	"cache":              runCache,
+	"compare":            runCompare,
+	"debug-bundle":       runDebugBundle,
+	"diff":               runDiff,
+	"publish":            runPublish,
+	"review":             runReview,
+	"self":               runSelf,
+	"vendor-check":       runVendorCheck,
+	"verify-attestation": runVerifyAttestation,
+}
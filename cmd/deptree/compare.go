@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	engine := fs.String("engine", engineGo, "How the packages are resolved: go (go get in a temp module each) or proxy (module proxy protocol, no go command needed)")
+	caCert := fs.String("ca-cert", "", "Path to a PEM CA bundle to trust for outbound HTTPS")
+	quiet := fs.Bool("q", false, "Do not print progress to stderr")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree compare [flags] <package>[@version] <package>[@version]")
+		fmt.Fprintln(fs.Output(), "Fetches two remote packages, such as candidate libraries, and compares the modules each brings in, their depth and their size in the module cache.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("compare needs exactly two packages, got %d arguments", fs.NArg())
+	}
+	if *engine != engineGo && *engine != engineProxy {
+		return fmt.Errorf("-engine must be go or proxy, got %q", *engine)
+	}
+
+	client, err := deptree.NewHTTPClient(*caCert)
+	if err != nil {
+		return err
+	}
+
+	spin := newSpinner(os.Stderr, !*quiet)
+	var graphs [2]*deptree.Graph
+	for i, name := range fs.Args() {
+		graph, err := loadEach(*engine, client, name, spin, &deptree.Timings{})
+		if err != nil {
+			return err
+		}
+		if graph.Root == nil {
+			return fmt.Errorf("%s: no dependencies found", name)
+		}
+		writeWarnings(os.Stderr, graph, 0)
+		// Sizes cover the modules in the module cache, which 'go get'
+		// extracts for the packages it builds
+		if err := deptree.DetectSizes(graph); err != nil {
+			return fmt.Errorf("failed to measure module sizes: %w", err)
+		}
+		graphs[i] = graph
+	}
+
+	_, err = os.Stdout.Write(deptree.RenderComparison(deptree.ComparePackages(graphs[0], graphs[1])))
+	return err
+}
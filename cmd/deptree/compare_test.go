@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+func TestRunCompareArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"github.com/spf13/cobra"},
+		{"github.com/spf13/cobra", "github.com/urfave/cli/v2", "github.com/alecthomas/kong"},
+		{"-engine", "gopath", "github.com/spf13/cobra", "github.com/urfave/cli/v2"},
+	} {
+		if err := runCompare(args); err == nil {
+			t.Errorf("Expected runCompare %q to fail", args)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	projectPath := fs.String("path", ".", "Path to the Go project to compare")
+	format := fs.String("format", "list", "Output format: list or tree")
+	quietExit := fs.Bool("quiet-exit", false, "Print nothing and exit with status 1 if the module set changed, 0 if not (2 on errors)")
+	risk := fs.Bool("risk", false, "Also summarize the change in aggregate risk: new vulnerabilities, licenses and owners and the added modules, weighed into one score")
+	githubToken := fs.String("token", "", "GitHub personal access token for -risk (or use GITHUB_TOKEN env var)")
+	caCert := fs.String("ca-cert", "", "Path to a PEM CA bundle to trust for outbound HTTPS")
+	vulnDB := fs.String("vuln-db", deptree.VulnDBOSV, "Vulnerability database for -risk: osv, github or the path of an offline OSV bundle")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree diff [flags] <directory|snapshot|git-ref>")
+		fmt.Fprintln(fs.Output(), "Compares the project at -path against another working tree, a snapshot saved with -save or -format json, or a git ref of the project.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("diff needs exactly one graph to compare against, got %d arguments", fs.NArg())
+	}
+	if *format != "list" && *format != "tree" {
+		return fmt.Errorf("diff -format must be list or tree, got %q", *format)
+	}
+	if *risk && *quietExit {
+		return fmt.Errorf("-risk cannot be used with -quiet-exit, which prints nothing")
+	}
+
+	before, after, err := loadDiffGraphs(*projectPath, fs.Arg(0))
+	if err != nil && *quietExit {
+		// Keep status 1 meaning "changed", like cmp(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitStatus(2)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *risk {
+		if *githubToken == "" {
+			*githubToken = os.Getenv("GITHUB_TOKEN")
+		}
+		if err := assessDiffRisk(before, after, *caCert, *vulnDB, *githubToken); err != nil {
+			return err
+		}
+	}
+	return writeDiff(os.Stdout, before, after, *format, *quietExit, *risk)
+}
+
+// assessDiffRisk detects the vulnerabilities and licenses of the before and
+// after graphs for deptree.DiffRisk.
+func assessDiffRisk(before, after *deptree.Graph, caCert, vulnDB, token string) error {
+	client, err := deptree.NewHTTPClient(caCert)
+	if err != nil {
+		return err
+	}
+	db, err := deptree.NewVulnDB(vulnDB, deptree.Options{HTTPClient: client}, token)
+	if err != nil {
+		return err
+	}
+	for _, g := range []*deptree.Graph{before, after} {
+		if err := deptree.DetectVulnerabilities(g, db, deptree.SeverityUnknown); err != nil {
+			return fmt.Errorf("failed to check vulnerabilities: %w", err)
+		}
+		deptree.DetectLicenses(g, deptree.Options{HTTPClient: client}, token)
+		writeWarnings(os.Stderr, g, 0)
+	}
+	return nil
+}
+
+// loadDiffGraphs loads the base graph and the current graph of the project.
+func loadDiffGraphs(projectPath, base string) (before, after *deptree.Graph, err error) {
+	if before, err = loadDiffBase(projectPath, base); err != nil {
+		return nil, nil, err
+	}
+	if after, err = deptree.Load(projectPath, ""); err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// writeDiff writes the changes from before to after to w, followed by the
+// change in aggregate risk when risk is set, or only reports whether there
+// are any through the exit status when quietExit is set.
+func writeDiff(w io.Writer, before, after *deptree.Graph, format string, quietExit, risk bool) error {
+	changes := deptree.DiffGraphs(before, after)
+	if quietExit {
+		if len(changes) > 0 {
+			return exitStatus(1)
+		}
+		return nil
+	}
+	deptree.AttributeChanges(before, after, changes)
+
+	var output []byte
+	var err error
+	if format == "tree" {
+		output, err = renderDiffTree(after, changes)
+		if err != nil {
+			return err
+		}
+	} else {
+		output = renderDiffList(changes)
+	}
+	if risk {
+		output = append(output, deptree.RenderRiskDelta(deptree.DiffRisk(before, after))...)
+	}
+
+	_, err = w.Write(output)
+	return err
+}
+
+// loadDiffBase loads the graph to compare against: a snapshot saved with
+// -save or -format json, a working tree, or the project at a git ref.
+func loadDiffBase(projectPath, base string) (*deptree.Graph, error) {
+	if info, err := os.Stat(base); err == nil {
+		if info.IsDir() {
+			return deptree.Load(base, "")
+		}
+		return loadGraphFile(base)
+	}
+
+	return loadGitRef(projectPath, base)
+}
+
+// loadGitRef reads the module graph of the project as of a git ref.
+func loadGitRef(projectPath, ref string) (*deptree.Graph, error) {
+	tmpDir, err := os.MkdirTemp("", "deptree-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractGitRef(projectPath, ref, tmpDir); err != nil {
+		return nil, err
+	}
+	return deptree.Load(tmpDir, "")
+}
+
+// extractGitRef writes the go.mod and go.sum of the project as of a git ref
+// into dir. They are all 'go mod graph' needs, so the ref is read with git
+// show instead of being checked out, leaving the working tree alone.
+// Relative replacement directories are made absolute, as they would
+// otherwise be resolved against dir.
+func extractGitRef(projectPath, ref, dir string) error {
+	prefix, err := git(projectPath, "rev-parse", "--show-prefix")
+	if err != nil {
+		return fmt.Errorf("%s is not a directory, snapshot or git ref: %w", ref, err)
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	for _, file := range []string{"go.mod", "go.sum"} {
+		content, err := git(projectPath, "show", ref+":"+prefix+file)
+		if err != nil {
+			if file == "go.sum" {
+				// A module without dependencies has no go.sum
+				continue
+			}
+			return fmt.Errorf("failed to read %s at %s: %w", file, ref, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return absoluteReplaces(projectPath, dir)
+}
+
+// absoluteReplaces rewrites the replace directives of the go.mod in dir
+// whose replacement is a directory relative to the module, resolving it
+// against projectPath instead.
+func absoluteReplaces(projectPath, dir string) error {
+	mf, err := deptree.ReadModFile(dir)
+	if err != nil {
+		return err
+	}
+	var edits []string
+	for _, r := range mf.Replace {
+		if r.New.Version != "" || !strings.HasPrefix(r.New.Path, "./") && !strings.HasPrefix(r.New.Path, "../") {
+			continue
+		}
+		target, err := filepath.Abs(filepath.Join(projectPath, r.New.Path))
+		if err != nil {
+			return err
+		}
+		edits = append(edits, "-replace="+r.Old.String()+"="+target)
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command("go", append(append([]string{"mod", "edit"}, edits...), "go.mod")...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to resolve relative replacements: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// diffMarkers are the line prefixes of each kind of change.
+var diffMarkers = map[deptree.ChangeKind]string{
+	deptree.ChangeAdded:      "+",
+	deptree.ChangeRemoved:    "-",
+	deptree.ChangeUpgraded:   "↑",
+	deptree.ChangeDowngraded: "↓",
+}
+
+// diffLine formats c with its marker and, if known, the changes that
+// caused it.
+func diffLine(c deptree.ModuleChange) string {
+	line := diffMarkers[c.Kind] + " " + c.String()
+	if attribution := c.Attribution(); attribution != "" {
+		line += " " + attribution
+	}
+	return line
+}
+
+func renderDiffList(changes []deptree.ModuleChange) []byte {
+	var buf bytes.Buffer
+	for _, c := range changes {
+		fmt.Fprintln(&buf, diffLine(c))
+	}
+	writeDiffSummary(&buf, changes)
+	return buf.Bytes()
+}
+
+// renderDiffTree draws the tree of the current graph reduced to the paths
+// leading to added, upgraded or downgraded modules, followed by the
+// removed modules, which are no longer in the tree.
+func renderDiffTree(g *deptree.Graph, changes []deptree.ModuleChange) ([]byte, error) {
+	changed := make(map[string]deptree.ModuleChange)
+	var removed []deptree.ModuleChange
+	for _, c := range changes {
+		if c.Kind == deptree.ChangeRemoved {
+			removed = append(removed, c)
+			continue
+		}
+		changed[c.Path+"@"+c.New] = c
+	}
+
+	var buf bytes.Buffer
+	if root := changedSubtree(g.Root, changed); root != nil {
+		out, err := deptree.Render("tree", &deptree.Graph{Root: root}, deptree.RenderOptions{})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(out)
+	}
+
+	if len(removed) > 0 {
+		fmt.Fprintln(&buf, "\nRemoved:")
+		for _, c := range removed {
+			fmt.Fprintln(&buf, diffLine(c))
+		}
+	}
+
+	writeDiffSummary(&buf, changes)
+	return buf.Bytes(), nil
+}
+
+// changedSubtree copies the part of the tree below node that leads to a
+// changed module, annotating the changed nodes. It returns nil if nothing
+// below node changed.
+func changedSubtree(node *deptree.Node, changed map[string]deptree.ModuleChange) *deptree.Node {
+	copied := deptree.NewNode(node.Name)
+	for name, child := range node.Children {
+		if sub := changedSubtree(child, changed); sub != nil {
+			copied.Children[name] = sub
+		}
+	}
+
+	c, isChanged := changed[node.Name]
+	if isChanged {
+		note := "(" + string(c.Kind) + ")"
+		if c.Old != "" {
+			note = fmt.Sprintf("(%s from %s)", c.Kind, c.Old)
+		}
+		copied.Annotations = append(copied.Annotations, note)
+	}
+
+	if !isChanged && len(copied.Children) == 0 {
+		return nil
+	}
+	return copied
+}
+
+func writeDiffSummary(buf *bytes.Buffer, changes []deptree.ModuleChange) {
+	counts := make(map[deptree.ChangeKind]int)
+	for _, c := range changes {
+		counts[c.Kind]++
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(buf, "No module changes")
+		return
+	}
+	fmt.Fprintf(buf, "\n%d added, %d removed, %d upgraded, %d downgraded\n",
+		counts[deptree.ChangeAdded], counts[deptree.ChangeRemoved], counts[deptree.ChangeUpgraded], counts[deptree.ChangeDowngraded])
+}
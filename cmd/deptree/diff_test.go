@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestRenderDiff(t *testing.T) {
+	root := deptree.NewNode("app")
+	a := deptree.NewNode("a@v1.1.0")
+	a.Children["b@v1.0.0"] = deptree.NewNode("b@v1.0.0")
+	root.Children[a.Name] = a
+	root.Children["same@v1.0.0"] = deptree.NewNode("same@v1.0.0")
+	g := &deptree.Graph{Root: root}
+
+	changes := []deptree.ModuleChange{
+		{Path: "a", Kind: deptree.ChangeUpgraded, Old: "v1.0.0", New: "v1.1.0"},
+		{Path: "b", Kind: deptree.ChangeAdded, New: "v1.0.0"},
+		{Path: "c", Kind: deptree.ChangeRemoved, Old: "v0.9.0"},
+	}
+
+	list := string(renderDiffList(changes))
+	expected := `↑ a v1.0.0 -> v1.1.0
++ b@v1.0.0
+- c@v0.9.0
+
+1 added, 1 removed, 1 upgraded, 0 downgraded
+`
+	if list != expected {
+		t.Errorf("Expected list:\n%s\nGot:\n%s", expected, list)
+	}
+
+	out, err := renderDiffTree(g, changes)
+	if err != nil {
+		t.Fatalf("renderDiffTree failed: %v", err)
+	}
+	tree := string(out)
+	expected = `app
+└── a@v1.1.0 (upgraded from v1.0.0)
+    └── b@v1.0.0 (added)
+
+Removed:
+- c@v0.9.0
+
+1 added, 1 removed, 1 upgraded, 0 downgraded
+`
+	if tree != expected {
+		t.Errorf("Expected tree:\n%s\nGot:\n%s", expected, tree)
+	}
+
+	changes[1].Causes = changes[:1]
+	if got := diffLine(changes[1]); got != "+ b@v1.0.0 (added by a v1.0.0 -> v1.1.0)" {
+		t.Errorf("diffLine = %q", got)
+	}
+
+	if got := string(renderDiffList(nil)); !strings.Contains(got, "No module changes") {
+		t.Errorf("Expected no-change message, got %q", got)
+	}
+}
+
+func TestWriteDiffQuietExit(t *testing.T) {
+	graph := func(deps map[string][]string) *deptree.Graph {
+		return &deptree.Graph{Deps: deps, Root: deptree.NewNode("app")}
+	}
+	before := graph(map[string][]string{"app": {"a@v1.0.0"}})
+	after := graph(map[string][]string{"app": {"a@v1.1.0"}})
+
+	if err := writeDiff(io.Discard, before, before, "list", true, false); err != nil {
+		t.Errorf("Expected no error for an unchanged graph, got %v", err)
+	}
+	var status exitStatus
+	if err := writeDiff(io.Discard, before, after, "list", true, false); !errors.As(err, &status) || status != 1 {
+		t.Errorf("Expected exit status 1 for a changed graph, got %v", err)
+	}
+}
+
+func TestWriteDiffRisk(t *testing.T) {
+	before := &deptree.Graph{Deps: map[string][]string{"app": {"github.com/a/x@v1.0.0"}}, Root: deptree.NewNode("app")}
+	after := &deptree.Graph{
+		Deps:            map[string][]string{"app": {"github.com/a/x@v1.1.0", "github.com/b/y@v1.0.0"}},
+		Root:            deptree.NewNode("app"),
+		Vulnerabilities: map[string][]string{"github.com/b/y@v1.0.0": {"GO-2024-0001"}},
+	}
+
+	var buf strings.Builder
+	if err := writeDiff(&buf, before, after, "list", false, true); err != nil {
+		t.Fatalf("writeDiff failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "1 added, 0 removed, 1 upgraded") || !strings.Contains(buf.String(), "Risk delta: +14") {
+		t.Errorf("Expected the diff followed by the risk delta, got:\n%s", buf.String())
+	}
+}
+
+// initReplaceRepo creates a git repository holding the module
+// example.com/service, which replaces example.com/lib with the sibling
+// directory ../lib, tags it v1.0.0 and returns the directory of service.
+func initReplaceRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	files := map[string]string{
+		"service/go.mod": "module example.com/service\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n\nreplace example.com/lib => ../lib\n",
+		"lib/go.mod":     "module example.com/lib\n\ngo 1.21\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "init"}, {"tag", "v1.0.0"}} {
+		if _, err := git(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(repo, "service")
+}
+
+func TestLoadGitRefRelativeReplace(t *testing.T) {
+	dir := initReplaceRepo(t)
+
+	g, err := loadGitRef(dir, "v1.0.0")
+	if err != nil {
+		t.Fatalf("loadGitRef failed: %v", err)
+	}
+	if _, ok := g.Deps["example.com/lib@v1.0.0"]; !ok {
+		t.Errorf("Expected example.com/lib from ../lib in the graph, got %v", g.Deps)
+	}
+}
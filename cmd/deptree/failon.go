@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// Conditions -fail-on accepts.
+const (
+	failOnVuln     = "vuln"
+	failOnOutdated = "outdated"
+	failOnNewDep   = "new-dep"
+)
+
+var failOnConditions = []string{failOnVuln, failOnOutdated, failOnNewDep}
+
+// failOnList returns the conditions listed in -fail-on.
+func (o options) failOnList() []string {
+	return strings.FieldsFunc(o.failOn, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// failsOn reports whether -fail-on lists condition.
+func (o options) failsOn(condition string) bool {
+	return slices.Contains(o.failOnList(), condition)
+}
+
+// checkFailOn reports the -fail-on conditions g meets, listing the modules
+// behind each on w. New dependencies are modules g has and baseline lacks.
+// Vulnerable, outdated and low-scoring modules are marked in the rendered
+// output, so they are only listed when -quiet leaves that out.
+// Vulnerabilities fail the run through -vuln itself, and modules scoring
+// below -min-scorecard fail it too. Failed lookups of newer versions fail
+// -fail-on outdated, so that an unreachable proxy cannot pass the check.
+func checkFailOn(w io.Writer, g, baseline *deptree.Graph, opts options) error {
+	var errs []error
+
+	if opts.vuln && opts.violationsOnly {
+		for _, module := range slices.Sorted(maps.Keys(g.Vulnerabilities)) {
+			fmt.Fprintf(w, "Vulnerable: %s (%s)\n", module, strings.Join(g.Vulnerabilities[module], ", "))
+		}
+	}
+
+	if opts.failsOn(failOnOutdated) && len(g.Outdated) > 0 {
+		if opts.violationsOnly {
+			for _, module := range slices.Sorted(maps.Keys(g.Outdated)) {
+				fmt.Fprintf(w, "Outdated: %s (%s available)\n", module, g.Outdated[module])
+			}
+		}
+		errs = append(errs, fmt.Errorf("%d outdated module(s) found", len(g.Outdated)))
+	}
+	if opts.failsOn(failOnOutdated) && len(g.OutdatedErrors) > 0 {
+		errs = append(errs, fmt.Errorf("newer versions of %d module path(s) could not be checked", len(g.OutdatedErrors)))
+	}
+
+	if opts.minScorecard > 0 {
+		if below := g.BelowScorecard(opts.minScorecard); len(below) > 0 {
+			if opts.violationsOnly {
+				for _, module := range below {
+					fmt.Fprintf(w, "Low scorecard: %s (%.1f)\n", module, g.Scorecards[module].Score)
+				}
+			}
+			errs = append(errs, fmt.Errorf("%d module(s) score below -min-scorecard %g", len(below), opts.minScorecard))
+		}
+	}
+
+	if opts.failsOn(failOnNewDep) {
+		changes := deptree.DiffGraphs(baseline, g)
+		deptree.AttributeChanges(baseline, g, changes)
+		added := 0
+		for _, c := range changes {
+			if c.Kind != deptree.ChangeAdded {
+				continue
+			}
+			added++
+			line := "New dependency: " + c.String()
+			if attribution := c.Attribution(); attribution != "" {
+				line += " " + attribution
+			}
+			fmt.Fprintln(w, line)
+		}
+		if added > 0 {
+			errs = append(errs, fmt.Errorf("%d new dependency(ies) since %s", added, opts.baseline))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestCheckFailOn(t *testing.T) {
+	baseline := &deptree.Graph{
+		Root: deptree.NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/spf13/cobra@v1.7.0"},
+		},
+	}
+	g := &deptree.Graph{
+		Root: deptree.NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app":               {"github.com/spf13/cobra@v1.8.0", "golang.org/x/text@v0.14.0"},
+			"github.com/spf13/cobra@v1.8.0": {"github.com/spf13/pflag@v1.0.5"},
+		},
+		Outdated:        map[string]string{"golang.org/x/text@v0.14.0": "v0.15.0"},
+		Vulnerabilities: map[string][]string{"golang.org/x/text@v0.14.0": {"GO-2024-0001"}},
+	}
+
+	var buf bytes.Buffer
+	err := checkFailOn(&buf, g, baseline, options{failOn: "new-dep", baseline: "main"})
+	if err == nil || err.Error() != "2 new dependency(ies) since main" {
+		t.Errorf("new-dep error = %v", err)
+	}
+	want := "New dependency: github.com/spf13/pflag@v1.0.5 (added by github.com/spf13/cobra v1.7.0 -> v1.8.0)\n" +
+		"New dependency: golang.org/x/text@v0.14.0\n"
+	if buf.String() != want {
+		t.Errorf("new-dep output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	err = checkFailOn(&buf, g, nil, options{failOn: "outdated", outdated: true})
+	if err == nil || !strings.Contains(err.Error(), "1 outdated module(s)") {
+		t.Errorf("outdated error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("outdated modules listed without -quiet:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	err = checkFailOn(&buf, g, nil, options{failOn: "vuln,outdated", vuln: true, outdated: true, violationsOnly: true})
+	if err == nil || strings.Contains(err.Error(), "vulnerable") {
+		t.Errorf("vuln,outdated error = %v; vulnerabilities fail through -vuln", err)
+	}
+	want = "Vulnerable: golang.org/x/text@v0.14.0 (GO-2024-0001)\n" +
+		"Outdated: golang.org/x/text@v0.14.0 (v0.15.0 available)\n"
+	if buf.String() != want {
+		t.Errorf("-quiet output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	unchecked := &deptree.Graph{
+		Root:           g.Root,
+		Deps:           g.Deps,
+		Outdated:       map[string]string{},
+		OutdatedErrors: map[string]error{"golang.org/x/text": errors.New("module proxy returned status 503")},
+	}
+	err = checkFailOn(&buf, unchecked, nil, options{failOn: "outdated", outdated: true})
+	if err == nil || err.Error() != "newer versions of 1 module path(s) could not be checked" {
+		t.Errorf("outdated error with failed lookups = %v", err)
+	}
+
+	buf.Reset()
+	g.Scorecards = map[string]deptree.Scorecard{"github.com/spf13/cobra@v1.8.0": {Score: 4.2}, "github.com/spf13/pflag@v1.0.5": {Score: 7.5}}
+	err = checkFailOn(&buf, g, nil, options{minScorecard: 5, violationsOnly: true})
+	if err == nil || err.Error() != "1 module(s) score below -min-scorecard 5" {
+		t.Errorf("min-scorecard error = %v", err)
+	}
+	if want := "Low scorecard: github.com/spf13/cobra@v1.8.0 (4.2)\n"; buf.String() != want {
+		t.Errorf("-quiet output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	if err := checkFailOn(&buf, g, nil, options{}); err != nil {
+		t.Errorf("no -fail-on: %v", err)
+	}
+}
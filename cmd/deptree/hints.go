@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// largeTree is the number of tree lines above which -depth is suggested.
+const largeTree = 200
+
+// hintsMarker is the file next to the config file listing the kinds of
+// hints shown, one per line, so that each kind is only shown on the first
+// run that has it.
+const hintsMarker = "hints-shown"
+
+// Kinds of hints, recorded in hintsMarker once shown.
+const (
+	hintDepth     = "depth"
+	hintSelected  = "selected"
+	hintRateLimit = "rate-limit"
+	hintRerun     = "rerun"
+)
+
+// hint is a suggestion of usageHints.
+type hint struct {
+	kind, text string
+}
+
+// usageHints suggests flags that would help with what was detected in the
+// analyzed graph. Hints only point at flags the user did not pass.
+func usageHints(opts options, g *deptree.Graph) []hint {
+	var hints []hint
+
+	if opts.outputFormat() == "tree" && opts.depth == 0 {
+		lines := 0
+		g.Walk(func(*deptree.Node) { lines++ })
+		// Trees above -max-nodes were summarized, suggesting flags already
+		if lines > largeTree && (opts.maxNodes == 0 || lines <= opts.maxNodes) {
+			hints = append(hints, hint{hintDepth, fmt.Sprintf("%d lines of tree output; try -depth 2, -format export or -why <module>", lines)})
+		}
+	}
+
+	// Several packages are analyzed apart, so -selected is not available
+	if !opts.selected && len(opts.packages()) < 2 {
+		versions := make(map[string]int)
+		for _, name := range g.Modules() {
+			path, version := deptree.SplitModule(name)
+			if version != "" {
+				versions[path]++
+			}
+		}
+		multi := 0
+		for _, n := range versions {
+			if n > 1 {
+				multi++
+			}
+		}
+		if multi > 0 {
+			hints = append(hints, hint{hintSelected, fmt.Sprintf("%d module(s) appear at several versions; -selected shows only the versions the build uses", multi)})
+		}
+	}
+
+	if opts.fetchDesc && opts.githubToken == "" {
+		for _, err := range g.DescriptionErrors {
+			var rateLimitErr *deptree.RateLimitError
+			if errors.As(err, &rateLimitErr) {
+				hints = append(hints, hint{hintRateLimit, "descriptions hit the GitHub rate limit; set GITHUB_TOKEN or pass -token for 5000 requests/hour"})
+				break
+			}
+		}
+	}
+
+	if opts.fetchDesc && !opts.noCache && len(g.Partial) > 0 {
+		hints = append(hints, hint{hintRerun, "rerun to fill in the missing descriptions from the cache and the network"})
+	}
+
+	return hints
+}
+
+// showHints writes the hints to w whose kind no earlier run has shown,
+// unless the config file turns hints off, and records their kinds as shown.
+func showHints(w io.Writer, hints []hint) {
+	if len(hints) == 0 {
+		return
+	}
+	path, err := configPath()
+	if err != nil {
+		// Without a config directory the hints cannot be recorded, so they
+		// are shown every time
+		writeHints(w, hints)
+		return
+	}
+	if c, err := loadConfig(path); err == nil && c.Hints != nil && !*c.Hints {
+		return
+	}
+	marker := filepath.Join(filepath.Dir(path), hintsMarker)
+	data, _ := os.ReadFile(marker)
+	shown := strings.Fields(string(data))
+
+	var fresh []hint
+	for _, h := range hints {
+		if !slices.Contains(shown, h.kind) {
+			fresh = append(fresh, h)
+			shown = append(shown, h.kind)
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+	writeHints(w, fresh)
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err == nil {
+		os.WriteFile(marker, []byte(strings.Join(shown, "\n")+"\n"), 0644)
+	}
+}
+
+func writeHints(w io.Writer, hints []hint) {
+	for _, h := range hints {
+		fmt.Fprintf(w, "Hint: %s\n", h.text)
+	}
+}
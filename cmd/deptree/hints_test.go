@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestUsageHints(t *testing.T) {
+	root := deptree.NewNode("example.com/app")
+	deps := map[string][]string{"example.com/app": {"golang.org/x/sys@v0.1.0", "golang.org/x/sys@v0.2.0"}}
+	for i := range largeTree {
+		name := fmt.Sprintf("example.com/dep%d@v1.0.0", i)
+		root.Children[name] = deptree.NewNode(name)
+	}
+	g := &deptree.Graph{
+		Root: root,
+		Deps: deps,
+		DescriptionErrors: map[string]error{
+			"github.com/a/b@v1.0.0": &deptree.RateLimitError{},
+		},
+	}
+
+	hints := usageHints(options{format: "tree", fetchDesc: true}, g)
+	want := []hint{
+		{hintDepth, "201 lines of tree output"},
+		{hintSelected, "1 module(s) appear at several versions"},
+		{hintRateLimit, "GitHub rate limit"},
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("Expected %d hints, got %q", len(want), hints)
+	}
+	for i, w := range want {
+		if hints[i].kind != w.kind || !strings.Contains(hints[i].text, w.text) {
+			t.Errorf("Hint %d = %q, want %s containing %q", i, hints[i], w.kind, w.text)
+		}
+	}
+
+	// Hints for flags already in use are left out
+	hints = usageHints(options{format: "tree", depth: 2, selected: true, fetchDesc: true, githubToken: "secret"}, g)
+	if len(hints) != 0 {
+		t.Errorf("Expected no hints, got %q", hints)
+	}
+}
+
+func TestShowHints(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DEPTREE_CONFIG", filepath.Join(dir, "config.json"))
+	hints := []hint{{hintDepth, "try -depth 2"}}
+
+	// Only the first run with hints shows them
+	var first, second bytes.Buffer
+	showHints(&first, hints)
+	showHints(&second, hints)
+	if first.String() != "Hint: try -depth 2\n" || second.Len() != 0 {
+		t.Errorf("Expected the hints once, got %q and then %q", first.String(), second.String())
+	}
+
+	// A kind of hint not shown before still is on a later run
+	var later bytes.Buffer
+	showHints(&later, append(hints, hint{hintRateLimit, "set GITHUB_TOKEN"}))
+	if later.String() != "Hint: set GITHUB_TOKEN\n" {
+		t.Errorf("Expected only the new kind of hint, got %q", later.String())
+	}
+
+	// The config file can turn them off before they were ever shown
+	if err := os.Remove(filepath.Join(dir, hintsMarker)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"hints": false}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var off bytes.Buffer
+	showHints(&off, hints)
+	if off.Len() != 0 {
+		t.Errorf("Expected no hints with \"hints\": false, got %q", off.String())
+	}
+}
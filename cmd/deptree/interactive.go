@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+const interactiveHelp = "↑/↓ move  →/enter expand  ← collapse  / search  n next match  d description  c copy chain  q quit"
+
+// browserRow is one visible line of the interactive tree.
+type browserRow struct {
+	node   *deptree.Node
+	depth  int
+	parent int // index of the parent row, or -1 for the root
+}
+
+// browser is the state of the interactive tree: which nodes are expanded,
+// the selected row and the search. It has no terminal dependencies, so it
+// can be driven by tests through handleKey and view.
+type browser struct {
+	root     *deptree.Node
+	expanded map[*deptree.Node]bool
+	rows     []browserRow
+	cursor   int
+	offset   int
+
+	searching bool
+	query     string
+	status    string
+
+	// describe fetches the description of a module on demand.
+	describe     func(module string) (string, error)
+	descriptions map[string]string
+
+	// copied are the dependency chains copied with "c", in order.
+	copied []string
+
+	quit bool
+}
+
+func newBrowser(root *deptree.Node, describe func(string) (string, error)) *browser {
+	b := &browser{
+		root:         root,
+		expanded:     map[*deptree.Node]bool{root: true},
+		describe:     describe,
+		descriptions: make(map[string]string),
+	}
+	b.refresh()
+	return b
+}
+
+// refresh rebuilds the visible rows from the expanded set, keeping the
+// selected node selected.
+func (b *browser) refresh() {
+	var selected *deptree.Node
+	if b.cursor < len(b.rows) {
+		selected = b.rows[b.cursor].node
+	}
+
+	b.rows = b.rows[:0]
+	var add func(node *deptree.Node, depth, parent int)
+	add = func(node *deptree.Node, depth, parent int) {
+		index := len(b.rows)
+		b.rows = append(b.rows, browserRow{node: node, depth: depth, parent: parent})
+		if !b.expanded[node] {
+			return
+		}
+		for _, name := range slices.Sorted(maps.Keys(node.Children)) {
+			add(node.Children[name], depth+1, index)
+		}
+	}
+	add(b.root, 0, -1)
+
+	b.cursor = 0
+	for i, row := range b.rows {
+		if row.node == selected {
+			b.cursor = i
+		}
+	}
+}
+
+func (b *browser) handleKey(key string) {
+	if b.searching {
+		b.handleSearchKey(key)
+		return
+	}
+
+	b.status = ""
+	row := b.rows[b.cursor]
+
+	switch key {
+	case "q", "ctrl-c":
+		b.quit = true
+	case "up", "k":
+		b.cursor = max(b.cursor-1, 0)
+	case "down", "j":
+		b.cursor = min(b.cursor+1, len(b.rows)-1)
+	case "right", "l", "enter", " ":
+		if len(row.node.Children) > 0 {
+			if key == " " && b.expanded[row.node] {
+				delete(b.expanded, row.node)
+			} else {
+				b.expanded[row.node] = true
+			}
+			b.refresh()
+		}
+	case "left", "h":
+		if b.expanded[row.node] && row.node != b.root {
+			delete(b.expanded, row.node)
+			b.refresh()
+		} else if row.parent >= 0 {
+			b.cursor = row.parent
+		}
+	case "/":
+		b.searching = true
+		b.query = ""
+	case "n":
+		b.findNext()
+	case "d":
+		b.fetchDescription(row.node.Name)
+	case "c":
+		chain := b.chain(b.cursor)
+		b.copied = append(b.copied, chain)
+		b.status = "copied: " + chain
+	}
+}
+
+func (b *browser) handleSearchKey(key string) {
+	switch key {
+	case "enter":
+		b.searching = false
+		b.findNext()
+	case "esc", "ctrl-c":
+		b.searching = false
+	case "backspace":
+		if b.query != "" {
+			_, size := utf8.DecodeLastRuneInString(b.query)
+			b.query = b.query[:len(b.query)-size]
+		}
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			b.query += key
+		}
+	}
+}
+
+// findNext selects the next node after the selected one, in tree order,
+// whose name contains the query, expanding its ancestors.
+func (b *browser) findNext() {
+	if b.query == "" {
+		return
+	}
+	query := strings.ToLower(b.query)
+
+	type match struct {
+		node      *deptree.Node
+		ancestors []*deptree.Node
+	}
+	var order []match
+	var walk func(node *deptree.Node, ancestors []*deptree.Node)
+	walk = func(node *deptree.Node, ancestors []*deptree.Node) {
+		order = append(order, match{node, slices.Clone(ancestors)})
+		ancestors = append(ancestors, node)
+		for _, name := range slices.Sorted(maps.Keys(node.Children)) {
+			walk(node.Children[name], ancestors)
+		}
+	}
+	walk(b.root, nil)
+
+	start := 0
+	for i, m := range order {
+		if m.node == b.rows[b.cursor].node {
+			start = i + 1
+			break
+		}
+	}
+
+	for i := range order {
+		m := order[(start+i)%len(order)]
+		if !strings.Contains(strings.ToLower(m.node.Name), query) {
+			continue
+		}
+		for _, ancestor := range m.ancestors {
+			b.expanded[ancestor] = true
+		}
+		b.refresh()
+		for j, row := range b.rows {
+			if row.node == m.node {
+				b.cursor = j
+			}
+		}
+		return
+	}
+
+	b.status = fmt.Sprintf("no module matches %q", b.query)
+}
+
+// chain formats the path from the root to the node of row i with
+// deptree.FormatChain.
+func (b *browser) chain(i int) string {
+	var path []string
+	for ; i >= 0; i = b.rows[i].parent {
+		path = append(path, b.rows[i].node.Name)
+	}
+	slices.Reverse(path)
+	return deptree.FormatChain(path)
+}
+
+func (b *browser) fetchDescription(name string) {
+	if _, ok := b.descriptions[name]; ok {
+		return
+	}
+	desc, err := b.describe(name)
+	if err != nil {
+		desc = fmt.Sprintf("(%s)", err)
+	}
+	b.descriptions[name] = desc
+}
+
+// view renders the browser into a screen of the given size.
+func (b *browser) view(height, width int) string {
+	treeHeight := max(height-3, 1)
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+treeHeight {
+		b.offset = b.cursor - treeHeight + 1
+	}
+
+	var buf bytes.Buffer
+	for i := b.offset; i < len(b.rows) && i < b.offset+treeHeight; i++ {
+		row := b.rows[i]
+		marker := "  "
+		if len(row.node.Children) > 0 {
+			marker = "▸ "
+			if b.expanded[row.node] {
+				marker = "▾ "
+			}
+		}
+		line := truncate(strings.Repeat("  ", row.depth)+marker+row.node.Name, width)
+		if i == b.cursor {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		buf.WriteString(line + "\r\n")
+	}
+	for i := len(b.rows) - b.offset; i < treeHeight; i++ {
+		buf.WriteString("\r\n")
+	}
+
+	buf.WriteString(strings.Repeat("─", max(width, 1)) + "\r\n")
+
+	selected := b.rows[b.cursor].node.Name
+	switch {
+	case b.searching:
+		buf.WriteString(truncate("/"+b.query, width) + "\r\n")
+	case b.status != "":
+		buf.WriteString(truncate(b.status, width) + "\r\n")
+	default:
+		info := selected
+		if desc, ok := b.descriptions[selected]; ok {
+			info += " - " + desc
+		}
+		buf.WriteString(truncate(info, width) + "\r\n")
+	}
+	buf.WriteString(truncate(interactiveHelp, width))
+
+	return buf.String()
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width > 0 && len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s
+}
+
+// parseKeys splits raw terminal input into key names.
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		switch {
+		case bytes.HasPrefix(input, []byte("\x1b[A")):
+			keys, input = append(keys, "up"), input[3:]
+		case bytes.HasPrefix(input, []byte("\x1b[B")):
+			keys, input = append(keys, "down"), input[3:]
+		case bytes.HasPrefix(input, []byte("\x1b[C")):
+			keys, input = append(keys, "right"), input[3:]
+		case bytes.HasPrefix(input, []byte("\x1b[D")):
+			keys, input = append(keys, "left"), input[3:]
+		case input[0] == 0x1b:
+			keys, input = append(keys, "esc"), input[1:]
+		case input[0] == '\r' || input[0] == '\n':
+			keys, input = append(keys, "enter"), input[1:]
+		case input[0] == 0x7f || input[0] == 0x08:
+			keys, input = append(keys, "backspace"), input[1:]
+		case input[0] == 0x03:
+			keys, input = append(keys, "ctrl-c"), input[1:]
+		default:
+			_, size := utf8.DecodeRune(input)
+			keys, input = append(keys, string(input[:size])), input[size:]
+		}
+	}
+	return keys
+}
+
+// runInteractive lets the user explore the tree of g in the terminal until
+// they quit. The terminal is put in raw mode with stty, which every Unix
+// system has.
+func runInteractive(g *deptree.Graph, client *http.Client, token string) error {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("-interactive needs a terminal on stdin")
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return fmt.Errorf("-interactive needs a terminal with stty: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	var b *browser
+	// Restore the terminal and show the cursor again, then print the copied
+	// chains where they outlive the screen
+	defer func() {
+		stty(strings.TrimSpace(saved))
+		fmt.Print("\x1b[?25h\x1b[H\x1b[2J")
+		if b != nil {
+			for _, chain := range b.copied {
+				fmt.Println(chain)
+			}
+		}
+	}()
+	fmt.Print("\x1b[?25l")
+
+	b = newBrowser(g.Root, func(module string) (string, error) {
+		if desc, ok := g.Descriptions[module]; ok {
+			return desc, nil
+		}
+		return deptree.FetchDescription(deptree.Options{HTTPClient: client}, module, token)
+	})
+
+	input := make([]byte, 64)
+	for !b.quit {
+		height, width := terminalSize()
+		fmt.Print("\x1b[H\x1b[2J" + b.view(height, width))
+
+		n, err := os.Stdin.Read(input)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(input[:n]) {
+			if key == "d" && !b.searching {
+				fmt.Print("\x1b[" + strconv.Itoa(height-1) + ";1H\x1b[2Kfetching description...")
+			}
+			copied := len(b.copied)
+			b.handleKey(key)
+			if len(b.copied) > copied {
+				fmt.Print(osc52(b.copied[copied]))
+			}
+		}
+	}
+
+	return nil
+}
+
+// osc52 returns the escape sequence asking the terminal to put text on the
+// system clipboard. Terminals without OSC 52 support ignore it; the copied
+// chains are printed on exit as well.
+func osc52(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// terminalSize returns the rows and columns of the terminal, falling back to
+// 24x80.
+func terminalSize() (height, width int) {
+	out, err := stty("size")
+	if err != nil {
+		return 24, 80
+	}
+	if _, err := fmt.Sscan(out, &height, &width); err != nil || height == 0 || width == 0 {
+		return 24, 80
+	}
+	return height, width
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func testBrowserTree() *deptree.Node {
+	root := deptree.NewNode("example.com/app")
+	cobra := deptree.NewNode("github.com/spf13/cobra@v1.8.0")
+	pflag := deptree.NewNode("github.com/spf13/pflag@v1.0.5")
+	yaml := deptree.NewNode("gopkg.in/yaml.v3@v3.0.1")
+	cobra.Children[pflag.Name] = pflag
+	root.Children[cobra.Name] = cobra
+	root.Children[yaml.Name] = yaml
+	return root
+}
+
+func TestBrowserNavigation(t *testing.T) {
+	b := newBrowser(testBrowserTree(), nil)
+	if len(b.rows) != 3 {
+		t.Fatalf("Expected root and its children visible, got %d rows", len(b.rows))
+	}
+
+	b.handleKey("down")
+	b.handleKey("right")
+	if len(b.rows) != 4 || b.rows[2].node.Name != "github.com/spf13/pflag@v1.0.5" {
+		t.Fatalf("Expected cobra to expand, got %d rows", len(b.rows))
+	}
+
+	b.handleKey("down")
+	b.handleKey("left") // leaf: jump to parent
+	if b.rows[b.cursor].node.Name != "github.com/spf13/cobra@v1.8.0" {
+		t.Errorf("Expected parent to be selected, got %s", b.rows[b.cursor].node.Name)
+	}
+	b.handleKey("left") // expanded: collapse
+	if len(b.rows) != 3 {
+		t.Errorf("Expected cobra to collapse, got %d rows", len(b.rows))
+	}
+
+	b.handleKey("q")
+	if !b.quit {
+		t.Error("Expected q to quit")
+	}
+}
+
+func TestBrowserSearch(t *testing.T) {
+	b := newBrowser(testBrowserTree(), nil)
+
+	for _, key := range parseKeys([]byte("/pflx\x7fag\r")) {
+		b.handleKey(key)
+	}
+	if got := b.rows[b.cursor].node.Name; got != "github.com/spf13/pflag@v1.0.5" {
+		t.Fatalf("Expected search to select pflag, got %s", got)
+	}
+
+	for _, key := range parseKeys([]byte("/nothing\r")) {
+		b.handleKey(key)
+	}
+	if !strings.Contains(b.view(10, 80), `no module matches "nothing"`) {
+		t.Error("Expected a no-match status")
+	}
+}
+
+func TestBrowserDescription(t *testing.T) {
+	calls := 0
+	b := newBrowser(testBrowserTree(), func(module string) (string, error) {
+		calls++
+		return "An app", nil
+	})
+
+	b.handleKey("d")
+	b.handleKey("d")
+	if calls != 1 {
+		t.Errorf("Expected the description to be fetched once, got %d", calls)
+	}
+	if !strings.Contains(b.view(10, 80), "example.com/app - An app") {
+		t.Errorf("Expected the description in the status line, got:\n%s", b.view(10, 80))
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("\x1b[A\x1b[Bjé\r\x1b"))
+	want := []string{"up", "down", "j", "é", "enter", "esc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys() = %q, want %q", got, want)
+	}
+}
+
+func TestBrowserCopyChain(t *testing.T) {
+	b := newBrowser(testBrowserTree(), nil)
+
+	for _, key := range parseKeys([]byte("/pflag\rc")) {
+		b.handleKey(key)
+	}
+	want := "example.com/app > github.com/spf13/cobra@v1.8.0 > github.com/spf13/pflag@v1.0.5"
+	if len(b.copied) != 1 || b.copied[0] != want {
+		t.Fatalf("copied = %q, want [%q]", b.copied, want)
+	}
+	if !strings.Contains(b.view(10, 120), "copied: "+want) {
+		t.Errorf("Expected the copied chain in the status line, got:\n%s", b.view(10, 120))
+	}
+	if got := osc52("a > b"); got != "\x1b]52;c;YSA+IGI=\a" {
+		t.Errorf("osc52 = %q", got)
+	}
+}
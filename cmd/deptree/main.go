@@ -0,0 +1,807 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+type options struct {
+	packagePath string
+	packageName string
+	packageSet  string
+	format      string
+	exportMode  bool
+	fetchDesc   bool
+	githubToken string
+	caCert      string
+	depth       int
+	why         string
+	chain       bool
+	trimPrefix  string
+	descExec    string
+	selected    bool
+	maxOwners   int
+	policyFile  string
+	license     bool
+	maintenance bool
+	staleYears  int
+	timings     bool
+	vuln        bool
+	severity    string
+	vulnDB      string
+	pageSize    int
+	maxNodes    int
+	sample      int
+	seed        uint64
+	noCache     bool
+	cacheTTL    time.Duration
+	budget      time.Duration
+	quiet       bool
+	failOn      string
+	baseline    string
+	// violationsOnly is set by -quiet; quiet by -q.
+	violationsOnly bool
+	interactive    bool
+	scorecard      bool
+	minScorecard   float64
+	walk           string
+	saveFile       string
+	outputFile     string
+	directOnly     bool
+	color          string
+	outdated       bool
+	engine         string
+	loadFile       string
+	version        string
+	module         string
+	recursive      bool
+	lang           string
+	stats          bool
+	duplicates     bool
+	replaces       bool
+	unused         bool
+	expandAll      bool
+	sortOrder      string
+	checkSums      bool
+	verify         bool
+	hosting        bool
+	risk           bool
+	obligations    bool
+	size           bool
+	tags           string
+	goos           string
+	goarch         string
+	tests          bool
+	noTests        bool
+	profile        string
+	metadataSource string
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				exit(err)
+			}
+			return
+		}
+		if path, ok := findPlugin(os.Args[1]); ok {
+			if err := runPlugin(os.Args[1], path, os.Args[2:]); err != nil {
+				exit(err)
+			}
+			return
+		}
+		if !strings.HasPrefix(os.Args[1], "-") {
+			exit(fmt.Errorf("unknown command %q: not built in and no %s%s on PATH", os.Args[1], pluginPrefix, os.Args[1]))
+		}
+	}
+
+	var opts options
+	defineFlags(flag.CommandLine, &opts)
+	flag.Parse()
+	if err := applyProfile(flag.CommandLine, opts.profile); err != nil {
+		exit(err)
+	}
+
+	// Use environment variable if token not provided via flag
+	if opts.githubToken == "" {
+		opts.githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	if err := runToOutput(opts); err != nil {
+		exit(err)
+	}
+}
+
+// runToOutput runs the tree command, writing its output to the -o file if
+// one is given and to stdout otherwise.
+func runToOutput(opts options) error {
+	if opts.outputFile == "" {
+		return run(opts, os.Stdout)
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	f, err := os.Create(opts.outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	err = run(opts, f)
+	if closeErr := f.Close(); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to write output file: %w", closeErr))
+	}
+	return err
+}
+
+// defineFlags defines the flags of the tree command on fs, storing their
+// values in opts.
+func defineFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.packagePath, "path", ".", "Path to the Go package (default: current directory)")
+	fs.Var(packageList{&opts.packageName}, "package", "Package name to fetch and analyze, with an optional @version, @latest or @branch (e.g., github.com/spf13/cobra@v1.8.0); several, comma-separated or repeated, are analyzed separately and shown as sibling roots")
+	fs.StringVar(&opts.packageSet, "package-set", "", "With several -package, list only the modules all of them (intersection) or any of them (union) depend on")
+	fs.StringVar(&opts.loadFile, "load", "", "Analyze a graph snapshot written by -save instead of a module")
+	fs.StringVar(&opts.outputFile, "o", "", "Write the output to this file instead of stdout")
+	fs.StringVar(&opts.profile, "profile", "", "Apply the flags of a named profile from the config file ($DEPTREE_CONFIG or deptree/config.json in the user config directory); flags given on the command line take precedence")
+	fs.StringVar(&opts.saveFile, "save", "", "Write the analyzed graph, including fetched metadata, to a snapshot file for later -load")
+	fs.StringVar(&opts.version, "version", "", "Version of -package to analyze: latest, a version, branch or commit, or ask to choose from the versions on the module proxy")
+	fs.StringVar(&opts.lang, "lang", deptree.DefaultLang, "Language of html, md-table and markdown report headings: "+strings.Join(deptree.Languages(), ", "))
+	fs.StringVar(&opts.module, "module", "", "In a go.work workspace, analyze only this workspace module")
+	fs.BoolVar(&opts.recursive, "recursive", false, "Analyze every module below -path concurrently as one graph, like a go.work workspace, fetching shared metadata once")
+	fs.StringVar(&opts.engine, "engine", engineGo, "How -package is resolved: go (go get in a temp module) or proxy (module proxy protocol, no go command needed)")
+	fs.StringVar(&opts.format, "format", "tree", "Output format: "+strings.Join(deptree.RendererNames(), ", "))
+	fs.BoolVar(&opts.exportMode, "export", false, "Export as flat list sorted by name with no duplicates (same as -format export)")
+	fs.BoolVar(&opts.fetchDesc, "desc", false, "Fetch and display repository descriptions (GitHub, GitLab, Bitbucket and vanity import paths)")
+	fs.StringVar(&opts.metadataSource, "metadata-source", sourceForge, "Where -desc and -license get metadata: forge (GitHub, GitLab and Bitbucket APIs) or pkgsite (pkg.go.dev synopses, licenses and importer counts, no token needed)")
+	fs.StringVar(&opts.descExec, "desc-exec", "", "Shell command each fetched description is piped through before display (requires -desc)")
+	fs.BoolVar(&opts.noCache, "no-cache", false, "Fetch descriptions even if they are cached on disk")
+	fs.DurationVar(&opts.cacheTTL, "cache-ttl", deptree.DefaultCacheTTL, "How long cached descriptions stay valid")
+	fs.BoolVar(&opts.license, "license", false, "Detect and display each module's license with a summary of license counts")
+	fs.BoolVar(&opts.maintenance, "maintenance", false, "Fetch star count, archived flag and last push of GitHub repositories, flagging archived and stale ones")
+	fs.BoolVar(&opts.scorecard, "scorecard", false, "Fetch the OpenSSF Scorecard score (0-10) of the repositories of GitHub-hosted dependencies")
+	fs.Float64Var(&opts.minScorecard, "min-scorecard", 0, "Exit with status 1 if a dependency's OpenSSF Scorecard score is below this (implies -scorecard; 0 to disable)")
+	fs.IntVar(&opts.staleYears, "stale-years", defaultStaleYears, "Years without a push after which -maintenance flags a repository as stale, and without a release after which -risk flags a module")
+	fs.BoolVar(&opts.vuln, "vuln", false, "Check every module against the OSV vulnerability database, marking affected modules and failing if any are found")
+	fs.StringVar(&opts.vulnDB, "vuln-db", deptree.VulnDBOSV, "Vulnerability database for -vuln: osv, github (GitHub Advisory Database, uses -token) or the path of an offline OSV bundle (a JSON array of advisories or a directory of advisory files)")
+	fs.StringVar(&opts.severity, "severity", "", "Only report vulnerabilities rated at least this severe: low, medium, high or critical (requires -vuln)")
+	fs.BoolVar(&opts.outdated, "outdated", false, "Check the module proxy (GOPROXY) for newer versions and mark outdated modules")
+	fs.DurationVar(&opts.budget, "budget", 0, "Stop fetching descriptions and licenses after this long and show partial results (e.g., 30s; 0 for no limit)")
+	fs.StringVar(&opts.githubToken, "token", "", "GitHub personal access token (or use GITHUB_TOKEN env var)")
+	fs.BoolVar(&opts.selected, "selected", false, "Collapse modules to the versions MVS selected ('go list -m all'), marking pruned versions")
+	fs.BoolVar(&opts.directOnly, "direct-only", false, "Show only the dependencies the root go.mod requires directly (without // indirect)")
+	fs.BoolVar(&opts.interactive, "interactive", false, "Explore the tree in a terminal UI with navigation, search and on-demand descriptions")
+	fs.IntVar(&opts.depth, "depth", 0, "Maximum tree depth to print (0 for unlimited)")
+	fs.StringVar(&opts.sortOrder, "sort", deptree.SortName, "Order of the requirements below each module in the tree: name, depth (longest chains first), children (most requirements first), size (largest first, needs -size) or version (oldest first)")
+	fs.StringVar(&opts.walk, "walk", deptree.WalkDFS, "Traversal order of the tree and ndjson formats: dfs or bfs (level by level)")
+	fs.BoolVar(&opts.chain, "chain", false, "Print each -why path on one line as root > ... > module")
+	fs.BoolVar(&opts.expandAll, "expand-all", false, "Write the requirements of a module at every place it occurs in the tree or markdown list, not just the first, which later ones refer to with (see above)")
+	fs.StringVar(&opts.trimPrefix, "trim-prefix", "", "Strip this prefix from displayed module paths, or \"auto\" for the longest shared prefix (tree, export and markdown only)")
+	fs.IntVar(&opts.maxOwners, "max-owners", 0, "Fail if the graph has more distinct external owners/organizations than this (0 for no limit)")
+	fs.StringVar(&opts.policyFile, "policy", "", "Fail if the graph violates the rules in this YAML or JSON file: banned-modules, banned-licenses, max-depth, allowed-hosts and max-owners")
+	fs.BoolVar(&opts.replaces, "replaces", false, "List the replace directives of go.mod with the modules each replaces, the excluded modules and the directives that apply to nothing")
+	fs.BoolVar(&opts.unused, "unused", false, "List the requirements of go.mod that no package of the module, its tests or its tools imports, as candidates for 'go mod tidy' or removal")
+	fs.BoolVar(&opts.duplicates, "duplicates", false, "List modules required at more than one version and which modules require each version")
+	fs.StringVar(&opts.tags, "tags", "", "Comma-separated build tags; restricts the graph to the modules whose packages the build imports")
+	fs.StringVar(&opts.goos, "GOOS", "", "Target operating system; restricts the graph to the modules whose packages the build imports")
+	fs.StringVar(&opts.goarch, "GOARCH", "", "Target architecture; restricts the graph to the modules whose packages the build imports")
+	fs.BoolVar(&opts.tests, "tests", false, "Mark modules only the tests of the main module import with [test]")
+	fs.BoolVar(&opts.noTests, "no-tests", false, "Leave out modules only tests import, showing what the packages of the main module import")
+	fs.BoolVar(&opts.size, "size", false, "Show the size of each module's source in the module cache; export output is sorted by size")
+	fs.BoolVar(&opts.verify, "verify", false, "Cross-check go.sum hashes against the checksum database (GOSUMDB) and the module cache, and report modules missing from go.sum; exits with status 1 on a mismatch")
+	fs.BoolVar(&opts.checkSums, "check-sums", false, "Check that go.sum has an entry for every module in the graph and none for modules outside it; exits with status 1 on a mismatch")
+	fs.BoolVar(&opts.hosting, "hosting", false, "Report the hosting provider and self-stated GitHub location of each dependency owner (best-effort metadata, uses -token)")
+	fs.BoolVar(&opts.risk, "risk", false, "Score each dependency on maintainer risk signals (single maintainer, archived, no release in -stale-years, pre-v1, replaced) and print them riskiest first")
+	fs.BoolVar(&opts.obligations, "obligations", false, "Detect licenses and summarize what they oblige you to do (attribution, copyleft, patent clauses) per license family")
+	fs.BoolVar(&opts.stats, "stats", false, "Print summary statistics of the graph instead of the tree")
+	fs.StringVar(&opts.why, "why", "", "Print every dependency path from the root to the given module (path or path@version)")
+	fs.BoolVar(&opts.timings, "timings", false, "Print how long each phase took and the number of API calls to stderr")
+	fs.BoolVar(&opts.timings, "v", false, "Verbose output (same as -timings)")
+	fs.StringVar(&opts.color, "color", colorAuto, "Colorize tree and export output: auto (on a terminal unless NO_COLOR is set), always or never")
+	fs.IntVar(&opts.pageSize, "page-size", 0, "Lines per page of tree and export output on a terminal, waiting for a key between pages (0 for the terminal height, -1 to disable paging and $PAGER)")
+	fs.IntVar(&opts.sample, "sample", 0, "Show only this many random paths from the root to leaf modules, for a feel of a huge graph")
+	fs.Uint64Var(&opts.seed, "seed", 0, "Seed of the -sample paths, to repeat a sample (default: random)")
+	fs.IntVar(&opts.maxNodes, "max-nodes", defaultMaxNodes, "Summarize a tree with more nodes than this on a terminal, listing its largest subtrees (0 to always print the full tree)")
+	fs.BoolVar(&opts.quiet, "q", false, "Do not print usage hints or progress to stderr")
+	fs.StringVar(&opts.failOn, "fail-on", "", "Comma-separated conditions that exit with status 1: vuln (implies -vuln), outdated (implies -outdated) or new-dep (modules missing from -baseline)")
+	fs.StringVar(&opts.baseline, "baseline", "", "Directory, snapshot or git ref whose graph -fail-on new-dep compares against")
+	fs.BoolVar(&opts.violationsOnly, "quiet", false, "Print only violations instead of the graph, for use as a CI gate")
+	fs.StringVar(&opts.caCert, "ca-cert", "", "Path to a PEM CA bundle to trust for outbound HTTPS (e.g., a corporate proxy CA)")
+}
+
+// Values of the -metadata-source flag.
+const (
+	sourceForge   = "forge"
+	sourcePkgsite = "pkgsite"
+)
+
+// Values of the -engine flag.
+const (
+	engineGo    = "go"
+	engineProxy = "proxy"
+)
+
+// exitStatus is returned by commands that report their result through the
+// exit status alone.
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// findingsError is returned by run when the output was written but the
+// graph has vulnerabilities, policy violations or -fail-on conditions, so
+// callers can tell these from failures to analyze the graph.
+type findingsError struct {
+	err error
+}
+
+func (e findingsError) Error() string { return e.err.Error() }
+
+func (e findingsError) Unwrap() error { return e.err }
+
+// exit terminates with the status carried by err, printing the error unless
+// it is a bare exitStatus.
+func exit(err error) {
+	var status exitStatus
+	if errors.As(err, &status) {
+		os.Exit(int(status))
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
+
+func run(opts options, stdout io.Writer) error {
+	var workDir string
+	var cleanup bool
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	// -fail-on needs the checks whose results it fails on
+	if opts.failsOn(failOnVuln) {
+		opts.vuln = true
+	}
+	if opts.failsOn(failOnOutdated) {
+		opts.outdated = true
+	}
+	if opts.minScorecard > 0 {
+		opts.scorecard = true
+	}
+
+	format := opts.outputFormat()
+	if _, err := deptree.LookupRenderer(format); err != nil {
+		return err
+	}
+
+	client, err := deptree.NewHTTPClient(opts.caCert)
+	if err != nil {
+		return err
+	}
+
+	var policy deptree.Policy
+	if opts.policyFile != "" {
+		if policy, err = deptree.LoadPolicy(opts.policyFile); err != nil {
+			return err
+		}
+	}
+	if opts.maxOwners > 0 {
+		policy.MaxOwners = opts.maxOwners
+	}
+
+	var baseline *deptree.Graph
+	if opts.baseline != "" {
+		if baseline, err = loadDiffBase(opts.packagePath, opts.baseline); err != nil {
+			return fmt.Errorf("failed to load -baseline: %w", err)
+		}
+	}
+
+	if opts.packageName, err = packageQuery(opts, client, os.Stdin, os.Stderr); err != nil {
+		return err
+	}
+
+	timings := &deptree.Timings{}
+	if opts.timings {
+		counter := countRequests(client)
+		defer func() { writeTimings(os.Stderr, timings, counter) }()
+	}
+
+	spin := newSpinner(os.Stderr, !opts.quiet)
+
+	packages := opts.packages()
+	if len(packages) == 1 && opts.engine != engineProxy {
+		tmpDir, err := os.MkdirTemp("", "deptree-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		defer func() {
+			if cleanup {
+				os.RemoveAll(tmpDir)
+			}
+		}()
+
+		// Show what 'go get' downloads, which takes a while on a cold
+		// module cache
+		spin.Start("go get " + opts.packageName)
+		done := timings.Track("package setup")
+		err = deptree.SetupPackageProgress(tmpDir, opts.packageName, spin.writer())
+		done()
+		spin.Stop()
+		if err != nil {
+			cleanup = true
+			return fmt.Errorf("failed to setup package: %w", err)
+		}
+
+		workDir = tmpDir
+		cleanup = true
+	} else {
+		workDir = opts.packagePath
+	}
+
+	var selectedVersions func() (map[string]string, error)
+	if opts.selected && opts.engine != engineProxy && opts.loadFile == "" {
+		selectedVersions = deptree.StartSelectedVersions(workDir)
+	}
+
+	var graph *deptree.Graph
+	switch {
+	case opts.loadFile != "":
+		done := timings.Track("snapshot loading")
+		graph, err = loadGraphFile(opts.loadFile)
+		done()
+	case len(packages) > 1:
+		graph, err = loadPackages(opts, client, packages, spin, timings)
+	case opts.engine == engineProxy:
+		graph, err = deptree.LoadFromProxy(client, opts.packageName)
+	case opts.recursive:
+		graph, err = deptree.LoadRecursive(workDir)
+	default:
+		if opts.packageName == "" {
+			warnNewerToolchains(workDir)
+		}
+		spin.Start("go mod graph")
+		graph, err = deptree.Load(workDir, opts.packageName)
+		spin.Stop()
+	}
+	if err != nil {
+		return err
+	}
+	for _, phase := range graph.Timings.Phases() {
+		timings.Add(phase.Name, phase.Duration)
+	}
+
+	if graph.Root == nil {
+		fmt.Fprintln(stdout, "No dependencies found")
+		return nil
+	}
+
+	build := opts.buildConfig()
+	if !build.IsZero() || opts.noTests {
+		done := timings.Track("build packages")
+		err := graph.ApplyBuildConfig(workDir, build)
+		done()
+		if err != nil {
+			return err
+		}
+	}
+	if opts.tests {
+		done := timings.Track("test packages")
+		err := graph.MarkTestDependencies(workDir, build)
+		done()
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.module != "" {
+		if err := graph.SelectWorkspaceModule(opts.module); err != nil {
+			return err
+		}
+	}
+
+	if opts.packageSet != "" {
+		if err := graph.ReduceToPackageSet(opts.packageSet); err != nil {
+			return err
+		}
+	}
+
+	if graph.Unexpanded > 0 {
+		switch {
+		case opts.sample > 0:
+			// Sampled below without building the full tree
+		case opts.streamsTree() && summarizesTree(opts, graph, stdout):
+			// Summarized below without building the tree
+		case opts.streamsTree():
+			fmt.Fprintf(os.Stderr, "Warning: the dependency tree has %d nodes, more than %d; printing it while traversing\n", graph.Unexpanded, deptree.MaxTreeNodes)
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: the dependency tree has %d nodes, more than %d; building it may use a lot of memory\n", graph.Unexpanded, deptree.MaxTreeNodes)
+			graph.Expand()
+		}
+	}
+
+	warned := writeWarnings(os.Stderr, graph, 0)
+
+	if opts.selected {
+		done := timings.Track("version selection")
+		var selected map[string]string
+		if selectedVersions == nil {
+			selected = graph.SelectVersions()
+		} else if selected, err = selectedVersions(); err != nil {
+			return err
+		}
+		graph.CollapseToSelected(selected)
+		done()
+	}
+
+	if opts.directOnly {
+		graph.FilterDirect()
+	}
+
+	if opts.sample > 0 {
+		seed := opts.seed
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		paths, leaves := deptree.SamplePaths(graph, opts.sample, seed)
+		graph.KeepPaths(paths)
+		if !opts.quiet {
+			fmt.Fprintf(os.Stderr, "Showing random paths to %d of %d leaf module(s); -seed %d repeats this sample\n", len(paths), leaves, seed)
+		}
+	}
+
+	if opts.why != "" {
+		if opts.chain {
+			_, err = stdout.Write(deptree.RenderWhyChains(graph, opts.why))
+			return err
+		}
+		_, err = stdout.Write(deptree.RenderWhy(graph, opts.why))
+		return err
+	}
+
+	if opts.stats {
+		_, err = stdout.Write(deptree.RenderStats(graph))
+		return err
+	}
+
+	if opts.duplicates {
+		_, err = stdout.Write(deptree.RenderDuplicates(graph))
+		return err
+	}
+
+	if opts.replaces {
+		_, err = stdout.Write(deptree.RenderReplaces(graph))
+		return err
+	}
+
+	if opts.unused {
+		done := timings.Track("unused requirements")
+		unused, err := graph.UnusedRequirements(workDir, build)
+		done()
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(deptree.RenderUnused(unused, build.Resolved()))
+		return err
+	}
+
+	if opts.checkSums {
+		check := deptree.CheckSums(graph)
+		if _, err := stdout.Write(deptree.RenderSumCheck(check)); err != nil {
+			return err
+		}
+		if !check.OK() {
+			return exitStatus(1)
+		}
+		return nil
+	}
+
+	if opts.verify {
+		done := timings.Track("checksum verification")
+		spin.Start("verifying go.sum")
+		verification := deptree.VerifySums(graph, deptree.Options{HTTPClient: client})
+		spin.Stop()
+		done()
+		if _, err := stdout.Write(deptree.RenderSumVerification(verification)); err != nil {
+			return err
+		}
+		if !verification.OK() {
+			return exitStatus(1)
+		}
+		return nil
+	}
+
+	metadata := deptree.Options{HTTPClient: client}
+	if opts.budget > 0 {
+		metadata.HTTPClient = deptree.WithBudget(client, opts.budget)
+	}
+
+	if opts.hosting {
+		done := timings.Track("hosting")
+		hostings := deptree.DetectHosting(graph, metadata, opts.githubToken)
+		done()
+		_, err = stdout.Write(deptree.RenderHosting(hostings))
+		return err
+	}
+
+	if opts.risk {
+		done := timings.Track("risk signals")
+		report := deptree.AssessRisk(graph, metadata, opts.githubToken, staleAfter(opts.staleYears))
+		done()
+		if report.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", report.Err)
+		}
+		if len(graph.Partial) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: time budget of %s exceeded; risk signals for %d module(s) are incomplete\n", opts.budget, len(graph.Partial))
+		}
+		_, err = stdout.Write(deptree.RenderRisk(report))
+		return err
+	}
+
+	if opts.obligations {
+		if graph.Licenses == nil {
+			done := timings.Track("licenses")
+			deptree.DetectLicenses(graph, metadata, opts.githubToken)
+			done()
+		}
+		writeWarnings(os.Stderr, graph, warned)
+		if len(graph.Partial) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: time budget of %s exceeded; licenses of %d module(s) are unknown\n", opts.budget, len(graph.Partial))
+		}
+		_, err = stdout.Write(deptree.RenderObligations(graph))
+		return err
+	}
+
+	switch {
+	case opts.metadataSource == sourcePkgsite && (opts.fetchDesc || opts.license):
+		done := timings.Track("pkg.go.dev metadata")
+		spin.Start("fetching pkg.go.dev metadata")
+		deptree.FetchPkgsiteMetadata(graph, metadata, opts.license, spin.counter("fetched %d/%d pkg.go.dev pages"))
+		spin.Stop()
+		done()
+	case opts.fetchDesc:
+		done := timings.Track("descriptions")
+		cache := openDescriptionCache(opts)
+		spin.Start("fetching descriptions")
+		deptree.FetchDescriptionsProgress(graph, metadata, opts.githubToken, cache, spin.counter("fetched %d/%d descriptions"))
+		spin.Stop()
+		if err := cache.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		done()
+	}
+	if opts.fetchDesc && opts.descExec != "" {
+		if err := deptree.TransformDescriptions(graph, opts.descExec); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	// pkg.go.dev metadata includes the licenses
+	if opts.license && opts.metadataSource != sourcePkgsite || policy.NeedsLicenses() && graph.Licenses == nil {
+		done := timings.Track("licenses")
+		deptree.DetectLicenses(graph, metadata, opts.githubToken)
+		done()
+	}
+
+	if opts.maintenance {
+		done := timings.Track("repository status")
+		err := deptree.FetchRepoStatuses(graph, metadata, opts.githubToken, staleAfter(opts.staleYears))
+		done()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	if opts.scorecard {
+		done := timings.Track("scorecards")
+		err := deptree.FetchScorecards(graph, metadata)
+		done()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	if opts.vuln {
+		// validate has already rejected unknown severities
+		minSeverity, _ := deptree.ParseSeverity(opts.severity)
+		db, err := deptree.NewVulnDB(opts.vulnDB, deptree.Options{HTTPClient: client}, opts.githubToken)
+		if err != nil {
+			return err
+		}
+		done := timings.Track("vulnerabilities")
+		err = deptree.DetectVulnerabilities(graph, db, minSeverity)
+		done()
+		if err != nil {
+			return fmt.Errorf("failed to check vulnerabilities: %w", err)
+		}
+	}
+
+	if opts.outdated {
+		done := timings.Track("outdated versions")
+		err := deptree.DetectOutdated(graph, client)
+		done()
+		if err != nil {
+			return fmt.Errorf("failed to check for newer versions: %w", err)
+		}
+	}
+
+	if opts.size {
+		if err := deptree.DetectSizes(graph); err != nil {
+			return fmt.Errorf("failed to measure module sizes: %w", err)
+		}
+	}
+
+	// -outdated warns too, such as about GOPROXY=off
+	writeWarnings(os.Stderr, graph, warned)
+	if len(graph.Partial) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: time budget of %s exceeded; metadata for %d module(s) is incomplete\n", opts.budget, len(graph.Partial))
+	}
+
+	// The dependencies' go.mod files tell replace and exclude edges apart
+	if slices.Contains([]string{"json", "dot", "graphml", "gexf"}, format) || opts.saveFile != "" {
+		if err := deptree.ReadModFiles(graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read go.mod files from the module cache: %v\n", err)
+		}
+	}
+
+	if opts.saveFile != "" {
+		if err := saveGraphFile(opts.saveFile, graph); err != nil {
+			return err
+		}
+	}
+
+	if opts.interactive {
+		return runInteractive(graph, client, opts.githubToken)
+	}
+
+	renderOpts := deptree.RenderOptions{
+		ShowDesc:    opts.fetchDesc,
+		ShowLicense: opts.license,
+		MaxDepth:    opts.depth,
+		TrimPrefix:  opts.trimPrefix,
+		Walk:        opts.walk,
+		Color:       useColor(opts.color, stdout),
+		Lang:        opts.lang,
+		Export:      opts.exportMode,
+		ExpandAll:   opts.expandAll,
+		Sort:        opts.sortOrder,
+	}
+	switch {
+	case opts.violationsOnly:
+		// -quiet prints only the violations reported below
+	case summarizesTree(opts, graph, stdout):
+		if err := writeTreeSummary(stdout, os.Stderr, graph, opts.maxNodes); err != nil {
+			return err
+		}
+	default:
+		done := timings.Track("rendering")
+		err := writeOutput(stdout, graph, format, renderOpts, opts.pageSize)
+		done()
+		if err != nil {
+			return err
+		}
+	}
+
+	if !opts.quiet && !opts.violationsOnly {
+		showHints(os.Stderr, usageHints(opts, graph))
+	}
+
+	policyErr := checkPolicy(graph, policy)
+
+	var vulnErr error
+	if n := len(graph.Vulnerabilities); n > 0 {
+		vulnErr = fmt.Errorf("%d vulnerable module(s) found", n)
+	}
+
+	failErr := checkFailOn(os.Stderr, graph, baseline, opts)
+
+	if err := errors.Join(policyErr, vulnErr, failErr); err != nil {
+		return findingsError{err}
+	}
+	return nil
+}
+
+// defaultStaleYears is the default of -stale-years, for the tree command
+// and review alike.
+const defaultStaleYears = 2
+
+// staleAfter returns how long the -stale-years setting years is.
+func staleAfter(years int) time.Duration {
+	return time.Duration(years) * 365 * 24 * time.Hour
+}
+
+// writeWarnings writes the warnings of graph from index warned on, which
+// earlier calls have written, and returns the index to continue from.
+func writeWarnings(w io.Writer, graph *deptree.Graph, warned int) int {
+	for _, warning := range graph.Warnings[warned:] {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	return len(graph.Warnings)
+}
+
+// warnNewerToolchains warns about the module in dir and its cached
+// requirements needing a newer Go than the local one, which otherwise only
+// surfaces as a 'go mod graph' failure or a toolchain download.
+func warnNewerToolchains(dir string) {
+	local, err := deptree.LocalGoVersion()
+	if err != nil || local == "" {
+		return
+	}
+	newer, err := deptree.CheckToolchains(dir, local)
+	if err != nil {
+		return
+	}
+	for _, req := range newer {
+		fmt.Fprintf(os.Stderr, "Warning: %s, newer than the local %s; 'go mod graph' may fail or switch toolchains (see GOTOOLCHAIN)\n", req, local)
+	}
+}
+
+// loadGraphFile reads a graph from a snapshot written by -save or, failing
+// that, from the output of the json format.
+func loadGraphFile(path string) (*deptree.Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	graph, err := deptree.LoadSnapshot(bytes.NewReader(data))
+	if errors.Is(err, deptree.ErrNotSnapshot) {
+		graph, err = deptree.ReadJSONGraph(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return graph, nil
+}
+
+// saveGraphFile writes graph to a snapshot file at path.
+func saveGraphFile(path string, graph *deptree.Graph) error {
+	var buf bytes.Buffer
+	if err := deptree.SaveSnapshot(&buf, graph); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// openDescriptionCache opens the on-disk description cache, or returns nil
+// when caching is disabled or unavailable.
+func openDescriptionCache(opts options) *deptree.DescriptionCache {
+	if opts.noCache {
+		return nil
+	}
+
+	path, err := deptree.DefaultDescriptionCachePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: description cache disabled: %v\n", err)
+		return nil
+	}
+	cache, err := deptree.OpenDescriptionCache(path, opts.cacheTTL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: description cache disabled: %v\n", err)
+		return nil
+	}
+	return cache
+}
+
+// checkPolicy reports every policy violation on stderr and fails if there
+// are any.
+func checkPolicy(g *deptree.Graph, policy deptree.Policy) error {
+	violations := policy.Check(g)
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "Policy violation: %s\n", v)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d policy violation(s)", len(violations))
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRun_NoPackageNameOrPath(t *testing.T) {
+	// Create a temporary directory with a minimal Go module
+	tmpDir := t.TempDir()
+
+	// Create go.mod
+	goModContent := []byte("module test\n\ngo 1.21\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), goModContent, 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	// Create a simple main.go
+	mainGoContent := []byte("package main\n\nfunc main() {}\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), mainGoContent, 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	err := run(options{packagePath: tmpDir}, io.Discard)
+	if err != nil {
+		t.Errorf("run() failed: %v", err)
+	}
+}
+
+func TestRun_ExportMode(t *testing.T) {
+	// Create a temporary directory with a minimal Go module
+	tmpDir := t.TempDir()
+
+	// Create go.mod
+	goModContent := []byte("module test\n\ngo 1.21\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), goModContent, 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	// Create a simple main.go
+	mainGoContent := []byte("package main\n\nfunc main() {}\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), mainGoContent, 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err := run(options{packagePath: tmpDir, exportMode: true}, &buf)
+	if err != nil {
+		t.Errorf("run() in export mode failed: %v", err)
+	}
+	if buf.String() != "test\n" {
+		t.Errorf("Expected the export to list the main module, got %q", buf.String())
+	}
+}
+
+func TestRunToOutputFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module test\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "deps.json")
+	if err := runToOutput(options{packagePath: tmpDir, format: "json", outputFile: output}); err != nil {
+		t.Fatalf("runToOutput failed: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte(`"root": "test"`)) {
+		t.Errorf("Expected the JSON output in the file, got %s", data)
+	}
+
+	// Invalid options leave no file behind
+	invalid := filepath.Join(t.TempDir(), "invalid.txt")
+	if err := runToOutput(options{packagePath: tmpDir, format: "nope", outputFile: invalid, interactive: true}); err == nil {
+		t.Error("Expected invalid options to fail")
+	}
+	if _, err := os.Stat(invalid); !os.IsNotExist(err) {
+		t.Errorf("Expected no output file for invalid options, got %v", err)
+	}
+}
+
+func TestPackageFlag(t *testing.T) {
+	fs := flag.NewFlagSet("deptree", flag.ContinueOnError)
+	var opts options
+	defineFlags(fs, &opts)
+	if err := fs.Parse([]string{"-package", "github.com/spf13/cobra, github.com/urfave/cli/v2", "-package", "github.com/alecthomas/kong@v1.2.1"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"github.com/spf13/cobra", "github.com/urfave/cli/v2", "github.com/alecthomas/kong@v1.2.1"}
+	if got := opts.packages(); !slices.Equal(got, want) {
+		t.Errorf("packages() = %q, want %q", got, want)
+	}
+}
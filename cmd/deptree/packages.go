@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// packageList is the value of the -package flag: a comma-separated list of
+// packages, to which a repeated flag adds.
+type packageList struct {
+	value *string
+}
+
+func (p packageList) String() string {
+	if p.value == nil {
+		return ""
+	}
+	return *p.value
+}
+
+func (p packageList) Set(s string) error {
+	if *p.value != "" {
+		s = *p.value + "," + s
+	}
+	*p.value = s
+	return nil
+}
+
+// packages returns the packages listed in -package.
+func (o options) packages() []string {
+	var packages []string
+	for _, name := range strings.Split(o.packageName, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			packages = append(packages, name)
+		}
+	}
+	return packages
+}
+
+// loadPackages analyzes each package on its own, in a temp module of its
+// own or from the module proxy, so each keeps the versions it would select
+// when used alone, and combines the graphs below deptree.PackagesRoot.
+func loadPackages(opts options, client *http.Client, packages []string, spin *spinner, timings *deptree.Timings) (*deptree.Graph, error) {
+	var graphs []*deptree.Graph
+	for _, name := range packages {
+		graph, err := loadEach(opts.engine, client, name, spin, timings)
+		if err != nil {
+			return nil, err
+		}
+		if graph.Root == nil {
+			fmt.Fprintf(os.Stderr, "Warning: no dependencies found for %s\n", name)
+			continue
+		}
+		graphs = append(graphs, graph)
+	}
+	return deptree.CombinePackages(graphs), nil
+}
+
+// loadEach loads the graph of one of several packages analyzed on their
+// own with the given -engine.
+func loadEach(engine string, client *http.Client, name string, spin *spinner, timings *deptree.Timings) (*deptree.Graph, error) {
+	var graph *deptree.Graph
+	var err error
+	if engine == engineProxy {
+		graph, err = deptree.LoadFromProxy(client, name)
+	} else {
+		graph, err = loadPackage(name, spin, timings)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return graph, nil
+}
+
+// loadPackage fetches one package of loadEach with 'go get' in a temp
+// module and loads its graph.
+func loadPackage(name string, spin *spinner, timings *deptree.Timings) (*deptree.Graph, error) {
+	tmpDir, err := os.MkdirTemp("", "deptree-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	spin.Start("go get " + name)
+	done := timings.Track("package setup")
+	err = deptree.SetupPackageProgress(tmpDir, name, spin.writer())
+	done()
+	spin.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup package: %w", err)
+	}
+
+	spin.Start("go mod graph")
+	defer spin.Stop()
+	return deptree.Load(tmpDir, name)
+}
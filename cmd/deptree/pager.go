@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// pagerPrompt is shown below each page until a key is pressed.
+const pagerPrompt = "-- %d/%d lines: space for more, enter for one line, q to quit --"
+
+// writeOutput renders graph in format to out. Tree and export output on a
+// terminal goes through $PAGER when it is set, written while the graph is
+// walked, or else through the built-in pager when it is longer than a
+// page; -page-size -1 turns both off. Graphs too large to expand are
+// always written while traversing.
+func writeOutput(out io.Writer, graph *deptree.Graph, format string, renderOpts deptree.RenderOptions, pageSizeFlag int) error {
+	render := func(w io.Writer) error {
+		if graph.Unexpanded > 0 {
+			return deptree.StreamTree(w, graph, renderOpts)
+		}
+		if err := deptree.RenderTo(w, format, graph, renderOpts); err != nil {
+			return fmt.Errorf("failed to render %s output: %w", format, err)
+		}
+		return nil
+	}
+
+	terminal, ok := out.(*os.File)
+	if !ok || terminal != os.Stdout || (format != "tree" && format != "export") || pageSizeFlag < 0 || !isTerminal(terminal) {
+		return render(out)
+	}
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return writeToPager(pager, terminal, render)
+	}
+	size := pageSize(pageSizeFlag, terminal)
+	if size == 0 || graph.Unexpanded > 0 {
+		return render(out)
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		return err
+	}
+	output := buf.Bytes()
+	if lines := bytes.Count(output, []byte("\n")); lines > size {
+		header := fmt.Sprintf("%d module(s), %d lines of output", len(graph.Modules()), lines)
+		return writePagedTerminal(output, header, size)
+	}
+	_, err := out.Write(output)
+	return err
+}
+
+// writeToPager runs the pager command line through the shell with its
+// output on out and has render write into it. LESS defaults to FRX, as for
+// git, so less passes colors through and exits on output shorter than a
+// screen. Quitting the pager before the end stops rendering without an
+// error. If the pager cannot be started, render writes to out directly.
+func writeToPager(pager string, out *os.File, render func(io.Writer) error) error {
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return render(out)
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to start pager %q: %v\n", pager, err)
+		return render(out)
+	}
+
+	renderErr := render(in)
+	in.Close()
+	waitErr := cmd.Wait()
+	if errors.Is(renderErr, syscall.EPIPE) || errors.Is(renderErr, os.ErrClosed) {
+		renderErr = nil
+	}
+	if waitErr != nil {
+		waitErr = fmt.Errorf("pager %q failed: %w", pager, waitErr)
+	}
+	return errors.Join(renderErr, waitErr)
+}
+
+// pageSize resolves the -page-size flag for output written to out: the
+// given size, the terminal height when it is 0, or 0 (no paging) when it is
+// negative or stdin or out is not a terminal.
+func pageSize(size int, out *os.File) int {
+	if size < 0 || !isTerminal(os.Stdin) || !isTerminal(out) {
+		return 0
+	}
+	if size == 0 {
+		height, _ := terminalSize()
+		// Keep a line for the prompt
+		size = height - 1
+	}
+	return max(size, 1)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writePagedTerminal pages output on the terminal, reading single key
+// presses from stdin without echoing them.
+func writePagedTerminal(output []byte, header string, size int) error {
+	saved, err := stty("-g")
+	if err != nil {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+	// Without isig, ctrl-c is read as a key rather than killing deptree
+	// before the terminal is restored
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+	defer stty(strings.TrimSpace(saved))
+
+	return writePaged(os.Stdout, os.Stdin, output, header, size)
+}
+
+// writePaged writes output to w a page of size lines at a time, starting
+// with header, and waits for a key from keys after each page: space shows
+// the next page, enter the next line, and q, escape or ctrl-c stop.
+func writePaged(w io.Writer, keys io.Reader, output []byte, header string, size int) error {
+	lines := bytes.SplitAfter(output, []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if header != "" {
+		lines = append([][]byte{[]byte(header + "\n")}, lines...)
+	}
+
+	input := make([]byte, 64)
+	shown, next := 0, size
+	for shown < len(lines) {
+		end := min(next, len(lines))
+		for _, line := range lines[shown:end] {
+			if _, err := w.Write(line); err != nil {
+				return err
+			}
+		}
+		shown = end
+		if shown == len(lines) {
+			break
+		}
+
+		fmt.Fprintf(w, pagerPrompt, shown, len(lines))
+		n, err := keys.Read(input)
+		// Erase the prompt
+		fmt.Fprint(w, "\r\x1b[2K")
+		if err != nil {
+			return nil
+		}
+		keys := parseKeys(input[:n])
+		if len(keys) == 0 {
+			continue
+		}
+		switch keys[len(keys)-1] {
+		case "q", "esc", "ctrl-c":
+			return nil
+		case "enter", "down", "j":
+			next = shown + 1
+		default:
+			next = shown + size
+		}
+	}
+	return nil
+}
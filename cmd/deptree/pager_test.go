@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWritePaged(t *testing.T) {
+	var output strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&output, "line %d\n", i)
+	}
+
+	var out bytes.Buffer
+	// A page, one line, then quit
+	keys := &scriptedKeys{presses: []string{" ", "\n", "q"}}
+	if err := writePaged(&out, keys, []byte(output.String()), "10 module(s)", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.ReplaceAll(out.String(), "\r\x1b[2K", "")
+	want := "10 module(s)\nline 1\nline 2\n" +
+		fmt.Sprintf(pagerPrompt, 3, 11) + "line 3\nline 4\nline 5\n" +
+		fmt.Sprintf(pagerPrompt, 6, 11) + "line 6\n" +
+		fmt.Sprintf(pagerPrompt, 7, 11)
+	if got != want {
+		t.Errorf("Paged output:\n%q\nwant:\n%q", got, want)
+	}
+
+	out.Reset()
+	keys = &scriptedKeys{presses: []string{" ", " ", " "}}
+	if err := writePaged(&out, keys, []byte(output.String()), "", 4); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "line 10\n") {
+		t.Errorf("Expected all lines after paging to the end, got %q", out.String())
+	}
+	if len(keys.presses) != 1 {
+		t.Errorf("Expected no prompt after the last page, %d key(s) left", len(keys.presses))
+	}
+
+	// A read without a key prompts again
+	out.Reset()
+	keys = &scriptedKeys{presses: []string{"", "q"}}
+	if err := writePaged(&out, keys, []byte(output.String()), "", 4); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.Contains(got, "line 4\n") || strings.Contains(got, "line 5\n") {
+		t.Errorf("Expected one page before quitting after an empty read, got %q", got)
+	}
+}
+
+func TestWriteToPager(t *testing.T) {
+	out, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	render := func(w io.Writer) error {
+		for i := 1; i <= 100000; i++ {
+			if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// A pager quitting early ends rendering without an error
+	if err := writeToPager("head -n 2", out, render); err != nil {
+		t.Fatalf("writeToPager failed: %v", err)
+	}
+	got, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "line 1\nline 2\n" {
+		t.Errorf("Pager output %q, want the first two lines", got)
+	}
+
+	if err := writeToPager("exit 3", out, func(io.Writer) error { return nil }); err == nil {
+		t.Error("Expected an error for a failing pager")
+	}
+}
+
+// scriptedKeys returns one scripted key press per Read.
+type scriptedKeys struct {
+	presses []string
+}
+
+func (k *scriptedKeys) Read(p []byte) (int, error) {
+	if len(k.presses) == 0 {
+		return 0, fmt.Errorf("no more keys")
+	}
+	n := copy(p, k.presses[0])
+	k.presses = k.presses[1:]
+	return n, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pluginPrefix starts the names of the executables deptree runs as
+// external subcommands, like git does: "deptree foo" runs deptree-foo from
+// PATH.
+const pluginPrefix = "deptree-"
+
+// findPlugin returns the path of the executable implementing the external
+// subcommand name, if there is one on PATH.
+func findPlugin(name string) (string, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	return path, err == nil
+}
+
+// runPlugin analyzes the project like the tree command, taking its flags
+// from args up to the first other argument or "--", and runs the plugin at
+// path with the remaining args, passing the graph in the json format on
+// its stdin. A failing plugin's exit status becomes deptree's; otherwise
+// deptree fails on the findings of the analysis like the tree command.
+func runPlugin(name, path string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	var opts options
+	defineFlags(fs, &opts)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: deptree %s [flags] [--] [plugin args]\n", name)
+		fmt.Fprintf(fs.Output(), "Runs the plugin %s with the dependency graph as JSON on its stdin. Takes the flags of the tree command, which select what to analyze and the metadata to fetch; the arguments after them are passed to the plugin.\n", path)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyProfile(fs, opts.profile); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.format != "tree" && opts.format != "json", opts.exportMode:
+		return fmt.Errorf("plugins read the json format, so -format and -export are not available")
+	case opts.outputFile != "":
+		return fmt.Errorf("plugins read the graph from stdin, so -o is not available")
+	}
+	opts.format = "json"
+	if opts.githubToken == "" {
+		opts.githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	// The findings of the analysis, such as vulnerable modules, are
+	// reported once the plugin has seen the graph
+	var graph bytes.Buffer
+	findings := run(opts, &graph)
+	if findings != nil && !errors.As(findings, new(findingsError)) {
+		return findings
+	}
+	if !json.Valid(graph.Bytes()) {
+		// An empty project has no graph, only a message for the user
+		os.Stderr.Write(graph.Bytes())
+		return findings
+	}
+
+	cmd := exec.Command(path, fs.Args()...)
+	cmd.Stdin = &graph
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitStatus(max(exitErr.ExitCode(), 1))
+		}
+		return fmt.Errorf("failed to run %s: %w", pluginPrefix+name, err)
+	}
+	return findings
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPlugin(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\ncat > \"$1\"\nexit 3\n"
+	if err := os.WriteFile(filepath.Join(bin, "deptree-dump"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	path, ok := findPlugin("dump")
+	if !ok {
+		t.Fatal("Expected deptree-dump to be found on PATH")
+	}
+	for _, name := range []string{"missing", "-desc", "../dump"} {
+		if _, ok := findPlugin(name); ok {
+			t.Errorf("Expected no plugin for %q", name)
+		}
+	}
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "go.mod"), []byte("module test\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(t.TempDir(), "graph.json")
+
+	err := runPlugin("dump", path, []string{"-path", project, "--", output})
+	var status exitStatus
+	if !errors.As(err, &status) || status != 3 {
+		t.Errorf("Expected the plugin's exit status 3, got %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"root": "test"`) {
+		t.Errorf("Expected the JSON graph on the plugin's stdin, got %s", data)
+	}
+
+	if err := runPlugin("dump", path, []string{"-path", project, "-format", "dot"}); err == nil {
+		t.Error("Expected -format dot to be rejected")
+	}
+
+	// Findings do not keep the plugin from running, and fail once it
+	// succeeds
+	script = "#!/bin/sh\ncat > \"$1\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "deptree-save"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path, _ = findPlugin("save")
+	dependency := filepath.Join(project, "a")
+	if err := os.Mkdir(dependency, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dependency, "go.mod"), []byte("module example.com/a\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gomod := "module test\n\ngo 1.21\n\nrequire example.com/a v1.0.0\n\nreplace example.com/a => ./a\n"
+	if err := os.WriteFile(filepath.Join(project, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policy, []byte(`{"banned-modules": ["example.com/a"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(output)
+
+	err = runPlugin("save", path, []string{"-path", project, "-policy", policy, "--", output})
+	if err == nil || !strings.Contains(err.Error(), "policy violation") {
+		t.Errorf("Expected the policy violation after the plugin ran, got %v", err)
+	}
+	if data, err := os.ReadFile(output); err != nil || !strings.Contains(string(data), "example.com/a") {
+		t.Errorf("Expected the plugin to get the graph despite the violation, got %s (%v)", data, err)
+	}
+
+}
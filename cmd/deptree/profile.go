@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// config is the deptree config file.
+type config struct {
+	// Profiles maps the names -profile selects to the flags they set, by
+	// flag name without the dash. A list sets a flag once per element.
+	Profiles map[string]map[string]any `json:"profiles"`
+	// Hints set to false turns the usage hints off.
+	Hints *bool `json:"hints"`
+}
+
+// configPath returns the path of the config file: $DEPTREE_CONFIG, or
+// config.json in the deptree directory of the user's config directory,
+// e.g. ~/.config/deptree/config.json on Linux.
+func configPath() (string, error) {
+	if path := os.Getenv("DEPTREE_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "deptree", "config.json"), nil
+}
+
+// loadConfig reads the config file at path.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var c config
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// applyProfile sets the flags of the named profile from the config file in
+// fs. Flags given on the command line take precedence over the profile.
+func applyProfile(fs *flag.FlagSet, name string) error {
+	if name == "" {
+		return nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	c, err := loadConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("-profile %s: no config file at %s to define it in", name, path)
+	}
+	if err != nil {
+		return err
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("-profile %s is not defined in %s (profiles: %s)", name, path, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for _, flagName := range slices.Sorted(maps.Keys(profile)) {
+		switch {
+		case flagName == "profile":
+			return fmt.Errorf("profile %s cannot select another profile", name)
+		case fs.Lookup(flagName) == nil:
+			return fmt.Errorf("profile %s sets unknown flag -%s", name, flagName)
+		case given[flagName]:
+			continue
+		}
+		values, ok := profile[flagName].([]any)
+		if !ok {
+			values = []any{profile[flagName]}
+		}
+		for _, value := range values {
+			if err := fs.Set(flagName, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("profile %s: invalid value %v for -%s: %w", name, value, flagName, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{"profiles": {
+		"compliance": {"format": "cyclonedx", "license": true, "fail-on": "vuln"},
+		"quickcheck": {"depth": 2, "direct-only": true, "package": ["github.com/a/b", "github.com/c/d"]},
+		"broken": {"no-such-flag": 1}
+	}}`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DEPTREE_CONFIG", path)
+
+	parse := func(args ...string) (options, error) {
+		fs := flag.NewFlagSet("deptree", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		var opts options
+		defineFlags(fs, &opts)
+		if err := fs.Parse(args); err != nil {
+			return opts, err
+		}
+		return opts, applyProfile(fs, opts.profile)
+	}
+
+	opts, err := parse("-profile", "compliance", "-format", "spdx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.format != "spdx" || !opts.license || opts.failOn != "vuln" {
+		t.Errorf("Expected the profile with -format from the command line, got format %q, license %v, fail-on %q", opts.format, opts.license, opts.failOn)
+	}
+
+	opts, err = parse("-profile", "quickcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.depth != 2 || !opts.directOnly || opts.packageName != "github.com/a/b,github.com/c/d" {
+		t.Errorf("Expected depth 2, -direct-only and both packages, got %d, %v, %q", opts.depth, opts.directOnly, opts.packageName)
+	}
+
+	for profile, want := range map[string]string{
+		"broken":  "unknown flag -no-such-flag",
+		"missing": "profiles: broken, compliance, quickcheck",
+	} {
+		if _, err := parse("-profile", profile); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("-profile %s: expected error containing %q, got %v", profile, want, err)
+		}
+	}
+
+	t.Setenv("DEPTREE_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	if _, err := parse("-profile", "compliance"); err == nil || !strings.Contains(err.Error(), "no config file") {
+		t.Errorf("Expected a missing config file to be reported, got %v", err)
+	}
+	if _, err := parse(); err != nil {
+		t.Errorf("Expected no config needed without -profile, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// spinnerFrames are drawn in turn in front of the status line.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is how often the status line is redrawn.
+const spinnerInterval = 100 * time.Millisecond
+
+// spinner keeps a status line on a terminal redrawn while a long operation
+// runs, so it is clear deptree is not hung. A nil *spinner shows nothing,
+// which is what newSpinner returns when output is not a terminal.
+type spinner struct {
+	out io.Writer
+
+	mu     sync.Mutex
+	status string
+	line   []byte
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// newSpinner returns a spinner drawing on out if enabled and out is a
+// terminal, and nil otherwise.
+func newSpinner(out *os.File, enabled bool) *spinner {
+	if !enabled || !isTerminal(out) {
+		return nil
+	}
+	return &spinner{out: out}
+}
+
+// Start shows status until Stop is called.
+func (s *spinner) Start(status string) {
+	if s == nil {
+		return
+	}
+	s.Update(status)
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+func (s *spinner) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		s.mu.Lock()
+		fmt.Fprintf(s.out, "\r\x1b[2K%s %s", spinnerFrames[frame%len(spinnerFrames)], s.status)
+		s.mu.Unlock()
+		select {
+		case <-s.stop:
+			fmt.Fprint(s.out, "\r\x1b[2K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Update replaces the status shown.
+func (s *spinner) Update(status string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// Stop erases the status line.
+func (s *spinner) Stop() {
+	if s == nil || s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+}
+
+// Write shows each complete line written as the status, such as the
+// "go: downloading" lines of 'go get'.
+func (s *spinner) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.line = append(s.line, p...)
+	var last string
+	for {
+		i := bytes.IndexByte(s.line, '\n')
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimSpace(string(s.line[:i])); line != "" {
+			last = line
+		}
+		s.line = s.line[i+1:]
+	}
+	s.mu.Unlock()
+	if last != "" {
+		s.Update(last)
+	}
+	return len(p), nil
+}
+
+// writer returns s as an io.Writer, or nil if s is nil.
+func (s *spinner) writer() io.Writer {
+	if s == nil {
+		return nil
+	}
+	return s
+}
+
+// counter returns a progress callback showing counts as format, which
+// takes the count done and the total, or nil if s is nil.
+func (s *spinner) counter(format string) func(done, total int) {
+	if s == nil {
+		return nil
+	}
+	return func(done, total int) {
+		s.Update(fmt.Sprintf(format, done, total))
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSpinner(t *testing.T) {
+	var out bytes.Buffer
+	s := &spinner{out: &out}
+	s.Start("go get example.com/m")
+	s.Write([]byte("go: downloading example.com/a v1.0.0\ngo: downloading exa"))
+	if s.status != "go: downloading example.com/a v1.0.0" {
+		t.Errorf("Expected the last complete line as status, got %q", s.status)
+	}
+	s.Write([]byte("mple.com/b v1.2.0\n"))
+	if s.status != "go: downloading example.com/b v1.2.0" {
+		t.Errorf("Expected lines split across writes to be joined, got %q", s.status)
+	}
+	s.counter("fetched %d/%d descriptions")(45, 120)
+	if s.status != "fetched 45/120 descriptions" {
+		t.Errorf("Unexpected counter status %q", s.status)
+	}
+	s.Stop()
+
+	if !strings.HasSuffix(out.String(), "\r\x1b[2K") {
+		t.Errorf("Expected Stop to erase the status line, got %q", out.String())
+	}
+
+	// A nil spinner, as for output that is not a terminal, does nothing
+	var none *spinner
+	none.Start("x")
+	none.Update("y")
+	none.Stop()
+	if none.writer() != nil || none.counter("%d/%d") != nil {
+		t.Error("Expected a nil spinner to give no writer or counter")
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// sitePageName matches the module pages of a published site, which
+// publishing again removes when their module left the graph.
+var sitePageName = regexp.MustCompile(`^m[0-9a-f]{12}\.html$`)
+
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	var opts options
+	defineFlags(fs, &opts)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree publish -o <directory> [flags]")
+		fmt.Fprintln(fs.Output(), "Writes the dependency graph as a static site: an index of the modules with a search box, a page per module and a search-index.json, for GitHub Pages or any web server. Takes the flags of the tree command, which select what to analyze and the metadata to fetch.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("publish takes no arguments, got %q", fs.Args())
+	}
+	if err := applyProfile(fs, opts.profile); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.outputFile == "":
+		return fmt.Errorf("publish needs the directory to write the site to with -o")
+	case opts.format != "tree" || opts.exportMode || opts.depth > 0 || opts.walk != deptree.WalkDFS:
+		return fmt.Errorf("publish writes its own pages, so -format, -export, -depth and -walk are not available")
+	case opts.interactive || opts.why != "" || opts.stats || opts.duplicates || opts.replaces || opts.unused || opts.checkSums || opts.verify || opts.hosting || opts.risk || opts.obligations:
+		return fmt.Errorf("publish writes the graph, so -interactive and reports such as -why and -stats are not available")
+	}
+	dir := opts.outputFile
+	if opts.githubToken == "" {
+		opts.githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	// The graph reaches the site through a snapshot, which keeps all the
+	// fetched metadata
+	f, err := os.CreateTemp("", "deptree-*.snapshot")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	saveFile := opts.saveFile
+	opts.saveFile = f.Name()
+	opts.outputFile = ""
+	opts.format = "json"
+
+	// Findings such as vulnerable modules or -fail-on conditions are
+	// reported once the site is written; the snapshot is saved only when
+	// the analysis got that far
+	runErr := run(opts, io.Discard)
+	snapshot, err := os.ReadFile(f.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if len(snapshot) == 0 {
+		if runErr != nil {
+			return runErr
+		}
+		return fmt.Errorf("no dependencies found to publish")
+	}
+	if saveFile != "" {
+		if err := os.WriteFile(saveFile, snapshot, 0644); err != nil {
+			return fmt.Errorf("failed to save snapshot: %w", err)
+		}
+	}
+	graph, err := loadGraphFile(f.Name())
+	if err != nil {
+		return err
+	}
+
+	files, err := deptree.RenderSite(graph, deptree.RenderOptions{
+		ShowDesc:    opts.fetchDesc,
+		ShowLicense: opts.license,
+		Lang:        opts.lang,
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeSite(dir, files); err != nil {
+		return err
+	}
+
+	if !opts.quiet {
+		fmt.Fprintf(os.Stderr, "Wrote %d file(s) to %s\n", len(files), dir)
+	}
+	return runErr
+}
+
+// writeSite writes files to dir and removes the pages of modules no longer
+// in the graph, leaving any other files in dir alone.
+func writeSite(dir string, files []deptree.SiteFile) error {
+	written := make(map[string]bool)
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create site directory: %w", err)
+		}
+		if err := os.WriteFile(path, file.Data, 0644); err != nil {
+			return fmt.Errorf("failed to write site: %w", err)
+		}
+		written[path] = true
+	}
+
+	pages := filepath.Join(dir, "modules")
+	entries, err := os.ReadDir(pages)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read site directory: %w", err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(pages, entry.Name())
+		if sitePageName.MatchString(entry.Name()) && !written[path] {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove stale page: %w", err)
+			}
+		}
+	}
+	return nil
+}
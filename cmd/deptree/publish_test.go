@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestRunPublish(t *testing.T) {
+	graph := filepath.Join(t.TempDir(), "graph.json")
+	data := `{"root": "test", "modules": [{"name": "test", "path": "test"}, {"name": "example.com/dep@v1.0.0", "path": "example.com/dep", "version": "v1.0.0"}],
+		"edges": [{"from": "test", "to": "example.com/dep@v1.0.0", "kind": "direct"}]}`
+	if err := os.WriteFile(graph, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	site := t.TempDir()
+	stale := filepath.Join(site, "modules", "m000000000000.html")
+	other := filepath.Join(site, "modules", "notes.html")
+	for _, path := range []string{stale, other} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := runPublish([]string{"-load", graph, "-o", site, "-q"}); err != nil {
+		t.Fatalf("runPublish failed: %v", err)
+	}
+	for _, name := range []string{"index.html", "search-index.json", deptree.SiteModulePage("example.com/dep@v1.0.0")} {
+		if _, err := os.Stat(filepath.Join(site, filepath.FromSlash(name))); err != nil {
+			t.Errorf("Expected %s in the site: %v", name, err)
+		}
+	}
+	index, err := os.ReadFile(filepath.Join(site, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "example.com/dep") {
+		t.Errorf("Expected the dependency in index.html, got:\n%s", index)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale module page to be removed, got %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Expected other files to be kept: %v", err)
+	}
+
+	if err := runPublish([]string{"-load", graph}); err == nil || !strings.Contains(err.Error(), "-o") {
+		t.Errorf("Expected an error without -o, got %v", err)
+	}
+	if err := runPublish([]string{"-load", graph, "-o", site, "-format", "json"}); err == nil {
+		t.Error("Expected -format to be rejected")
+	}
+}
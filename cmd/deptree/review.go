@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// reviewReport collects everything the review checklist reports about a
+// candidate dependency.
+type reviewReport struct {
+	Module string
+
+	Repo    *deptree.GitHubRepo
+	RepoErr error
+
+	// Brings is every module the candidate pulls in, excluding itself.
+	Brings []string
+	// New are the modules in Brings whose path is not yet in the project.
+	New []string
+	// Shared maps modules in Brings to the version the project already uses.
+	Shared map[string]string
+	// ProjectErr is set when the project's own graph could not be read.
+	ProjectErr error
+
+	Vulns    map[string][]string
+	VulnsErr error
+}
+
+func runReview(args []string) error {
+	fs := flag.NewFlagSet("review", flag.ContinueOnError)
+	projectPath := fs.String("path", ".", "Path to the Go project the module would be added to")
+	githubToken := fs.String("token", "", "GitHub personal access token (or use GITHUB_TOKEN env var)")
+	caCert := fs.String("ca-cert", "", "Path to a PEM CA bundle to trust for outbound HTTPS")
+	staleYears := fs.Int("stale-years", defaultStaleYears, "Years without a push after which the repository is flagged as possibly unmaintained")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree review [flags] <module>[@version]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("review needs exactly one module, got %d arguments", fs.NArg())
+	}
+	if *staleYears < 1 {
+		return fmt.Errorf("-stale-years must be at least 1, got %d", *staleYears)
+	}
+
+	if *githubToken == "" {
+		*githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	client, err := deptree.NewHTTPClient(*caCert)
+	if err != nil {
+		return err
+	}
+
+	report, err := reviewModule(client, fs.Arg(0), *projectPath, *githubToken)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(renderReview(report, time.Now(), staleAfter(*staleYears)))
+	return err
+}
+
+// reviewModule resolves the candidate module in a temp module and gathers
+// the checklist data, comparing it with the project at projectPath.
+func reviewModule(client *http.Client, module, projectPath, token string) (*reviewReport, error) {
+	tmpDir, err := os.MkdirTemp("", "deptree-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := deptree.SetupPackage(tmpDir, module); err != nil {
+		return nil, fmt.Errorf("failed to setup package: %w", err)
+	}
+
+	deps, err := deptree.ReadModuleGraph(tmpDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	candidate := &deptree.Graph{Root: deptree.BuildDependencyTree(deps, module), Deps: deps}
+
+	projectDeps, projectErr := deptree.ReadModuleGraph(projectPath)
+	project := &deptree.Graph{Deps: projectDeps}
+
+	return buildReviewReport(client, candidate, project, projectErr, token), nil
+}
+
+func buildReviewReport(client *http.Client, candidate, project *deptree.Graph, projectErr error, token string) *reviewReport {
+	report := &reviewReport{
+		Module:     candidate.Root.Name,
+		Brings:     candidate.Reachable(candidate.Root.Name),
+		Shared:     make(map[string]string),
+		ProjectErr: projectErr,
+	}
+
+	projectVersions := make(map[string]string)
+	for _, name := range project.Modules() {
+		path, version := deptree.SplitModule(name)
+		if existing, ok := projectVersions[path]; !ok || deptree.CompareVersions(version, existing) > 0 {
+			projectVersions[path] = version
+		}
+	}
+
+	for _, name := range report.Brings {
+		path, _ := deptree.SplitModule(name)
+		if version, ok := projectVersions[path]; ok {
+			report.Shared[name] = version
+		} else {
+			report.New = append(report.New, name)
+		}
+	}
+
+	report.Repo, report.RepoErr = deptree.FetchGitHubRepo(deptree.Options{HTTPClient: client}, report.Module, token)
+	report.Vulns, report.VulnsErr = deptree.QueryVulnerabilities(deptree.Options{HTTPClient: client}, append([]string{report.Module}, report.Brings...))
+
+	return report
+}
+
+// renderReview formats the report as a checklist. Items are marked [ok],
+// [!!] when they need attention, such as no push for longer than
+// staleAfter, or [??] when they could not be determined.
+func renderReview(r *reviewReport, now time.Time, staleAfter time.Duration) []byte {
+	var buf bytes.Buffer
+	item := func(status, format string, args ...any) {
+		fmt.Fprintf(&buf, "[%s] %s\n", status, fmt.Sprintf(format, args...))
+	}
+
+	fmt.Fprintf(&buf, "Dependency review: %s\n\n", r.Module)
+
+	// License
+	switch {
+	case r.RepoErr != nil:
+		item("??", "License: unknown (%s)", r.RepoErr)
+	case r.Repo.License == nil || r.Repo.License.SPDXID == "" || r.Repo.License.SPDXID == "NOASSERTION":
+		item("!!", "License: none detected")
+	default:
+		item("ok", "License: %s", r.Repo.License.SPDXID)
+	}
+
+	// Maintenance signals
+	_, version := deptree.SplitModule(r.Module)
+	if strings.HasPrefix(version, "v0.") {
+		item("!!", "Version: %s is pre-v1 and makes no compatibility promise", version)
+	} else {
+		item("ok", "Version: %s", version)
+	}
+	if r.RepoErr != nil {
+		item("??", "Maintenance: unknown (%s)", r.RepoErr)
+	} else {
+		lastPush := r.Repo.PushedAt.Format("2006-01-02")
+		switch {
+		case r.Repo.Archived:
+			item("!!", "Maintenance: repository is archived (last push %s)", lastPush)
+		case now.Sub(r.Repo.PushedAt) > staleAfter:
+			item("!!", "Maintenance: no push since %s", lastPush)
+		default:
+			item("ok", "Maintenance: last push %s, %d stars", lastPush, r.Repo.StargazersCount)
+		}
+	}
+
+	// Transitive weight
+	if len(r.New) == 0 {
+		item("ok", "Transitive weight: adds no new modules (%d already in the project)", len(r.Brings))
+	} else {
+		item("!!", "Transitive weight: adds %d new module(s) of %d required", len(r.New), len(r.Brings))
+		for _, name := range r.New {
+			fmt.Fprintf(&buf, "       + %s\n", name)
+		}
+	}
+
+	// Overlap with existing dependencies
+	if r.ProjectErr != nil {
+		item("??", "Overlap: project graph unavailable (%s)", r.ProjectErr)
+	} else {
+		item("ok", "Overlap: %d module(s) already in the project", len(r.Shared))
+		for _, name := range slices.Sorted(maps.Keys(r.Shared)) {
+			path, version := deptree.SplitModule(name)
+			if existing := r.Shared[name]; existing != version {
+				fmt.Fprintf(&buf, "       = %s (needs %s, project has %s)\n", path, version, existing)
+			} else {
+				fmt.Fprintf(&buf, "       = %s\n", name)
+			}
+		}
+	}
+
+	// Vulnerabilities
+	switch {
+	case r.VulnsErr != nil:
+		item("??", "Vulnerabilities: unknown (%s)", r.VulnsErr)
+	case len(r.Vulns) == 0:
+		item("ok", "Vulnerabilities: none known")
+	default:
+		item("!!", "Vulnerabilities: %d affected module(s)", len(r.Vulns))
+		for _, name := range slices.Sorted(maps.Keys(r.Vulns)) {
+			fmt.Fprintf(&buf, "       ! %s: %s\n", name, strings.Join(r.Vulns[name], ", "))
+		}
+	}
+
+	return buf.Bytes()
+}
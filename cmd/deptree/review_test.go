@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestRenderReview(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := &reviewReport{
+		Module: "github.com/example/pkg@v0.4.0",
+		Repo: &deptree.GitHubRepo{
+			PushedAt: now.AddDate(-3, 0, 0),
+			License:  &deptree.GitHubLicense{SPDXID: "MIT"},
+		},
+		Brings: []string{"dep1@v1.0.0", "dep2@v1.2.0"},
+		New:    []string{"dep1@v1.0.0"},
+		Shared: map[string]string{"dep2@v1.2.0": "v1.1.0"},
+		Vulns:  map[string][]string{"dep1@v1.0.0": {"GO-2024-0001"}},
+	}
+
+	output := string(renderReview(report, now, staleAfter(defaultStaleYears)))
+
+	for _, want := range []string{
+		"[ok] License: MIT",
+		"[!!] Version: v0.4.0 is pre-v1",
+		"[!!] Maintenance: no push since 2022-01-01",
+		"[!!] Transitive weight: adds 1 new module(s) of 2 required",
+		"= dep2 (needs v1.2.0, project has v1.1.0)",
+		"! dep1@v1.0.0: GO-2024-0001",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderReviewStaleYears(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := &reviewReport{
+		Module: "github.com/example/pkg@v1.4.0",
+		Repo:   &deptree.GitHubRepo{PushedAt: now.AddDate(-1, -6, 0), StargazersCount: 12},
+	}
+
+	tests := []struct {
+		years int
+		want  string
+	}{
+		{defaultStaleYears, "[ok] Maintenance: last push 2023-07-01, 12 stars"},
+		{1, "[!!] Maintenance: no push since 2023-07-01"},
+	}
+	for _, tt := range tests {
+		output := string(renderReview(report, now, staleAfter(tt.years)))
+		if !strings.Contains(output, tt.want) {
+			t.Errorf("renderReview() with -stale-years %d = %q, want it to contain %q", tt.years, output, tt.want)
+		}
+	}
+}
+
+func TestRenderReviewUnknowns(t *testing.T) {
+	report := &reviewReport{
+		Module:     "gopkg.in/yaml.v3@v3.0.1",
+		RepoErr:    errors.New("not a GitHub module"),
+		ProjectErr: errors.New("no go.mod"),
+		VulnsErr:   errors.New("OSV API returned status 503"),
+	}
+
+	output := string(renderReview(report, time.Now(), staleAfter(defaultStaleYears)))
+
+	for _, want := range []string{
+		"[??] License: unknown (not a GitHub module)",
+		"[??] Overlap: project graph unavailable (no go.mod)",
+		"[??] Vulnerabilities: unknown (OSV API returned status 503)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
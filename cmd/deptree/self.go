@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// runSelf prints the modules deptree itself was built from, read from the
+// build info embedded in the running binary.
+func runSelf(args []string) error {
+	fs := flag.NewFlagSet("self", flag.ContinueOnError)
+	format := fs.String("format", "tree", "Output format: any format of the default command")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree self [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("self takes no arguments, got %d", fs.NArg())
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return fmt.Errorf("this deptree binary was built without module support, so it has no build info")
+	}
+
+	output, err := deptree.Render(*format, deptree.FromBuildInfo(info), deptree.RenderOptions{
+		Color: useColor(colorAuto, os.Stdout),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(output)
+	return err
+}
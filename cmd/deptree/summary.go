@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// defaultMaxNodes is the default of -max-nodes: trees larger than this are
+// summarized on a terminal rather than flooding it.
+const defaultMaxNodes = 10000
+
+// summaryTop is how many of the largest subtrees a tree summary lists.
+const summaryTop = 10
+
+// summarizesTree reports whether the tree of graph is summarized instead of
+// printed: it is tree output bound for a terminal and has more nodes than
+// -max-nodes within -depth. Output redirected to a file or pipe is always
+// printed in full.
+func summarizesTree(opts options, graph *deptree.Graph, out io.Writer) bool {
+	if opts.maxNodes == 0 || opts.outputFormat() != "tree" || opts.interactive {
+		return false
+	}
+	if f, ok := out.(*os.File); !ok || !isTerminal(f) {
+		return false
+	}
+	return graph.TreeNodes(opts.depth) > opts.maxNodes
+}
+
+// writeTreeSummary writes the summary of a tree larger than maxNodes to w,
+// and to stderr how to get the full tree.
+func writeTreeSummary(w, stderr io.Writer, graph *deptree.Graph, maxNodes int) error {
+	fmt.Fprintf(stderr, "Warning: the dependency tree has more than %d nodes; showing a summary. Use -max-nodes 0 to print it in full, or narrow it with -depth, -direct-only or -why <module>\n", maxNodes)
+	_, err := w.Write(deptree.RenderTreeSummary(graph, summaryTop))
+	return err
+}
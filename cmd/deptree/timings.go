@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// requestCounter is an http.RoundTripper that counts outbound requests per
+// host.
+type requestCounter struct {
+	base   http.RoundTripper
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func countRequests(client *http.Client) *requestCounter {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	counter := &requestCounter{base: base, counts: make(map[string]int)}
+	client.Transport = counter
+	return counter
+}
+
+func (c *requestCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.counts[req.URL.Host]++
+	c.mu.Unlock()
+	return c.base.RoundTrip(req)
+}
+
+// writeTimings prints the duration of every phase and the number of API
+// calls made to each host.
+func writeTimings(w io.Writer, timings *deptree.Timings, counter *requestCounter) {
+	var total time.Duration
+	fmt.Fprintln(w, "Timings:")
+	for _, phase := range timings.Phases() {
+		fmt.Fprintf(w, "  %-20s %s\n", phase.Name, roundDuration(phase.Duration))
+		total += phase.Duration
+	}
+	fmt.Fprintf(w, "  %-20s %s\n", "total", roundDuration(total))
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	fmt.Fprintln(w, "API calls:")
+	if len(counter.counts) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, host := range slices.Sorted(maps.Keys(counter.counts)) {
+		fmt.Fprintf(w, "  %-20s %d\n", host, counter.counts[host])
+	}
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+func TestWriteTimings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := server.Client()
+	counter := countRequests(client)
+	for range 3 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	timings := &deptree.Timings{}
+	timings.Add("graph retrieval", 1500*time.Millisecond)
+	timings.Add("rendering", 250*time.Microsecond)
+
+	var buf bytes.Buffer
+	writeTimings(&buf, timings, counter)
+	output := buf.String()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	for _, want := range []string{"graph retrieval      1.5s", "rendering            250µs", "total                1.5s", host} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if !strings.Contains(output, "      3\n") {
+		t.Errorf("Expected 3 API calls to be counted, got:\n%s", output)
+	}
+}
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// flagRule rejects one nonsensical flag combination.
+type flagRule struct {
+	violated func(o options) bool
+	message  func(o options) string
+}
+
+// flagRules lists the combinations validate rejects. Add a rule here when a
+// new flag only makes sense together with (or without) other flags.
+var flagRules = []flagRule{
+	{
+		violated: func(o options) bool { return o.depth < 0 },
+		message:  func(o options) string { return fmt.Sprintf("-depth must not be negative, got %d", o.depth) },
+	},
+	{
+		violated: func(o options) bool { return o.packageName != "" && o.packagePath != "" && o.packagePath != "." },
+		message:  func(o options) string { return "-path and -package are mutually exclusive" },
+	},
+	{
+		violated: func(o options) bool {
+			return o.exportMode && !slices.Contains([]string{"", "tree", "export", "markdown", "csv", "tsv"}, o.format)
+		},
+		message: func(o options) string {
+			return fmt.Sprintf("-export cannot be combined with -format %s", o.format)
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.depth > 0 && !o.walksTree() && (o.outputFormat() != "markdown" || o.exportMode)
+		},
+		message: func(o options) string {
+			if o.outputFormat() == "markdown" {
+				return "-depth does not apply to the markdown table of -export"
+			}
+			return fmt.Sprintf("-depth only applies to the tree, ndjson and markdown formats, not %s", o.outputFormat())
+		},
+	},
+	{
+		violated: func(o options) bool {
+			format := o.outputFormat()
+			return o.trimPrefix != "" && (format != "tree" && format != "export" && format != "markdown" || o.interactive)
+		},
+		message: func(o options) string {
+			return "-trim-prefix only applies to the tree, export and markdown formats and cannot be combined with -interactive"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.metadataSource != "" && o.metadataSource != sourceForge && o.metadataSource != sourcePkgsite
+		},
+		message: func(o options) string {
+			return fmt.Sprintf("-metadata-source must be %s or %s, got %q", sourceForge, sourcePkgsite, o.metadataSource)
+		},
+	},
+	{
+		violated: func(o options) bool { return o.walk != "" && o.walk != deptree.WalkDFS && o.walk != deptree.WalkBFS },
+		message:  func(o options) string { return fmt.Sprintf("-walk must be dfs or bfs, got %q", o.walk) },
+	},
+	{
+		violated: func(o options) bool { return o.sortOrder != "" && !slices.Contains(deptree.SortOrders, o.sortOrder) },
+		message: func(o options) string {
+			return fmt.Sprintf("-sort must be one of %s, got %q", strings.Join(deptree.SortOrders, ", "), o.sortOrder)
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.sortOrder != "" && o.sortOrder != deptree.SortName && (o.outputFormat() != "tree" || o.walk == deptree.WalkBFS || o.interactive || o.why != "")
+		},
+		message: func(o options) string {
+			return "-sort only applies to the tree format with -walk dfs, not to -interactive or -why"
+		},
+	},
+	{
+		violated: func(o options) bool { return o.sortOrder == deptree.SortSize && !o.size },
+		message:  func(o options) string { return "-sort size needs the module sizes from -size" },
+	},
+	{
+		violated: func(o options) bool {
+			format := o.outputFormat()
+			return o.expandAll && (format != "tree" && format != "markdown" || o.exportMode || o.walk == deptree.WalkBFS)
+		},
+		message: func(o options) string {
+			return "-expand-all only applies to the tree format with -walk dfs and the markdown list"
+		},
+	},
+	{
+		violated: func(o options) bool { return o.walk == deptree.WalkBFS && !o.walksTree() },
+		message: func(o options) string {
+			return fmt.Sprintf("-walk only applies to the tree and ndjson formats, not %s", o.outputFormat())
+		},
+	},
+	{
+		violated: func(o options) bool { return len(o.reportModes()) > 1 },
+		message: func(o options) string {
+			modes := o.reportModes()
+			return fmt.Sprintf("%s and %s are mutually exclusive", strings.Join(modes[:len(modes)-1], ", "), modes[len(modes)-1])
+		},
+	},
+	{
+		violated: func(o options) bool { return len(o.reportModes()) == 1 && len(o.graphFlags()) > 0 },
+		message: func(o options) string {
+			return fmt.Sprintf("%s prints a report instead of the graph, so it cannot be combined with %s", o.reportModes()[0], strings.Join(o.graphFlags(), ", "))
+		},
+	},
+	{
+		violated: func(o options) bool { return o.why != "" && o.directOnly },
+		message:  func(o options) string { return "-why cannot be combined with -direct-only" },
+	},
+	{
+		violated: func(o options) bool {
+			return o.unused && (o.packageName != "" || o.engine == engineProxy || o.loadFile != "" || o.recursive)
+		},
+		message: func(o options) string {
+			return "-unused needs the packages of the module at -path, so it cannot be combined with -package, -engine proxy, -load or -recursive"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.checkSums && (o.selected || o.directOnly || o.engine == engineProxy)
+		},
+		message: func(o options) string {
+			return "-check-sums needs the full graph of a go.sum, so it cannot be combined with -selected, -direct-only or -engine proxy"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.verify && (o.selected || o.directOnly || o.engine == engineProxy)
+		},
+		message: func(o options) string {
+			return "-verify needs the full graph of a go.sum, so it cannot be combined with -selected, -direct-only or -engine proxy"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			format := o.outputFormat()
+			return o.size && format != "tree" && format != "export" && format != "json"
+		},
+		message: func(o options) string {
+			return "-size only applies to the tree, export and json formats"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return (!o.buildConfig().IsZero() || o.tests || o.noTests) && (o.engine == engineProxy || o.loadFile != "" || o.recursive)
+		},
+		message: func(o options) string {
+			return "-tags, -GOOS, -GOARCH, -tests and -no-tests need the packages of the module, so they cannot be combined with -engine proxy, -load or -recursive"
+		},
+	},
+	{
+		violated: func(o options) bool { return o.recursive && (o.packageName != "" || o.loadFile != "") },
+		message: func(o options) string {
+			return "-recursive analyzes the modules below -path, so it cannot be combined with -package or -load"
+		},
+	},
+	{
+		violated: func(o options) bool { return (o.maintenance || o.risk) && o.staleYears < 1 },
+		message:  func(o options) string { return fmt.Sprintf("-stale-years must be at least 1, got %d", o.staleYears) },
+	},
+	{
+		violated: func(o options) bool { return o.minScorecard < 0 || o.minScorecard > 10 },
+		message: func(o options) string {
+			return fmt.Sprintf("-min-scorecard must be between 0 and 10, got %g", o.minScorecard)
+		},
+	},
+	{
+		violated: func(o options) bool { return o.tests && o.noTests },
+		message:  func(o options) string { return "-tests and -no-tests are mutually exclusive" },
+	},
+	{
+		violated: func(o options) bool {
+			format := o.outputFormat()
+			return o.pageSize > 0 && (format != "tree" && format != "export" || o.interactive)
+		},
+		message: func(o options) string {
+			return "-page-size only applies to the tree and export formats and cannot be combined with -interactive"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.interactive && (o.exportMode || o.outputFormat() != "tree" || o.depth > 0 || o.why != "" || o.outputFile != "")
+		},
+		message: func(o options) string {
+			return "-interactive cannot be combined with -format, -export, -depth, -why or -o"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.loadFile != "" && (o.packageName != "" || (o.packagePath != "" && o.packagePath != ".") || o.selected)
+		},
+		message: func(o options) string { return "-load cannot be combined with -path, -package or -selected" },
+	},
+	{
+		violated: func(o options) bool { return o.saveFile != "" && o.why != "" },
+		message:  func(o options) string { return "-save cannot be combined with -why" },
+	},
+	{
+		violated: func(o options) bool {
+			return o.color != "" && o.color != colorAuto && o.color != colorAlways && o.color != colorNever
+		},
+		message: func(o options) string { return fmt.Sprintf("-color must be auto, always or never, got %q", o.color) },
+	},
+	{
+		violated: func(o options) bool { return o.engine != "" && o.engine != engineGo && o.engine != engineProxy },
+		message:  func(o options) string { return fmt.Sprintf("-engine must be go or proxy, got %q", o.engine) },
+	},
+	{
+		violated: func(o options) bool { return o.engine == engineProxy && o.packageName == "" },
+		message:  func(o options) string { return "-engine proxy requires -package" },
+	},
+	{
+		violated: func(o options) bool { return o.version != "" && o.packageName == "" },
+		message:  func(o options) string { return "-version requires -package" },
+	},
+	{
+		violated: func(o options) bool { return o.version != "" && strings.Contains(o.packageName, "@") },
+		message: func(o options) string {
+			return fmt.Sprintf("-version cannot be combined with a version in -package %s", o.packageName)
+		},
+	},
+	{
+		violated: func(o options) bool { return o.version != "" && len(o.packages()) > 1 },
+		message: func(o options) string {
+			return "-version cannot be combined with several -package; add a version to each"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return len(o.packages()) > 1 && (!o.buildConfig().IsZero() || o.tests || o.noTests || o.selected)
+		},
+		message: func(o options) string {
+			return "several -package cannot be combined with -tags, -GOOS, -GOARCH, -tests, -no-tests or -selected"
+		},
+	},
+	{
+		violated: func(o options) bool {
+			return o.packageSet != "" && o.packageSet != deptree.SetIntersection && o.packageSet != deptree.SetUnion
+		},
+		message: func(o options) string {
+			return fmt.Sprintf("-package-set must be intersection or union, got %q", o.packageSet)
+		},
+	},
+	{
+		violated: func(o options) bool { return o.packageSet != "" && len(o.packages()) < 2 },
+		message:  func(o options) string { return "-package-set requires several -package" },
+	},
+	{
+		violated: func(o options) bool { return o.module != "" && (o.packageName != "" || o.loadFile != "") },
+		message:  func(o options) string { return "-module cannot be combined with -package or -load" },
+	},
+	{
+		violated: func(o options) bool { return o.lang != "" && !slices.Contains(deptree.Languages(), o.lang) },
+		message: func(o options) string {
+			return fmt.Sprintf("-lang must be one of %s, got %q", strings.Join(deptree.Languages(), ", "), o.lang)
+		},
+	},
+	{
+		violated: func(o options) bool { return o.sample < 0 },
+		message:  func(o options) string { return fmt.Sprintf("-sample must not be negative, got %d", o.sample) },
+	},
+	{
+		violated: func(o options) bool {
+			return o.sample > 0 && (!o.walksTree() || o.why != "" || o.stats || o.duplicates || o.interactive || o.saveFile != "" || o.packageSet != "")
+		},
+		message: func(o options) string {
+			return "-sample needs tree or ndjson output and cannot be combined with -why, -stats, -duplicates, -interactive, -save or -package-set"
+		},
+	},
+	{
+		violated: func(o options) bool { return o.seed != 0 && o.sample == 0 },
+		message:  func(o options) string { return "-seed requires -sample" },
+	},
+	{
+		violated: func(o options) bool { return o.maxNodes < 0 },
+		message:  func(o options) string { return fmt.Sprintf("-max-nodes must not be negative, got %d", o.maxNodes) },
+	},
+	{
+		violated: func(o options) bool { return o.maxOwners < 0 },
+		message:  func(o options) string { return fmt.Sprintf("-max-owners must not be negative, got %d", o.maxOwners) },
+	},
+	{
+		violated: func(o options) bool {
+			for _, condition := range o.failOnList() {
+				if !slices.Contains(failOnConditions, condition) {
+					return true
+				}
+			}
+			return false
+		},
+		message: func(o options) string {
+			return fmt.Sprintf("-fail-on must list conditions of %s, got %q", strings.Join(failOnConditions, ", "), o.failOn)
+		},
+	},
+	{
+		violated: func(o options) bool { return o.failsOn(failOnNewDep) != (o.baseline != "") },
+		message:  func(o options) string { return "-fail-on new-dep and -baseline require each other" },
+	},
+	{
+		violated: func(o options) bool {
+			return o.interactive && (o.failOn != "" || o.violationsOnly || o.policyFile != "" || o.maxOwners > 0)
+		},
+		message: func(o options) string {
+			return "-fail-on, -quiet, -policy and -max-owners cannot be combined with -interactive"
+		},
+	},
+	{
+		violated: func(o options) bool { return o.cacheTTL < 0 },
+		message:  func(o options) string { return fmt.Sprintf("-cache-ttl must not be negative, got %s", o.cacheTTL) },
+	},
+	{
+		violated: func(o options) bool { return o.budget < 0 },
+		message:  func(o options) string { return fmt.Sprintf("-budget must not be negative, got %s", o.budget) },
+	},
+	{
+		violated: func(o options) bool { return o.severity != "" && !o.vuln && !o.failsOn(failOnVuln) },
+		message:  func(o options) string { return "-severity requires -vuln" },
+	},
+	{
+		violated: func(o options) bool { return o.chain && o.why == "" },
+		message:  func(o options) string { return "-chain requires -why" },
+	},
+	{
+		violated: func(o options) bool {
+			return o.vulnDB != "" && o.vulnDB != deptree.VulnDBOSV && !o.vuln && !o.failsOn(failOnVuln)
+		},
+		message: func(o options) string { return "-vuln-db requires -vuln" },
+	},
+	{
+		violated: func(o options) bool {
+			_, err := deptree.ParseSeverity(o.severity)
+			return err != nil
+		},
+		message: func(o options) string {
+			_, err := deptree.ParseSeverity(o.severity)
+			return "-severity: " + err.Error()
+		},
+	},
+	{
+		violated: func(o options) bool { return o.descExec != "" && !o.fetchDesc },
+		message:  func(o options) string { return "-desc-exec requires -desc" },
+	},
+}
+
+// validate fails fast on flag combinations that would otherwise be silently
+// ignored.
+func (o options) validate() error {
+	for _, rule := range flagRules {
+		if rule.violated(o) {
+			return fmt.Errorf("invalid flags: %s", rule.message(o))
+		}
+	}
+	return nil
+}
+
+// reportModes lists the set flags that print a report of the graph and
+// return instead of printing the graph itself.
+func (o options) reportModes() []string {
+	var modes []string
+	for _, mode := range []struct {
+		flag string
+		set  bool
+	}{
+		{"-why", o.why != ""},
+		{"-stats", o.stats},
+		{"-duplicates", o.duplicates},
+		{"-replaces", o.replaces},
+		{"-unused", o.unused},
+		{"-check-sums", o.checkSums},
+		{"-verify", o.verify},
+		{"-hosting", o.hosting},
+		{"-risk", o.risk},
+		{"-obligations", o.obligations},
+	} {
+		if mode.set {
+			modes = append(modes, mode.flag)
+		}
+	}
+	return modes
+}
+
+// graphFlags lists the set flags that shape, annotate or gate the printed
+// graph, which the report modes skip.
+func (o options) graphFlags() []string {
+	var flags []string
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"-format", o.format != "" && o.format != "tree"},
+		{"-export", o.exportMode},
+		{"-depth", o.depth > 0},
+		{"-interactive", o.interactive},
+		{"-desc", o.fetchDesc},
+		// -obligations detects the licenses it summarizes
+		{"-license", o.license && !o.obligations},
+		{"-vuln", o.vuln},
+		{"-outdated", o.outdated},
+		{"-size", o.size},
+		{"-maintenance", o.maintenance},
+		{"-scorecard", o.scorecard && o.minScorecard == 0},
+		{"-min-scorecard", o.minScorecard > 0},
+		{"-policy", o.policyFile != ""},
+		{"-max-owners", o.maxOwners > 0},
+		{"-fail-on", o.failOn != ""},
+		{"-quiet", o.violationsOnly},
+		{"-save", o.saveFile != ""},
+		{"-trim-prefix", o.trimPrefix != ""},
+	} {
+		if flag.set {
+			flags = append(flags, flag.name)
+		}
+	}
+	return flags
+}
+
+// outputFormat resolves the renderer name from -format and -export.
+func (o options) outputFormat() string {
+	if o.exportMode && (o.format == "markdown" || o.format == "csv" || o.format == "tsv") {
+		// These formats have a flat form for -export, or are flat anyway
+		return o.format
+	}
+	if o.exportMode {
+		return "export"
+	}
+	if o.format == "" {
+		return "tree"
+	}
+	return o.format
+}
+
+// buildConfig returns the build configuration set by -tags, -GOOS and
+// -GOARCH.
+func (o options) buildConfig() deptree.BuildConfig {
+	return deptree.BuildConfig{
+		Tags:   strings.FieldsFunc(o.tags, func(r rune) bool { return r == ',' || r == ' ' }),
+		GOOS:   o.goos,
+		GOARCH: o.goarch,
+	}
+}
+
+// streamsTree reports whether the tree can be printed with StreamTree, which
+// needs no expanded tree: the DFS tree format and no step that rewrites or
+// saves the tree.
+func (o options) streamsTree() bool {
+	return o.outputFormat() == "tree" && o.walk != deptree.WalkBFS && !o.interactive &&
+		!o.selected && !o.directOnly && o.why == "" && o.saveFile == ""
+}
+
+// walksTree reports whether the output format prints the tree node by node,
+// so that -depth and -walk apply.
+func (o options) walksTree() bool {
+	format := o.outputFormat()
+	return format == "tree" || format == "ndjson"
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    options
+		wantErr bool
+	}{
+		{"defaults", options{packagePath: ".", format: "tree"}, false},
+		{"depth with tree", options{format: "tree", depth: 2}, false},
+		{"export flag with default format", options{format: "tree", exportMode: true}, false},
+		{"negative depth", options{depth: -1}, true},
+		{"path and package", options{packagePath: "/src/app", packageName: "github.com/spf13/cobra"}, true},
+		{"package with default path", options{packagePath: ".", packageName: "github.com/spf13/cobra"}, false},
+		{"export with json", options{format: "json", exportMode: true}, true},
+		{"depth with export", options{exportMode: true, depth: 1}, true},
+		{"depth with dot", options{format: "dot", depth: 1}, true},
+		{"pkgsite metadata", options{format: "tree", fetchDesc: true, metadataSource: "pkgsite"}, false},
+		{"trim-prefix with export", options{format: "tree", exportMode: true, trimPrefix: "github.com/"}, false},
+		{"trim-prefix with markdown", options{format: "markdown", trimPrefix: "auto"}, false},
+		{"trim-prefix with json", options{format: "json", trimPrefix: "auto"}, true},
+		{"trim-prefix with cyclonedx", options{format: "cyclonedx", trimPrefix: "github.com/"}, true},
+		{"trim-prefix with csv export", options{format: "csv", exportMode: true, trimPrefix: "auto"}, true},
+		{"trim-prefix with interactive", options{format: "tree", interactive: true, trimPrefix: "auto"}, true},
+		{"unknown metadata source", options{format: "tree", metadataSource: "github"}, true},
+		{"depth with markdown", options{format: "markdown", depth: 1}, false},
+		{"export with markdown", options{format: "markdown", exportMode: true}, false},
+		{"export with csv", options{format: "csv", exportMode: true}, false},
+		{"depth with tsv", options{format: "tsv", depth: 2}, true},
+		{"depth with markdown export", options{format: "markdown", exportMode: true, depth: 1}, true},
+		{"desc-exec without desc", options{format: "tree", descExec: "cat"}, true},
+		{"desc-exec with desc", options{format: "tree", descExec: "cat", fetchDesc: true}, false},
+		{"why alone", options{format: "tree", why: "golang.org/x/text"}, false},
+		{"interactive with json", options{format: "json", interactive: true}, true},
+		{"interactive with output file", options{format: "tree", interactive: true, outputFile: "deps.txt"}, true},
+		{"output file", options{format: "dot", outputFile: "deps.dot"}, false},
+		{"walk bfs with ndjson", options{format: "ndjson", walk: "bfs", depth: 2}, false},
+		{"walk bfs with json", options{format: "json", walk: "bfs"}, true},
+		{"sort depth", options{format: "tree", sortOrder: "depth"}, false},
+		{"sort name with json", options{format: "json", sortOrder: "name"}, false},
+		{"sort children with json", options{format: "json", sortOrder: "children"}, true},
+		{"sort depth with why", options{format: "tree", sortOrder: "depth", why: "golang.org/x/text"}, true},
+		{"expand-all with markdown", options{format: "markdown", expandAll: true}, false},
+		{"expand-all with markdown export", options{format: "markdown", exportMode: true, expandAll: true}, true},
+		{"sort with walk bfs", options{format: "tree", walk: "bfs", sortOrder: "version"}, true},
+		{"unknown sort", options{format: "tree", sortOrder: "random"}, true},
+		{"sort size without size", options{format: "tree", sortOrder: "size"}, true},
+		{"sort size with size", options{format: "tree", sortOrder: "size", size: true}, false},
+		{"expand-all with tree", options{format: "tree", expandAll: true, depth: 3}, false},
+		{"expand-all with json", options{format: "json", expandAll: true}, true},
+		{"expand-all with export", options{format: "tree", exportMode: true, expandAll: true}, true},
+		{"expand-all with walk bfs", options{format: "tree", walk: "bfs", expandAll: true}, true},
+		{"unknown walk", options{format: "tree", walk: "up"}, true},
+		{"severity with vuln", options{format: "tree", vuln: true, severity: "High"}, false},
+		{"severity without vuln", options{format: "tree", severity: "high"}, true},
+		{"unknown severity", options{format: "tree", vuln: true, severity: "severe"}, true},
+		{"load with default path", options{packagePath: ".", format: "tree", loadFile: "deps.snapshot"}, false},
+		{"load with package", options{packagePath: ".", packageName: "github.com/spf13/cobra", loadFile: "deps.snapshot"}, true},
+		{"load with selected", options{format: "tree", loadFile: "deps.snapshot", selected: true}, true},
+		{"why with direct-only", options{format: "tree", why: "golang.org/x/text", directOnly: true}, true},
+		{"color always", options{format: "tree", color: "always"}, false},
+		{"unknown color", options{format: "tree", color: "yes"}, true},
+		{"version with package", options{format: "tree", packageName: "github.com/spf13/cobra", version: "v1.8.0"}, false},
+		{"version ask with package", options{format: "tree", packageName: "github.com/spf13/cobra", version: "ask"}, false},
+		{"version without package", options{format: "tree", version: "latest"}, true},
+		{"stats alone", options{format: "tree", stats: true}, false},
+		{"stats with selected", options{format: "tree", stats: true, selected: true}, false},
+		{"duplicates alone", options{format: "tree", duplicates: true}, false},
+		{"replaces alone", options{format: "tree", replaces: true}, false},
+		{"unused alone", options{format: "tree", unused: true}, false},
+		{"unused with tags", options{format: "tree", unused: true, tags: "integration"}, false},
+		{"unused with package", options{format: "tree", unused: true, packageName: "github.com/spf13/cobra"}, true},
+		{"unused with load", options{format: "tree", unused: true, loadFile: "deps.json"}, true},
+		{"check-sums alone", options{format: "tree", checkSums: true}, false},
+		{"check-sums with selected", options{format: "tree", checkSums: true, selected: true}, true},
+		{"check-sums with proxy engine", options{format: "tree", checkSums: true, engine: engineProxy}, true},
+		{"verify alone", options{format: "tree", verify: true}, false},
+		{"verify with direct-only", options{format: "tree", verify: true, directOnly: true}, true},
+		{"hosting alone", options{format: "tree", hosting: true}, false},
+		{"hosting with selected", options{format: "tree", hosting: true, selected: true}, false},
+		{"risk alone", options{format: "tree", risk: true, staleYears: 2}, false},
+		{"risk with zero stale years", options{format: "tree", risk: true}, true},
+		{"obligations alone", options{format: "tree", obligations: true}, false},
+		{"obligations with license", options{format: "tree", obligations: true, license: true}, false},
+		{"policy alone", options{format: "tree", policyFile: "policy.yaml"}, false},
+		{"policy with json", options{format: "json", policyFile: "policy.yaml"}, false},
+		{"max-owners with interactive", options{format: "tree", maxOwners: 5, interactive: true}, true},
+		{"policy with interactive", options{format: "tree", policyFile: "policy.yaml", interactive: true}, true},
+		{"fail-on vuln and outdated", options{format: "tree", failOn: "vuln,outdated"}, false},
+		{"fail-on unknown condition", options{format: "tree", failOn: "vuln,license"}, true},
+		{"fail-on new-dep with baseline", options{format: "tree", failOn: "new-dep", baseline: "main"}, false},
+		{"fail-on new-dep without baseline", options{format: "tree", failOn: "new-dep"}, true},
+		{"baseline without fail-on new-dep", options{format: "tree", baseline: "main"}, true},
+		{"severity with fail-on vuln", options{format: "tree", failOn: "vuln", severity: "high"}, false},
+		{"quiet alone", options{format: "tree", violationsOnly: true}, false},
+		{"quiet with interactive", options{format: "tree", violationsOnly: true, interactive: true}, true},
+		{"recursive alone", options{packagePath: ".", format: "tree", recursive: true}, false},
+		{"recursive with module", options{packagePath: ".", format: "tree", recursive: true, module: "example.com/a"}, false},
+		{"recursive with package", options{packagePath: ".", format: "tree", recursive: true, packageName: "github.com/spf13/cobra"}, true},
+		{"recursive with GOOS", options{packagePath: ".", format: "tree", recursive: true, goos: "windows"}, true},
+		{"maintenance with export", options{format: "tree", exportMode: true, maintenance: true, staleYears: 2}, false},
+		{"maintenance with zero stale-years", options{format: "tree", maintenance: true, staleYears: 0}, true},
+		{"scorecard with export", options{format: "tree", exportMode: true, scorecard: true}, false},
+		{"min-scorecard", options{format: "json", minScorecard: 7}, false},
+		{"min-scorecard above 10", options{format: "tree", minScorecard: 11}, true},
+		{"negative min-scorecard", options{format: "tree", minScorecard: -1}, true},
+		{"size with export", options{format: "tree", exportMode: true, size: true}, false},
+		{"size with json", options{format: "json", size: true}, false},
+		{"size with dot", options{format: "dot", size: true}, true},
+		{"GOOS alone", options{format: "tree", goos: "windows"}, false},
+		{"tags with proxy engine", options{format: "tree", tags: "integration", engine: engineProxy, packageName: "example.com/m"}, true},
+		{"GOARCH with load", options{format: "tree", goarch: "arm64", loadFile: "deps.snapshot"}, true},
+		{"vuln-db with vuln", options{format: "tree", vuln: true, vulnDB: "github"}, false},
+		{"vuln-db without vuln", options{format: "tree", vulnDB: "osv-mirror.json"}, true},
+		{"page-size with tree", options{format: "tree", pageSize: 40}, false},
+		{"page-size with json", options{format: "json", pageSize: 40}, true},
+		{"page-size disabled with json", options{format: "json", pageSize: -1}, false},
+		{"max-nodes disabled", options{format: "tree", maxNodes: 0}, false},
+		{"negative max-nodes", options{format: "tree", maxNodes: -1}, true},
+		{"sample with tree", options{format: "tree", sample: 5, seed: 42}, false},
+		{"sample with json", options{format: "json", sample: 5}, true},
+		{"sample with why", options{format: "tree", sample: 5, why: "golang.org/x/text"}, true},
+		{"seed without sample", options{format: "tree", seed: 42}, true},
+		{"tests alone", options{format: "tree", tests: true}, false},
+		{"tests with no-tests", options{format: "tree", tests: true, noTests: true}, true},
+		{"no-tests with load", options{format: "tree", noTests: true, loadFile: "deps.snapshot"}, true},
+		{"chain with why", options{format: "tree", why: "golang.org/x/text", chain: true}, false},
+		{"chain without why", options{format: "tree", chain: true}, true},
+		{"lang de", options{format: "html", lang: "de"}, false},
+		{"unknown lang", options{format: "html", lang: "xx"}, true},
+		{"module alone", options{packagePath: ".", format: "tree", module: "example.com/a"}, false},
+		{"module with package", options{packagePath: ".", format: "tree", module: "example.com/a", packageName: "github.com/spf13/cobra"}, true},
+		{"module with load", options{packagePath: ".", format: "tree", module: "example.com/a", loadFile: "deps.snapshot"}, true},
+		{"version with versioned package", options{format: "tree", packageName: "github.com/spf13/cobra@v1.7.0", version: "v1.8.0"}, true},
+		{"several packages", options{format: "tree", packageName: "github.com/spf13/cobra,github.com/urfave/cli/v2"}, false},
+		{"version with several packages", options{format: "tree", packageName: "github.com/spf13/cobra,github.com/urfave/cli/v2", version: "latest"}, true},
+		{"selected with several packages", options{format: "tree", packageName: "github.com/spf13/cobra,github.com/urfave/cli/v2", selected: true}, true},
+		{"tags with several packages", options{format: "tree", packageName: "github.com/spf13/cobra,github.com/urfave/cli/v2", tags: "integration"}, true},
+		{"package set with several packages", options{format: "tree", packageName: "github.com/spf13/cobra,github.com/urfave/cli/v2", packageSet: "intersection"}, false},
+		{"package set with one package", options{format: "tree", packageName: "github.com/spf13/cobra", packageSet: "union"}, true},
+		{"unknown package set", options{format: "tree", packageName: "github.com/spf13/cobra,github.com/urfave/cli/v2", packageSet: "difference"}, true},
+		{"proxy engine with package", options{packagePath: ".", packageName: "github.com/spf13/cobra", engine: "proxy"}, false},
+		{"proxy engine without package", options{packagePath: ".", engine: "proxy"}, true},
+		{"unknown engine", options{packagePath: ".", packageName: "github.com/spf13/cobra", engine: "gopath"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReportModes(t *testing.T) {
+	type flag struct {
+		name string
+		set  func(o *options)
+	}
+	modes := []flag{
+		{"why", func(o *options) { o.why = "golang.org/x/text" }},
+		{"stats", func(o *options) { o.stats = true }},
+		{"duplicates", func(o *options) { o.duplicates = true }},
+		{"replaces", func(o *options) { o.replaces = true }},
+		{"unused", func(o *options) { o.unused = true }},
+		{"check-sums", func(o *options) { o.checkSums = true }},
+		{"verify", func(o *options) { o.verify = true }},
+		{"hosting", func(o *options) { o.hosting = true }},
+		{"risk", func(o *options) { o.risk = true }},
+		{"obligations", func(o *options) { o.obligations = true }},
+	}
+	graphFlags := []flag{
+		{"json", func(o *options) { o.format = "json" }},
+		{"export", func(o *options) { o.exportMode = true }},
+		{"depth", func(o *options) { o.depth = 2 }},
+		{"interactive", func(o *options) { o.interactive = true }},
+		{"desc", func(o *options) { o.fetchDesc = true }},
+		{"license", func(o *options) { o.license = true }},
+		{"vuln", func(o *options) { o.vuln = true }},
+		{"outdated", func(o *options) { o.outdated = true }},
+		{"size", func(o *options) { o.size = true }},
+		{"maintenance", func(o *options) { o.maintenance = true }},
+		{"scorecard", func(o *options) { o.scorecard = true }},
+		{"min-scorecard", func(o *options) { o.minScorecard = 5 }},
+		{"policy", func(o *options) { o.policyFile = "policy.yaml" }},
+		{"max-owners", func(o *options) { o.maxOwners = 5 }},
+		{"fail-on", func(o *options) { o.failOn = "vuln" }},
+		{"quiet", func(o *options) { o.violationsOnly = true }},
+		{"save", func(o *options) { o.saveFile = "deps.snapshot" }},
+		{"trim-prefix", func(o *options) { o.trimPrefix = "auto" }},
+	}
+
+	for i, mode := range modes {
+		// Every report mode conflicts with the other report modes and with
+		// every flag of the printed graph.
+		others := append(append([]flag{}, modes[i+1:]...), graphFlags...)
+		for _, other := range others {
+			if mode.name == "obligations" && other.name == "license" {
+				// -obligations detects the licenses it summarizes
+				continue
+			}
+			t.Run(mode.name+" with "+other.name, func(t *testing.T) {
+				opts := options{packagePath: ".", format: "tree", staleYears: 2}
+				mode.set(&opts)
+				other.set(&opts)
+				if err := opts.validate(); err == nil {
+					t.Errorf("validate() error = nil, want an error")
+				}
+			})
+		}
+	}
+}
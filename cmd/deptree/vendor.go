@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// runVendorCheck compares vendor/modules.txt with the modules the build of
+// the project needs, exiting with status 1 if they differ.
+func runVendorCheck(args []string) error {
+	fs := flag.NewFlagSet("vendor-check", flag.ContinueOnError)
+	projectPath := fs.String("path", ".", "Path of the module whose vendor directory to check")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deptree vendor-check [flags]")
+		fmt.Fprintln(fs.Output(), "Reports modules missing from vendor/modules.txt, vendored but not needed, or vendored at another version than the build selects.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("vendor-check takes no arguments, got %d", fs.NArg())
+	}
+
+	vendored, err := deptree.ReadVendorModules(*projectPath)
+	if err != nil {
+		return err
+	}
+	graph, err := deptree.Load(*projectPath, "")
+	if err != nil {
+		return err
+	}
+	needed, err := graph.VendorNeeds(*projectPath)
+	if err != nil {
+		return err
+	}
+
+	check := deptree.CheckVendor(vendored, needed)
+	if _, err := os.Stdout.Write(deptree.RenderVendorCheck(check)); err != nil {
+		return err
+	}
+	if !check.OK() {
+		return exitStatus(1)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/leinonen/deptree/pkg/deptree"
+)
+
+// versionAsk is the -version value that lists the available versions and
+// prompts for one.
+const versionAsk = "ask"
+
+// maxListedVersions bounds the versions shown by the -version ask prompt;
+// older ones can still be typed in.
+const maxListedVersions = 20
+
+// packageQuery returns -package with the version requested by -version
+// appended, prompting on in and out for -version ask.
+func packageQuery(opts options, client *http.Client, in io.Reader, out io.Writer) (string, error) {
+	switch opts.version {
+	case "":
+		return opts.packageName, nil
+	case versionAsk:
+		version, err := promptVersion(client, opts.packageName, in, out)
+		if err != nil {
+			return "", err
+		}
+		return opts.packageName + "@" + version, nil
+	}
+	return opts.packageName + "@" + opts.version, nil
+}
+
+// promptVersion lists the tagged versions of the module providing
+// packageName and reads the choice: a list number, a version or query, or
+// an empty line for the newest.
+func promptVersion(client *http.Client, packageName string, in io.Reader, out io.Writer) (string, error) {
+	module, versions, err := deptree.ListVersions(client, packageName)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(out, "Versions of %s:\n", module)
+	for i, version := range versions[:min(len(versions), maxListedVersions)] {
+		fmt.Fprintf(out, "%3d) %s\n", i+1, version)
+	}
+	if n := len(versions) - maxListedVersions; n > 0 {
+		fmt.Fprintf(out, "     (+%d older)\n", n)
+	}
+	fmt.Fprintf(out, "Version to analyze [%s]: ", versions[0])
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("no version chosen: %w", err)
+	}
+	choice := strings.TrimSpace(line)
+	if choice == "" {
+		return versions[0], nil
+	}
+	if i, err := strconv.Atoi(choice); err == nil {
+		if i < 1 || i > len(versions) {
+			return "", fmt.Errorf("no version numbered %d", i)
+		}
+		return versions[i-1], nil
+	}
+	return choice, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPackageQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/example.com/tool/@v/list" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("v1.0.0\nv1.1.0\nv1.2.0\n"))
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	tests := []struct {
+		name    string
+		version string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"no version", "", "", "example.com/tool/cmd", false},
+		{"explicit version", "v1.1.0", "", "example.com/tool/cmd@v1.1.0", false},
+		{"latest", "latest", "", "example.com/tool/cmd@latest", false},
+		{"ask default", versionAsk, "\n", "example.com/tool/cmd@v1.2.0", false},
+		{"ask by number", versionAsk, "3\n", "example.com/tool/cmd@v1.0.0", false},
+		{"ask by query", versionAsk, "main", "example.com/tool/cmd@main", false},
+		{"ask out of range", versionAsk, "4\n", "", true},
+		{"ask without input", versionAsk, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := options{packageName: "example.com/tool/cmd", version: tt.version}
+			got, err := packageQuery(opts, server.Client(), strings.NewReader(tt.input), &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("packageQuery error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("packageQuery = %q, want %q", got, tt.want)
+			}
+			if tt.version == versionAsk && !strings.Contains(out.String(), "  1) v1.2.0\n") {
+				t.Errorf("Expected the newest version listed first, got:\n%s", out.String())
+			}
+		})
+	}
+}
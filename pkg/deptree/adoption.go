@@ -0,0 +1,136 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// ModFileRevision is the go.mod of the main module as of one commit.
+type ModFileRevision struct {
+	Commit  string
+	Date    time.Time
+	ModFile *ModFile
+}
+
+// Adoption is the revision that added a module to the requirements of the
+// main module.
+type Adoption struct {
+	Path    string
+	Version string
+	Commit  string
+	Date    time.Time
+}
+
+// FindAdoptions returns when each module the last of revisions requires was
+// adopted, sorted by path. revisions must be ordered oldest first. A module
+// that was removed and added again counts from when it was added again,
+// at the version it was added at.
+func FindAdoptions(revisions []ModFileRevision) []Adoption {
+	adopted := make(map[string]Adoption)
+	for _, rev := range revisions {
+		required := make(map[string]bool)
+		for _, req := range rev.ModFile.Require {
+			required[req.Path] = true
+			if _, ok := adopted[req.Path]; !ok {
+				adopted[req.Path] = Adoption{Path: req.Path, Version: req.Version, Commit: rev.Commit, Date: rev.Date}
+			}
+		}
+		for path := range adopted {
+			if !required[path] {
+				delete(adopted, path)
+			}
+		}
+	}
+
+	adoptions := make([]Adoption, 0, len(adopted))
+	for _, path := range slices.Sorted(maps.Keys(adopted)) {
+		adoptions = append(adoptions, adopted[path])
+	}
+	return adoptions
+}
+
+// VulnerableAdoption is a module adopted at a version affected by an
+// advisory that was already published at the time.
+type VulnerableAdoption struct {
+	Adoption
+	Advisory  string
+	Published time.Time
+	// Fixed is the first version fixing the adopted one, if there is one
+	// now; it may not have existed at adoption time.
+	Fixed string
+}
+
+// CheckAdoptions asks db for the advisories affecting the version each
+// module was adopted at and returns those published before the adoption,
+// ordered by path and advisory. Advisories without a publication date are
+// skipped, since they cannot be placed in time.
+func CheckAdoptions(adoptions []Adoption, db VulnDB) ([]VulnerableAdoption, error) {
+	modules := make([]string, len(adoptions))
+	for i, a := range adoptions {
+		modules[i] = a.Path + "@" + a.Version
+	}
+	found, err := db.Query(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make(map[string]bool)
+	for _, ids := range found {
+		for _, id := range ids {
+			unique[id] = true
+		}
+	}
+	advisories := fetchAdvisories(db, slices.Sorted(maps.Keys(unique)))
+
+	var vulnerable []VulnerableAdoption
+	for i, a := range adoptions {
+		ids := found[modules[i]]
+		slices.Sort(ids)
+		for _, id := range ids {
+			adv := advisories[id]
+			if adv == nil || adv.vuln.Published.IsZero() || !adv.vuln.Published.Before(a.Date) {
+				continue
+			}
+			vulnerable = append(vulnerable, VulnerableAdoption{
+				Adoption:  a,
+				Advisory:  id,
+				Published: adv.vuln.Published,
+				Fixed:     adv.vuln.fixedVersion(a.Path, a.Version),
+			})
+		}
+	}
+	return vulnerable, nil
+}
+
+// RenderAdoptions lists the modules adopted while a published advisory
+// affected the adopted version, followed by how many of the checked
+// adoptions that applies to.
+func RenderAdoptions(vulnerable []VulnerableAdoption, checked int) []byte {
+	var buf bytes.Buffer
+	modules := make(map[string]bool)
+	for _, v := range vulnerable {
+		modules[v.Path] = true
+		line := fmt.Sprintf("%s@%s adopted %s in %s; %s published %s",
+			v.Path, v.Version, v.Date.Format(time.DateOnly), shortCommit(v.Commit), v.Advisory, v.Published.Format(time.DateOnly))
+		if v.Fixed != "" {
+			line += ", fixed in " + v.Fixed
+		}
+		fmt.Fprintln(&buf, line)
+	}
+	if len(vulnerable) > 0 {
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(&buf, "%d of %d module(s) adopted with a known vulnerability\n", len(modules), checked)
+	return buf.Bytes()
+}
+
+// shortCommit abbreviates a commit hash like git's default.
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
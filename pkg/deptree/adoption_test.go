@@ -0,0 +1,87 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindAdoptions(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	mod := func(requires ...string) *ModFile {
+		mf := &ModFile{}
+		for _, r := range requires {
+			path, version := SplitModule(r)
+			mf.Require = append(mf.Require, ModRequire{Path: path, Version: version})
+		}
+		return mf
+	}
+
+	adoptions := FindAdoptions([]ModFileRevision{
+		{Commit: "a1", Date: day(1), ModFile: mod("example.com/kept@v1.0.0", "example.com/readded@v1.0.0")},
+		{Commit: "b2", Date: day(2), ModFile: mod("example.com/kept@v1.1.0", "example.com/dropped@v1.0.0")},
+		{Commit: "c3", Date: day(3), ModFile: mod("example.com/kept@v1.2.0", "example.com/readded@v1.3.0")},
+	})
+
+	want := []Adoption{
+		{Path: "example.com/kept", Version: "v1.0.0", Commit: "a1", Date: day(1)},
+		{Path: "example.com/readded", Version: "v1.3.0", Commit: "c3", Date: day(3)},
+	}
+	if len(adoptions) != len(want) {
+		t.Fatalf("FindAdoptions = %+v, want %+v", adoptions, want)
+	}
+	for i := range want {
+		if adoptions[i] != want[i] {
+			t.Errorf("adoption %d = %+v, want %+v", i, adoptions[i], want[i])
+		}
+	}
+}
+
+func TestCheckAdoptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vulns.json")
+	bundle := `[
+		{
+			"id": "GO-2021-0113",
+			"published": "2021-10-06T17:51:21Z",
+			"affected": [{
+				"package": {"name": "golang.org/x/text", "ecosystem": "Go"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.7"}]}]
+			}]
+		},
+		{
+			"id": "GO-2099-0001",
+			"published": "2099-01-01T00:00:00Z",
+			"affected": [{
+				"package": {"name": "example.com/lib", "ecosystem": "Go"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+			}]
+		}
+	]`
+	if err := os.WriteFile(path, []byte(bundle), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := LoadOfflineVulnDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adoptions := []Adoption{
+		{Path: "example.com/lib", Version: "v1.0.0", Commit: "0123456789abcdef", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "golang.org/x/text", Version: "v0.3.5", Commit: "fedcba9876543210", Date: time.Date(2022, 5, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	vulnerable, err := CheckAdoptions(adoptions, db)
+	if err != nil {
+		t.Fatalf("CheckAdoptions failed: %v", err)
+	}
+	if len(vulnerable) != 1 || vulnerable[0].Path != "golang.org/x/text" || vulnerable[0].Fixed != "v0.3.7" {
+		t.Fatalf("Expected only golang.org/x/text adopted while vulnerable, got %+v", vulnerable)
+	}
+
+	out := string(RenderAdoptions(vulnerable, len(adoptions)))
+	expected := "golang.org/x/text@v0.3.5 adopted 2022-05-02 in fedcba9; GO-2021-0113 published 2021-10-06, fixed in v0.3.7\n\n1 of 2 module(s) adopted with a known vulnerability\n"
+	if !strings.Contains(out, expected) {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+}
@@ -0,0 +1,72 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Anonymizer replaces module paths with placeholders such as
+// "example.com/m3", keeping versions, so a graph can be shared without
+// revealing which modules it contains. A path gets the same placeholder
+// every time, numbered in order of first appearance. Toolchain entries
+// are kept as they are.
+type Anonymizer struct {
+	paths map[string]string
+}
+
+// NewAnonymizer returns an Anonymizer that has seen no paths yet.
+func NewAnonymizer() *Anonymizer {
+	return &Anonymizer{paths: make(map[string]string)}
+}
+
+// Module returns the placeholder of a module such as "example.com/m@v1.2.3",
+// with the version kept.
+func (a *Anonymizer) Module(name string) string {
+	if IsToolchainDep(name) {
+		return name
+	}
+	path, version, hasVersion := strings.Cut(name, "@")
+	placeholder, ok := a.paths[path]
+	if !ok {
+		placeholder = fmt.Sprintf("example.com/m%d", len(a.paths)+1)
+		a.paths[path] = placeholder
+	}
+	if hasVersion {
+		return placeholder + "@" + version
+	}
+	return placeholder
+}
+
+// Graph anonymizes the output of 'go mod graph', line by line.
+func (a *Anonymizer) Graph(output []byte) []byte {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			fields[i] = a.Module(field)
+		}
+		fmt.Fprintln(&buf, strings.Join(fields, " "))
+	}
+	return buf.Bytes()
+}
+
+// Text replaces the paths the Anonymizer has seen wherever they occur in
+// text, such as in log output. Longer paths are replaced first, so a path
+// is not partly replaced through a prefix of it.
+func (a *Anonymizer) Text(text []byte) []byte {
+	paths := make([]string, 0, len(a.paths))
+	for path := range a.paths {
+		paths = append(paths, path)
+	}
+	slices.SortFunc(paths, func(x, y string) int { return len(y) - len(x) })
+
+	pairs := make([]string, 0, 2*len(paths))
+	for _, path := range paths {
+		pairs = append(pairs, path, a.paths[path])
+	}
+	return []byte(strings.NewReplacer(pairs...).Replace(string(text)))
+}
@@ -0,0 +1,27 @@
+package deptree
+
+import (
+	"testing"
+)
+
+func TestAnonymizer(t *testing.T) {
+	a := NewAnonymizer()
+	graph := "corp.example/app github.com/corp/secret@v1.2.0\n" +
+		"corp.example/app go@1.21\n" +
+		"github.com/corp/secret@v1.2.0 github.com/corp/secret/v2@v2.0.0\n" +
+		"github.com/corp/secret@v1.2.0 toolchain@go1.22.1\n"
+
+	got := string(a.Graph([]byte(graph)))
+	want := "example.com/m1 example.com/m2@v1.2.0\n" +
+		"example.com/m1 go@1.21\n" +
+		"example.com/m2@v1.2.0 example.com/m3@v2.0.0\n" +
+		"example.com/m2@v1.2.0 toolchain@go1.22.1\n"
+	if got != want {
+		t.Errorf("Graph:\ngot  %q\nwant %q", got, want)
+	}
+
+	log := "Warning: github.com/corp/secret/v2@v2.0.0 requires go 1.23 (via github.com/corp/secret)\n"
+	if got, want := string(a.Text([]byte(log))), "Warning: example.com/m3@v2.0.0 requires go 1.23 (via example.com/m2)\n"; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+}
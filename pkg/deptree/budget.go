@@ -0,0 +1,85 @@
+package deptree
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// ErrBudgetExceeded is returned for requests made through a client from
+// WithBudget once its time budget has run out.
+var ErrBudgetExceeded = errors.New("time budget exceeded")
+
+// WithBudget returns a copy of client whose requests all share a deadline d
+// from now. Requests in flight at the deadline are cancelled and later ones
+// fail immediately, both with ErrBudgetExceeded, so metadata fetching ends
+// with partial results instead of running on.
+func WithBudget(client *http.Client, d time.Duration) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	budgeted := *client
+	budgeted.Transport = &budgetTransport{base: base, ctx: ctx, cancel: cancel}
+	return &budgeted
+}
+
+type budgetTransport struct {
+	base   http.RoundTripper
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.ctx.Err() != nil {
+		t.cancel()
+		return nil, ErrBudgetExceeded
+	}
+
+	// Cancel the request at whichever comes first, its own deadline or the
+	// budget's
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		stop()
+		cancel()
+		if t.ctx.Err() != nil {
+			return nil, ErrBudgetExceeded
+		}
+		return nil, err
+	}
+
+	resp.Body = &budgetBody{ReadCloser: resp.Body, release: func() {
+		stop()
+		cancel()
+	}}
+	return resp, nil
+}
+
+// budgetBody releases the per-request context once the body is closed.
+type budgetBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *budgetBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// markPartial records that metadata for module is incomplete because the
+// time budget ran out. Callers hold their own lock.
+func (g *Graph) markPartial(module string) {
+	if !slices.Contains(g.Partial, module) {
+		g.Partial = append(g.Partial, module)
+		slices.Sort(g.Partial)
+	}
+}
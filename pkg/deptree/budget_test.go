@@ -0,0 +1,57 @@
+package deptree
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "slow") {
+			// Hang until the client gives up
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"description":"quick"}`))
+	}))
+	defer server.Close()
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldURL }()
+
+	g := &Graph{
+		Root: NewNode("github.com/fast/repo@v1.0.0"),
+		Deps: map[string][]string{
+			"github.com/fast/repo@v1.0.0": {"github.com/slow/repo@v1.0.0"},
+		},
+	}
+
+	start := time.Now()
+	FetchDescriptions(g, Options{HTTPClient: WithBudget(server.Client(), 100*time.Millisecond)}, "", nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected fetching to stop at the budget, took %s", elapsed)
+	}
+
+	if g.Descriptions["github.com/fast/repo@v1.0.0"] != "quick" {
+		t.Errorf("Expected fast module to be described, got %q", g.Descriptions["github.com/fast/repo@v1.0.0"])
+	}
+	if got := g.Descriptions["github.com/slow/repo@v1.0.0"]; got != "(time budget exceeded)" {
+		t.Errorf("Expected slow module to be marked, got %q", got)
+	}
+	if !reflect.DeepEqual(g.Partial, []string{"github.com/slow/repo@v1.0.0"}) {
+		t.Errorf("Unexpected partial modules: %v", g.Partial)
+	}
+
+	// Once the budget is spent, requests fail without reaching the server
+	client := WithBudget(server.Client(), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+	}
+}
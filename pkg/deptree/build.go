@@ -0,0 +1,247 @@
+package deptree
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// BuildConfig is a build configuration to restrict a graph to with
+// ApplyBuildConfig. Empty fields default to the go command's own defaults.
+type BuildConfig struct {
+	Tags   []string
+	GOOS   string
+	GOARCH string
+}
+
+// IsZero reports whether c leaves every setting at its default.
+func (c BuildConfig) IsZero() bool {
+	return len(c.Tags) == 0 && c.GOOS == "" && c.GOARCH == ""
+}
+
+// Resolved returns c with an empty GOOS and GOARCH replaced by the ones the
+// go command builds for by default.
+func (c BuildConfig) Resolved() BuildConfig {
+	if c.GOOS != "" && c.GOARCH != "" {
+		return c
+	}
+	settings := goSettings("GOOS", "GOARCH")
+	if c.GOOS == "" {
+		c.GOOS = settings["GOOS"]
+	}
+	if c.GOARCH == "" {
+		c.GOARCH = settings["GOARCH"]
+	}
+	return c
+}
+
+// String describes c as GOOS/GOARCH and its build tags, e.g.
+// "linux/amd64 with tags integration". Empty fields are shown as "default".
+func (c BuildConfig) String() string {
+	goos, goarch := cmp.Or(c.GOOS, "default"), cmp.Or(c.GOARCH, "default")
+	if len(c.Tags) == 0 {
+		return goos + "/" + goarch + " without build tags"
+	}
+	return goos + "/" + goarch + " with tags " + strings.Join(c.Tags, ",")
+}
+
+// ApplyBuildConfig replaces the edges of g with those between modules whose
+// packages import each other when the main module's packages are built with
+// cfg, as reported by 'go list -deps' in dir. Modules only needed by other
+// platforms, build tags or tests drop out of the graph, so two
+// configurations can be compared. 'go list' selects a single version of
+// each module, and standard library packages belong to no module.
+func (g *Graph) ApplyBuildConfig(dir string, cfg BuildConfig) error {
+	if g.Root == nil {
+		return nil
+	}
+
+	output, err := g.listPackages(dir, cfg, false, g.mainPackages())
+	if err != nil {
+		return err
+	}
+
+	deps, err := parseBuildEdges(bytes.NewReader(output))
+	if err != nil {
+		return err
+	}
+	if g.Workspace != nil {
+		deps[WorkspaceRoot] = g.Deps[WorkspaceRoot]
+	}
+	g.Deps = deps
+	g.expandRoot(g.Root.Name)
+	g.syncDescriptions()
+	return nil
+}
+
+// MarkTestDependencies finds the modules that only the tests of the main
+// module's packages import when built with cfg, stores them in g.TestOnly
+// and tags their tree nodes with "[test]". Modules no package imports at
+// all are left unmarked.
+func (g *Graph) MarkTestDependencies(dir string, cfg BuildConfig) error {
+	if g.Root == nil {
+		return nil
+	}
+
+	modules := make(map[bool]map[string]bool)
+	for _, tests := range []bool{false, true} {
+		output, err := g.listPackages(dir, cfg, tests, g.mainPackages())
+		if err != nil {
+			return err
+		}
+		if modules[tests], err = parseListedModules(bytes.NewReader(output)); err != nil {
+			return err
+		}
+	}
+
+	g.TestOnly = make(map[string]bool)
+	for name := range modules[true] {
+		if !modules[false][name] {
+			g.TestOnly[name] = true
+		}
+	}
+
+	g.Walk(func(node *Node) {
+		if g.TestOnly[node.Name] {
+			node.Annotations = append(node.Annotations, testTag)
+		}
+	})
+	return nil
+}
+
+// testTag marks modules only tests depend on.
+const testTag = "[test]"
+
+// mainPackages returns the package patterns matching every package of the
+// main modules of g.
+func (g *Graph) mainPackages() []string {
+	if g.Workspace == nil {
+		path, _ := SplitModule(g.Root.Name)
+		return []string{path + "/..."}
+	}
+	var patterns []string
+	for _, member := range slices.Sorted(maps.Keys(g.Workspace)) {
+		patterns = append(patterns, member+"/...")
+	}
+	return patterns
+}
+
+// listPackages runs 'go list -deps -json' in dir on the packages matching
+// patterns, including their tests when tests is set. flags are passed on to
+// 'go list'.
+func (g *Graph) listPackages(dir string, cfg BuildConfig, tests bool, patterns []string, flags ...string) ([]byte, error) {
+	args := []string{"list", "-deps", "-json=ImportPath,Module,Imports,Standard"}
+	if tests {
+		args = append(args, "-test")
+	}
+	args = append(args, flags...)
+	if len(cfg.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(cfg.Tags, ","))
+	}
+	cmd := goCommand(dir, append(args, patterns...)...)
+	if cfg.GOOS != "" {
+		cmd.Env = append(cmd.Env, "GOOS="+cfg.GOOS)
+	}
+	if cfg.GOARCH != "" {
+		cmd.Env = append(cmd.Env, "GOARCH="+cfg.GOARCH)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'go list -deps': %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// listedPackage is the part of a 'go list -json' package deptree needs.
+type listedPackage struct {
+	ImportPath string
+	Module     *struct {
+		Path    string
+		Version string
+	}
+	Imports  []string
+	Standard bool
+}
+
+// module names the module of p like 'go mod graph' does: path@version, or
+// the bare path for main modules.
+func (p listedPackage) module() string {
+	if p.Module.Version == "" {
+		return p.Module.Path
+	}
+	return p.Module.Path + "@" + p.Module.Version
+}
+
+// decodeListedPackages reads the packages printed by 'go list -json',
+// leaving out standard library packages, which belong to no module.
+func decodeListedPackages(r io.Reader) ([]listedPackage, error) {
+	var packages []listedPackage
+	dec := json.NewDecoder(r)
+	for {
+		var pkg listedPackage
+		err := dec.Decode(&pkg)
+		if errors.Is(err, io.EOF) {
+			return packages, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'go list' output: %w", err)
+		}
+		if !pkg.Standard && pkg.Module != nil {
+			packages = append(packages, pkg)
+		}
+	}
+}
+
+// parseListedModules returns the modules of the packages printed by
+// 'go list -json'.
+func parseListedModules(r io.Reader) (map[string]bool, error) {
+	packages, err := decodeListedPackages(r)
+	if err != nil {
+		return nil, err
+	}
+	modules := make(map[string]bool)
+	for _, pkg := range packages {
+		modules[pkg.module()] = true
+	}
+	return modules, nil
+}
+
+// parseBuildEdges reads the packages printed by 'go list -deps -json' and
+// returns the edges between the modules of importing and imported packages,
+// keyed by importing module and sorted. Modules are named like in 'go mod
+// graph'.
+func parseBuildEdges(r io.Reader) (map[string][]string, error) {
+	packages, err := decodeListedPackages(r)
+	if err != nil {
+		return nil, err
+	}
+	modules := make(map[string]string)
+	for _, pkg := range packages {
+		modules[pkg.ImportPath] = pkg.module()
+	}
+
+	deps := make(map[string][]string)
+	for _, pkg := range packages {
+		from := modules[pkg.ImportPath]
+		for _, imported := range pkg.Imports {
+			to, ok := modules[imported]
+			if !ok || to == from || slices.Contains(deps[from], to) {
+				continue
+			}
+			deps[from] = append(deps[from], to)
+		}
+	}
+	for _, tos := range deps {
+		slices.Sort(tos)
+	}
+	return deps, nil
+}
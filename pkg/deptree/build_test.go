@@ -0,0 +1,90 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBuildEdges(t *testing.T) {
+	output := `{
+	"ImportPath": "fmt",
+	"Standard": true
+}
+{
+	"ImportPath": "golang.org/x/text/language",
+	"Module": {"Path": "golang.org/x/text", "Version": "v0.14.0"},
+	"Imports": ["errors", "golang.org/x/text/internal/tag"]
+}
+{
+	"ImportPath": "golang.org/x/text/internal/tag",
+	"Module": {"Path": "golang.org/x/text", "Version": "v0.14.0"},
+	"Imports": ["sort"]
+}
+{
+	"ImportPath": "example.com/app/internal/win",
+	"Module": {"Path": "example.com/app"},
+	"Imports": ["golang.org/x/sys/windows", "golang.org/x/text/language"]
+}
+{
+	"ImportPath": "golang.org/x/sys/windows",
+	"Module": {"Path": "golang.org/x/sys", "Version": "v0.20.0"}
+}
+{
+	"ImportPath": "example.com/app",
+	"Module": {"Path": "example.com/app"},
+	"Imports": ["example.com/app/internal/win", "fmt", "golang.org/x/text/language"]
+}
+`
+	got, err := parseBuildEdges(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"example.com/app": {"golang.org/x/sys@v0.20.0", "golang.org/x/text@v0.14.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBuildEdges = %v, want %v", got, want)
+	}
+
+	if _, err := parseBuildEdges(strings.NewReader("{")); err == nil {
+		t.Error("Expected an error for truncated output")
+	}
+}
+
+func TestParseListedModules(t *testing.T) {
+	output := `{"ImportPath": "errors", "Standard": true}
+{"ImportPath": "example.com/app", "Module": {"Path": "example.com/app"}}
+{"ImportPath": "example.com/app [example.com/app.test]", "Module": {"Path": "example.com/app"}}
+{"ImportPath": "github.com/stretchr/testify/assert", "Module": {"Path": "github.com/stretchr/testify", "Version": "v1.9.0"}}
+`
+	got, err := parseListedModules(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"example.com/app": true, "github.com/stretchr/testify@v1.9.0": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseListedModules = %v, want %v", got, want)
+	}
+}
+
+func TestBuildConfigString(t *testing.T) {
+	tests := []struct {
+		cfg  BuildConfig
+		want string
+	}{
+		{BuildConfig{}, "default/default without build tags"},
+		{BuildConfig{GOOS: "linux", GOARCH: "amd64"}, "linux/amd64 without build tags"},
+		{BuildConfig{Tags: []string{"integration", "e2e"}, GOOS: "windows"}, "windows/default with tags integration,e2e"},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+
+	cfg := BuildConfig{GOOS: "plan9"}.Resolved()
+	if cfg.GOOS != "plan9" || cfg.GOARCH == "" {
+		t.Errorf("Resolved() = %+v, want GOOS plan9 and the default GOARCH", cfg)
+	}
+}
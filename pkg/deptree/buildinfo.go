@@ -0,0 +1,48 @@
+package deptree
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// FromBuildInfo builds the graph of the modules linked into a Go binary, as
+// recorded in its build info. Binaries only record the module list, not the
+// requirements between modules, so the graph is flat: the main module
+// requires every linked module and the go version it was built with. The
+// replacements and go.sum hashes of the build info are kept, so edges show
+// replacements and SBOM formats carry digests.
+func FromBuildInfo(info *debug.BuildInfo) *Graph {
+	rootName := info.Main.Path
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		rootName += "@" + v
+	}
+
+	g := &Graph{
+		Deps:    map[string][]string{rootName: {}},
+		ModFile: &ModFile{},
+		Sums:    GoSum{},
+	}
+	if goVersion, ok := strings.CutPrefix(info.GoVersion, "go"); ok {
+		g.Deps[rootName] = append(g.Deps[rootName], "go@"+goVersion)
+	}
+	for _, dep := range info.Deps {
+		name := dep.Path + "@" + dep.Version
+		g.Deps[rootName] = append(g.Deps[rootName], name)
+		if dep.Sum != "" {
+			g.Sums[name] = dep.Sum
+		}
+		if dep.Replace != nil {
+			g.ModFile.Replace = append(g.ModFile.Replace, ModReplace{
+				Old: ModVersion{Path: dep.Path, Version: dep.Version},
+				New: ModVersion{Path: dep.Replace.Path, Version: dep.Replace.Version},
+			})
+			if dep.Replace.Sum != "" {
+				g.Sums[name] = dep.Replace.Sum
+			}
+		}
+	}
+	g.Root = NewNode(rootName)
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+
+	return g
+}
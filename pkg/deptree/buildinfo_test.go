@@ -0,0 +1,42 @@
+package deptree
+
+import (
+	"reflect"
+	"runtime/debug"
+	"testing"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	info := &debug.BuildInfo{
+		GoVersion: "go1.22.1",
+		Main:      debug.Module{Path: "example.com/tool", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0", Sum: "h1:cobra"},
+			{Path: "golang.org/x/text", Version: "v0.14.0", Sum: "h1:text", Replace: &debug.Module{Path: "../text", Sum: ""}},
+		},
+	}
+
+	g := FromBuildInfo(info)
+
+	if g.Root.Name != "example.com/tool" {
+		t.Errorf("Expected the (devel) main module without version, got %s", g.Root.Name)
+	}
+	want := []string{"go@1.22.1", "github.com/spf13/cobra@v1.8.0", "golang.org/x/text@v0.14.0"}
+	if !reflect.DeepEqual(g.Deps[g.Root.Name], want) {
+		t.Errorf("Deps = %v, want %v", g.Deps[g.Root.Name], want)
+	}
+	if len(g.Root.Children) != 3 {
+		t.Errorf("Expected a flat tree of 3 modules, got %d", len(g.Root.Children))
+	}
+	if g.Sums["github.com/spf13/cobra@v1.8.0"] != "h1:cobra" {
+		t.Errorf("Expected the go.sum hash of cobra, got %v", g.Sums)
+	}
+	if rep := g.classifyEdge(g.Root.Name, "golang.org/x/text@v0.14.0").Replace; rep != "../text" {
+		t.Errorf("Expected golang.org/x/text to be replaced by ../text, got %q", rep)
+	}
+
+	info.Main.Version = "v1.2.3"
+	if g := FromBuildInfo(info); g.Root.Name != "example.com/tool@v1.2.3" {
+		t.Errorf("Expected a released main module with its version, got %s", g.Root.Name)
+	}
+}
@@ -0,0 +1,318 @@
+package deptree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached descriptions are used before they are
+// fetched again.
+const DefaultCacheTTL = 24 * time.Hour
+
+// DescriptionCache persists fetched descriptions between runs, keyed by
+// module path. A nil *DescriptionCache caches nothing.
+type DescriptionCache struct {
+	path    string
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]descriptionCacheEntry
+	dirty   bool
+	// hits and misses count Get results since the cache was opened.
+	hits, misses int64
+}
+
+type descriptionCacheEntry struct {
+	// Description is empty when the repository has none set.
+	Description string    `json:"description"`
+	Fetched     time.Time `json:"fetched"`
+	SourceLinks
+}
+
+// DefaultDescriptionCachePath returns descriptions.json in the deptree
+// directory of the user's cache directory, e.g.
+// ~/.cache/deptree/descriptions.json on Linux.
+func DefaultDescriptionCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "deptree", "descriptions.json"), nil
+}
+
+// OpenDescriptionCache loads the cache stored at path, treating entries
+// older than ttl as missing. A missing or unreadable cache file starts an
+// empty cache, since the cache only saves work.
+func OpenDescriptionCache(path string, ttl time.Duration) (*DescriptionCache, error) {
+	c := &DescriptionCache{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]descriptionCacheEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read description cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]descriptionCacheEntry)
+	}
+
+	return c, nil
+}
+
+// Get returns the cached description of the module and whether a fresh
+// entry exists. The description is empty when the repository has none set.
+func (c *DescriptionCache) Get(module string) (string, bool) {
+	desc, _, ok := c.GetSource(module)
+	return desc, ok
+}
+
+// GetSource is Get, also returning the links to the module's source
+// cached with the description.
+func (c *DescriptionCache) GetSource(module string) (string, SourceLinks, bool) {
+	if c == nil {
+		return "", SourceLinks{}, false
+	}
+	path, _ := SplitModule(module)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[path]
+	if !ok || time.Since(entry.Fetched) > c.ttl {
+		c.misses++
+		return "", SourceLinks{}, false
+	}
+	c.hits++
+	return entry.Description, entry.SourceLinks, true
+}
+
+// Put records the description of the module.
+func (c *DescriptionCache) Put(module, description string) {
+	c.PutSource(module, description, SourceLinks{})
+}
+
+// PutSource records the description of the module and the links to its
+// source.
+func (c *DescriptionCache) PutSource(module, description string, links SourceLinks) {
+	if c == nil {
+		return
+	}
+	path, _ := SplitModule(module)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = descriptionCacheEntry{Description: description, Fetched: time.Now(), SourceLinks: links}
+	c.dirty = true
+}
+
+// Save writes the cache back to disk, dropping expired entries, and adds
+// the hits and misses since it was opened to the cache statistics. The
+// entries are only written if one was added or removed.
+func (c *DescriptionCache) Save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hits+c.misses > 0 {
+		if err := c.saveStats(); err != nil {
+			return err
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	for path, entry := range c.entries {
+		if time.Since(entry.Fetched) > c.ttl {
+			delete(c.entries, path)
+		}
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write through a temp file so concurrent runs never see a partial cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), "descriptions-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write description cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write description cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write description cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write description cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// CacheEntry is one cached description, as listed by Entries.
+type CacheEntry struct {
+	// Module is the module path the entry is keyed by.
+	Module      string
+	Description string
+	Fetched     time.Time
+	// Size is the number of bytes the entry takes in the cache file.
+	Size int
+	// Expired reports whether the entry is older than the cache's TTL.
+	Expired bool
+}
+
+// Entries returns the cached descriptions whose module path matches
+// pattern (see MatchModulePattern), sorted by module path. An empty pattern
+// matches every entry.
+func (c *DescriptionCache) Entries(pattern string) []CacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entries []CacheEntry
+	for _, path := range slices.Sorted(maps.Keys(c.entries)) {
+		if pattern != "" && !MatchModulePattern(pattern, path) {
+			continue
+		}
+		entry := c.entries[path]
+		data, _ := json.Marshal(map[string]descriptionCacheEntry{path: entry})
+		entries = append(entries, CacheEntry{
+			Module:      path,
+			Description: entry.Description,
+			Fetched:     entry.Fetched,
+			Size:        len(data),
+			Expired:     time.Since(entry.Fetched) > c.ttl,
+		})
+	}
+	return entries
+}
+
+// Remove deletes the entries whose module path matches pattern and returns
+// how many were removed. Call Save to write the change to disk.
+func (c *DescriptionCache) Remove(pattern string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for path := range c.entries {
+		if pattern == "" || MatchModulePattern(pattern, path) {
+			delete(c.entries, path)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.dirty = true
+	}
+	return removed
+}
+
+// MatchModulePattern reports whether the module path matches pattern, which
+// is either a path.Match glob such as "github.com/spf13/*" or a path ending
+// in "/..." that matches the path and everything below it, as in go
+// command patterns.
+func MatchModulePattern(pattern, modulePath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return modulePath == prefix || strings.HasPrefix(modulePath, prefix+"/")
+	}
+	matched, _ := path.Match(pattern, modulePath)
+	return matched
+}
+
+// CacheStats are the hit counts of a description cache accumulated over
+// every run that used it.
+type CacheStats struct {
+	Hits   int64     `json:"hits"`
+	Misses int64     `json:"misses"`
+	Since  time.Time `json:"since"`
+}
+
+// HitRate returns the share of lookups that were answered from the cache,
+// between 0 and 1.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// cacheStatsPath returns the file the statistics of the cache at
+// cachePath are kept in, e.g. descriptions-stats.json.
+func cacheStatsPath(cachePath string) string {
+	return strings.TrimSuffix(cachePath, filepath.Ext(cachePath)) + "-stats.json"
+}
+
+// ReadCacheStats returns the statistics of the cache at cachePath, which are
+// zero if none were recorded yet.
+func ReadCacheStats(cachePath string) (CacheStats, error) {
+	var stats CacheStats
+	data, err := os.ReadFile(cacheStatsPath(cachePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, fmt.Errorf("failed to read cache statistics: %w", err)
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return CacheStats{}, nil
+	}
+	return stats, nil
+}
+
+// ResetCacheStats deletes the statistics of the cache at cachePath.
+func ResetCacheStats(cachePath string) error {
+	if err := os.Remove(cacheStatsPath(cachePath)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to reset cache statistics: %w", err)
+	}
+	return nil
+}
+
+// saveStats adds the hits and misses counted by c to its statistics file.
+// c.mu must be held.
+func (c *DescriptionCache) saveStats() error {
+	stats, err := ReadCacheStats(c.path)
+	if err != nil {
+		return err
+	}
+	if stats.Since.IsZero() {
+		stats.Since = time.Now()
+	}
+	stats.Hits += c.hits
+	stats.Misses += c.misses
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(cacheStatsPath(c.path), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache statistics: %w", err)
+	}
+	c.hits, c.misses = 0, 0
+	return nil
+}
@@ -0,0 +1,181 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDescriptionCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deptree", "descriptions.json")
+
+	cache, err := OpenDescriptionCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDescriptionCache failed: %v", err)
+	}
+	if _, ok := cache.Get("github.com/spf13/cobra@v1.8.0"); ok {
+		t.Error("Expected empty cache")
+	}
+
+	cache.Put("github.com/spf13/cobra@v1.8.0", "A Commander for modern Go CLI interactions")
+	cache.Put("github.com/empty/repo@v1.0.0", "")
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reopened, err := OpenDescriptionCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDescriptionCache failed: %v", err)
+	}
+	// Entries are keyed by path, so other versions hit too
+	if desc, ok := reopened.Get("github.com/spf13/cobra@v1.10.1"); !ok || desc != "A Commander for modern Go CLI interactions" {
+		t.Errorf("Get() = %q, %v", desc, ok)
+	}
+	if desc, ok := reopened.Get("github.com/empty/repo@v1.0.0"); !ok || desc != "" {
+		t.Errorf("Expected cached empty description, got %q, %v", desc, ok)
+	}
+
+	expired, err := OpenDescriptionCache(path, 0)
+	if err != nil {
+		t.Fatalf("OpenDescriptionCache failed: %v", err)
+	}
+	if _, ok := expired.Get("github.com/spf13/cobra@v1.8.0"); ok {
+		t.Error("Expected expired entry to be ignored")
+	}
+
+	// A corrupt cache file starts an empty cache
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if corrupt, err := OpenDescriptionCache(path, time.Hour); err != nil || len(corrupt.entries) != 0 {
+		t.Errorf("Expected empty cache for corrupt file, got %v, %v", corrupt, err)
+	}
+
+	// A nil cache caches nothing
+	var none *DescriptionCache
+	none.Put("github.com/spf13/cobra@v1.8.0", "ignored")
+	if _, ok := none.Get("github.com/spf13/cobra@v1.8.0"); ok {
+		t.Error("Expected nil cache to miss")
+	}
+	if err := none.Save(); err != nil {
+		t.Errorf("Expected nil cache Save to succeed, got %v", err)
+	}
+}
+
+func TestFetchDescriptionsUsesCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"description": "fetched"}`))
+	}))
+	defer server.Close()
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldURL }()
+
+	cache, err := OpenDescriptionCache(filepath.Join(t.TempDir(), "descriptions.json"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Put("github.com/cached/repo@v1.0.0", "from cache")
+	cache.Put("github.com/empty/repo@v1.0.0", "")
+
+	g := &Graph{
+		Root: NewNode("github.com/cached/repo@v1.0.0"),
+		Deps: map[string][]string{
+			"github.com/cached/repo@v1.0.0": {"github.com/empty/repo@v1.0.0", "github.com/new/repo@v1.0.0"},
+		},
+	}
+	var calls, lastDone, lastTotal int
+	FetchDescriptionsProgress(g, Options{HTTPClient: server.Client()}, "", cache, func(done, total int) {
+		calls++
+		lastDone, lastTotal = done, total
+	})
+
+	if calls != 3 || lastDone != 3 || lastTotal != 3 {
+		t.Errorf("Expected progress for each of 3 modules ending at 3/3, got %d call(s) ending at %d/%d", calls, lastDone, lastTotal)
+	}
+	if requests != 1 {
+		t.Errorf("Expected only the uncached module to be fetched, got %d requests", requests)
+	}
+	if g.Descriptions["github.com/cached/repo@v1.0.0"] != "from cache" {
+		t.Errorf("Expected cached description, got %q", g.Descriptions["github.com/cached/repo@v1.0.0"])
+	}
+	if g.DescriptionErrors["github.com/empty/repo@v1.0.0"] == nil {
+		t.Error("Expected cached empty description to be reported as missing")
+	}
+	if desc, ok := cache.Get("github.com/new/repo@v1.0.0"); !ok || desc != "fetched" {
+		t.Errorf("Expected fetched description to be cached, got %q, %v", desc, ok)
+	}
+}
+
+func TestDescriptionCacheManagement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "descriptions.json")
+	cache, err := OpenDescriptionCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDescriptionCache failed: %v", err)
+	}
+	cache.Put("github.com/spf13/cobra@v1.8.0", "A Commander")
+	cache.Put("golang.org/x/text@v0.14.0", "")
+	cache.Put("golang.org/x/sys@v0.5.0", "")
+	cache.Get("github.com/spf13/cobra@v1.8.0")
+	cache.Get("github.com/missing/repo@v1.0.0")
+	cache.Get("golang.org/x/text@v0.14.0")
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	stats, err := ReadCacheStats(path)
+	if err != nil || stats.Hits != 2 || stats.Misses != 1 || stats.Since.IsZero() {
+		t.Errorf("ReadCacheStats = %+v, %v; want 2 hits, 1 miss", stats, err)
+	}
+
+	entries := cache.Entries("golang.org/x/...")
+	if len(entries) != 2 || entries[0].Module != "golang.org/x/sys" || entries[0].Size == 0 || entries[0].Expired {
+		t.Errorf("Entries(golang.org/x/...) = %+v", entries)
+	}
+
+	if n := cache.Remove("github.com/*/cobra"); n != 1 {
+		t.Errorf("Remove removed %d entries, want 1", n)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	reopened, err := OpenDescriptionCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDescriptionCache failed: %v", err)
+	}
+	if len(reopened.Entries("")) != 2 {
+		t.Errorf("Expected 2 entries after removal, got %+v", reopened.Entries(""))
+	}
+
+	if err := ResetCacheStats(path); err != nil {
+		t.Fatalf("ResetCacheStats failed: %v", err)
+	}
+	if stats, _ := ReadCacheStats(path); stats.Hits != 0 {
+		t.Errorf("Expected reset statistics, got %+v", stats)
+	}
+}
+
+func TestMatchModulePattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"golang.org/x/...", "golang.org/x/text", true},
+		{"golang.org/x/...", "golang.org/x", true},
+		{"golang.org/x/...", "golang.org/xy", false},
+		{"github.com/spf13/*", "github.com/spf13/cobra", true},
+		{"github.com/spf13/*", "github.com/spf13/cobra/v2", false},
+		{"github.com/spf13/cobra", "github.com/spf13/cobra", true},
+	}
+	for _, tt := range tests {
+		if got := MatchModulePattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchModulePattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package deptree
+
+// ANSI SGR sequences used when RenderOptions.Color is set.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiCyan    = "\x1b[36m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiYellow  = "\x1b[33m"
+)
+
+// painter styles module labels in human-readable renderers: the root in
+// bold, direct dependencies in cyan, toolchain entries dimmed, vulnerable
+// modules in red and outdated, archived or stale ones in yellow. It leaves labels untouched
+// when color is disabled.
+type painter struct {
+	enabled  bool
+	root     string
+	direct   map[string]bool
+	vulns    map[string][]string
+	outdated map[string]string
+	repos    map[string]RepoStatus
+}
+
+func newPainter(g *Graph, opts RenderOptions, direct map[string]bool) painter {
+	p := painter{enabled: opts.Color, direct: direct, vulns: g.Vulnerabilities, outdated: g.Outdated, repos: g.RepoStatus}
+	if g.Root != nil {
+		p.root = g.Root.Name
+	}
+	return p
+}
+
+// paint wraps label, the displayed form of module name, in the style that
+// applies to name. Problems take precedence over the module's role.
+func (p painter) paint(name, label string) string {
+	if !p.enabled {
+		return label
+	}
+
+	var style string
+	switch {
+	case len(p.vulns[name]) > 0:
+		style = ansiBoldRed
+	case p.outdated[name] != "", p.repos[name].Risky():
+		style = ansiYellow
+	case name == p.root:
+		style = ansiBold
+	case IsToolchainDep(name):
+		style = ansiDim
+	case p.direct[name]:
+		style = ansiCyan
+	default:
+		return label
+	}
+	return style + label + ansiReset
+}
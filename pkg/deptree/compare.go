@@ -0,0 +1,159 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// PackageProfile is what ComparePackages measures of one package's graph.
+type PackageProfile struct {
+	// Module is the root module of the graph.
+	Module string
+	// Modules maps the paths of the modules the root depends on to the
+	// version minimal version selection picks.
+	Modules map[string]string
+	// Direct counts the modules the root's go.mod requires directly.
+	Direct int
+	// MaxDepth is the length of the longest shortest requirement chain from
+	// the root to any module.
+	MaxDepth int
+	// Size is the total size in the module cache of the Measured modules,
+	// when the graph has Sizes (see DetectSizes).
+	Size     int64
+	Measured int
+	Sized    bool
+}
+
+// Comparison is the result of ComparePackages.
+type Comparison struct {
+	A, B PackageProfile
+	// Shared are the module paths both packages depend on, and OnlyA and
+	// OnlyB those only one of them does, sorted.
+	Shared, OnlyA, OnlyB []string
+}
+
+// ComparePackages compares the dependency sets of two separately loaded
+// packages, such as candidate libraries, by module path: a module both
+// depend on at different versions is shared.
+func ComparePackages(a, b *Graph) Comparison {
+	c := Comparison{A: profilePackage(a), B: profilePackage(b)}
+	for _, path := range slices.Sorted(maps.Keys(c.A.Modules)) {
+		if _, ok := c.B.Modules[path]; ok {
+			c.Shared = append(c.Shared, path)
+		} else {
+			c.OnlyA = append(c.OnlyA, path)
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(c.B.Modules)) {
+		if _, ok := c.A.Modules[path]; !ok {
+			c.OnlyB = append(c.OnlyB, path)
+		}
+	}
+	return c
+}
+
+// profilePackage measures the graph of one package for ComparePackages.
+func profilePackage(g *Graph) PackageProfile {
+	p := PackageProfile{Modules: make(map[string]string)}
+	if g.Root == nil {
+		return p
+	}
+	p.Module = g.Root.Name
+	rootPath, _ := SplitModule(g.Root.Name)
+
+	direct := make(map[string]bool)
+	for name := range g.DirectDependencies() {
+		path, _ := SplitModule(name)
+		direct[path] = true
+	}
+
+	p.Sized = g.Sizes != nil
+	for path, version := range g.SelectVersions() {
+		if path == rootPath {
+			continue
+		}
+		p.Modules[path] = version
+		if direct[path] {
+			p.Direct++
+		}
+		if size, ok := g.Sizes[path+"@"+version]; ok {
+			p.Size += size
+			p.Measured++
+		}
+	}
+	p.MaxDepth = g.maxDepth()
+	return p
+}
+
+// RenderComparison formats a comparison as a side-by-side table of the two
+// packages, followed by the modules they share and those only one of them
+// brings, and which of them is lighter.
+func RenderComparison(c Comparison) []byte {
+	var buf bytes.Buffer
+
+	size := func(p PackageProfile) string {
+		switch {
+		case !p.Sized:
+			return "n/a"
+		case p.Measured < len(p.Modules):
+			return fmt.Sprintf("%s (%d of %d measured)", FormatSize(p.Size), p.Measured, len(p.Modules))
+		}
+		return FormatSize(p.Size)
+	}
+	rows := [][3]string{
+		{"", c.A.Module, c.B.Module},
+		{"Modules", fmt.Sprint(len(c.A.Modules)), fmt.Sprint(len(c.B.Modules))},
+		{"  direct", fmt.Sprint(c.A.Direct), fmt.Sprint(c.B.Direct)},
+		{"  unique", fmt.Sprint(len(c.OnlyA)), fmt.Sprint(len(c.OnlyB))},
+		{"  shared", fmt.Sprint(len(c.Shared)), fmt.Sprint(len(c.Shared))},
+		{"Max depth", fmt.Sprint(c.A.MaxDepth), fmt.Sprint(c.B.MaxDepth)},
+		{"Size", size(c.A), size(c.B)},
+	}
+	var widths [2]int
+	for _, row := range rows {
+		widths[0] = max(widths[0], len(row[0]))
+		widths[1] = max(widths[1], len(row[1]))
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "%-*s  %-*s  %s\n", widths[0], row[0], widths[1], row[1], row[2])
+	}
+
+	writeModuleVersions(&buf, fmt.Sprintf("Shared (%d)", len(c.Shared)), c.Shared, func(path string) string {
+		if a, b := c.A.Modules[path], c.B.Modules[path]; a != b {
+			return a + " vs " + b
+		}
+		return c.A.Modules[path]
+	})
+	writeModuleVersions(&buf, fmt.Sprintf("Only %s (%d)", c.A.Module, len(c.OnlyA)), c.OnlyA, func(path string) string { return c.A.Modules[path] })
+	writeModuleVersions(&buf, fmt.Sprintf("Only %s (%d)", c.B.Module, len(c.OnlyB)), c.OnlyB, func(path string) string { return c.B.Modules[path] })
+
+	buf.WriteByte('\n')
+	switch na, nb := len(c.A.Modules), len(c.B.Modules); {
+	case na < nb:
+		fmt.Fprintf(&buf, "%s brings %d fewer module(s)\n", c.A.Module, nb-na)
+	case nb < na:
+		fmt.Fprintf(&buf, "%s brings %d fewer module(s)\n", c.B.Module, na-nb)
+	default:
+		fmt.Fprintf(&buf, "Both bring %d module(s)\n", na)
+	}
+	return buf.Bytes()
+}
+
+// writeModuleVersions appends a titled list of module paths with the
+// version text version gives each, if there are any.
+func writeModuleVersions(buf *bytes.Buffer, title string, paths []string, version func(string) string) {
+	if len(paths) == 0 {
+		return
+	}
+	width := 0
+	for _, path := range paths {
+		width = max(width, len(path))
+	}
+	fmt.Fprintf(buf, "\n%s:\n", title)
+	for _, path := range paths {
+		fmt.Fprintf(buf, "  %s\n", strings.TrimRight(fmt.Sprintf("%-*s  %s", width, path, version(path)), " "))
+	}
+}
@@ -0,0 +1,42 @@
+package deptree
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestComparePackages(t *testing.T) {
+	cobra := &Graph{Deps: map[string][]string{
+		"github.com/spf13/cobra@v1.8.0": {"github.com/spf13/pflag@v1.0.5", "golang.org/x/sys@v0.18.0"},
+		"github.com/spf13/pflag@v1.0.5": {"golang.org/x/sys@v0.20.0"},
+	}}
+	cobra.expandRoot("github.com/spf13/cobra@v1.8.0")
+	cobra.Sizes = map[string]int64{"github.com/spf13/pflag@v1.0.5": 2048, "golang.org/x/sys@v0.20.0": 1024}
+	cli := &Graph{Deps: map[string][]string{
+		"github.com/urfave/cli/v2@v2.27.1": {"golang.org/x/sys@v0.19.0"},
+	}}
+	cli.expandRoot("github.com/urfave/cli/v2@v2.27.1")
+
+	c := ComparePackages(cobra, cli)
+	if !slices.Equal(c.Shared, []string{"golang.org/x/sys"}) || !slices.Equal(c.OnlyA, []string{"github.com/spf13/pflag"}) || len(c.OnlyB) != 0 {
+		t.Errorf("shared %v, only cobra %v, only cli %v; want golang.org/x/sys shared and github.com/spf13/pflag only in cobra", c.Shared, c.OnlyA, c.OnlyB)
+	}
+	if c.A.Modules["golang.org/x/sys"] != "v0.20.0" || c.A.MaxDepth != 2 || c.A.Size != 3072 || c.A.Measured != 2 {
+		t.Errorf("cobra profile = %+v, want x/sys selected at v0.20.0, depth 2 and 3 KiB measured", c.A)
+	}
+
+	out := string(RenderComparison(c))
+	for _, want := range []string{
+		"           github.com/spf13/cobra@v1.8.0  github.com/urfave/cli/v2@v2.27.1\n",
+		"Modules    2                              1\n",
+		"Size       3.0 KiB                        n/a\n",
+		"  golang.org/x/sys  v0.20.0 vs v0.19.0\n",
+		"Only github.com/spf13/cobra@v1.8.0 (1):\n  github.com/spf13/pflag  v1.0.5\n",
+		"\ngithub.com/urfave/cli/v2@v2.27.1 brings 1 fewer module(s)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
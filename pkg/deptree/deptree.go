@@ -0,0 +1,285 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Node is one module in the dependency tree.
+type Node struct {
+	Name        string
+	Description string
+	// Annotations are short notes displayed after the name, such as
+	// "(v1.2.0 pruned)".
+	Annotations []string
+	Children    map[string]*Node
+}
+
+// NewNode returns a node for the given module with no children.
+func NewNode(name string) *Node {
+	return &Node{
+		Name:     name,
+		Children: make(map[string]*Node),
+	}
+}
+
+// SetupPackage initializes a throwaway "temp" module in tmpDir that imports
+// packageName, so that its dependency graph can be read with 'go mod graph'.
+// packageName may carry any version query 'go get' accepts, such as
+// @v1.8.0, @latest or a branch name.
+func SetupPackage(tmpDir, packageName string) error {
+	return SetupPackageProgress(tmpDir, packageName, nil)
+}
+
+// SetupPackageProgress is SetupPackage, copying what 'go get' reports while
+// it resolves and downloads modules, such as "go: downloading
+// example.com/m v1.2.3", to progress as it happens when progress is not
+// nil.
+func SetupPackageProgress(tmpDir, packageName string, progress io.Writer) error {
+	modInit := goCommand(tmpDir, "mod", "init", "temp")
+	if err := modInit.Run(); err != nil {
+		return fmt.Errorf("failed to run 'go mod init': %w", err)
+	}
+
+	var output bytes.Buffer
+	goGet := goCommand(tmpDir, "get", packageName)
+	goGet.Stdout = &output
+	goGet.Stderr = &output
+	if progress != nil {
+		goGet.Stderr = io.MultiWriter(&output, progress)
+	}
+	if err := goGet.Run(); err != nil {
+		return fmt.Errorf("failed to run 'go get %s': %w\nOutput: %s", packageName, err, output.String())
+	}
+
+	mainGo := filepath.Join(tmpDir, "main.go")
+	importPath, _, _ := strings.Cut(packageName, "@")
+	content := fmt.Sprintf("package main\n\nimport _ \"%s\"\n\nfunc main() {}\n", importPath)
+	if err := os.WriteFile(mainGo, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write main.go: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the dependency graph of the module in dir, rooted at
+// requestedPackage when dir holds a temp module set up by SetupPackage, and
+// the go.mod files needed to classify its edges. In a workspace, the graph
+// is rooted at WorkspaceRoot with every workspace module below it. Projects
+// without a go.mod are read with LoadLegacy instead.
+func Load(dir, requestedPackage string) (*Graph, error) {
+	if requestedPackage == "" && legacySource(dir) != "" {
+		return LoadLegacy(dir)
+	}
+
+	timings := &Timings{}
+
+	done := timings.Track("graph retrieval")
+	deps, skipped, err := readModuleGraph(dir)
+	done()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	g := &Graph{Deps: deps, Timings: timings}
+	for _, line := range skipped {
+		g.warn(WarnGraphLine, "", "skipped 'go mod graph' line %q", line)
+	}
+	if len(deps) == 0 {
+		return g, nil
+	}
+
+	if requestedPackage == "" {
+		workPath, err := FindWorkFile(dir)
+		if err != nil {
+			return nil, err
+		}
+		if workPath != "" {
+			defer timings.Track("workspace")()
+			if err := g.loadWorkspace(workPath); err != nil {
+				return nil, err
+			}
+			g.warnReplaceCollisions()
+			return g, nil
+		}
+	}
+
+	// go.mod names the main module, which the tree is rooted at
+	done = timings.Track("go.mod and go.sum")
+	g.ModFile, err = ReadModFile(dir)
+	if err == nil {
+		g.Sums, err = ReadGoSum(dir)
+	}
+	done()
+	if err != nil {
+		return nil, err
+	}
+
+	done = timings.Track("tree building")
+	g.expandRoot(treeRootName(deps, g.ModFile.Module.Path, requestedPackage))
+	done()
+
+	g.RootModFile = g.ModFile
+	if path, version := SplitModule(g.Root.Name); version != "" {
+		if g.RootModFile, err = ReadCachedModFile(path, version); err != nil {
+			return nil, err
+		}
+	}
+	g.warnReplaceCollisions()
+
+	return g, nil
+}
+
+// ReadModuleGraph runs 'go mod graph' in packagePath and returns the
+// requirement edges keyed by requiring module.
+func ReadModuleGraph(packagePath string) (map[string][]string, error) {
+	deps, _, err := readModuleGraph(packagePath)
+	return deps, err
+}
+
+// readModuleGraph is ReadModuleGraph, also returning the non-blank lines of
+// output that are not an edge between two modules.
+func readModuleGraph(packagePath string) (map[string][]string, []string, error) {
+	output, err := ModuleGraphOutput(packagePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deps := make(map[string][]string)
+	var skipped []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Fields(line)
+		switch len(parts) {
+		case 0:
+		case 2:
+			from := parts[0]
+			to := parts[1]
+			deps[from] = append(deps[from], to)
+		default:
+			skipped = append(skipped, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error reading output: %w", err)
+	}
+
+	return deps, skipped, nil
+}
+
+// ModuleGraphOutput runs 'go mod graph' in packagePath and returns its
+// output unparsed.
+func ModuleGraphOutput(packagePath string) ([]byte, error) {
+	cmd := goCommand(packagePath, "mod", "graph")
+
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("failed to run 'go mod graph': %w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'go mod graph': %w", err)
+	}
+	return output, nil
+}
+
+// BuildDependencyTree builds the tree rooted at the main module of deps, or at
+// requestedPackage when the graph comes from a temp module set up by
+// SetupPackage. Without a go.mod to name the main module, it is the first
+// module without a version in lexicographic order.
+func BuildDependencyTree(deps map[string][]string, requestedPackage string) *Node {
+	root := NewNode(treeRootName(deps, "", requestedPackage))
+	buildTree(root, deps, make(map[string]bool))
+	return root
+}
+
+// treeRootName returns the module the tree of deps is rooted at. 'go mod
+// graph' prints main modules without a version, so that is mainModule, the
+// module go.mod names, if deps has it, and otherwise the first module
+// without a version in lexicographic order, or the first module at all if
+// every one has a version. Picking in order keeps the tree the same from
+// run to run when several modules lack a version, as in a vendored or
+// hand-edited graph.
+func treeRootName(deps map[string][]string, mainModule, requestedPackage string) string {
+	var rootModule string
+	if _, ok := deps[mainModule]; ok && mainModule != "" {
+		rootModule = mainModule
+	} else {
+		modules := slices.Sorted(maps.Keys(deps))
+		if i := slices.IndexFunc(modules, func(name string) bool { return !strings.Contains(name, "@") }); i >= 0 {
+			rootModule = modules[i]
+		} else if len(modules) > 0 {
+			rootModule = modules[0]
+		}
+	}
+
+	// If we have a temp module and a requested package, use the module
+	// providing the package as root. temp requires exactly one version of
+	// it: the one 'go get' resolved the version query to.
+	if rootModule == "temp" && requestedPackage != "" {
+		if name := requestedModule(deps[rootModule], requestedPackage); name != "" {
+			return name
+		}
+	}
+
+	return rootModule
+}
+
+// requestedModule returns the requirement of the temp module that provides
+// requestedPackage. The package might include a subpath (e.g.
+// github.com/a-h/templ/cmd/templ) while the module name is just the base
+// (e.g. github.com/a-h/templ@v0.3.960), so the longest module path
+// containing the package wins. Failing that, a module nested under the
+// requested path is used.
+func requestedModule(requirements []string, requestedPackage string) string {
+	packageBase, _, _ := strings.Cut(requestedPackage, "@")
+
+	var best, bestPath, nested string
+	for _, name := range slices.Sorted(slices.Values(requirements)) {
+		childBase, _ := SplitModule(name)
+		switch {
+		case packageBase == childBase || strings.HasPrefix(packageBase, childBase+"/"):
+			if len(childBase) > len(bestPath) {
+				best, bestPath = name, childBase
+			}
+		case nested == "" && strings.HasPrefix(childBase, packageBase+"/"):
+			nested = name
+		}
+	}
+
+	if best != "" {
+		return best
+	}
+	return nested
+}
+
+func buildTree(node *Node, deps map[string][]string, visited map[string]bool) {
+	if visited[node.Name] {
+		return
+	}
+	visited[node.Name] = true
+
+	children := deps[node.Name]
+	for _, child := range children {
+		if _, exists := node.Children[child]; !exists {
+			childNode := NewNode(child)
+			node.Children[child] = childNode
+			buildTree(childNode, deps, visited)
+		}
+	}
+}
+
+// IsToolchainDep reports whether dep is a go or toolchain pseudo-module.
+func IsToolchainDep(dep string) bool {
+	return strings.HasPrefix(dep, "go@") || strings.HasPrefix(dep, "toolchain@")
+}
@@ -0,0 +1,234 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewNode(t *testing.T) {
+	node := NewNode("test-package")
+
+	if node.Name != "test-package" {
+		t.Errorf("Expected node name to be 'test-package', got '%s'", node.Name)
+	}
+
+	if node.Children == nil {
+		t.Error("Expected Children map to be initialized")
+	}
+
+	if len(node.Children) != 0 {
+		t.Errorf("Expected empty Children map, got %d items", len(node.Children))
+	}
+}
+
+func TestIsToolchainDep(t *testing.T) {
+	tests := []struct {
+		name     string
+		dep      string
+		expected bool
+	}{
+		{"go toolchain", "go@1.21.0", true},
+		{"toolchain", "toolchain@go1.21.0", true},
+		{"regular dependency", "github.com/spf13/cobra@v1.7.0", false},
+		{"empty string", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsToolchainDep(tt.dep)
+			if result != tt.expected {
+				t.Errorf("IsToolchainDep(%q) = %v, want %v", tt.dep, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadModuleGraph(t *testing.T) {
+	// Create a temporary directory with a minimal Go module
+	tmpDir := t.TempDir()
+
+	// Create go.mod
+	goModContent := []byte("module test\n\ngo 1.21\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), goModContent, 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	// Create a simple main.go
+	mainGoContent := []byte("package main\n\nfunc main() {}\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), mainGoContent, 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	deps, err := ReadModuleGraph(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadModuleGraph failed: %v", err)
+	}
+
+	// For a minimal module with no dependencies, we should get an empty or minimal result
+	if deps == nil {
+		t.Error("Expected non-nil deps map")
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	deps := map[string][]string{
+		"root":        {"dep1@v1.0.0", "dep2@v1.0.0"},
+		"dep1@v1.0.0": {"dep3@v1.0.0"},
+		"dep2@v1.0.0": {},
+		"dep3@v1.0.0": {},
+	}
+
+	root := NewNode("root")
+	visited := make(map[string]bool)
+	buildTree(root, deps, visited)
+
+	// Check that root has two children
+	if len(root.Children) != 2 {
+		t.Errorf("Expected root to have 2 children, got %d", len(root.Children))
+	}
+
+	// Check that dep1 exists and has dep3 as child
+	dep1, exists := root.Children["dep1@v1.0.0"]
+	if !exists {
+		t.Error("Expected dep1@v1.0.0 to be in root's children")
+	} else {
+		if len(dep1.Children) != 1 {
+			t.Errorf("Expected dep1 to have 1 child, got %d", len(dep1.Children))
+		}
+		if _, exists := dep1.Children["dep3@v1.0.0"]; !exists {
+			t.Error("Expected dep3@v1.0.0 to be in dep1's children")
+		}
+	}
+
+	// Check that dep2 exists
+	_, exists = root.Children["dep2@v1.0.0"]
+	if !exists {
+		t.Error("Expected dep2@v1.0.0 to be in root's children")
+	}
+
+	// Check visited map
+	if !visited["root"] {
+		t.Error("Expected root to be marked as visited")
+	}
+}
+
+func TestBuildDependencyTree(t *testing.T) {
+	deps := map[string][]string{
+		"mymodule":    {"dep1@v1.0.0", "dep2@v1.0.0"},
+		"dep1@v1.0.0": {"dep3@v1.0.0"},
+		"dep2@v1.0.0": {},
+		"dep3@v1.0.0": {},
+	}
+
+	tree := BuildDependencyTree(deps, "")
+
+	if tree.Name != "mymodule" {
+		t.Errorf("Expected root name to be 'mymodule', got '%s'", tree.Name)
+	}
+
+	if len(tree.Children) != 2 {
+		t.Errorf("Expected tree to have 2 children, got %d", len(tree.Children))
+	}
+}
+
+func TestTreeRootNameSeveralUnversioned(t *testing.T) {
+	deps := map[string][]string{
+		"example.com/zeta":  {"dep1@v1.0.0"},
+		"example.com/app":   {"dep1@v1.0.0", "example.com/zeta"},
+		"example.com/alpha": {"dep2@v1.0.0"},
+		"dep1@v1.0.0":       {},
+		"dep2@v1.0.0":       {},
+	}
+
+	tests := []struct {
+		name       string
+		mainModule string
+		want       string
+	}{
+		{"go.mod names the main module", "example.com/app", "example.com/app"},
+		{"main module missing from the graph", "example.com/other", "example.com/alpha"},
+		{"no go.mod", "", "example.com/alpha"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order differs between runs, so ask repeatedly
+			for range 20 {
+				if got := treeRootName(deps, tt.mainModule, ""); got != tt.want {
+					t.Fatalf("treeRootName(%q) = %q, want %q", tt.mainModule, got, tt.want)
+				}
+			}
+		})
+	}
+
+	versioned := map[string][]string{"b@v1.0.0": {"c@v1.0.0"}, "a@v1.0.0": {"b@v1.0.0"}}
+	for range 20 {
+		if got := BuildDependencyTree(versioned, "").Name; got != "a@v1.0.0" {
+			t.Fatalf("Expected the lexicographically first module without unversioned ones, got %q", got)
+		}
+	}
+}
+
+func TestBuildDependencyTreeWithTemp(t *testing.T) {
+	deps := map[string][]string{
+		"temp":                          {"github.com/example/pkg@v1.0.0"},
+		"github.com/example/pkg@v1.0.0": {"dep1@v1.0.0"},
+		"dep1@v1.0.0":                   {},
+	}
+
+	tree := BuildDependencyTree(deps, "github.com/example/pkg")
+
+	// Should return the requested package as root, not "temp"
+	if !strings.Contains(tree.Name, "github.com/example/pkg") {
+		t.Errorf("Expected root to be the requested package, got '%s'", tree.Name)
+	}
+}
+
+func TestBuildDependencyTreeWithVersionedPackage(t *testing.T) {
+	deps := map[string][]string{
+		"temp": {
+			"github.com/spf13/cobra-cli@v1.3.0",
+			"github.com/spf13/cobra@v1.8.0",
+			"github.com/spf13/pflag@v1.0.5",
+		},
+		"github.com/spf13/cobra@v1.8.0":     {"github.com/spf13/pflag@v1.0.5"},
+		"github.com/spf13/cobra-cli@v1.3.0": {},
+		"github.com/spf13/pflag@v1.0.5":     {},
+	}
+
+	for _, pkg := range []string{
+		"github.com/spf13/cobra@v1.8.0",
+		"github.com/spf13/cobra@latest",
+		"github.com/spf13/cobra/doc@main",
+	} {
+		if tree := BuildDependencyTree(deps, pkg); tree.Name != "github.com/spf13/cobra@v1.8.0" {
+			t.Errorf("BuildDependencyTree(%q) root = %q, want github.com/spf13/cobra@v1.8.0", pkg, tree.Name)
+		}
+	}
+}
+
+func TestBuildTreeCyclicDependency(t *testing.T) {
+	// Test that buildTree handles cyclic dependencies gracefully
+	deps := map[string][]string{
+		"root":        {"dep1@v1.0.0"},
+		"dep1@v1.0.0": {"dep2@v1.0.0"},
+		"dep2@v1.0.0": {"dep1@v1.0.0"}, // Cycle back to dep1
+	}
+
+	root := NewNode("root")
+	visited := make(map[string]bool)
+
+	// This should not cause infinite recursion
+	buildTree(root, deps, visited)
+
+	// Verify the tree was built
+	if len(root.Children) != 1 {
+		t.Errorf("Expected root to have 1 child, got %d", len(root.Children))
+	}
+
+	// Verify visited tracking prevents infinite loops
+	if !visited["root"] || !visited["dep1@v1.0.0"] || !visited["dep2@v1.0.0"] {
+		t.Error("Expected all nodes to be marked as visited")
+	}
+}
@@ -0,0 +1,83 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// descExecWorkers bounds how many description hook processes run at once.
+const descExecWorkers = 8
+
+// TransformDescriptions pipes every successfully fetched description through
+// the shell command on stdin and replaces it with the command's trimmed
+// stdout. The module name is passed in the DEPTREE_MODULE environment
+// variable. Descriptions whose command fails are kept unchanged and the
+// failures are returned joined together.
+func TransformDescriptions(g *Graph, command string) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	transformed := make(map[string]string)
+	sem := make(chan struct{}, descExecWorkers)
+
+	for name, desc := range g.Descriptions {
+		if _, failed := g.DescriptionErrors[name]; failed {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name, desc string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			output, err := runDescExec(command, name, desc)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			transformed[name] = output
+		}(name, desc)
+	}
+
+	// The loop above still ranges over g.Descriptions while the hooks run,
+	// so their output is only stored once all of them are done
+	wg.Wait()
+	for name, desc := range transformed {
+		g.Descriptions[name] = desc
+	}
+	g.syncDescriptions()
+	return errors.Join(errs...)
+}
+
+func runDescExec(command, module, desc string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), "DEPTREE_MODULE="+module)
+	cmd.Stdin = strings.NewReader(desc)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("description hook failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("description hook failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
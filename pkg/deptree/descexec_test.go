@@ -0,0 +1,60 @@
+package deptree
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestTransformDescriptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command uses POSIX shell utilities")
+	}
+
+	root := NewNode("mymodule")
+	child := NewNode("github.com/a/b@v1.0.0")
+	root.Children[child.Name] = child
+
+	g := &Graph{
+		Root: root,
+		Descriptions: map[string]string{
+			"mymodule":              "(not a GitHub module)",
+			"github.com/a/b@v1.0.0": "hello world",
+		},
+		DescriptionErrors: map[string]error{
+			"mymodule": errors.New("not a GitHub module"),
+		},
+	}
+
+	if err := TransformDescriptions(g, `tr a-z A-Z; printf " [%s]" "$DEPTREE_MODULE"`); err != nil {
+		t.Fatalf("TransformDescriptions failed: %v", err)
+	}
+
+	if got := g.Descriptions["github.com/a/b@v1.0.0"]; got != "HELLO WORLD [github.com/a/b@v1.0.0]" {
+		t.Errorf("Unexpected transformed description %q", got)
+	}
+	if child.Description != g.Descriptions[child.Name] {
+		t.Errorf("Expected node description to be updated, got %q", child.Description)
+	}
+	if got := g.Descriptions["mymodule"]; got != "(not a GitHub module)" {
+		t.Errorf("Expected failed fetch to be left alone, got %q", got)
+	}
+}
+
+func TestTransformDescriptionsFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command uses POSIX shell utilities")
+	}
+
+	g := &Graph{
+		Root:         NewNode("github.com/a/b@v1.0.0"),
+		Descriptions: map[string]string{"github.com/a/b@v1.0.0": "original"},
+	}
+
+	if err := TransformDescriptions(g, "echo broken >&2; exit 3"); err == nil {
+		t.Fatal("Expected error from failing command")
+	}
+	if got := g.Descriptions["github.com/a/b@v1.0.0"]; got != "original" {
+		t.Errorf("Expected original description to be kept, got %q", got)
+	}
+}
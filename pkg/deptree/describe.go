@@ -0,0 +1,231 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	gitlabAPIURL    = "https://gitlab.com"
+	bitbucketAPIURL = "https://api.bitbucket.org"
+)
+
+var (
+	metaTagPattern   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?is)(\w+)\s*=\s*["']([^"']*)["']`)
+)
+
+// describer fetches repository descriptions from the forge hosting each
+// module, sharing one rate-limit aware GitHub client.
+type describer struct {
+	client *http.Client
+	github *GitHubClient
+	// gitlabURL and bitbucketAPIURL are the base URLs of the gitlab.com
+	// and Bitbucket APIs.
+	gitlabURL, bitbucketAPIURL string
+}
+
+func newDescriber(opts Options, token string) *describer {
+	return &describer{
+		client:          opts.client(),
+		github:          NewGitHubClient(opts, token),
+		gitlabURL:       opts.gitlabURL(),
+		bitbucketAPIURL: opts.bitbucketAPIURL(),
+	}
+}
+
+// SourceLinks are where the source of a module lives, for linking to it
+// from inventories.
+type SourceLinks struct {
+	// Homepage is the project's website as its repository records it, or
+	// else the web page of the repository.
+	Homepage string `json:"homepage,omitempty"`
+	// CloneURL is the URL of the repository the module is fetched from.
+	CloneURL string `json:"cloneUrl,omitempty"`
+}
+
+// FetchDescription returns the repository description of a module hosted on
+// GitHub, GitLab or Bitbucket. Other import paths, such as golang.org/x or
+// corporate vanity paths, are resolved to their repository through the
+// go-get=1 meta tags the go command uses.
+func FetchDescription(opts Options, modulePath, token string) (string, error) {
+	return newDescriber(opts, token).Description(modulePath)
+}
+
+func (d *describer) Description(modulePath string) (string, error) {
+	desc, _, err := d.Describe(modulePath)
+	return desc, err
+}
+
+// Describe returns the repository description of a module along with the
+// links to its source. The links are also returned with an error once the
+// repository is known, since they do not depend on its forge's API.
+func (d *describer) Describe(modulePath string) (string, SourceLinks, error) {
+	path, _ := SplitModule(modulePath)
+
+	// Import paths of remote modules start with a domain name
+	host, _, _ := strings.Cut(path, "/")
+	if IsToolchainDep(modulePath) || !strings.Contains(host, ".") {
+		return "", SourceLinks{}, fmt.Errorf("not a remote module")
+	}
+
+	repoURL := ""
+	switch {
+	case strings.HasPrefix(path, "github.com/"), strings.HasPrefix(path, "bitbucket.org/"),
+		d.github.enterpriseHost != "" && strings.HasPrefix(path, d.github.enterpriseHost+"/"):
+		// The repository is the host and the first two path elements,
+		// below which are major version suffixes and nested modules
+		elems := strings.SplitN(path, "/", 4)
+		repoURL = "https://" + strings.Join(elems[:min(3, len(elems))], "/")
+	default:
+		var err error
+		if repoURL, err = d.resolveRepo(path); err != nil {
+			return "", SourceLinks{}, err
+		}
+	}
+
+	return d.describeRepo(repoURL)
+}
+
+// resolveRepo returns the repository URL of the go-import meta tag served
+// for path.
+func (d *describer) resolveRepo(path string) (string, error) {
+	resp, err := d.client.Get("https://" + path + "?go-get=1")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import path: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("import path lookup returned status %d", resp.StatusCode)
+	}
+
+	// The meta tags are in the head, so the start of the page is enough
+	page, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read import path page: %w", err)
+	}
+
+	repoURL, ok := parseGoImport(page, path)
+	if !ok {
+		return "", fmt.Errorf("no go-import meta tag for %s", path)
+	}
+	return repoURL, nil
+}
+
+// parseGoImport finds the go-import meta tag in page whose import prefix
+// covers path and returns its repository URL.
+func parseGoImport(page []byte, path string) (string, bool) {
+	for _, tag := range metaTagPattern.FindAll(page, -1) {
+		attrs := make(map[string]string)
+		for _, m := range attributePattern.FindAllSubmatch(tag, -1) {
+			attrs[strings.ToLower(string(m[1]))] = string(m[2])
+		}
+		if attrs["name"] != "go-import" {
+			continue
+		}
+
+		// content is "import-prefix vcs repo-root"
+		fields := strings.Fields(attrs["content"])
+		if len(fields) != 3 || fields[1] == "mod" {
+			continue
+		}
+		if path == fields[0] || strings.HasPrefix(path, fields[0]+"/") {
+			return fields[2], true
+		}
+	}
+	return "", false
+}
+
+// describeRepo fetches the description of the repository at repoURL from
+// its forge's API, and returns it with the links to the repository: its
+// homepage, if the forge records one, defaults to the repository page.
+func (d *describer) describeRepo(repoURL string) (string, SourceLinks, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", SourceLinks{}, fmt.Errorf("invalid repository URL %q: %w", repoURL, err)
+	}
+	repoPath := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	parts := strings.Split(repoPath, "/")
+	links := SourceLinks{Homepage: strings.TrimSuffix(repoURL, ".git"), CloneURL: repoURL}
+
+	var desc, homepage string
+	switch {
+	case u.Host == "github.com", d.github.enterpriseHost != "" && u.Host == d.github.enterpriseHost:
+		desc, homepage, err = d.describeGitHubRepo(u.Host + "/" + repoPath)
+
+	case u.Host == "go.googlesource.com":
+		// The Go project's repositories are mirrored under github.com/golang
+		desc, homepage, err = d.describeGitHubRepo("github.com/golang/" + repoPath)
+
+	case u.Host == "bitbucket.org" && len(parts) >= 2:
+		desc, homepage, err = d.fetchJSONDescription(fmt.Sprintf("%s/2.0/repositories/%s/%s", d.bitbucketAPIURL, parts[0], parts[1]))
+
+	case u.Host == "gitlab.com":
+		desc, homepage, err = d.fetchJSONDescription(fmt.Sprintf("%s/api/v4/projects/%s", d.gitlabURL, url.PathEscape(repoPath)))
+
+	case strings.Contains(u.Host, "gitlab"):
+		// Self-hosted GitLab instances serve the same API
+		desc, homepage, err = d.fetchJSONDescription(fmt.Sprintf("https://%s/api/v4/projects/%s", u.Host, url.PathEscape(repoPath)))
+
+	default:
+		err = fmt.Errorf("unsupported repository host %s", u.Host)
+	}
+
+	if homepage != "" {
+		links.Homepage = homepage
+	}
+	return desc, links, err
+}
+
+// describeGitHubRepo returns the description and homepage of a GitHub
+// repository, failing with errNoDescription if it has no description.
+func (d *describer) describeGitHubRepo(modulePath string) (string, string, error) {
+	repo, err := d.github.Repo(modulePath)
+	if err != nil {
+		return "", "", err
+	}
+	if repo.Description == "" {
+		return "", repo.Homepage, errNoDescription
+	}
+	return repo.Description, repo.Homepage, nil
+}
+
+// fetchJSONDescription fetches apiURL and returns the "description" and
+// "website" fields of the JSON response. GitLab and Bitbucket both use
+// the former; only Bitbucket has the latter.
+func (d *describer) fetchJSONDescription(apiURL string) (string, string, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "deptree-cli")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch repository metadata: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("%s API returned status %d", req.URL.Host, resp.StatusCode)
+	}
+
+	var repo struct {
+		Description string `json:"description"`
+		Website     string `json:"website"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return "", "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if repo.Description == "" {
+		return "", repo.Website, errNoDescription
+	}
+	return repo.Description, repo.Website, nil
+}
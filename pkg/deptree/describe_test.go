@@ -0,0 +1,131 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGoImport(t *testing.T) {
+	page := []byte(`<html><head>
+<meta name="go-import" content="example.com/mod mod https://proxy.example.com">
+<meta content="example.com/mod git https://gitlab.example.com/team/mod.git" name="go-import">
+<meta name="go-source" content="example.com/mod _ https://gitlab.example.com/team/mod">
+</head></html>`)
+
+	repo, ok := parseGoImport(page, "example.com/mod/v2")
+	if !ok || repo != "https://gitlab.example.com/team/mod.git" {
+		t.Errorf("parseGoImport() = %q, %v", repo, ok)
+	}
+
+	if _, ok := parseGoImport(page, "example.com/other"); ok {
+		t.Error("Expected no match for an unrelated path")
+	}
+}
+
+func TestFetchDescriptionNonGitHub(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := strings.TrimPrefix(server.URL, "https://")
+		switch {
+		case r.URL.Query().Get("go-get") == "1" && r.URL.Path == "/vanity":
+			w.Write([]byte(`<meta name="go-import" content="` + host + `/vanity git https://gitlab.com/group/sub/vanity">`))
+		case r.URL.Query().Get("go-get") == "1" && r.URL.Path == "/x/text":
+			w.Write([]byte(`<meta name="go-import" content="` + host + `/x/text git https://go.googlesource.com/text">`))
+		case r.URL.EscapedPath() == "/api/v4/projects/group%2Fsub%2Fvanity":
+			w.Write([]byte(`{"description": "from GitLab"}`))
+		case r.URL.Path == "/2.0/repositories/team/repo":
+			w.Write([]byte(`{"description": "from Bitbucket"}`))
+		case r.URL.Path == "/repos/golang/text":
+			w.Write([]byte(`{"description": "[mirror] Go text processing support"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldGitHub, oldGitLab, oldBitbucket := githubAPIURL, gitlabAPIURL, bitbucketAPIURL
+	githubAPIURL, gitlabAPIURL, bitbucketAPIURL = server.URL, server.URL, server.URL
+	defer func() { githubAPIURL, gitlabAPIURL, bitbucketAPIURL = oldGitHub, oldGitLab, oldBitbucket }()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	for module, want := range map[string]string{
+		host + "/vanity@v1.0.0":              "from GitLab",
+		host + "/x/text@v0.14.0":             "[mirror] Go text processing support",
+		"bitbucket.org/team/repo/sub@v1.0.0": "from Bitbucket",
+	} {
+		desc, err := FetchDescription(Options{HTTPClient: server.Client()}, module, "")
+		if err != nil || desc != want {
+			t.Errorf("FetchDescription(%s) = %q, %v; want %q", module, desc, err, want)
+		}
+	}
+
+	for _, module := range []string{"mymodule", "go@1.21.0", host + "/unknown@v1.0.0"} {
+		if _, err := FetchDescription(Options{HTTPClient: server.Client()}, module, ""); err == nil {
+			t.Errorf("Expected error for %s", module)
+		}
+	}
+}
+
+func TestFetchDescriptionsLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/spf13/cobra":
+			w.Write([]byte(`{"description": "A Commander", "homepage": "https://cobra.dev"}`))
+		case "/repos/pkg/errors":
+			w.Write([]byte(`{"description": "", "homepage": ""}`))
+		case "/2.0/repositories/team/repo":
+			w.Write([]byte(`{"description": "from Bitbucket", "website": "https://team.example.com"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldGitHub, oldBitbucket := githubAPIURL, bitbucketAPIURL
+	githubAPIURL, bitbucketAPIURL = server.URL, server.URL
+	defer func() { githubAPIURL, bitbucketAPIURL = oldGitHub, oldBitbucket }()
+
+	cache, err := OpenDescriptionCache(filepath.Join(t.TempDir(), "descriptions.json"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Graph{
+		Root: NewNode("myapp"),
+		Deps: map[string][]string{
+			"myapp": {"github.com/spf13/cobra/v2@v2.0.0", "github.com/pkg/errors@v0.9.1", "bitbucket.org/team/repo/sub@v1.0.0"},
+		},
+	}
+	want := map[string]SourceLinks{
+		"github.com/spf13/cobra/v2@v2.0.0":   {Homepage: "https://cobra.dev", CloneURL: "https://github.com/spf13/cobra"},
+		"github.com/pkg/errors@v0.9.1":       {Homepage: "https://github.com/pkg/errors", CloneURL: "https://github.com/pkg/errors"},
+		"bitbucket.org/team/repo/sub@v1.0.0": {Homepage: "https://team.example.com", CloneURL: "https://bitbucket.org/team/repo"},
+	}
+
+	FetchDescriptions(g, Options{HTTPClient: server.Client()}, "", cache)
+	if !reflect.DeepEqual(g.Links, want) {
+		t.Errorf("Links = %v, want %v", g.Links, want)
+	}
+
+	// A second run answers from the cache, links included
+	server.Close()
+	FetchDescriptions(g, Options{HTTPClient: server.Client()}, "", cache)
+	if !reflect.DeepEqual(g.Links, want) {
+		t.Errorf("Links from cache = %v, want %v", g.Links, want)
+	}
+
+	out, err := jsonRenderer{}.Render(g, RenderOptions{ShowDesc: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"homepage": "https://cobra.dev"`) || !strings.Contains(string(out), `"cloneUrl": "https://github.com/spf13/cobra"`) {
+		t.Errorf("Expected links in the JSON output, got:\n%s", out)
+	}
+	if out, _ := (jsonRenderer{}).Render(g, RenderOptions{}); strings.Contains(string(out), "cloneUrl") {
+		t.Errorf("Expected no links without descriptions, got:\n%s", out)
+	}
+}
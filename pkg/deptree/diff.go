@@ -0,0 +1,206 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is how a module changed between two graphs.
+type ChangeKind string
+
+const (
+	ChangeAdded      ChangeKind = "added"
+	ChangeRemoved    ChangeKind = "removed"
+	ChangeUpgraded   ChangeKind = "upgraded"
+	ChangeDowngraded ChangeKind = "downgraded"
+)
+
+// ModuleChange is one module that differs between two graphs. Old is empty
+// for added modules and New for removed ones.
+type ModuleChange struct {
+	Path string
+	Kind ChangeKind
+	Old  string
+	New  string
+	// Causes are the changes of top-level dependencies that brought this
+	// change along, as found by AttributeChanges.
+	Causes []ModuleChange
+}
+
+func (c ModuleChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s@%s", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s@%s", c.Path, c.Old)
+	}
+	return fmt.Sprintf("%s %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Attribution describes the changes that caused c, such as "(added by
+// github.com/spf13/cobra v1.7.0 -> v1.8.0)", or returns "" if c has none.
+func (c ModuleChange) Attribution() string {
+	if len(c.Causes) == 0 {
+		return ""
+	}
+	causes := make([]string, len(c.Causes))
+	for i, cause := range c.Causes {
+		causes[i] = cause.String()
+	}
+	return fmt.Sprintf("(%s by %s)", c.Kind, strings.Join(causes, ", "))
+}
+
+// DiffGraphs compares the modules of the before and after graphs by path,
+// using the highest version of each path as the one the graph selects, and
+// returns every change sorted by path.
+func DiffGraphs(before, after *Graph) []ModuleChange {
+	oldVersions := highestVersions(before)
+	newVersions := highestVersions(after)
+
+	paths := maps.Clone(oldVersions)
+	maps.Copy(paths, newVersions)
+
+	var changes []ModuleChange
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		oldVersion, inOld := oldVersions[path]
+		newVersion, inNew := newVersions[path]
+
+		change := ModuleChange{Path: path, Old: oldVersion, New: newVersion}
+		switch {
+		case !inOld:
+			change.Kind = ChangeAdded
+		case !inNew:
+			change.Kind = ChangeRemoved
+		case CompareVersions(newVersion, oldVersion) > 0:
+			change.Kind = ChangeUpgraded
+		case CompareVersions(newVersion, oldVersion) < 0:
+			change.Kind = ChangeDowngraded
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// AttributeChanges sets the Causes of every change to a transitive
+// dependency: the changed top-level dependencies through which the module
+// is reached, in the after graph for added, upgraded and downgraded modules
+// and in the before graph for removed ones. Top-level dependencies are the
+// direct dependencies of the root, or all its requirements when the graph
+// has no go.mod to tell them apart. Changes to top-level dependencies
+// themselves have no causes.
+func AttributeChanges(before, after *Graph, changes []ModuleChange) {
+	byPath := make(map[string]ModuleChange, len(changes))
+	for _, c := range changes {
+		byPath[c.Path] = c
+	}
+
+	oldCauses := newChangeAttributor(before, byPath, ChangeAdded)
+	newCauses := newChangeAttributor(after, byPath, ChangeRemoved)
+	for i := range changes {
+		c := &changes[i]
+		if c.Kind == ChangeRemoved {
+			c.Causes = oldCauses.causes(c.Path + "@" + c.Old)
+		} else {
+			c.Causes = newCauses.causes(c.Path + "@" + c.New)
+		}
+	}
+}
+
+// changeAttributor finds the changed top-level dependencies of one graph
+// that reach a module.
+type changeAttributor struct {
+	g        *Graph
+	topLevel []string
+	byPath   map[string]ModuleChange
+	// skip is the change kind that cannot occur in g: additions are not in
+	// the before graph and removals not in the after graph.
+	skip      ChangeKind
+	reachable map[string]map[string]bool
+}
+
+func newChangeAttributor(g *Graph, byPath map[string]ModuleChange, skip ChangeKind) *changeAttributor {
+	a := &changeAttributor{g: g, byPath: byPath, skip: skip, reachable: make(map[string]map[string]bool)}
+	if g == nil || g.Root == nil {
+		return a
+	}
+	a.topLevel = slices.Sorted(maps.Keys(g.DirectDependencies()))
+	if len(a.topLevel) == 0 {
+		a.topLevel = slices.Clone(g.Deps[g.Root.Name])
+		slices.Sort(a.topLevel)
+	}
+	return a
+}
+
+// causes returns the changes of the top-level dependencies reaching name,
+// or nil if name is a top-level dependency itself.
+func (a *changeAttributor) causes(name string) []ModuleChange {
+	if slices.Contains(a.topLevel, name) {
+		return nil
+	}
+
+	var causes []ModuleChange
+	for _, top := range a.topLevel {
+		path, _ := SplitModule(top)
+		cause, ok := a.byPath[path]
+		if !ok || cause.Kind == a.skip {
+			continue
+		}
+		if a.reachable[top] == nil {
+			a.reachable[top] = make(map[string]bool)
+			for _, reached := range a.g.Reachable(top) {
+				a.reachable[top][reached] = true
+			}
+		}
+		if a.reachable[top][name] {
+			causes = append(causes, cause)
+		}
+	}
+	return causes
+}
+
+// ReadJSONGraph reads a graph saved with the json format, rebuilding its
+// edges and tree. Descriptions and licenses in the snapshot are restored
+// too.
+func ReadJSONGraph(r io.Reader) (*Graph, error) {
+	var snapshot jsonGraph
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON graph: %w", err)
+	}
+	if snapshot.Root == "" {
+		return nil, fmt.Errorf("JSON graph has no root module")
+	}
+
+	g := &Graph{
+		Deps:         make(map[string][]string),
+		Descriptions: make(map[string]string),
+		Licenses:     make(map[string]string),
+		Pruned:       snapshot.Pruned,
+	}
+	for _, module := range snapshot.Modules {
+		if _, ok := g.Deps[module.Name]; !ok {
+			g.Deps[module.Name] = []string{}
+		}
+		if module.Description != "" {
+			g.Descriptions[module.Name] = module.Description
+		}
+		if module.License != "" {
+			g.Licenses[module.Name] = module.License
+		}
+	}
+	for _, edge := range snapshot.Edges {
+		g.Deps[edge.From] = append(g.Deps[edge.From], edge.To)
+	}
+
+	g.Root = NewNode(snapshot.Root)
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+	g.syncDescriptions()
+
+	return g, nil
+}
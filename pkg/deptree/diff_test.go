@@ -0,0 +1,110 @@
+package deptree
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDiffGraphs(t *testing.T) {
+	before := &Graph{Deps: map[string][]string{
+		"app": {"a@v1.0.0", "b@v1.2.0", "c@v1.0.0", "same@v1.0.0"},
+	}}
+	after := &Graph{Deps: map[string][]string{
+		"app":      {"a@v1.1.0", "b@v1.1.0", "d@v0.1.0", "same@v1.0.0"},
+		"a@v1.1.0": {"b@v1.0.0"},
+	}}
+
+	got := DiffGraphs(before, after)
+	want := []ModuleChange{
+		{Path: "a", Kind: ChangeUpgraded, Old: "v1.0.0", New: "v1.1.0"},
+		{Path: "b", Kind: ChangeDowngraded, Old: "v1.2.0", New: "v1.1.0"},
+		{Path: "c", Kind: ChangeRemoved, Old: "v1.0.0"},
+		{Path: "d", Kind: ChangeAdded, New: "v0.1.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffGraphs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadJSONGraph(t *testing.T) {
+	root := NewNode("app")
+	root.Children["a@v1.0.0"] = NewNode("a@v1.0.0")
+	original := &Graph{
+		Root:         root,
+		Deps:         map[string][]string{"app": {"a@v1.0.0"}, "a@v1.0.0": {"b@v1.0.0"}},
+		Descriptions: map[string]string{"a@v1.0.0": "module a"},
+	}
+
+	out, err := Render("json", original, RenderOptions{ShowDesc: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	g, err := ReadJSONGraph(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("ReadJSONGraph failed: %v", err)
+	}
+
+	if g.Root.Name != "app" {
+		t.Errorf("Expected root app, got %s", g.Root.Name)
+	}
+	if !reflect.DeepEqual(g.Modules(), original.Modules()) {
+		t.Errorf("Modules() = %v, want %v", g.Modules(), original.Modules())
+	}
+	a := g.Root.Children["a@v1.0.0"]
+	if a == nil || a.Children["b@v1.0.0"] == nil {
+		t.Fatal("Expected the tree to be rebuilt from the edges")
+	}
+	if a.Description != "module a" {
+		t.Errorf("Expected description to be restored, got %q", a.Description)
+	}
+
+	if _, err := ReadJSONGraph(bytes.NewReader([]byte(`{"modules": []}`))); err == nil {
+		t.Error("Expected error for a graph without root")
+	}
+}
+
+func TestAttributeChanges(t *testing.T) {
+	before := &Graph{
+		Deps: map[string][]string{
+			"app":          {"cobra@v1.7.0", "old@v1.0.0"},
+			"cobra@v1.7.0": {"pflag@v1.0.5"},
+			"old@v1.0.0":   {"gone@v0.1.0"},
+		},
+		RootModFile: &ModFile{Require: []ModRequire{
+			{Path: "cobra", Version: "v1.7.0"},
+			{Path: "old", Version: "v1.0.0"},
+		}},
+	}
+	before.Root = BuildDependencyTree(before.Deps, "")
+	after := &Graph{
+		Deps: map[string][]string{
+			"app":          {"cobra@v1.8.0", "yaml@v3.0.1"},
+			"cobra@v1.8.0": {"pflag@v1.0.5", "yaml@v3.0.1", "md2man@v2.0.3"},
+		},
+		RootModFile: &ModFile{Require: []ModRequire{
+			{Path: "cobra", Version: "v1.8.0"},
+			{Path: "yaml", Version: "v3.0.1", Indirect: true},
+		}},
+	}
+	after.Root = BuildDependencyTree(after.Deps, "")
+
+	changes := DiffGraphs(before, after)
+	AttributeChanges(before, after, changes)
+
+	want := map[string]string{
+		"cobra":  "",
+		"gone":   "(removed by old@v1.0.0)",
+		"md2man": "(added by cobra v1.7.0 -> v1.8.0)",
+		"old":    "",
+		"yaml":   "(added by cobra v1.7.0 -> v1.8.0)",
+	}
+	got := make(map[string]string)
+	for _, c := range changes {
+		got[c.Path] = c.Attribution()
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Attributions = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package deptree
+
+import (
+	"maps"
+	"slices"
+)
+
+// directTag marks direct dependencies in the tree and export formats.
+const directTag = "[direct]"
+
+// DirectDependencies returns the modules the root module's go.mod requires
+// without an // indirect comment, or in a workspace, those any workspace
+// module's go.mod requires that way. It is empty when the root go.mod is
+// unknown, since every requirement of the root would look direct then.
+func (g *Graph) DirectDependencies() map[string]bool {
+	direct := make(map[string]bool)
+	if g.Root == nil {
+		return direct
+	}
+
+	for _, from := range append([]string{g.Root.Name}, slices.Sorted(maps.Keys(g.Workspace))...) {
+		if g.mainModFile(from) == nil {
+			continue
+		}
+		for _, to := range g.Deps[from] {
+			if !IsToolchainDep(to) && g.classifyEdge(from, to).Kind == EdgeDirect {
+				direct[to] = true
+			}
+		}
+	}
+	return direct
+}
+
+// FilterDirect reduces g to the root module and its direct dependencies, as
+// reported by DirectDependencies. The dependencies of direct dependencies
+// are dropped along with indirect requirements.
+func (g *Graph) FilterDirect() {
+	if g.Root == nil {
+		return
+	}
+	direct := g.DirectDependencies()
+
+	var kept []string
+	for _, to := range g.Deps[g.Root.Name] {
+		if direct[to] {
+			kept = append(kept, to)
+		}
+	}
+	g.Deps = map[string][]string{g.Root.Name: kept}
+
+	for name, child := range g.Root.Children {
+		if !direct[name] {
+			delete(g.Root.Children, name)
+			continue
+		}
+		child.Children = make(map[string]*Node)
+	}
+}
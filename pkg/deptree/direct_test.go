@@ -0,0 +1,100 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func directTestGraph() *Graph {
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app":         {"github.com/a/lib@v1.0.0", "github.com/b/util@v0.2.0", "go@1.21"},
+			"github.com/a/lib@v1.0.0": {"github.com/b/util@v0.2.0"},
+		},
+		ModFile: &ModFile{Require: []ModRequire{
+			{Path: "github.com/a/lib", Version: "v1.0.0"},
+			{Path: "github.com/b/util", Version: "v0.2.0", Indirect: true},
+		}},
+	}
+	g.RootModFile = g.ModFile
+	g.Root = NewNode("example.com/app")
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+	return g
+}
+
+func TestDirectDependencies(t *testing.T) {
+	g := directTestGraph()
+	want := map[string]bool{"github.com/a/lib@v1.0.0": true}
+	if got := g.DirectDependencies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DirectDependencies() = %v, want %v", got, want)
+	}
+
+	g.RootModFile = nil
+	if got := g.DirectDependencies(); len(got) != 0 {
+		t.Errorf("Expected no direct dependencies without a root go.mod, got %v", got)
+	}
+}
+
+func TestDirectDependenciesMarked(t *testing.T) {
+	g := directTestGraph()
+
+	out, err := Render("tree", g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `example.com/app
+├── github.com/a/lib@v1.0.0 [direct]
+│   └── github.com/b/util@v0.2.0
+├── github.com/b/util@v0.2.0
+└── go@1.21
+`
+	if string(out) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+
+	out, err = Render("export", g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(string(out), "github.com/a/lib@v1.0.0 [direct]\ngithub.com/b/util@v0.2.0\n") {
+		t.Errorf("Expected export output to mark only the direct dependency, got:\n%s", out)
+	}
+}
+
+func TestFilterDirect(t *testing.T) {
+	g := directTestGraph()
+	g.FilterDirect()
+
+	if want := []string{"example.com/app", "github.com/a/lib@v1.0.0"}; !reflect.DeepEqual(g.Modules(), want) {
+		t.Errorf("Modules() = %v, want %v", g.Modules(), want)
+	}
+	if len(g.Root.Children) != 1 || len(g.Root.Children["github.com/a/lib@v1.0.0"].Children) != 0 {
+		t.Errorf("Expected the tree to hold only the direct dependency, got %v", g.Root.Children)
+	}
+}
+
+func TestTreeRendererColor(t *testing.T) {
+	g := directTestGraph()
+	g.Vulnerabilities = map[string][]string{"github.com/b/util@v0.2.0": {"GO-2024-0001"}}
+
+	out, err := Render("tree", g, RenderOptions{Color: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		ansiBold + "example.com/app" + ansiReset + "\n",
+		ansiCyan + "github.com/a/lib@v1.0.0 [direct]" + ansiReset + "\n",
+		"└── " + ansiBoldRed + "github.com/b/util@v0.2.0" + ansiReset + "\n",
+		ansiDim + "go@1.21" + ansiReset,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected output to contain %q, got:\n%q", want, out)
+		}
+	}
+
+	out, _ = Render("tree", g, RenderOptions{})
+	if strings.Contains(string(out), "\x1b[") {
+		t.Errorf("Expected no escape sequences without Color, got:\n%q", out)
+	}
+}
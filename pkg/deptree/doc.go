@@ -0,0 +1,23 @@
+// Package deptree reads Go module dependency graphs and renders them as
+// trees, flat lists and machine-readable formats.
+//
+// A typical embedding loads a graph, optionally enriches it with metadata,
+// and renders it:
+//
+//	g, err := deptree.Load(".", "")
+//	if err != nil {
+//		return err
+//	}
+//	out, err := deptree.Render("tree", g, deptree.RenderOptions{MaxDepth: 2})
+//
+// The package never prints. Problems that do not stop an analysis, such as
+// replace collisions or failed metadata lookups, are collected in
+// Graph.Warnings with a WarningKind, for the embedding application to
+// present.
+//
+// Functions that fetch metadata from the public services take an Options
+// with the embedder's own client and base URLs; those that only talk to
+// module proxies take the *http.Client to send requests with.
+//
+// The deptree command in cmd/deptree is a thin CLI over this package.
+package deptree
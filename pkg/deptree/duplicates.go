@@ -0,0 +1,99 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DuplicateModule is a module path the graph holds at more than one version.
+type DuplicateModule struct {
+	Path string
+	// Versions are ordered from lowest to highest.
+	Versions []DuplicateVersion
+}
+
+// DuplicateVersion is one version of a DuplicateModule and the modules
+// requiring exactly that version, sorted.
+type DuplicateVersion struct {
+	Version    string
+	RequiredBy []string
+}
+
+// Duplicates returns the module paths reachable from the root of g at more
+// than one version, sorted by path. Only the highest version of each is
+// normally selected by MVS, so the others bloat the graph without being
+// built.
+func Duplicates(g *Graph) []DuplicateModule {
+	if g.Root == nil {
+		return nil
+	}
+
+	reachable := map[string]bool{g.Root.Name: true}
+	versions := make(map[string]map[string]bool)
+	for _, name := range g.Reachable(g.Root.Name) {
+		reachable[name] = true
+		if path, version := SplitModule(name); version != "" {
+			if versions[path] == nil {
+				versions[path] = make(map[string]bool)
+			}
+			versions[path][version] = true
+		}
+	}
+
+	requiredBy := make(map[string][]string)
+	for from, tos := range g.Deps {
+		if !reachable[from] {
+			continue
+		}
+		for _, to := range tos {
+			if !slices.Contains(requiredBy[to], from) {
+				requiredBy[to] = append(requiredBy[to], from)
+			}
+		}
+	}
+
+	var duplicates []DuplicateModule
+	for _, path := range slices.Sorted(maps.Keys(versions)) {
+		if len(versions[path]) < 2 {
+			continue
+		}
+		d := DuplicateModule{Path: path}
+		for _, version := range slices.SortedFunc(maps.Keys(versions[path]), CompareVersions) {
+			parents := requiredBy[path+"@"+version]
+			slices.Sort(parents)
+			d.Versions = append(d.Versions, DuplicateVersion{Version: version, RequiredBy: parents})
+		}
+		duplicates = append(duplicates, d)
+	}
+	return duplicates
+}
+
+// RenderDuplicates formats the result of Duplicates, one block per module
+// with one line per version and the modules requiring it.
+func RenderDuplicates(g *Graph) []byte {
+	var buf bytes.Buffer
+	duplicates := Duplicates(g)
+	if len(duplicates) == 0 {
+		fmt.Fprintln(&buf, "No module appears at more than one version")
+		return buf.Bytes()
+	}
+
+	for i, d := range duplicates {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(&buf, "%s (%d versions)\n", d.Path, len(d.Versions))
+		width := 0
+		for _, v := range d.Versions {
+			width = max(width, len(v.Version))
+		}
+		for _, v := range d.Versions {
+			fmt.Fprintf(&buf, "  %-*s  required by %s\n", width, v.Version, strings.Join(v.RequiredBy, ", "))
+		}
+	}
+	fmt.Fprintf(&buf, "\n%d module(s) at more than one version\n", len(duplicates))
+	return buf.Bytes()
+}
@@ -0,0 +1,45 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDuplicates(t *testing.T) {
+	g := &Graph{Deps: map[string][]string{
+		"example.com/app":       {"github.com/a/x@v1.0.0", "golang.org/x/text@v0.14.0"},
+		"github.com/a/x@v1.0.0": {"golang.org/x/text@v0.3.0", "golang.org/x/text@v0.3.0"},
+		// Not reachable from the root
+		"github.com/old/y@v0.1.0": {"golang.org/x/text@v0.2.0"},
+	}}
+	g.Root = BuildDependencyTree(g.Deps, "")
+
+	want := []DuplicateModule{{
+		Path: "golang.org/x/text",
+		Versions: []DuplicateVersion{
+			{Version: "v0.3.0", RequiredBy: []string{"github.com/a/x@v1.0.0"}},
+			{Version: "v0.14.0", RequiredBy: []string{"example.com/app"}},
+		},
+	}}
+	if got := Duplicates(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("Duplicates = %+v, want %+v", got, want)
+	}
+
+	out := string(RenderDuplicates(g))
+	for _, line := range []string{
+		"golang.org/x/text (2 versions)\n",
+		"  v0.3.0   required by github.com/a/x@v1.0.0\n",
+		"  v0.14.0  required by example.com/app\n",
+		"\n1 module(s) at more than one version\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in:\n%s", line, out)
+		}
+	}
+
+	g.Deps["github.com/a/x@v1.0.0"] = nil
+	if out := string(RenderDuplicates(g)); !strings.HasPrefix(out, "No module appears") {
+		t.Errorf("Expected no duplicates, got:\n%s", out)
+	}
+}
@@ -0,0 +1,114 @@
+package deptree
+
+import (
+	"slices"
+	"sort"
+)
+
+// EdgeKind records how a requirement edge arises in the graph.
+type EdgeKind string
+
+const (
+	// EdgeDirect is a requirement listed in the root module's go.mod.
+	EdgeDirect EdgeKind = "direct"
+	// EdgeIndirect is a requirement the root module's go.mod marks // indirect.
+	EdgeIndirect EdgeKind = "indirect"
+	// EdgeTransitive is inherited through MVS from a dependency's go.mod.
+	EdgeTransitive EdgeKind = "transitive"
+	// EdgeReplace is a requirement of a module the main module's go.mod
+	// replaces. 'go mod graph' lists the replacement's requirements under
+	// the replaced module, so the edge comes from the replacement's go.mod
+	// and not from the replaced version's own.
+	EdgeReplace EdgeKind = "replace"
+	// EdgeExclude is a requirement raised above the version the requiring
+	// module's go.mod names, because the main module's go.mod excludes
+	// that version.
+	EdgeExclude EdgeKind = "exclude"
+)
+
+// Edge is one requirement from one module to another.
+type Edge struct {
+	From string
+	To   string
+	Kind EdgeKind
+	// Replace is the replacement target when the main module's go.mod
+	// replaces To, and empty otherwise.
+	Replace string
+}
+
+// Edges returns every requirement edge in the graph, annotated with its kind,
+// ordered by source and then target. Toolchain entries and synthetic modules
+// are left out.
+func (g *Graph) Edges() []Edge {
+	var edges []Edge
+
+	for from, tos := range g.Deps {
+		if isSyntheticModule(from) || IsToolchainDep(from) {
+			continue
+		}
+		for _, to := range tos {
+			if IsToolchainDep(to) {
+				continue
+			}
+			edges = append(edges, g.classifyEdge(from, to))
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+
+	return edges
+}
+
+// classifyEdge tells the kind of the requirement from -> to. Replace and
+// exclude edges are told apart from transitive ones with the go.mod of
+// from in g.ModFiles; without it, every requirement of a replaced module
+// counts as a replace edge and exclusions go unnoticed.
+func (g *Graph) classifyEdge(from, to string) Edge {
+	edge := Edge{From: from, To: to, Kind: EdgeTransitive}
+	path, version := SplitModule(to)
+
+	if mf := g.mainModFile(from); mf != nil || (g.Root != nil && from == g.Root.Name) {
+		edge.Kind = EdgeDirect
+		if mf != nil {
+			if req, ok := mf.FindRequire(path); ok && req.Indirect {
+				edge.Kind = EdgeIndirect
+			}
+		}
+	} else if g.ModFile != nil {
+		own := g.ModFiles[from]
+		req, required := ModRequire{}, false
+		if own != nil {
+			req, required = own.FindRequire(path)
+		}
+		switch {
+		case g.replaced(from):
+			if !required || req.Version != version {
+				edge.Kind = EdgeReplace
+			}
+		case required && req.Version != version && slices.Contains(g.ModFile.Exclude, ModVersion{Path: path, Version: req.Version}):
+			edge.Kind = EdgeExclude
+		}
+	}
+
+	// Only the main module's replace directives take effect
+	if g.ModFile != nil {
+		if rep, ok := g.ModFile.FindReplace(path, version); ok {
+			edge.Replace = rep.String()
+		}
+	}
+
+	return edge
+}
+
+// replaced reports whether the main module's go.mod replaces the module
+// version name.
+func (g *Graph) replaced(name string) bool {
+	path, version := SplitModule(name)
+	_, ok := g.ModFile.FindReplace(path, version)
+	return ok
+}
@@ -0,0 +1,117 @@
+package deptree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphEdges(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"dep1@v1.0.0", "dep2@v1.0.0", "go@1.21.0"},
+			"dep1@v1.0.0": {"dep3@v1.0.0"},
+		},
+		ModFile: &ModFile{
+			Require: []ModRequire{
+				{Path: "dep1", Version: "v1.0.0"},
+				{Path: "dep2", Version: "v1.0.0", Indirect: true},
+			},
+			Replace: []ModReplace{
+				{Old: ModVersion{Path: "dep3"}, New: ModVersion{Path: "../dep3"}},
+			},
+		},
+	}
+	g.RootModFile = g.ModFile
+
+	edges := g.Edges()
+	if len(edges) != 3 {
+		t.Fatalf("Expected 3 edges without toolchain entries, got %d: %+v", len(edges), edges)
+	}
+
+	expected := []Edge{
+		{From: "dep1@v1.0.0", To: "dep3@v1.0.0", Kind: EdgeTransitive, Replace: "../dep3"},
+		{From: "mymodule", To: "dep1@v1.0.0", Kind: EdgeDirect},
+		{From: "mymodule", To: "dep2@v1.0.0", Kind: EdgeIndirect},
+	}
+	for i, want := range expected {
+		if edges[i] != want {
+			t.Errorf("edges[%d] = %+v, want %+v", i, edges[i], want)
+		}
+	}
+}
+
+func TestGraphEdgesReplaceAndExclude(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"fork@v1.0.0", "dep1@v1.0.0", "dep2@v1.0.0"},
+			"fork@v1.0.0": {"shared@v1.0.0", "extra@v1.0.0"},
+			"dep1@v1.0.0": {"lib@v1.2.0"},
+			"dep2@v1.0.0": {"lib@v1.2.0"},
+		},
+		ModFile: &ModFile{
+			Require: []ModRequire{{Path: "fork", Version: "v1.0.0"}, {Path: "dep1", Version: "v1.0.0"}, {Path: "dep2", Version: "v1.0.0"}},
+			Replace: []ModReplace{{Old: ModVersion{Path: "fork"}, New: ModVersion{Path: "../fork"}}},
+			Exclude: []ModVersion{{Path: "lib", Version: "v1.1.0"}},
+		},
+		ModFiles: map[string]*ModFile{
+			// The replaced version requires shared, but not extra
+			"fork@v1.0.0": {Require: []ModRequire{{Path: "shared", Version: "v1.0.0"}}},
+			// dep1 requires the excluded lib v1.1.0, dep2 requires v1.2.0
+			"dep1@v1.0.0": {Require: []ModRequire{{Path: "lib", Version: "v1.1.0"}}},
+			"dep2@v1.0.0": {Require: []ModRequire{{Path: "lib", Version: "v1.2.0"}}},
+		},
+	}
+	g.RootModFile = g.ModFile
+
+	kinds := make(map[string]EdgeKind)
+	for _, edge := range g.Edges() {
+		kinds[edge.From+" "+edge.To] = edge.Kind
+	}
+	for edge, want := range map[string]EdgeKind{
+		"fork@v1.0.0 shared@v1.0.0": EdgeTransitive,
+		"fork@v1.0.0 extra@v1.0.0":  EdgeReplace,
+		"dep1@v1.0.0 lib@v1.2.0":    EdgeExclude,
+		"dep2@v1.0.0 lib@v1.2.0":    EdgeTransitive,
+		"mymodule fork@v1.0.0":      EdgeDirect,
+	} {
+		if kinds[edge] != want {
+			t.Errorf("kind of %s = %q, want %q", edge, kinds[edge], want)
+		}
+	}
+
+	// Without the replaced version's go.mod, all its requirements come
+	// from the replacement
+	g.ModFiles = nil
+	for _, edge := range g.Edges() {
+		if edge.From == "fork@v1.0.0" && edge.Kind != EdgeReplace {
+			t.Errorf("kind of %s %s = %q, want %q", edge.From, edge.To, edge.Kind, EdgeReplace)
+		}
+		if edge.From == "dep1@v1.0.0" && edge.Kind != EdgeTransitive {
+			t.Errorf("kind of %s %s = %q, want %q", edge.From, edge.To, edge.Kind, EdgeTransitive)
+		}
+	}
+
+	out, err := jsonRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(string(out), `"kind": "replace"`) {
+		t.Errorf("Expected a replace edge in JSON, got:\n%s", out)
+	}
+
+	g.ModFiles = map[string]*ModFile{"dep1@v1.0.0": {Require: []ModRequire{{Path: "lib", Version: "v1.1.0"}}}}
+	out, err = dotRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		NodeID("fork@v1.0.0") + " -> " + NodeID("extra@v1.0.0") + ` [kind="replace", style=dotted, color=blue];`,
+		NodeID("dep1@v1.0.0") + " -> " + NodeID("lib@v1.2.0") + ` [kind="exclude", style=dotted, color=orange];`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %q in DOT, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package deptree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var githubAPIURL = "https://api.github.com"
+
+// errNoDescription is returned for repositories without a description.
+var errNoDescription = errors.New("no description set")
+
+// GitHubRepo is the subset of the GitHub repository API response deptree uses.
+type GitHubRepo struct {
+	Description     string         `json:"description"`
+	HTMLURL         string         `json:"html_url"`
+	Homepage        string         `json:"homepage"`
+	Archived        bool           `json:"archived"`
+	StargazersCount int            `json:"stargazers_count"`
+	PushedAt        time.Time      `json:"pushed_at"`
+	License         *GitHubLicense `json:"license"`
+}
+
+// GitHubUser is the subset of the GitHub user and organization API response
+// deptree uses.
+type GitHubUser struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+	// Location is the free-form location the account states on its
+	// profile, if any.
+	Location string `json:"location"`
+}
+
+// GitHubContributor is one entry of the GitHub repository contributors API
+// response.
+type GitHubContributor struct {
+	Login         string `json:"login"`
+	Contributions int    `json:"contributions"`
+}
+
+// GitHubLicense is the license GitHub detected for a repository.
+type GitHubLicense struct {
+	SPDXID string `json:"spdx_id"`
+	Name   string `json:"name"`
+}
+
+func extractGitHubRepo(modulePath string) (owner, repo string, ok bool) {
+	return extractHostedRepo(modulePath, "github.com")
+}
+
+// extractHostedRepo returns the owner and repository of a module path on
+// host, whose repositories are the first two path elements like on GitHub.
+func extractHostedRepo(modulePath, host string) (owner, repo string, ok bool) {
+	// Remove version suffix if present
+	parts := strings.Split(modulePath, "@")
+	path := parts[0]
+
+	// Check if the module is on host
+	if !strings.HasPrefix(path, host+"/") {
+		return "", "", false
+	}
+
+	// Extract owner and repo (handle subpackages)
+	pathParts := strings.Split(strings.TrimPrefix(path, host+"/"), "/")
+	if len(pathParts) < 2 {
+		return "", "", false
+	}
+
+	return pathParts[0], pathParts[1], true
+}
+
+// FetchGitHubDescription returns the repository description of a
+// GitHub-hosted module.
+func FetchGitHubDescription(opts Options, modulePath, token string) (string, error) {
+	return NewGitHubClient(opts, token).Description(modulePath)
+}
+
+// FetchGitHubRepo fetches the repository metadata of a GitHub-hosted module.
+func FetchGitHubRepo(opts Options, modulePath, token string) (*GitHubRepo, error) {
+	return NewGitHubClient(opts, token).Repo(modulePath)
+}
+
+// githubWorkers bounds concurrent GitHub API requests.
+const githubWorkers = 8
+
+const (
+	// githubMaxRetries is how often a transient failure is retried.
+	githubMaxRetries = 3
+	// githubMaxWait is the longest deptree waits for a rate limit to reset
+	// before giving up.
+	githubMaxWait = time.Minute
+)
+
+// RateLimitError reports that the GitHub API rate limit is exhausted and
+// does not reset soon enough to wait for it.
+type RateLimitError struct {
+	// Reset is when the limit resets, if GitHub said so.
+	Reset time.Time
+	// Unauthenticated is set for requests sent without a token, which
+	// have a much lower limit.
+	Unauthenticated bool
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "GitHub API rate limit exceeded"
+	}
+	return fmt.Sprintf("GitHub API rate limit exceeded until %s", e.Reset.Local().Format("15:04:05"))
+}
+
+// GitHubClient fetches repository metadata from the GitHub API. It honors
+// the X-RateLimit-Remaining, X-RateLimit-Reset and Retry-After headers,
+// pausing all requests while the rate limit is exhausted, and retries
+// network errors and server errors with exponential backoff. It is safe for
+// concurrent use.
+type GitHubClient struct {
+	client *http.Client
+	token  string
+	// apiURL is the base URL of the API, and enterpriseHost the host of
+	// the modules it serves besides github.com, if it is GitHub
+	// Enterprise.
+	apiURL         string
+	enterpriseHost string
+
+	// sleep and now are replaced in tests.
+	sleep func(time.Duration)
+	now   func() time.Time
+
+	mu sync.Mutex
+	// resumeAt is when requests may be sent again after the rate limit ran
+	// out.
+	resumeAt time.Time
+	// exhausted is set when the rate limit ran out without saying when it
+	// resets; no more requests are sent then.
+	exhausted bool
+}
+
+// NewGitHubClient returns a client that sends requests to the GitHub API
+// at opts.GitHubAPIURL through opts.HTTPClient, authenticated with token if
+// it is not empty.
+func NewGitHubClient(opts Options, token string) *GitHubClient {
+	return &GitHubClient{
+		client:         opts.client(),
+		token:          token,
+		apiURL:         opts.githubAPIURL(),
+		enterpriseHost: opts.githubEnterpriseHost(),
+		sleep:          time.Sleep,
+		now:            time.Now,
+	}
+}
+
+// repoOf returns the owner and repository of a module hosted on github.com
+// or on the GitHub Enterprise host c serves.
+func (c *GitHubClient) repoOf(modulePath string) (owner, repo string, ok bool) {
+	if owner, repo, ok := extractGitHubRepo(modulePath); ok || c.enterpriseHost == "" {
+		return owner, repo, ok
+	}
+	return extractHostedRepo(modulePath, c.enterpriseHost)
+}
+
+// Description returns the repository description of a GitHub-hosted module.
+func (c *GitHubClient) Description(modulePath string) (string, error) {
+	ghRepo, err := c.Repo(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	if ghRepo.Description == "" {
+		return "", errNoDescription
+	}
+
+	return ghRepo.Description, nil
+}
+
+// Repo fetches the repository metadata of a GitHub-hosted module.
+func (c *GitHubClient) Repo(modulePath string) (*GitHubRepo, error) {
+	owner, repo, ok := c.repoOf(modulePath)
+	if !ok {
+		return nil, fmt.Errorf("not a GitHub module")
+	}
+
+	var ghRepo GitHubRepo
+	if err := c.fetch(fmt.Sprintf("%s/repos/%s/%s", c.apiURL, owner, repo), &ghRepo); err != nil {
+		return nil, err
+	}
+	return &ghRepo, nil
+}
+
+// Contributors fetches up to limit of the top contributors to the
+// repository of a GitHub-hosted module, most active first.
+func (c *GitHubClient) Contributors(modulePath string, limit int) ([]GitHubContributor, error) {
+	owner, repo, ok := c.repoOf(modulePath)
+	if !ok {
+		return nil, fmt.Errorf("not a GitHub module")
+	}
+
+	var contributors []GitHubContributor
+	if err := c.fetch(fmt.Sprintf("%s/repos/%s/%s/contributors?per_page=%d", c.apiURL, owner, repo, limit), &contributors); err != nil {
+		return nil, err
+	}
+	return contributors, nil
+}
+
+// User fetches the profile of a GitHub user or organization.
+func (c *GitHubClient) User(login string) (*GitHubUser, error) {
+	var user GitHubUser
+	if err := c.fetch(fmt.Sprintf("%s/users/%s", c.apiURL, login), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// fetch decodes the JSON response for url into v, waiting for the rate limit
+// and retrying transient failures.
+func (c *GitHubClient) fetch(url string, v any) error {
+	var lastErr error
+	for attempt := 0; attempt <= githubMaxRetries; attempt++ {
+		if attempt > 0 {
+			c.sleep(time.Second << (attempt - 1))
+		}
+		if err := c.waitForRateLimit(); err != nil {
+			return err
+		}
+
+		retry, err := c.get(url, v)
+		if !retry {
+			return err
+		}
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// get performs one request and reports whether a failure is worth retrying.
+func (c *GitHubClient) get(url string, v any) (bool, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set User-Agent to avoid GitHub API rate limiting issues
+	req.Header.Set("User-Agent", "deptree-cli")
+
+	// Add authentication if token is provided
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if errors.Is(err, ErrBudgetExceeded) {
+		return false, ErrBudgetExceeded
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to fetch from GitHub API: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	limited := c.recordRateLimit(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case limited:
+		// Retried after waiting for the reset, unless that is too far off
+		err := c.rateLimitError()
+		return err.Reset.Sub(c.now()) <= githubMaxWait, err
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		// Secondary rate limits, and proxies dropping the rate limit
+		// headers, leave only the message to tell a rate limit by
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if bytes.Contains(bytes.ToLower(body), []byte("rate limit")) {
+			c.mu.Lock()
+			c.exhausted = true
+			c.mu.Unlock()
+			return false, c.rateLimitError()
+		}
+		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	case resp.StatusCode >= 500:
+		return true, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return false, nil
+}
+
+// recordRateLimit pauses further requests when resp says the rate limit is
+// exhausted, and reports whether resp was rejected because of it.
+func (c *GitHubClient) recordRateLimit(resp *http.Response) bool {
+	var resume time.Time
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		resume = c.now().Add(time.Duration(seconds) * time.Second)
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			resume = time.Unix(reset, 0)
+		}
+	}
+
+	if resume.IsZero() {
+		return false
+	}
+
+	c.mu.Lock()
+	if resume.After(c.resumeAt) {
+		c.resumeAt = resume
+	}
+	c.mu.Unlock()
+
+	return resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// waitForRateLimit blocks until the rate limit has reset, or fails if that
+// is more than githubMaxWait away.
+func (c *GitHubClient) waitForRateLimit() error {
+	c.mu.Lock()
+	resumeAt, exhausted := c.resumeAt, c.exhausted
+	c.mu.Unlock()
+
+	if exhausted {
+		return c.rateLimitError()
+	}
+	wait := resumeAt.Sub(c.now())
+	if wait <= 0 {
+		return nil
+	}
+	if wait > githubMaxWait {
+		return c.rateLimitError()
+	}
+	c.sleep(wait)
+	return nil
+}
+
+// rateLimitError returns the error requests fail with while the rate limit
+// is exhausted.
+func (c *GitHubClient) rateLimitError() *RateLimitError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := &RateLimitError{Reset: c.resumeAt, Unauthenticated: c.token == ""}
+	if c.exhausted && !c.resumeAt.After(c.now()) {
+		err.Reset = time.Time{}
+	}
+	return err
+}
+
+// FetchDescriptions fetches the description of every module in g (see
+// FetchDescription) using a bounded number of concurrent requests and stores
+// it in g.Descriptions and on the tree nodes, and the links to each module's
+// repository in g.Links. Failures are recorded in
+// g.DescriptionErrors and stored as a parenthesized error message in place of
+// the description, except for the GitHub rate limit running out, which is
+// reported as one warning. Descriptions found in cache are not
+// fetched again, and newly fetched ones are added to it; cache may be nil.
+// Modules skipped because the client's time budget ran out are listed in
+// g.Partial.
+func FetchDescriptions(g *Graph, opts Options, token string, cache *DescriptionCache) {
+	FetchDescriptionsProgress(g, opts, token, cache, nil)
+}
+
+// FetchDescriptionsProgress is FetchDescriptions, calling progress with the
+// number of modules done and the total after each module when progress is
+// not nil. Calls are not concurrent.
+func FetchDescriptionsProgress(g *Graph, opts Options, token string, cache *DescriptionCache, progress func(done, total int)) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, githubWorkers)
+	describer := newDescriber(opts, token)
+
+	// Collect all unique modules; a module may occur at several places in the tree
+	modules := make(map[string]bool)
+	for _, name := range g.Modules() {
+		modules[name] = true
+	}
+	g.Walk(func(node *Node) {
+		modules[node.Name] = true
+	})
+
+	g.Descriptions = make(map[string]string)
+	g.DescriptionErrors = make(map[string]error)
+	g.Links = make(map[string]SourceLinks)
+	done := 0
+
+	// Fetch descriptions concurrently
+	for name := range modules {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			desc, links, cached := cache.GetSource(name)
+			var err error
+			if cached {
+				if desc == "" {
+					err = errNoDescription
+				}
+			} else {
+				desc, links, err = describer.Describe(name)
+				switch {
+				case err == nil:
+					cache.PutSource(name, desc, links)
+				case errors.Is(err, errNoDescription):
+					cache.PutSource(name, "", links)
+				}
+			}
+
+			mu.Lock()
+			if links != (SourceLinks{}) {
+				g.Links[name] = links
+			}
+			if errors.Is(err, ErrBudgetExceeded) {
+				g.markPartial(name)
+			}
+			var rateLimitErr *RateLimitError
+			switch {
+			case errors.As(err, &rateLimitErr):
+				// Reported once for all modules by warnFailures below
+				g.DescriptionErrors[name] = err
+			case err != nil:
+				// Store error message as description for display
+				g.Descriptions[name] = fmt.Sprintf("(%s)", err.Error())
+				g.DescriptionErrors[name] = err
+			default:
+				g.Descriptions[name] = desc
+			}
+			done++
+			if progress != nil {
+				progress(done, len(modules))
+			}
+			mu.Unlock()
+		}(name)
+	}
+
+	wg.Wait()
+	failed := make(map[string]error)
+	for name, err := range g.DescriptionErrors {
+		if !errors.Is(err, errNoDescription) && !errors.Is(err, ErrBudgetExceeded) {
+			failed[name] = err
+		}
+	}
+	g.warnFailures("description", failed)
+	g.syncDescriptions()
+}
@@ -0,0 +1,210 @@
+package deptree
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFetchGitHubRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/spf13/cobra" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		w.Write([]byte(`{"description":"A Commander","archived":true,"license":{"spdx_id":"Apache-2.0"}}`))
+	}))
+	defer server.Close()
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldURL }()
+
+	repo, err := FetchGitHubRepo(Options{HTTPClient: server.Client()}, "github.com/spf13/cobra/doc@v1.8.0", "secret")
+	if err != nil {
+		t.Fatalf("FetchGitHubRepo failed: %v", err)
+	}
+
+	if repo.Description != "A Commander" || !repo.Archived || repo.License.SPDXID != "Apache-2.0" {
+		t.Errorf("Unexpected repository metadata: %+v", repo)
+	}
+
+	if _, err := FetchGitHubRepo(Options{HTTPClient: server.Client()}, "gopkg.in/yaml.v3@v3.0.1", ""); err == nil {
+		t.Error("Expected error for non-GitHub module")
+	}
+}
+
+// testGitHubClient returns a client for server that records its sleeps
+// instead of sleeping.
+func testGitHubClient(t *testing.T, server *httptest.Server, now time.Time) (*GitHubClient, *[]time.Duration) {
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = oldURL })
+
+	var slept []time.Duration
+	c := NewGitHubClient(Options{HTTPClient: server.Client()}, "")
+	c.now = func() time.Time { return now }
+	c.sleep = func(d time.Duration) { slept = append(slept, d) }
+	return c, &slept
+}
+
+func TestGitHubClientRetriesServerErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"description":"A Commander"}`))
+	}))
+	defer server.Close()
+
+	c, slept := testGitHubClient(t, server, time.Now())
+	desc, err := c.Description("github.com/spf13/cobra")
+	if err != nil || desc != "A Commander" {
+		t.Fatalf("Description() = %q, %v", desc, err)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(*slept, want) {
+		t.Errorf("Expected exponential backoff %v, got %v", want, *slept)
+	}
+}
+
+func TestGitHubClientDoesNotRetryNotFound(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c, _ := testGitHubClient(t, server, time.Now())
+	if _, err := c.Repo("github.com/gone/repo"); err == nil {
+		t.Error("Expected error for missing repository")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+}
+
+func TestGitHubClientHonorsRetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"description":"A Commander"}`))
+	}))
+	defer server.Close()
+
+	c, slept := testGitHubClient(t, server, time.Now())
+	if _, err := c.Repo("github.com/spf13/cobra"); err != nil {
+		t.Fatalf("Repo failed: %v", err)
+	}
+	// Backoff before the retry, then the rest of the Retry-After pause
+	if len(*slept) != 2 || (*slept)[1] != 30*time.Second {
+		t.Errorf("Expected to wait for Retry-After, got %v", *slept)
+	}
+}
+
+func TestGitHubClientRateLimitExhausted(t *testing.T) {
+	now := time.Now()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+		w.Write([]byte(`{"description":"last one"}`))
+	}))
+	defer server.Close()
+
+	c, _ := testGitHubClient(t, server, now)
+	if _, err := c.Repo("github.com/spf13/cobra"); err != nil {
+		t.Fatalf("Expected the request using the last quota to succeed: %v", err)
+	}
+
+	// The limit resets in an hour, too long to wait for
+	_, err := c.Repo("github.com/spf13/pflag")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no request while rate limited, got %d", requests)
+	}
+}
+
+func TestGitHubClientRateLimitMessage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/private/repo":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Resource not accessible"}`))
+		default:
+			// A secondary rate limit, without X-RateLimit headers
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+		}
+	}))
+	defer server.Close()
+
+	c, _ := testGitHubClient(t, server, time.Now())
+	if _, err := c.Repo("github.com/private/repo"); err == nil || err.Error() != "GitHub API returned status 403" {
+		t.Errorf("Expected a plain 403 for other messages, got %v", err)
+	}
+
+	_, err := c.Repo("github.com/spf13/cobra")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || !rateLimitErr.Unauthenticated {
+		t.Fatalf("Expected an unauthenticated RateLimitError, got %v", err)
+	}
+	if _, err := c.Repo("github.com/spf13/pflag"); !errors.As(err, &rateLimitErr) {
+		t.Errorf("Expected RateLimitError once exhausted, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected no request after the rate limit ran out, got %d", requests)
+	}
+}
+
+func TestFetchDescriptionsRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldURL }()
+
+	g := &Graph{
+		Root: NewNode("myapp"),
+		Deps: map[string][]string{"myapp": {"github.com/a/one@v1.0.0", "github.com/a/two@v1.0.0"}},
+	}
+	FetchDescriptions(g, Options{HTTPClient: server.Client()}, "", nil)
+
+	for _, name := range []string{"github.com/a/one@v1.0.0", "github.com/a/two@v1.0.0"} {
+		if desc := g.Descriptions[name]; desc != "" {
+			t.Errorf("Expected no per-module message for %s, got %q", name, desc)
+		}
+	}
+	want := Warning{Kind: WarnRateLimit, Message: fmt.Sprintf("GitHub API rate limit exceeded until %s, skipped the description of 2 module(s); without a GitHub token the limit is 60 requests per hour", time.Unix(reset.Unix(), 0).Local().Format("15:04:05"))}
+	if !slices.Contains(g.Warnings, want) {
+		t.Errorf("Warnings = %v, want %v", g.Warnings, want)
+	}
+}
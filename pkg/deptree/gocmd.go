@@ -0,0 +1,65 @@
+package deptree
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// goCommand returns the go command with args, to run in dir with the
+// environment goEnv gives it.
+func goCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = goEnv(dir)
+	return cmd
+}
+
+// goEnv returns the environment the go command runs with in dir. GOMODCACHE
+// is set to the resolved module cache, so every go command deptree runs,
+// including those in the temp modules of different -package runs, shares
+// the one cache ModuleCacheFile reads from. Temp modules set up by
+// SetupPackage are also cut off from the user's workspace and GOFLAGS,
+// which are meant for their own modules: a -mod=vendor or go.work there
+// would make 'go get' and 'go mod graph' fail or read the wrong graph.
+func goEnv(dir string) []string {
+	env := os.Environ()
+	if cache, err := goModCache(); err == nil && cache != "" {
+		env = append(env, "GOMODCACHE="+cache)
+	}
+	if isTempModule(dir) {
+		env = append(env, "GOWORK=off", "GOFLAGS=-mod=mod")
+	}
+	return env
+}
+
+// isTempModule reports whether dir holds the "temp" module of
+// SetupPackage.
+func isTempModule(dir string) bool {
+	if dir == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return false
+	}
+	mf, err := ParseModFile(data)
+	return err == nil && mf.Module.Path == "temp"
+}
+
+// goSettings returns the named go environment variables as the go command
+// resolves them from the environment, the 'go env -w' configuration file
+// and its defaults. Without a go command, as with -engine proxy on a
+// machine without Go, they are read from the environment alone.
+func goSettings(names ...string) map[string]string {
+	settings := make(map[string]string)
+	output, err := exec.Command("go", append([]string{"env", "-json"}, names...)...).Output()
+	if err == nil && json.Unmarshal(output, &settings) == nil {
+		return settings
+	}
+	for _, name := range names {
+		settings[name] = os.Getenv(name)
+	}
+	return settings
+}
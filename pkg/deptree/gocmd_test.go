@@ -0,0 +1,32 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGoEnvIsolatesTempModules(t *testing.T) {
+	temp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(temp, "go.mod"), []byte("module temp\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "go.mod"), []byte("module example.com/project\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only what goEnv adds to the inherited environment counts
+	env := goEnv(temp)[len(os.Environ()):]
+	for _, want := range []string{"GOWORK=off", "GOFLAGS=-mod=mod"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("Expected %s in the environment of a temp module", want)
+		}
+	}
+
+	env = goEnv(project)[len(os.Environ()):]
+	if slices.Contains(env, "GOWORK=off") || slices.Contains(env, "GOFLAGS=-mod=mod") {
+		t.Error("Expected the workspace and GOFLAGS of a project to be left alone")
+	}
+}
@@ -0,0 +1,342 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ModFile is the subset of 'go mod edit -json' output deptree uses.
+type ModFile struct {
+	Module struct {
+		Path string
+		// Deprecated is the text of the module's "Deprecated:" comment.
+		Deprecated string
+	}
+	Go string
+	// Toolchain is the toolchain directive, such as "go1.22.1".
+	Toolchain struct {
+		Name string
+	}
+	Require []ModRequire
+	Replace []ModReplace
+	Exclude []ModVersion
+	Retract []ModRetract
+	// Tool are the tool directives, read by 'go mod edit -json' only.
+	Tool []ModTool
+}
+
+// ModVersion is a module path with an optional version.
+type ModVersion struct {
+	Path    string
+	Version string
+}
+
+// ModRequire is one require directive.
+type ModRequire struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// ModTool is one tool directive, naming the package of a tool the module
+// runs with 'go tool'.
+type ModTool struct {
+	Path string
+}
+
+// ModRetract is one retract directive, covering the versions from Low to
+// High inclusive. Low and High are equal for a single version.
+type ModRetract struct {
+	Low       string `json:"low"`
+	High      string `json:"high"`
+	Rationale string `json:"rationale,omitempty"`
+}
+
+// ModReplace is one replace directive.
+type ModReplace struct {
+	Old ModVersion
+	New ModVersion
+}
+
+// ReadModFile parses the go.mod of the module in dir.
+func ReadModFile(dir string) (*ModFile, error) {
+	return parseModFile(dir, "")
+}
+
+// ReadCachedModFile parses the go.mod of path@version from the module cache.
+// The module must already have been downloaded.
+func ReadCachedModFile(path, version string) (*ModFile, error) {
+	file, err := ModuleCacheFile(path, version, "mod")
+	if err != nil {
+		return nil, err
+	}
+	return parseModFile("", file)
+}
+
+func parseModFile(dir, file string) (*ModFile, error) {
+	args := []string{"mod", "edit", "-json"}
+	if file != "" {
+		args = append(args, file)
+	}
+	output, err := goCommand(dir, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'go mod edit -json': %w", err)
+	}
+
+	var mf ModFile
+	if err := json.Unmarshal(output, &mf); err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	return &mf, nil
+}
+
+// ParseModFile parses go.mod content without the go command, reading the
+// module path and deprecation notice, the go and toolchain versions and the
+// require, replace, exclude and retract directives into the same form 'go mod edit
+// -json' produces. Other directives are skipped.
+func ParseModFile(data []byte) (*ModFile, error) {
+	var mf ModFile
+	block := ""
+	// comments holds the comment block right above the current line
+	var comments []string
+
+	for i, line := range strings.Split(string(data), "\n") {
+		code, comment, hasComment := strings.Cut(line, "//")
+		comment = strings.TrimSpace(comment)
+		fields := strings.Fields(code)
+		if len(fields) == 0 {
+			if hasComment {
+				comments = append(comments, comment)
+			} else {
+				comments = nil
+			}
+			continue
+		}
+		leading := comments
+		comments = nil
+
+		verb := block
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			verb, fields = fields[0], fields[1:]
+		}
+
+		args := make([]string, len(fields))
+		for j, f := range fields {
+			arg, err := unquoteModToken(f)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			args[j] = arg
+		}
+
+		switch verb {
+		case "module":
+			if len(args) != 1 {
+				return nil, fmt.Errorf("line %d: malformed module directive", i+1)
+			}
+			mf.Module.Path = args[0]
+			mf.Module.Deprecated = deprecationNotice(append(leading, comment))
+		case "go":
+			if len(args) == 1 {
+				mf.Go = args[0]
+			}
+		case "toolchain":
+			if len(args) == 1 {
+				mf.Toolchain.Name = args[0]
+			}
+		case "require":
+			if len(args) != 2 {
+				return nil, fmt.Errorf("line %d: malformed require directive", i+1)
+			}
+			indirect := comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+			mf.Require = append(mf.Require, ModRequire{Path: args[0], Version: args[1], Indirect: indirect})
+		case "exclude":
+			if len(args) != 2 {
+				return nil, fmt.Errorf("line %d: malformed exclude directive", i+1)
+			}
+			mf.Exclude = append(mf.Exclude, ModVersion{Path: args[0], Version: args[1]})
+		case "replace":
+			arrow := slices.Index(args, "=>")
+			if arrow < 1 || arrow > 2 || len(args)-arrow-1 < 1 || len(args)-arrow-1 > 2 {
+				return nil, fmt.Errorf("line %d: malformed replace directive", i+1)
+			}
+			rep := ModReplace{Old: ModVersion{Path: args[0]}, New: ModVersion{Path: args[arrow+1]}}
+			if arrow == 2 {
+				rep.Old.Version = args[1]
+			}
+			if len(args) == arrow+3 {
+				rep.New.Version = args[arrow+2]
+			}
+			mf.Replace = append(mf.Replace, rep)
+		case "retract":
+			retract, ok := parseRetract(strings.Join(args, " "))
+			if !ok {
+				return nil, fmt.Errorf("line %d: malformed retract directive", i+1)
+			}
+			if comment != "" {
+				retract.Rationale = comment
+			} else {
+				retract.Rationale = strings.Join(leading, "\n")
+			}
+			mf.Retract = append(mf.Retract, retract)
+		}
+	}
+
+	return &mf, nil
+}
+
+// parseRetract parses the version or "[low, high]" interval of a retract
+// directive.
+func parseRetract(arg string) (ModRetract, bool) {
+	if interval, ok := strings.CutPrefix(arg, "["); ok {
+		interval, ok = strings.CutSuffix(interval, "]")
+		low, high, found := strings.Cut(interval, ",")
+		if !ok || !found {
+			return ModRetract{}, false
+		}
+		return ModRetract{Low: strings.TrimSpace(low), High: strings.TrimSpace(high)}, true
+	}
+	if arg == "" || strings.Contains(arg, " ") {
+		return ModRetract{}, false
+	}
+	return ModRetract{Low: arg, High: arg}, true
+}
+
+// deprecationNotice returns the text of the paragraph starting with
+// "Deprecated:" in the comments of a module directive, or "".
+func deprecationNotice(comments []string) string {
+	var paragraph []string
+	for _, line := range append(comments, "") {
+		if line != "" {
+			paragraph = append(paragraph, line)
+			continue
+		}
+		if len(paragraph) > 0 {
+			if text, ok := strings.CutPrefix(paragraph[0], "Deprecated:"); ok {
+				return strings.TrimSpace(strings.Join(append([]string{text}, paragraph[1:]...), " "))
+			}
+		}
+		paragraph = nil
+	}
+	return ""
+}
+
+// unquoteModToken unquotes a go.mod token written as an interpreted or raw
+// string literal and returns other tokens unchanged.
+func unquoteModToken(token string) (string, error) {
+	if strings.HasPrefix(token, `"`) || strings.HasPrefix(token, "`") {
+		return strconv.Unquote(token)
+	}
+	return token, nil
+}
+
+var goModCache = sync.OnceValues(func() (string, error) {
+	output, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run 'go env GOMODCACHE': %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+})
+
+// ModuleCacheFile returns the path of the .mod, .info, .zip or .ziphash file
+// of path@version in the module cache download directory.
+func ModuleCacheFile(path, version, ext string) (string, error) {
+	cache, err := goModCache()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "cache", "download", filepath.FromSlash(escapeModulePath(path)), "@v", escapeModulePath(version)+"."+ext), nil
+}
+
+// ModuleCacheDir returns the directory path@version is extracted to in the
+// module cache. It only exists once the module's source has been downloaded.
+func ModuleCacheDir(path, version string) (string, error) {
+	cache, err := goModCache()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, filepath.FromSlash(escapeModulePath(path))+"@"+escapeModulePath(version)), nil
+}
+
+// escapeModulePath applies the module cache case encoding, where each
+// upper-case letter is replaced by an exclamation mark and its lower case.
+func escapeModulePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unescapeModulePath reverses escapeModulePath.
+func unescapeModulePath(s string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range s {
+		switch {
+		case r == '!':
+			upper = true
+			continue
+		case upper:
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// FindRequire returns the requirement for the given module path, if any.
+func (mf *ModFile) FindRequire(path string) (ModRequire, bool) {
+	for _, req := range mf.Require {
+		if req.Path == path {
+			return req, true
+		}
+	}
+	return ModRequire{}, false
+}
+
+// FindReplace returns the replacement applied to path@version, if any. A
+// replace without an old version applies to every version of the module.
+func (mf *ModFile) FindReplace(path, version string) (ModVersion, bool) {
+	for _, rep := range mf.Replace {
+		if rep.Old.Path == path && (rep.Old.Version == "" || rep.Old.Version == version) {
+			return rep.New, true
+		}
+	}
+	return ModVersion{}, false
+}
+
+// SplitModule splits a 'go mod graph' node such as "example.com/m@v1.2.3"
+// into its path and version. The main module has no version.
+func SplitModule(name string) (path, version string) {
+	path, version, _ = strings.Cut(name, "@")
+	return path, version
+}
+
+// String formats v as path or path@version.
+func (v ModVersion) String() string {
+	if v.Version == "" {
+		return v.Path
+	}
+	return v.Path + "@" + v.Version
+}
@@ -0,0 +1,179 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEscapeModulePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"github.com/spf13/cobra", "github.com/spf13/cobra"},
+		{"github.com/BurntSushi/toml", "github.com/!burnt!sushi/toml"},
+		{"v1.0.0-RC1", "v1.0.0-!r!c1"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeModulePath(tt.input); got != tt.expected {
+			t.Errorf("escapeModulePath(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSplitModule(t *testing.T) {
+	path, version := SplitModule("github.com/spf13/cobra@v1.8.0")
+	if path != "github.com/spf13/cobra" || version != "v1.8.0" {
+		t.Errorf("SplitModule returned (%q, %q)", path, version)
+	}
+
+	path, version = SplitModule("mymodule")
+	if path != "mymodule" || version != "" {
+		t.Errorf("SplitModule returned (%q, %q) for main module", path, version)
+	}
+}
+
+func TestReadModFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goModContent := []byte(`module test
+
+go 1.21
+
+require (
+	example.com/direct v1.0.0
+	example.com/indirect v1.2.0 // indirect
+)
+
+replace example.com/direct => ../direct
+`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), goModContent, 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	mf, err := ReadModFile(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadModFile failed: %v", err)
+	}
+
+	if mf.Module.Path != "test" {
+		t.Errorf("Expected module path 'test', got %q", mf.Module.Path)
+	}
+
+	req, ok := mf.FindRequire("example.com/indirect")
+	if !ok || !req.Indirect {
+		t.Errorf("Expected example.com/indirect to be an indirect requirement, got %+v", req)
+	}
+
+	rep, ok := mf.FindReplace("example.com/direct", "v1.0.0")
+	if !ok || rep.String() != "../direct" {
+		t.Errorf("Expected replacement ../direct, got %q", rep.String())
+	}
+}
+
+func TestParseModFile(t *testing.T) {
+	mf, err := ParseModFile([]byte(`// A comment
+module "example.com/app"
+
+go 1.21
+
+toolchain go1.22.1
+
+require github.com/a/lib v1.2.0
+
+require (
+	github.com/b/util v0.3.0 // indirect
+	golang.org/x/text v0.14.0
+)
+
+replace (
+	github.com/a/lib => ../lib
+	golang.org/x/text v0.14.0 => golang.org/x/text v0.15.0
+)
+
+exclude github.com/b/util v0.2.0
+
+retract (
+	v1.0.0 // published by mistake
+)
+`))
+	if err != nil {
+		t.Fatalf("ParseModFile failed: %v", err)
+	}
+
+	if mf.Module.Path != "example.com/app" || mf.Go != "1.21" || mf.Toolchain.Name != "go1.22.1" {
+		t.Errorf("Unexpected module %q, go %q, toolchain %q", mf.Module.Path, mf.Go, mf.Toolchain.Name)
+	}
+	wantRequire := []ModRequire{
+		{Path: "github.com/a/lib", Version: "v1.2.0"},
+		{Path: "github.com/b/util", Version: "v0.3.0", Indirect: true},
+		{Path: "golang.org/x/text", Version: "v0.14.0"},
+	}
+	if !reflect.DeepEqual(mf.Require, wantRequire) {
+		t.Errorf("Require = %+v, want %+v", mf.Require, wantRequire)
+	}
+	wantReplace := []ModReplace{
+		{Old: ModVersion{Path: "github.com/a/lib"}, New: ModVersion{Path: "../lib"}},
+		{Old: ModVersion{Path: "golang.org/x/text", Version: "v0.14.0"}, New: ModVersion{Path: "golang.org/x/text", Version: "v0.15.0"}},
+	}
+	if !reflect.DeepEqual(mf.Replace, wantReplace) {
+		t.Errorf("Replace = %+v, want %+v", mf.Replace, wantReplace)
+	}
+	if want := []ModVersion{{Path: "github.com/b/util", Version: "v0.2.0"}}; !reflect.DeepEqual(mf.Exclude, want) {
+		t.Errorf("Exclude = %+v, want %+v", mf.Exclude, want)
+	}
+
+	if want := []ModRetract{{Low: "v1.0.0", High: "v1.0.0", Rationale: "published by mistake"}}; !reflect.DeepEqual(mf.Retract, want) {
+		t.Errorf("Retract = %+v, want %+v", mf.Retract, want)
+	}
+	if mf.Module.Deprecated != "" {
+		t.Errorf("Deprecated = %q, want none", mf.Module.Deprecated)
+	}
+
+	if _, err := ParseModFile([]byte("require github.com/a/lib\n")); err == nil {
+		t.Error("Expected error for a require without version")
+	}
+}
+
+func TestParseModFileDeprecatedAndRetract(t *testing.T) {
+	mf, err := ParseModFile([]byte(`// Package lib does things.
+//
+// Deprecated: use example.com/lib/v2
+// instead.
+module example.com/lib
+
+// Breaks the API.
+retract [v1.1.0, v1.1.3]
+
+retract v1.0.0
+`))
+	if err != nil {
+		t.Fatalf("ParseModFile failed: %v", err)
+	}
+
+	if want := "use example.com/lib/v2 instead."; mf.Module.Deprecated != want {
+		t.Errorf("Deprecated = %q, want %q", mf.Module.Deprecated, want)
+	}
+	want := []ModRetract{
+		{Low: "v1.1.0", High: "v1.1.3", Rationale: "Breaks the API."},
+		{Low: "v1.0.0", High: "v1.0.0"},
+	}
+	if !reflect.DeepEqual(mf.Retract, want) {
+		t.Errorf("Retract = %+v, want %+v", mf.Retract, want)
+	}
+
+	mf, err = ParseModFile([]byte("module example.com/old // Deprecated: unmaintained\n"))
+	if err != nil {
+		t.Fatalf("ParseModFile failed: %v", err)
+	}
+	if mf.Module.Deprecated != "unmaintained" {
+		t.Errorf("Deprecated = %q, want %q", mf.Module.Deprecated, "unmaintained")
+	}
+
+	if _, err := ParseModFile([]byte("retract [v1.0.0\n")); err == nil {
+		t.Error("Expected error for an unterminated retract interval")
+	}
+}
@@ -0,0 +1,72 @@
+package deptree
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GoSum holds the hashes recorded in a go.sum file, keyed by "path@version"
+// for module zips and "path@version/go.mod" for go.mod files.
+type GoSum map[string]string
+
+// ReadGoSum parses dir/go.sum. A missing file yields an empty GoSum.
+func ReadGoSum(dir string) (GoSum, error) {
+	return readSumFile(filepath.Join(dir, "go.sum"))
+}
+
+// readSumFile parses a go.sum or go.work.sum file.
+func readSumFile(path string) (GoSum, error) {
+	name := filepath.Base(path)
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return GoSum{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer f.Close()
+
+	sums := make(GoSum)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines are "path version[/go.mod] h1:hash"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		sums[fields[0]+"@"+fields[1]] = fields[2]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	return sums, nil
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest behind the h1 hash of the
+// module zip of path@version, or "" if go.sum has none.
+func (s GoSum) SHA256(module string) string {
+	return H1ToSHA256(s[module])
+}
+
+// H1ToSHA256 converts a go.sum "h1:" hash to the hex encoding of the
+// SHA-256 digest it carries, or returns "" if h1 is not such a hash.
+func H1ToSHA256(h1 string) string {
+	encoded, ok := strings.CutPrefix(h1, "h1:")
+	if !ok {
+		return ""
+	}
+	digest, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(digest)
+}
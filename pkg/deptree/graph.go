@@ -0,0 +1,170 @@
+package deptree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// Graph is the analyzed dependency graph handed to renderers: the tree rooted
+// at the analyzed module, the raw edges reported by 'go mod graph', and any
+// descriptions fetched for its modules.
+type Graph struct {
+	Root         *Node
+	Deps         map[string][]string
+	Descriptions map[string]string
+	// DescriptionErrors records why fetching a module's description failed.
+	DescriptionErrors map[string]error
+	// Links maps modules to the homepage and clone URL of their repository,
+	// as resolved along with their descriptions.
+	Links map[string]SourceLinks
+	// ModFile is the main module's go.mod, whose replace directives apply
+	// to the whole graph.
+	ModFile *ModFile
+	// RootModFile is the go.mod of the module at Root. It differs from
+	// ModFile when a remote package is analyzed through a temp module.
+	RootModFile *ModFile
+	// Workspace maps the modules used by go.work to their go.mod when the
+	// graph was loaded from a workspace. Root is WorkspaceRoot then.
+	Workspace map[string]*ModFile
+	// ModFiles maps module versions to their own go.mod, as read from the
+	// module cache by ReadModFiles or fetched by LoadFromProxy.
+	ModFiles map[string]*ModFile
+	// Timings records how long loading the graph took.
+	Timings *Timings
+	// Sums are the go.sum hashes of the main module.
+	Sums GoSum
+	// Licenses maps modules to the SPDX identifier of their license, as
+	// detected by DetectLicenses.
+	Licenses map[string]string
+	// LicenseErrors records why detecting a module's license failed,
+	// including the time budget running out. Such modules are UnknownLicense
+	// in Licenses, though they may well have a license.
+	LicenseErrors map[string]error
+	// ImportedBy maps modules to the number of packages importing their
+	// root package, as fetched by FetchPkgsiteMetadata.
+	ImportedBy map[string]int
+	// Vulnerabilities maps modules to the OSV advisory IDs affecting them,
+	// as detected by DetectVulnerabilities.
+	Vulnerabilities map[string][]string
+	// VulnerabilityDetails holds the advisories behind Vulnerabilities,
+	// in the same order.
+	VulnerabilityDetails map[string][]Vulnerability
+	// Outdated maps modules to the newer version the module proxy offers,
+	// as detected by DetectOutdated. It is nil unless versions were checked.
+	Outdated map[string]string
+	// OutdatedErrors records why looking up the latest version of a module
+	// path failed. Such modules are missing from Outdated, though they may
+	// well be outdated.
+	OutdatedErrors map[string]error
+	// Sizes maps modules to the size in bytes of their source in the module
+	// cache, as detected by DetectSizes. It is nil unless sizes were measured.
+	Sizes map[string]int64
+	// RepoStatus maps modules hosted on GitHub to the maintenance state of
+	// their repository, as fetched by FetchRepoStatuses.
+	RepoStatus map[string]RepoStatus
+	// Scorecards maps modules hosted on GitHub to the OpenSSF Scorecard of
+	// their repository, as fetched by FetchScorecards.
+	Scorecards map[string]Scorecard
+	// TestOnly holds the modules only the main module's tests import, as
+	// found by MarkTestDependencies.
+	TestOnly map[string]bool
+	// Partial lists the modules whose metadata is incomplete because the
+	// time budget of the client it was fetched with ran out (see WithBudget).
+	Partial []string
+	// Pruned lists the module versions dropped by CollapseToSelected.
+	Pruned []string
+	// Unexpanded is the node count of the tree when it exceeds MaxTreeNodes.
+	// Root is then a bare node: print the tree with StreamTree or build it
+	// with Expand.
+	Unexpanded int
+	// Legacy is LegacyGopkgLock or LegacyVendor when the graph is the flat
+	// inventory of a pre-modules project read by LoadLegacy.
+	Legacy string
+	// Warnings are the problems loading and analyzing the graph ran into
+	// without failing, in the order they were found.
+	Warnings []Warning
+}
+
+// Modules returns every module in the graph sorted by name with no
+// duplicates, leaving out synthetic modules (see isSyntheticModule) and
+// toolchain entries.
+func (g *Graph) Modules() []string {
+	uniqueDeps := make(map[string]bool)
+
+	for from, tos := range g.Deps {
+		// Include the "from" module unless it's synthetic
+		if !isSyntheticModule(from) && !IsToolchainDep(from) {
+			uniqueDeps[from] = true
+		}
+		// Include all "to" modules
+		for _, to := range tos {
+			if !IsToolchainDep(to) {
+				uniqueDeps[to] = true
+			}
+		}
+	}
+
+	var depList []string
+	for dep := range uniqueDeps {
+		depList = append(depList, dep)
+	}
+
+	sort.Strings(depList)
+	return depList
+}
+
+// Reachable returns every module reachable from the given module through
+// requirement edges, sorted by name and excluding the module itself and
+// toolchain entries.
+func (g *Graph) Reachable(from string) []string {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	var reached []string
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, to := range g.Deps[name] {
+			if seen[to] || IsToolchainDep(to) {
+				continue
+			}
+			seen[to] = true
+			reached = append(reached, to)
+			queue = append(queue, to)
+		}
+	}
+
+	sort.Strings(reached)
+	return reached
+}
+
+// Walk calls fn for every node of the tree in depth-first order.
+func (g *Graph) Walk(fn func(*Node)) {
+	var walk func(*Node)
+	walk = func(node *Node) {
+		fn(node)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if g.Root != nil {
+		walk(g.Root)
+	}
+}
+
+// syncDescriptions copies g.Descriptions onto every tree node, since a module
+// may occur at several places in the tree.
+func (g *Graph) syncDescriptions() {
+	g.Walk(func(node *Node) {
+		node.Description = g.Descriptions[node.Name]
+	})
+}
+
+// NodeID returns a stable identifier for a module name, derived from a hash
+// of its path@version so that it is the same in every run and export. IDs
+// start with a letter, making them valid identifiers in DOT and XML.
+func NodeID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return "m" + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,35 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphReachable(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"temp":        {"pkg@v1.0.0", "other@v1.0.0"},
+			"pkg@v1.0.0":  {"dep1@v1.0.0", "go@1.21.0"},
+			"dep1@v1.0.0": {"dep2@v1.0.0", "pkg@v1.0.0"},
+		},
+	}
+
+	got := g.Reachable("pkg@v1.0.0")
+	expected := []string{"dep1@v1.0.0", "dep2@v1.0.0"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Reachable() = %v, want %v", got, expected)
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	id := NodeID("golang.org/x/text@v0.3.5")
+	if id != NodeID("golang.org/x/text@v0.3.5") {
+		t.Error("Expected NodeID to be deterministic")
+	}
+	if id == NodeID("golang.org/x/text@v0.3.6") {
+		t.Error("Expected different versions to get different IDs")
+	}
+	if len(id) != 13 || id[0] != 'm' {
+		t.Errorf("Expected \"m\" followed by 12 hex digits, got %q", id)
+	}
+}
@@ -0,0 +1,170 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// HostingDisclaimer heads the hosting report. Hosting metadata tells where
+// source code is served from and what its owners say about themselves; it
+// is not a statement about legal jurisdiction.
+const HostingDisclaimer = "Best-effort metadata: locations are self-reported on GitHub profiles and may be missing, outdated or wrong; verify before relying on them for compliance."
+
+// hostingProviders names the services behind well-known repository hosts.
+var hostingProviders = map[string]string{
+	"github.com":          "GitHub",
+	"gitlab.com":          "GitLab",
+	"bitbucket.org":       "Bitbucket",
+	"codeberg.org":        "Codeberg",
+	"gitee.com":           "Gitee",
+	"sr.ht":               "SourceHut",
+	"git.sr.ht":           "SourceHut",
+	"go.googlesource.com": "Google Git",
+}
+
+// Hosting is where the modules of one owner (see ModuleOwner) are hosted.
+type Hosting struct {
+	Owner string
+	// Repository is the host and owner the source is served from, such as
+	// github.com/golang for a vanity import path; empty if it could not
+	// be resolved.
+	Repository string
+	// Provider names the hosting service, or is "self-hosted" for
+	// repository hosts deptree does not know.
+	Provider string
+	// Location is the location the owning GitHub account states on its
+	// profile, if any.
+	Location string
+	// Err is why the repository or location could not be determined.
+	Err     error
+	Modules []string
+}
+
+// DetectHosting reports where the modules of each external owner of g are
+// hosted, ordered by owner. Vanity import paths are resolved to their
+// repository through go-import meta tags, and the profile location of
+// GitHub owners is fetched from the GitHub API.
+func DetectHosting(g *Graph, opts Options, token string) []Hosting {
+	d := newDescriber(opts, token)
+	owners := g.Owners()
+
+	hostings := make([]Hosting, 0, len(owners))
+	for _, owner := range slices.Sorted(maps.Keys(owners)) {
+		modules := owners[owner]
+		slices.Sort(modules)
+		hostings = append(hostings, Hosting{Owner: owner, Modules: modules})
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	users := make(map[string]*GitHubUser)
+	sem := make(chan struct{}, githubWorkers)
+
+	for i := range hostings {
+		wg.Add(1)
+		go func(h *Hosting) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if h.Repository, h.Err = d.repositoryOwner(h); h.Err != nil {
+				return
+			}
+			host, login, _ := strings.Cut(h.Repository, "/")
+			h.Provider = hostingProviders[host]
+			if h.Provider == "" {
+				h.Provider = "self-hosted"
+			}
+			if host != "github.com" || login == "" {
+				return
+			}
+
+			mu.Lock()
+			user, ok := users[login]
+			mu.Unlock()
+			if !ok {
+				if user, h.Err = d.github.User(login); h.Err != nil {
+					return
+				}
+				mu.Lock()
+				users[login] = user
+				mu.Unlock()
+			}
+			h.Location = strings.TrimSpace(user.Location)
+		}(&hostings[i])
+	}
+	wg.Wait()
+
+	return hostings
+}
+
+// repositoryOwner returns the host and owner serving the source of h's
+// modules, resolving vanity import paths through the first of them.
+func (d *describer) repositoryOwner(h *Hosting) (string, error) {
+	host, _, _ := strings.Cut(h.Owner, "/")
+	if forgeHosts[host] {
+		return h.Owner, nil
+	}
+
+	path, _ := SplitModule(h.Modules[0])
+	repoURL, err := d.resolveRepo(path)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid repository URL %q", repoURL)
+	}
+
+	owner, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if !forgeHosts[u.Host] || owner == "" {
+		return u.Host, nil
+	}
+	return u.Host + "/" + owner, nil
+}
+
+// RenderHosting formats hostings as a plain-text report headed by
+// HostingDisclaimer, one block per owner followed by its modules.
+func RenderHosting(hostings []Hosting) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, HostingDisclaimer)
+	fmt.Fprintln(&buf)
+
+	if len(hostings) == 0 {
+		fmt.Fprintln(&buf, "No external modules")
+		return buf.Bytes()
+	}
+
+	for _, h := range hostings {
+		fmt.Fprintln(&buf, h.Owner)
+		if h.Err != nil {
+			fmt.Fprintf(&buf, "  error:      %v\n", h.Err)
+		}
+		if h.Repository != "" {
+			fmt.Fprintf(&buf, "  provider:   %s (%s)\n", h.Provider, h.Repository)
+		}
+		if h.Location != "" {
+			fmt.Fprintf(&buf, "  location:   %s\n", h.Location)
+		} else if h.Err == nil {
+			fmt.Fprintln(&buf, "  location:   unknown")
+		}
+		for _, m := range h.Modules {
+			fmt.Fprintf(&buf, "  - %s\n", m)
+		}
+		buf.WriteByte('\n')
+	}
+
+	locations := 0
+	for _, h := range hostings {
+		if h.Location != "" {
+			locations++
+		}
+	}
+	fmt.Fprintf(&buf, "%d owner(s), %d with a stated location\n", len(hostings), locations)
+	return buf.Bytes()
+}
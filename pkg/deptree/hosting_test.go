@@ -0,0 +1,95 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetectHosting(t *testing.T) {
+	var server *httptest.Server
+	userRequests := 0
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := strings.TrimPrefix(server.URL, "https://")
+		switch {
+		case r.URL.Query().Get("go-get") == "1" && r.URL.Path == "/x/text":
+			w.Write([]byte(`<meta name="go-import" content="` + host + `/x/text git https://github.com/golang/text">`))
+		case r.URL.Path == "/users/spf13":
+			userRequests++
+			w.Write([]byte(`{"login": "spf13", "type": "User", "location": " New York "}`))
+		case r.URL.Path == "/users/golang":
+			w.Write([]byte(`{"login": "golang", "type": "Organization"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldGitHub := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldGitHub }()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	g := &Graph{
+		Root: NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app": {
+				"github.com/spf13/cobra@v1.8.0",
+				"github.com/spf13/pflag@v1.0.5",
+				"gitlab.com/group/lib@v1.0.0",
+				host + "/x/text@v0.14.0",
+			},
+		},
+	}
+
+	hostings := DetectHosting(g, Options{HTTPClient: server.Client()}, "")
+	if len(hostings) != 3 {
+		t.Fatalf("DetectHosting returned %d owners, want 3: %+v", len(hostings), hostings)
+	}
+	byOwner := make(map[string]Hosting)
+	for _, h := range hostings {
+		byOwner[h.Owner] = h
+	}
+
+	spf13 := byOwner["github.com/spf13"]
+	if spf13.Provider != "GitHub" || spf13.Location != "New York" || len(spf13.Modules) != 2 || spf13.Err != nil {
+		t.Errorf("github.com/spf13 = %+v", spf13)
+	}
+	if userRequests != 1 {
+		t.Errorf("fetched the spf13 profile %d times, want 1", userRequests)
+	}
+
+	vanity := byOwner[host]
+	if vanity.Repository != "github.com/golang" || vanity.Provider != "GitHub" || vanity.Location != "" || vanity.Err != nil {
+		t.Errorf("%s = %+v", host, vanity)
+	}
+
+	gitlab := byOwner["gitlab.com/group"]
+	if gitlab.Provider != "GitLab" || gitlab.Location != "" || gitlab.Err != nil {
+		t.Errorf("gitlab.com/group = %+v", gitlab)
+	}
+
+	out := string(RenderHosting(hostings))
+	for _, want := range []string{
+		HostingDisclaimer,
+		"github.com/spf13\n  provider:   GitHub (github.com/spf13)\n  location:   New York\n",
+		"gitlab.com/group\n  provider:   GitLab (gitlab.com/group)\n  location:   unknown\n",
+		"3 owner(s), 1 with a stated location\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderHosting output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderHostingError(t *testing.T) {
+	out := string(RenderHosting([]Hosting{{
+		Owner:   "example.org",
+		Err:     errNoDescription,
+		Modules: []string{"example.org/lib@v1.0.0"},
+	}}))
+	if !strings.Contains(out, "example.org\n  error:      no description set\n  - example.org/lib@v1.0.0\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
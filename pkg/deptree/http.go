@@ -0,0 +1,57 @@
+package deptree
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// NewHTTPClient returns the client shared by every outbound request. It
+// honors HTTP_PROXY/HTTPS_PROXY/NO_PROXY and, when caCertPath is set, trusts
+// the certificates in that PEM bundle in addition to the system roots.
+func NewHTTPClient(caCertPath string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if caCertPath != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", caCertPath)
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport, Timeout: 10 * time.Second}, nil
+}
+
+// DescribeHTTPError adds a hint to certificate verification failures, which
+// almost always mean a TLS-intercepting proxy whose CA is not trusted.
+func DescribeHTTPError(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var verification *tls.CertificateVerificationError
+
+	switch {
+	case errors.As(err, &unknownAuthority),
+		errors.As(err, &hostname),
+		errors.As(err, &invalid),
+		errors.As(err, &verification):
+		return fmt.Errorf("TLS certificate verification failed: %w; if you are behind a TLS-intercepting proxy, pass its CA bundle with -ca-cert", err)
+	}
+
+	return err
+}
@@ -0,0 +1,66 @@
+package deptree
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTPClientWithCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, certPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+
+	client, err := NewHTTPClient(caPath)
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected request to succeed with custom CA, got: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClientInvalidCACert(t *testing.T) {
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caPath, []byte("not a certificate"), 0644); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+
+	if _, err := NewHTTPClient(caPath); err == nil {
+		t.Error("Expected error for bundle without certificates")
+	}
+}
+
+func TestDescribeHTTPErrorUnknownAuthority(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client, err := NewHTTPClient("")
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+
+	_, err = client.Get(server.URL)
+	if err == nil {
+		t.Fatal("Expected certificate verification error")
+	}
+
+	if msg := DescribeHTTPError(err).Error(); !strings.Contains(msg, "-ca-cert") {
+		t.Errorf("Expected hint about -ca-cert, got %q", msg)
+	}
+}
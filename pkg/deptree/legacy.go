@@ -0,0 +1,201 @@
+package deptree
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Sources of the dependency inventory of a pre-modules project, recorded in
+// Graph.Legacy.
+const (
+	LegacyGopkgLock = "Gopkg.lock"
+	LegacyVendor    = "vendor"
+)
+
+// legacySource returns where the dependencies of the pre-modules project in
+// dir can be read from, or "" if dir has a go.mod or nothing to read.
+func legacySource(dir string) string {
+	if fileExists(filepath.Join(dir, "go.mod")) {
+		return ""
+	}
+	if fileExists(filepath.Join(dir, LegacyGopkgLock)) {
+		return LegacyGopkgLock
+	}
+	if info, err := os.Stat(filepath.Join(dir, LegacyVendor)); err == nil && info.IsDir() {
+		return LegacyVendor
+	}
+	return ""
+}
+
+// LoadLegacy builds a flat dependency inventory of a pre-modules project from
+// its dep lock file or, failing that, from the repositories in its vendor
+// directory. Neither records which project requires which, so every
+// dependency hangs directly off the root.
+func LoadLegacy(dir string) (*Graph, error) {
+	source := legacySource(dir)
+
+	var projects []ModVersion
+	var err error
+	switch source {
+	case LegacyGopkgLock:
+		projects, err = ReadGopkgLock(filepath.Join(dir, LegacyGopkgLock))
+	case LegacyVendor:
+		projects, err = readVendorDir(filepath.Join(dir, LegacyVendor))
+	default:
+		return nil, fmt.Errorf("%s has no go.mod, Gopkg.lock or vendor directory", dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rootName := filepath.Base(abs)
+
+	g := &Graph{Deps: map[string][]string{rootName: {}}, Legacy: source}
+	g.warnLegacy()
+	for _, p := range projects {
+		name := p.Path
+		if p.Version != "" {
+			name += "@" + p.Version
+		}
+		g.Deps[rootName] = append(g.Deps[rootName], name)
+	}
+	g.Root = NewNode(rootName)
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+
+	return g, nil
+}
+
+// warnLegacy records in g.Warnings that a legacy inventory has no
+// transitive structure.
+func (g *Graph) warnLegacy() {
+	g.warn(WarnLegacy, "", "no go.mod found; listing dependencies from %s without their requirements", g.Legacy)
+}
+
+// ReadGopkgLock reads the projects pinned by a dep Gopkg.lock. Each project's
+// version is its tag, or its revision when it is pinned to a branch or
+// commit.
+func ReadGopkgLock(path string) ([]ModVersion, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var projects []ModVersion
+	var current map[string]string
+	flush := func() {
+		if current == nil || current["name"] == "" {
+			return
+		}
+		version := current["version"]
+		if version == "" {
+			version = current["revision"]
+		}
+		projects = append(projects, ModVersion{Path: current["name"], Version: version})
+	}
+
+	// Gopkg.lock is TOML, but only the string keys of [[projects]] tables
+	// are needed, which dep always writes one per line.
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "[[projects]]":
+			flush()
+			current = make(map[string]string)
+		case strings.HasPrefix(line, "["):
+			flush()
+			current = nil
+		case current != nil:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			if s, err := strconv.Unquote(strings.TrimSpace(value)); err == nil {
+				current[strings.TrimSpace(key)] = s
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	flush()
+
+	return projects, nil
+}
+
+// readVendorDir lists the repositories vendored in dir. Repository roots are
+// derived from the import path for well-known hosts and are otherwise the
+// outermost directory holding Go files. Versions are unknown.
+func readVendorDir(dir string) ([]ModVersion, error) {
+	found := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".go" {
+			return nil
+		}
+		if rel, err := filepath.Rel(dir, filepath.Dir(path)); err == nil && rel != "." {
+			found[vendorRepoRoot(filepath.ToSlash(rel))] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendor directory: %w", err)
+	}
+
+	// Sorting puts every root right before the nested packages it covers
+	var projects []ModVersion
+	for _, root := range slices.Sorted(maps.Keys(found)) {
+		if n := len(projects); n > 0 && strings.HasPrefix(root, projects[n-1].Path+"/") {
+			continue
+		}
+		projects = append(projects, ModVersion{Path: root})
+	}
+	return projects, nil
+}
+
+// vendorRootDepth is the number of path elements of a repository root on
+// hosts with a fixed layout.
+var vendorRootDepth = map[string]int{
+	"github.com":        3,
+	"gitlab.com":        3,
+	"bitbucket.org":     3,
+	"golang.org":        3,
+	"sigs.k8s.io":       3,
+	"google.golang.org": 2,
+	"k8s.io":            2,
+	"gopkg.in":          2,
+}
+
+// vendorRepoRoot returns the repository root of a vendored package path for
+// hosts whose layout is known, and the package path itself otherwise.
+func vendorRepoRoot(pkg string) string {
+	parts := strings.Split(pkg, "/")
+	n := vendorRootDepth[parts[0]]
+	if parts[0] == "gopkg.in" && len(parts) > 2 && !strings.Contains(parts[1], ".v") {
+		// gopkg.in/user/pkg.v1 rather than gopkg.in/pkg.v1
+		n = 3
+	}
+	if n == 0 || len(parts) < n {
+		return pkg
+	}
+	return strings.Join(parts[:n], "/")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
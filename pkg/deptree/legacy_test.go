@@ -0,0 +1,89 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLegacyGopkgLock(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "oldservice")
+	writeTestFile(t, filepath.Join(dir, "Gopkg.lock"), `# This file is autogenerated, do not edit; changes may be undone by the next 'dep ensure'.
+
+
+[[projects]]
+  digest = "1:cf31692c14422fa27c83a05292eb5cbe0fb2775972e8f1f8446a71549bd8980b"
+  name = "github.com/pkg/errors"
+  packages = ["."]
+  pruneopts = "UT"
+  revision = "ba968bfe8b2f7e042a574c888954fccecfa385b4"
+  version = "v0.8.1"
+
+[[projects]]
+  branch = "master"
+  name = "golang.org/x/net"
+  packages = ["context"]
+  revision = "3b0461eec859c4b73bb64fdc8285971fd33e3938"
+
+[solve-meta]
+  analyzer-name = "dep"
+  input-imports = ["github.com/pkg/errors"]
+`)
+	// A vendor directory next to Gopkg.lock is ignored in favor of the lock
+	writeTestFile(t, filepath.Join(dir, "vendor", "github.com", "other", "lib", "lib.go"), "package lib\n")
+
+	g, err := Load(dir, "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if g.Legacy != LegacyGopkgLock {
+		t.Errorf("Expected Legacy %q, got %q", LegacyGopkgLock, g.Legacy)
+	}
+	want := []string{
+		"github.com/pkg/errors@v0.8.1",
+		"golang.org/x/net@3b0461eec859c4b73bb64fdc8285971fd33e3938",
+		"oldservice",
+	}
+	if got := g.Modules(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Modules() = %v, want %v", got, want)
+	}
+	if g.Root.Name != "oldservice" || len(g.Root.Children) != 2 {
+		t.Errorf("Expected a flat tree under oldservice, got %s with %d children", g.Root.Name, len(g.Root.Children))
+	}
+}
+
+func TestLoadLegacyVendor(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{
+		"github.com/sirupsen/logrus/hooks/syslog/syslog.go",
+		"github.com/sirupsen/logrus/logrus.go",
+		"gopkg.in/yaml.v2/yaml.go",
+		"example.org/tools/util/util.go",
+		"example.org/tools/tools.go",
+	} {
+		writeTestFile(t, filepath.Join(dir, "vendor", file), "package x\n")
+	}
+
+	g, err := LoadLegacy(dir)
+	if err != nil {
+		t.Fatalf("LoadLegacy failed: %v", err)
+	}
+	want := []string{"example.org/tools", "github.com/sirupsen/logrus", "gopkg.in/yaml.v2"}
+	if got := g.Deps[g.Root.Name]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies = %v, want %v", got, want)
+	}
+	if len(g.Warnings) != 1 || g.Warnings[0].Kind != WarnLegacy {
+		t.Errorf("Expected a legacy warning, got %v", g.Warnings)
+	}
+}
@@ -0,0 +1,251 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnknownLicense is reported when no license could be detected.
+const UnknownLicense = "Unknown"
+
+// licenseWorkers bounds concurrent license lookups.
+const licenseWorkers = 8
+
+// licenseFilePattern matches the file names modules conventionally keep
+// their license text in.
+var licenseFilePattern = regexp.MustCompile(`(?i)^(licen[cs]e|copying|unlicense)(\.(md|txt|rst))?$`)
+
+// licenseTitles identify a license by the title it opens with. Long
+// licenses such as the GPL family cite each other in their terms, so only
+// the header is matched: a title line, optionally joined with the version
+// line below it.
+var licenseTitles = []struct {
+	id    string
+	title *regexp.Regexp
+}{
+	{"AGPL-3.0", regexp.MustCompile(`^gnu affero general public license,? version 3\b`)},
+	{"LGPL-3.0", regexp.MustCompile(`^gnu lesser general public license,? version 3\b`)},
+	{"LGPL-2.1", regexp.MustCompile(`^gnu lesser general public license,? version 2\.1\b`)},
+	{"LGPL-2.0", regexp.MustCompile(`^gnu library general public license,? version 2\b`)},
+	{"GPL-3.0", regexp.MustCompile(`^gnu general public license,? version 3\b`)},
+	{"GPL-2.0", regexp.MustCompile(`^gnu general public license,? version 2\b`)},
+	{"MPL-2.0", regexp.MustCompile(`^mozilla public license,? version 2\.0\b`)},
+	{"EPL-2.0", regexp.MustCompile(`^eclipse public license,? (- )?v(ersion)? ?2\.0\b`)},
+	{"Apache-2.0", regexp.MustCompile(`^apache license,? version 2\.0\b`)},
+	{"CC0-1.0", regexp.MustCompile(`^cc0 1\.0 universal\b`)},
+}
+
+// licenseHeaderLines is how many non-blank lines at the top of a license
+// file are searched for a title, which leaves room for a copyright line
+// above it.
+const licenseHeaderLines = 5
+
+// licenseSignatures identify the short permissive licenses, which usually
+// carry no title, by phrases from their text. They are only tried when the
+// header names no license. More specific licenses come first so that, for
+// example, BSD-3-Clause is not reported as BSD-2-Clause.
+var licenseSignatures = []struct {
+	id      string
+	phrases []string
+}{
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"Zlib", []string{"this software is provided 'as-is'", "altered source versions must be plainly marked"}},
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// IdentifyLicense returns the SPDX identifier of the license text, or
+// UnknownLicense.
+func IdentifyLicense(text []byte) string {
+	if id, ok := licenseFromTitle(string(text)); ok {
+		return id
+	}
+
+	normalized := strings.ToLower(whitespace.ReplaceAllString(string(text), " "))
+	for _, sig := range licenseSignatures {
+		matched := true
+		for _, phrase := range sig.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return sig.id
+		}
+	}
+
+	return UnknownLicense
+}
+
+// licenseFromTitle matches the first licenseHeaderLines non-blank lines of
+// text against licenseTitles.
+func licenseFromTitle(text string) (string, bool) {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ToLower(strings.TrimSpace(whitespace.ReplaceAllString(line, " ")))
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == licenseHeaderLines+1 {
+			break
+		}
+	}
+
+	for i, line := range lines[:min(len(lines), licenseHeaderLines)] {
+		header := line
+		if i+1 < len(lines) {
+			header += " " + lines[i+1]
+		}
+		for _, t := range licenseTitles {
+			if t.title.MatchString(header) {
+				return t.id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DetectLicense returns the license of path@version from the LICENSE file in
+// the module cache when the module has been downloaded, or else from the
+// GitHub licenses API for GitHub-hosted modules.
+func DetectLicense(opts Options, module, token string) (string, error) {
+	return detectLicense(NewGitHubClient(opts, token), module)
+}
+
+func detectLicense(github *GitHubClient, module string) (string, error) {
+	path, version := SplitModule(module)
+
+	if dir, err := ModuleCacheDir(path, version); err == nil {
+		if license, ok := licenseFromDir(dir); ok {
+			return license, nil
+		}
+	}
+
+	repo, err := github.Repo(module)
+	if err != nil {
+		return UnknownLicense, err
+	}
+	if repo.License == nil || repo.License.SPDXID == "" || repo.License.SPDXID == "NOASSERTION" {
+		return UnknownLicense, nil
+	}
+	return repo.License.SPDXID, nil
+}
+
+// licenseFromDir identifies the license of the first license file in dir.
+func licenseFromDir(dir string) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !licenseFilePattern.MatchString(entry.Name()) {
+			continue
+		}
+		text, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		return IdentifyLicense(text), true
+	}
+
+	return "", false
+}
+
+// DetectLicenses detects the license of every versioned module in g and
+// stores it in g.Licenses. Lookup failures are recorded as UnknownLicense,
+// with the error in g.LicenseErrors, and modules skipped because the client's
+// time budget ran out also in g.Partial. Other failures are summarized in
+// g.Warnings.
+func DetectLicenses(g *Graph, opts Options, token string) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, licenseWorkers)
+	github := NewGitHubClient(opts, token)
+
+	g.Licenses = make(map[string]string)
+	g.LicenseErrors = make(map[string]error)
+	failed := make(map[string]error)
+
+	for _, name := range g.Modules() {
+		if _, version := SplitModule(name); version == "" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			license, err := detectLicense(github, name)
+			mu.Lock()
+			switch {
+			case errors.Is(err, ErrBudgetExceeded):
+				g.markPartial(name)
+			case err != nil:
+				failed[name] = err
+			}
+			if err != nil {
+				g.LicenseErrors[name] = err
+			}
+			g.Licenses[name] = license
+			mu.Unlock()
+		}(name)
+	}
+
+	wg.Wait()
+	g.warnFailures("license", failed)
+}
+
+// LicenseCounts returns how many modules in g use each license.
+func (g *Graph) LicenseCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, license := range g.Licenses {
+		counts[license]++
+	}
+	return counts
+}
+
+// writeLicenseSummary appends a table of license counts, most used first.
+func writeLicenseSummary(buf *bytes.Buffer, g *Graph) {
+	counts := g.LicenseCounts()
+	licenses := sortedLicenses(counts)
+	width := len("License")
+	for _, license := range licenses {
+		width = max(width, len(license))
+	}
+
+	fmt.Fprintf(buf, "\n%-*s  Modules\n", width, "License")
+	for _, license := range licenses {
+		fmt.Fprintf(buf, "%-*s  %d\n", width, license, counts[license])
+	}
+}
+
+// sortedLicenses orders licenses by descending module count, then by name.
+func sortedLicenses(counts map[string]int) []string {
+	licenses := make([]string, 0, len(counts))
+	for license := range counts {
+		licenses = append(licenses, license)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		if counts[licenses[i]] != counts[licenses[j]] {
+			return counts[licenses[i]] > counts[licenses[j]]
+		}
+		return licenses[i] < licenses[j]
+	})
+	return licenses
+}
@@ -0,0 +1,123 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIdentifyLicense(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"MIT", "MIT License\n\nPermission is hereby granted, free of charge, to any person", "MIT"},
+		{"Apache", "Apache License\n   Version 2.0, January 2004", "Apache-2.0"},
+		{"BSD-3", "Redistribution and use in source and binary forms, with or without\nmodification... Neither the name of Google Inc.", "BSD-3-Clause"},
+		{"BSD-2", "Redistribution and use in source and binary forms, with or without modification", "BSD-2-Clause"},
+		{"LGPL before GPL", "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007", "LGPL-3.0"},
+		{"title below copyright", "Copyright (c) 2024 Example Corp\n\n   Apache License\n   Version 2.0, January 2004", "Apache-2.0"},
+		{"GPL cited in the body", "MIT License\n\nPermission is hereby granted, free of charge. Parts were once under the GNU General Public License, version 3.", "MIT"},
+		{"MPL", "Mozilla Public License Version 2.0", "MPL-2.0"},
+		{"unknown", "All rights reserved.", UnknownLicense},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IdentifyLicense([]byte(tt.text)); got != tt.expected {
+				t.Errorf("IdentifyLicense() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIdentifyLicenseTexts checks the full license texts in
+// testdata/licenses, named by their SPDX identifier. The GPL family and the
+// MPL cite each other in their terms, which must not decide the result.
+func TestIdentifyLicenseTexts(t *testing.T) {
+	for _, id := range []string{"GPL-2.0", "GPL-3.0", "LGPL-2.1", "LGPL-3.0", "AGPL-3.0", "MPL-2.0", "Apache-2.0"} {
+		t.Run(id, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join("testdata", "licenses", id))
+			if err != nil {
+				t.Fatalf("Failed to read license text: %v", err)
+			}
+			if got := IdentifyLicense(text); got != id {
+				t.Errorf("IdentifyLicense() = %q, want %q", got, id)
+			}
+		})
+	}
+}
+
+func TestLicenseFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := licenseFromDir(dir); ok {
+		t.Error("Expected no license in empty directory")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "LICENSE.md"), []byte("Permission is hereby granted, free of charge"), 0644); err != nil {
+		t.Fatalf("Failed to write license: %v", err)
+	}
+
+	if license, ok := licenseFromDir(dir); !ok || license != "MIT" {
+		t.Errorf("licenseFromDir() = %q, %v, want MIT", license, ok)
+	}
+}
+
+func TestDetectLicensesFromGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/example/licensed":
+			w.Write([]byte(`{"license":{"spdx_id":"Apache-2.0"}}`))
+		case "/repos/example/unlicensed":
+			w.Write([]byte(`{"license":null}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldURL }()
+
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule": {"github.com/example/licensed@v1.0.0", "github.com/example/unlicensed@v1.0.0", "example.com/vanity@v1.0.0"},
+		},
+	}
+	DetectLicenses(g, Options{HTTPClient: server.Client()}, "")
+
+	expected := map[string]string{
+		"github.com/example/licensed@v1.0.0":   "Apache-2.0",
+		"github.com/example/unlicensed@v1.0.0": UnknownLicense,
+		"example.com/vanity@v1.0.0":            UnknownLicense,
+	}
+	for module, license := range expected {
+		if got := g.Licenses[module]; got != license {
+			t.Errorf("Licenses[%q] = %q, want %q", module, got, license)
+		}
+	}
+	if _, ok := g.Licenses["mymodule"]; ok {
+		t.Error("Expected main module to be skipped")
+	}
+	if g.LicenseErrors["example.com/vanity@v1.0.0"] == nil || g.LicenseErrors["github.com/example/unlicensed@v1.0.0"] != nil {
+		t.Errorf("Expected only the failed lookup in LicenseErrors, got %v", g.LicenseErrors)
+	}
+
+	out, err := exportRenderer{}.Render(g, RenderOptions{ShowLicense: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+	if !strings.Contains(output, "github.com/example/licensed@v1.0.0 [Apache-2.0]") {
+		t.Errorf("Expected license next to module, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Unknown     2") {
+		t.Errorf("Expected license summary table, got:\n%s", output)
+	}
+}
@@ -0,0 +1,127 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RepoStatus is the maintenance state of the GitHub repository of a module.
+type RepoStatus struct {
+	Stars    int       `json:"stars"`
+	Archived bool      `json:"archived,omitempty"`
+	PushedAt time.Time `json:"pushedAt"`
+	// Stale is set when the last push is older than the threshold the
+	// status was fetched with.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Risky reports whether the repository is archived or stale.
+func (s RepoStatus) Risky() bool {
+	return s.Archived || s.Stale
+}
+
+// FetchRepoStatuses fetches the star count, archived flag and last push
+// date of the GitHub repository of every module in g, flags repositories
+// without a push for longer than staleAfter as stale, and stores the result
+// in g.RepoStatus and on the tree nodes. Each repository is fetched once
+// however many of its modules and versions g contains; modules hosted
+// elsewhere are left out. Modules skipped because the client's time budget
+// ran out are listed in g.Partial, and other failures are returned together.
+func FetchRepoStatuses(g *Graph, opts Options, token string, staleAfter time.Duration) error {
+	github := NewGitHubClient(opts, token)
+	return fetchRepoStatuses(g, github, staleAfter, time.Now())
+}
+
+func fetchRepoStatuses(g *Graph, github *GitHubClient, staleAfter time.Duration, now time.Time) error {
+	repos := make(map[string][]string)
+	for _, name := range g.Modules() {
+		if owner, repo, ok := github.repoOf(name); ok {
+			repos[owner+"/"+repo] = append(repos[owner+"/"+repo], name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	sem := make(chan struct{}, githubWorkers)
+	g.RepoStatus = make(map[string]RepoStatus)
+
+	for repo, modules := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ghRepo, err := github.Repo(modules[0])
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, ErrBudgetExceeded):
+				for _, name := range modules {
+					g.markPartial(name)
+				}
+				return
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s: %w", repo, err))
+				return
+			}
+			status := RepoStatus{
+				Stars:    ghRepo.StargazersCount,
+				Archived: ghRepo.Archived,
+				PushedAt: ghRepo.PushedAt,
+				Stale:    !ghRepo.PushedAt.IsZero() && now.Sub(ghRepo.PushedAt) > staleAfter,
+			}
+			for _, name := range modules {
+				g.RepoStatus[name] = status
+			}
+		}()
+	}
+	wg.Wait()
+
+	g.Walk(func(node *Node) {
+		if status, ok := g.RepoStatus[node.Name]; ok {
+			node.Annotations = append(node.Annotations, repoStatusLabel(status))
+		}
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to fetch %d GitHub repository(ies): %w", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// repoStatusLabel shows the stars and last push of a repository, led by a
+// warning if it is archived or stale.
+func repoStatusLabel(s RepoStatus) string {
+	label := fmt.Sprintf("★ %d, last push %s", s.Stars, s.PushedAt.Format(time.DateOnly))
+	switch {
+	case s.Archived:
+		label = "archived, " + label
+	case s.Stale:
+		label = "stale, " + label
+	}
+	return "(" + label + ")"
+}
+
+// writeRepoStatusSummary appends the number of archived and stale modules
+// to buf, if repository statuses were fetched.
+func writeRepoStatusSummary(buf *bytes.Buffer, g *Graph) {
+	if g.RepoStatus == nil {
+		return
+	}
+	archived, stale := 0, 0
+	for _, s := range g.RepoStatus {
+		switch {
+		case s.Archived:
+			archived++
+		case s.Stale:
+			stale++
+		}
+	}
+	fmt.Fprintf(buf, "\n%d archived and %d stale of %d module(s) on GitHub\n", archived, stale, len(g.RepoStatus))
+}
@@ -0,0 +1,81 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchRepoStatuses(t *testing.T) {
+	var cobraRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/spf13/cobra":
+			cobraRequests.Add(1)
+			w.Write([]byte(`{"stargazers_count": 38000, "pushed_at": "2026-09-01T10:00:00Z"}`))
+		case "/repos/pkg/errors":
+			w.Write([]byte(`{"stargazers_count": 8200, "archived": true, "pushed_at": "2021-11-02T10:00:00Z"}`))
+		case "/repos/old/lib":
+			w.Write([]byte(`{"stargazers_count": 12, "pushed_at": "2019-03-01T10:00:00Z"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	client, _ := testGitHubClient(t, server, now)
+
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {
+				"github.com/spf13/cobra@v1.8.0", "github.com/spf13/cobra@v1.7.0",
+				"github.com/pkg/errors@v0.9.1", "github.com/old/lib@v1.0.0",
+				"github.com/gone/repo@v1.0.0", "golang.org/x/text@v0.14.0",
+			},
+		},
+	}
+	g.expandRoot("example.com/app")
+
+	err := fetchRepoStatuses(g, client, 2*365*24*time.Hour, now)
+	if err == nil || !strings.Contains(err.Error(), "gone/repo") {
+		t.Errorf("Expected an error for the missing repository, got %v", err)
+	}
+	if n := cobraRequests.Load(); n != 1 {
+		t.Errorf("Fetched spf13/cobra %d times, want once for both versions", n)
+	}
+
+	want := map[string]string{
+		"github.com/spf13/cobra@v1.8.0": "(★ 38000, last push 2026-09-01)",
+		"github.com/spf13/cobra@v1.7.0": "(★ 38000, last push 2026-09-01)",
+		"github.com/pkg/errors@v0.9.1":  "(archived, ★ 8200, last push 2021-11-02)",
+		"github.com/old/lib@v1.0.0":     "(stale, ★ 12, last push 2019-03-01)",
+	}
+	if len(g.RepoStatus) != len(want) {
+		t.Errorf("RepoStatus has %d modules, want %d: %v", len(g.RepoStatus), len(want), g.RepoStatus)
+	}
+	for name, label := range want {
+		if got := repoStatusLabel(g.RepoStatus[name]); got != label {
+			t.Errorf("label of %s = %q, want %q", name, got, label)
+		}
+		if node := g.Root.Children[name]; node == nil || len(node.Annotations) != 1 || node.Annotations[0] != label {
+			t.Errorf("node %s not annotated with %q", name, label)
+		}
+	}
+
+	out, err := Render("export", g, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"github.com/pkg/errors@v0.9.1 (archived, ★ 8200, last push 2021-11-02)\n",
+		"\n1 archived and 1 stale of 4 module(s) on GitHub\n",
+	} {
+		if !strings.Contains(string(out), line) {
+			t.Errorf("export output missing %q:\n%s", line, out)
+		}
+	}
+}
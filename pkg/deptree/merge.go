@@ -0,0 +1,147 @@
+package deptree
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Conflict is a module that two merged graphs resolve to different versions.
+type Conflict struct {
+	Path string
+	// Ours and Theirs are the highest version of Path in the receiver and in
+	// the merged graph respectively.
+	Ours   string
+	Theirs string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %s vs %s", c.Path, c.Ours, c.Theirs)
+}
+
+// Merge adds the modules, requirement edges and metadata of other to g, so
+// that graphs of several binaries or repositories can be analyzed as one.
+// Metadata already present in g wins, per module. The warnings of both
+// graphs are kept, once each, and so are their partial and pruned modules.
+// The tree at g.Root is kept as is (or taken from other when g has none);
+// Modules, Edges and the renderers that build on them see the combined
+// graph.
+//
+// Merge returns the modules both graphs contain at different highest
+// versions, sorted by path. Since 'go mod graph' lists every required
+// version, the highest one is the version each graph selects.
+func (g *Graph) Merge(other *Graph) []Conflict {
+	conflicts := versionConflicts(g, other)
+
+	if g.Deps == nil {
+		g.Deps = make(map[string][]string)
+	}
+	for from, tos := range other.Deps {
+		existing := make(map[string]bool)
+		for _, to := range g.Deps[from] {
+			existing[to] = true
+		}
+		// Keep an entry for leaf modules so they stay in Modules
+		if _, ok := g.Deps[from]; !ok {
+			g.Deps[from] = []string{}
+		}
+		for _, to := range tos {
+			if !existing[to] {
+				existing[to] = true
+				g.Deps[from] = append(g.Deps[from], to)
+			}
+		}
+	}
+
+	g.Descriptions = mergeMissing(g.Descriptions, other.Descriptions)
+	g.DescriptionErrors = mergeMissing(g.DescriptionErrors, other.DescriptionErrors)
+	g.Links = mergeMissing(g.Links, other.Links)
+	g.Licenses = mergeMissing(g.Licenses, other.Licenses)
+	g.LicenseErrors = mergeMissing(g.LicenseErrors, other.LicenseErrors)
+	g.ImportedBy = mergeMissing(g.ImportedBy, other.ImportedBy)
+	g.Vulnerabilities = mergeMissing(g.Vulnerabilities, other.Vulnerabilities)
+	g.VulnerabilityDetails = mergeMissing(g.VulnerabilityDetails, other.VulnerabilityDetails)
+	g.Outdated = mergeMissing(g.Outdated, other.Outdated)
+	g.OutdatedErrors = mergeMissing(g.OutdatedErrors, other.OutdatedErrors)
+	g.Sizes = mergeMissing(g.Sizes, other.Sizes)
+	g.RepoStatus = mergeMissing(g.RepoStatus, other.RepoStatus)
+	g.Scorecards = mergeMissing(g.Scorecards, other.Scorecards)
+	g.TestOnly = mergeMissing(g.TestOnly, other.TestOnly)
+	g.ModFiles = mergeMissing(g.ModFiles, other.ModFiles)
+	g.Sums = mergeMissing(g.Sums, other.Sums)
+
+	g.Pruned = mergeSorted(g.Pruned, other.Pruned)
+	g.Partial = mergeSorted(g.Partial, other.Partial)
+	for _, w := range other.Warnings {
+		if !slices.Contains(g.Warnings, w) {
+			g.Warnings = append(g.Warnings, w)
+		}
+	}
+
+	if g.Root == nil {
+		g.Root = other.Root
+		g.ModFile = other.ModFile
+		g.RootModFile = other.RootModFile
+	}
+	g.syncDescriptions()
+
+	return conflicts
+}
+
+// mergeMissing copies the entries of src whose keys are not in dst.
+func mergeMissing[M ~map[string]V, V any](dst, src M) M {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(M, len(src))
+	}
+	for k, v := range src {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+// mergeSorted returns the sorted union of two module lists, or nil if both
+// are empty.
+func mergeSorted(a, b []string) []string {
+	names := make(map[string]bool)
+	for _, name := range append(slices.Clone(a), b...) {
+		names[name] = true
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(names))
+}
+
+func versionConflicts(a, b *Graph) []Conflict {
+	ours := highestVersions(a)
+	theirs := highestVersions(b)
+
+	var conflicts []Conflict
+	for _, path := range slices.Sorted(maps.Keys(ours)) {
+		if v, ok := theirs[path]; ok && v != ours[path] {
+			conflicts = append(conflicts, Conflict{Path: path, Ours: ours[path], Theirs: v})
+		}
+	}
+	return conflicts
+}
+
+// highestVersions maps every versioned module path in g to the highest
+// version of it the graph contains.
+func highestVersions(g *Graph) map[string]string {
+	highest := make(map[string]string)
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		if current, ok := highest[path]; !ok || CompareVersions(version, current) > 0 {
+			highest[path] = version
+		}
+	}
+	return highest
+}
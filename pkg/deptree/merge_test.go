@@ -0,0 +1,132 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphMerge(t *testing.T) {
+	a := &Graph{
+		Root: NewNode("example.com/a"),
+		Deps: map[string][]string{
+			"example.com/a":            {"golang.org/x/sys@v0.5.0", "golang.org/x/text@v0.3.0"},
+			"golang.org/x/text@v0.3.0": {"golang.org/x/sys@v0.1.0"},
+		},
+		Licenses: map[string]string{"golang.org/x/sys@v0.5.0": "BSD-3-Clause"},
+	}
+	b := &Graph{
+		Root: NewNode("example.com/b"),
+		Deps: map[string][]string{
+			"example.com/b":            {"golang.org/x/sys@v0.6.0", "golang.org/x/text@v0.3.0"},
+			"golang.org/x/text@v0.3.0": {"golang.org/x/sys@v0.1.0"},
+		},
+		Licenses: map[string]string{
+			"golang.org/x/sys@v0.5.0": "MIT",
+			"golang.org/x/sys@v0.6.0": "BSD-3-Clause",
+		},
+		Pruned: []string{"golang.org/x/sys@v0.1.0"},
+	}
+
+	conflicts := a.Merge(b)
+
+	wantConflicts := []Conflict{{Path: "golang.org/x/sys", Ours: "v0.5.0", Theirs: "v0.6.0"}}
+	if !reflect.DeepEqual(conflicts, wantConflicts) {
+		t.Errorf("Merge() conflicts = %v, want %v", conflicts, wantConflicts)
+	}
+
+	wantModules := []string{
+		"example.com/a",
+		"example.com/b",
+		"golang.org/x/sys@v0.1.0",
+		"golang.org/x/sys@v0.5.0",
+		"golang.org/x/sys@v0.6.0",
+		"golang.org/x/text@v0.3.0",
+	}
+	if got := a.Modules(); !reflect.DeepEqual(got, wantModules) {
+		t.Errorf("Modules() = %v, want %v", got, wantModules)
+	}
+
+	// Shared edges are not duplicated
+	if got := a.Deps["golang.org/x/text@v0.3.0"]; len(got) != 1 {
+		t.Errorf("Expected shared edge once, got %v", got)
+	}
+
+	// Existing metadata wins, missing metadata is added
+	if a.Licenses["golang.org/x/sys@v0.5.0"] != "BSD-3-Clause" || a.Licenses["golang.org/x/sys@v0.6.0"] != "BSD-3-Clause" {
+		t.Errorf("Unexpected merged licenses: %v", a.Licenses)
+	}
+	if a.Root.Name != "example.com/a" {
+		t.Errorf("Expected root to be kept, got %s", a.Root.Name)
+	}
+	if !reflect.DeepEqual(a.Pruned, []string{"golang.org/x/sys@v0.1.0"}) {
+		t.Errorf("Unexpected pruned versions: %v", a.Pruned)
+	}
+}
+
+func TestGraphMergeIntoEmpty(t *testing.T) {
+	g := &Graph{}
+	other := &Graph{
+		Root: NewNode("example.com/b"),
+		Deps: map[string][]string{"example.com/b": {"dep@v1.0.0"}},
+	}
+
+	if conflicts := g.Merge(other); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %v", conflicts)
+	}
+	if g.Root != other.Root {
+		t.Error("Expected root to be taken from the merged graph")
+	}
+	if len(g.Modules()) != 2 {
+		t.Errorf("Expected 2 modules, got %v", g.Modules())
+	}
+}
+
+func TestGraphMergeMetadata(t *testing.T) {
+	const dep = "example.com/dep@v1.0.0"
+	g := &Graph{Root: NewNode("example.com/a"), Deps: map[string][]string{"example.com/a": {}}}
+	other := &Graph{
+		Root:                 NewNode("example.com/b"),
+		Deps:                 map[string][]string{"example.com/b": {dep}},
+		Descriptions:         map[string]string{dep: "(not found)"},
+		DescriptionErrors:    map[string]error{dep: errNoDescription},
+		Links:                map[string]SourceLinks{dep: {Homepage: "https://example.com"}},
+		Licenses:             map[string]string{dep: UnknownLicense},
+		LicenseErrors:        map[string]error{dep: ErrBudgetExceeded},
+		ImportedBy:           map[string]int{dep: 3},
+		Vulnerabilities:      map[string][]string{dep: {"GO-2024-0001"}},
+		VulnerabilityDetails: map[string][]Vulnerability{dep: {{ID: "GO-2024-0001"}}},
+		Outdated:             map[string]string{dep: "v1.1.0"},
+		OutdatedErrors:       map[string]error{"example.com/other": ErrBudgetExceeded},
+		Sizes:                map[string]int64{dep: 1024},
+		RepoStatus:           map[string]RepoStatus{dep: {Stars: 7}},
+		Scorecards:           map[string]Scorecard{dep: {Score: 6.5}},
+		TestOnly:             map[string]bool{dep: true},
+		ModFiles:             map[string]*ModFile{dep: {Go: "1.22"}},
+		Sums:                 GoSum{dep: "h1:abc="},
+		Partial:              []string{dep},
+		Pruned:               []string{"example.com/dep@v0.9.0"},
+		Warnings:             []Warning{{Kind: WarnMetadata, Message: "failed to fetch"}},
+	}
+
+	g.Merge(other)
+	g.Merge(other)
+
+	// Every exported map or slice of metadata must have been merged
+	gv := reflect.ValueOf(g).Elem()
+	for i := range gv.NumField() {
+		field := gv.Type().Field(i)
+		switch field.Name {
+		case "Root", "Deps", "ModFile", "RootModFile", "Workspace", "Timings":
+			continue
+		}
+		if value := gv.Field(i); (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.Len() == 0 {
+			t.Errorf("Merge() left %s empty", field.Name)
+		}
+	}
+	if len(g.Warnings) != 1 {
+		t.Errorf("Expected the warning once, got %v", g.Warnings)
+	}
+	if got := g.VulnerabilityDetails[dep]; len(got) != 1 || got[0].ID != "GO-2024-0001" {
+		t.Errorf("Unexpected merged vulnerability details: %v", got)
+	}
+}
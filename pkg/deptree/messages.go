@@ -0,0 +1,56 @@
+package deptree
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLang is the language of report headings and labels unless
+// RenderOptions.Lang selects another.
+const DefaultLang = "en"
+
+// messageFiles is the message catalog: one messages/<lang>.json file per
+// language, mapping message keys to translations. Keys missing from a
+// translation fall back to English.
+//
+//go:embed messages/*.json
+var messageFiles embed.FS
+
+var catalog = sync.OnceValue(func() map[string]map[string]string {
+	files, _ := messageFiles.ReadDir("messages")
+	catalog := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := messageFiles.ReadFile("messages/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid message catalog %s: %v", file.Name(), err))
+		}
+		catalog[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = messages
+	}
+	return catalog
+})
+
+// Languages returns the languages report headings and labels are available
+// in, sorted.
+func Languages() []string {
+	return slices.Sorted(maps.Keys(catalog()))
+}
+
+// messagesFor returns the messages of lang, falling back to English for
+// unknown languages and untranslated keys.
+func messagesFor(lang string) map[string]string {
+	messages := maps.Clone(catalog()[DefaultLang])
+	if lang != "" && lang != DefaultLang {
+		maps.Copy(messages, catalog()[lang])
+	}
+	return messages
+}
@@ -0,0 +1,58 @@
+package deptree
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMessageCatalogComplete(t *testing.T) {
+	english := catalog()[DefaultLang]
+	if len(english) == 0 {
+		t.Fatal("English message catalog is empty")
+	}
+	for _, lang := range Languages() {
+		messages := catalog()[lang]
+		for _, key := range slices.Sorted(maps.Keys(english)) {
+			if messages[key] == "" {
+				t.Errorf("%s: missing translation of %q", lang, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%s: unknown message key %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestLocalizedReports(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{"mymodule": {"dep1@v1.0.0"}},
+	}
+	g.Root.Children["dep1@v1.0.0"] = NewNode("dep1@v1.0.0")
+
+	out, err := mdTableRenderer{}.Render(g, RenderOptions{Lang: "de"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "| Modul | Version | Lizenz | Beschreibung |\n") {
+		t.Errorf("Expected German table header, got:\n%s", out)
+	}
+
+	out, err = htmlRenderer{}.Render(g, RenderOptions{Lang: "fr"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{`<html lang="fr">`, "Dépendances de", "<p>2 modules</p>", `placeholder="Rechercher des modules..."`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %q in French report", want)
+		}
+	}
+
+	if messagesFor("xx")["module"] != "Module" {
+		t.Error("Expected unknown languages to fall back to English")
+	}
+}
@@ -0,0 +1,54 @@
+package deptree
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// ReadModFiles stores the go.mod of every module version in g, as found in
+// the module cache, in g.ModFiles. It makes no network requests: modules
+// whose go.mod has not been downloaded, or that are already in g.ModFiles,
+// are left out.
+func ReadModFiles(g *Graph) error {
+	if _, err := goModCache(); err != nil {
+		return err
+	}
+	return readModFiles(g, func(path, version string) ([]byte, error) {
+		file, err := ModuleCacheFile(path, version, "mod")
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(file)
+	})
+}
+
+// readModFiles reads the go.mod files of g through readFile, which reports
+// missing files with fs.ErrNotExist.
+func readModFiles(g *Graph, readFile func(path, version string) ([]byte, error)) error {
+	if g.ModFiles == nil {
+		g.ModFiles = make(map[string]*ModFile)
+	}
+
+	var errs []error
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" || g.ModFiles[name] != nil {
+			continue
+		}
+		data, err := readFile(path, version)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			var mf *ModFile
+			if mf, err = ParseModFile(data); err == nil {
+				g.ModFiles[name] = mf
+				continue
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestReadModFiles(t *testing.T) {
+	g := &Graph{Deps: map[string][]string{
+		"example.com/app": {"example.com/old@v1.0.0", "example.com/missing@v0.1.0"},
+	}}
+	reads := 0
+	readFile := func(path, version string) ([]byte, error) {
+		reads++
+		if path == "example.com/old" {
+			return []byte("// Deprecated: use example.com/new\nmodule example.com/old\n\ngo 1.16\n\nretract v0.9.0 // broken\n"), nil
+		}
+		return nil, fmt.Errorf("open %s@%s.mod: %w", path, version, fs.ErrNotExist)
+	}
+
+	if err := readModFiles(g, readFile); err != nil {
+		t.Fatalf("readModFiles failed: %v", err)
+	}
+	if len(g.ModFiles) != 1 || g.ModFiles["example.com/old@v1.0.0"] == nil {
+		t.Fatalf("ModFiles = %v, want only example.com/old@v1.0.0", g.ModFiles)
+	}
+	if reads != 2 {
+		t.Errorf("Read %d go.mod files, want 2 (the main module has no version)", reads)
+	}
+
+	g.Root = NewNode("example.com/app")
+	data, err := jsonRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var out struct {
+		Modules []jsonModule `json:"modules"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	for _, m := range out.Modules {
+		switch m.Path {
+		case "example.com/old":
+			want := jsonGoMod{Module: "example.com/old", Go: "1.16", Deprecated: "use example.com/new", Retract: []ModRetract{{Low: "v0.9.0", High: "v0.9.0", Rationale: "broken"}}}
+			if m.GoMod == nil || fmt.Sprint(*m.GoMod) != fmt.Sprint(want) {
+				t.Errorf("goMod = %+v, want %+v", m.GoMod, want)
+			}
+		default:
+			if m.GoMod != nil {
+				t.Errorf("%s: unexpected goMod %+v", m.Name, m.GoMod)
+			}
+		}
+	}
+
+	// Modules already read, e.g. by the proxy engine, are not read again.
+	reads = 0
+	if err := readModFiles(g, readFile); err != nil || reads != 1 {
+		t.Errorf("Second read: err %v, %d reads, want 1", err, reads)
+	}
+}
@@ -0,0 +1,178 @@
+package deptree
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// License families, from the fewest obligations to the most.
+const (
+	FamilyPublicDomain    = "public-domain"
+	FamilyPermissive      = "permissive"
+	FamilyWeakCopyleft    = "weak-copyleft"
+	FamilyStrongCopyleft  = "strong-copyleft"
+	FamilyNetworkCopyleft = "network-copyleft"
+)
+
+// licenseFamilies orders the families in obligation summaries, with their
+// headings.
+var licenseFamilies = []struct {
+	id, heading string
+}{
+	{FamilyPublicDomain, "Public domain equivalent"},
+	{FamilyPermissive, "Permissive"},
+	{FamilyWeakCopyleft, "Weak copyleft"},
+	{FamilyStrongCopyleft, "Strong copyleft"},
+	{FamilyNetworkCopyleft, "Network copyleft"},
+}
+
+// copyleftScopes describes what each LicenseTerms.Copyleft scope requires.
+var copyleftScopes = map[string]string{
+	"file":    "modified files must be made available in source form under the same license",
+	"library": "the library's source, with any changes, must be offered under the same license, and users must be able to relink the program against a modified library",
+	"strong":  "distributing a program that includes it requires offering the complete source of the program under the same license",
+	"network": "as strong copyleft, and letting users interact with a modified program over a network counts as distributing it",
+}
+
+// LicenseTerms is what the knowledge base records about the obligations
+// of one SPDX license.
+type LicenseTerms struct {
+	Name   string `json:"name"`
+	Family string `json:"family"`
+	// Attribution means copyright notices and the license text must
+	// accompany distributions.
+	Attribution bool `json:"attribution"`
+	// Copyleft is the scope of the copyleft, a key of copyleftScopes, or
+	// empty for none.
+	Copyleft string `json:"copyleft"`
+	// PatentGrant means contributors grant a patent license, which ends
+	// for whoever sues over patents in the work.
+	PatentGrant bool `json:"patentGrant"`
+	// Duties are further obligations specific to the license.
+	Duties []string `json:"duties"`
+}
+
+// obligationsData is the embedded knowledge base, mapping SPDX license
+// identifiers to their LicenseTerms.
+//
+//go:embed spdx/obligations.json
+var obligationsData []byte
+
+var licenseTerms = sync.OnceValue(func() map[string]LicenseTerms {
+	var terms map[string]LicenseTerms
+	if err := json.Unmarshal(obligationsData, &terms); err != nil {
+		panic(fmt.Sprintf("invalid license knowledge base: %v", err))
+	}
+	return terms
+})
+
+// LookupLicenseTerms returns the obligations of the license with the given
+// SPDX identifier. The -only and -or-later variants of GNU licenses share
+// the terms of their base identifier.
+func LookupLicenseTerms(id string) (LicenseTerms, bool) {
+	id = strings.TrimSuffix(strings.TrimSuffix(id, "-only"), "-or-later")
+	terms, ok := licenseTerms()[id]
+	return terms, ok
+}
+
+// RenderObligations summarizes what the licenses in g.Licenses oblige
+// users of the graph to do, per license family, followed by the modules
+// whose license is unknown or missing from the knowledge base, which need a
+// manual review.
+func RenderObligations(g *Graph) []byte {
+	var buf bytes.Buffer
+	counts := g.LicenseCounts()
+	byFamily := make(map[string][]string)
+	var unreviewed []string
+	for _, license := range sortedLicenses(counts) {
+		if terms, ok := LookupLicenseTerms(license); ok {
+			byFamily[terms.Family] = append(byFamily[terms.Family], license)
+		} else {
+			unreviewed = append(unreviewed, license)
+		}
+	}
+
+	for _, family := range licenseFamilies {
+		licenses := byFamily[family.id]
+		if len(licenses) == 0 {
+			continue
+		}
+		used := make([]string, len(licenses))
+		for i, license := range licenses {
+			used[i] = fmt.Sprintf("%s (%d)", license, counts[license])
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", family.heading, strings.Join(used, ", "))
+		for _, obligation := range familyObligations(licenses) {
+			fmt.Fprintf(&buf, "  - %s\n", obligation)
+		}
+		buf.WriteByte('\n')
+	}
+
+	if len(unreviewed) > 0 {
+		fmt.Fprintln(&buf, "Needs review, license unknown or not in the knowledge base:")
+		for _, module := range slices.Sorted(maps.Keys(g.Licenses)) {
+			if slices.Contains(unreviewed, g.Licenses[module]) {
+				fmt.Fprintf(&buf, "  - %s (%s)\n", module, g.Licenses[module])
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	known := len(g.Licenses)
+	for _, license := range unreviewed {
+		known -= counts[license]
+	}
+	fmt.Fprintf(&buf, "%d of %d module(s) with known license obligations\n", known, len(g.Licenses))
+	return buf.Bytes()
+}
+
+// familyObligations lists the obligations of licenses of one family, each
+// naming the licenses it applies to unless it applies to all of them.
+func familyObligations(licenses []string) []string {
+	var attribution, patent []string
+	copyleft := make(map[string][]string)
+	var duties []string
+	for _, license := range licenses {
+		terms, _ := LookupLicenseTerms(license)
+		if terms.Attribution {
+			attribution = append(attribution, license)
+		}
+		if terms.Copyleft != "" {
+			copyleft[terms.Copyleft] = append(copyleft[terms.Copyleft], license)
+		}
+		if terms.PatentGrant {
+			patent = append(patent, license)
+		}
+		for _, duty := range terms.Duties {
+			duties = append(duties, fmt.Sprintf("%s: %s", license, duty))
+		}
+	}
+
+	applies := func(subset []string) string {
+		if len(subset) == len(licenses) {
+			return ""
+		}
+		return " (" + strings.Join(subset, ", ") + ")"
+	}
+
+	var obligations []string
+	if len(attribution) > 0 {
+		obligations = append(obligations, "Attribution"+applies(attribution)+": ship the copyright notices and license texts with every distribution")
+	}
+	for _, scope := range slices.Sorted(maps.Keys(copyleft)) {
+		obligations = append(obligations, "Copyleft"+applies(copyleft[scope])+": "+copyleftScopes[scope])
+	}
+	if len(patent) > 0 {
+		obligations = append(obligations, "Patent clause"+applies(patent)+": contributors grant a patent license, which ends for whoever sues over patents in the work")
+	}
+	if len(obligations) == 0 && len(duties) == 0 {
+		obligations = append(obligations, "No conditions on use or distribution")
+	}
+	return append(obligations, duties...)
+}
@@ -0,0 +1,59 @@
+package deptree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupLicenseTerms(t *testing.T) {
+	for _, id := range []string{"MIT", "Apache-2.0", "GPL-3.0-only", "LGPL-2.1-or-later"} {
+		if _, ok := LookupLicenseTerms(id); !ok {
+			t.Errorf("Expected terms for %s", id)
+		}
+	}
+	if _, ok := LookupLicenseTerms(UnknownLicense); ok {
+		t.Error("Expected no terms for an unknown license")
+	}
+
+	// Every license IdentifyLicense can report must be in the knowledge base
+	for _, sig := range licenseSignatures {
+		terms, ok := LookupLicenseTerms(sig.id)
+		if !ok {
+			t.Errorf("%s is detected but not in the knowledge base", sig.id)
+			continue
+		}
+		if terms.Copyleft != "" && copyleftScopes[terms.Copyleft] == "" {
+			t.Errorf("%s has unknown copyleft scope %q", sig.id, terms.Copyleft)
+		}
+	}
+}
+
+func TestRenderObligations(t *testing.T) {
+	g := &Graph{Licenses: map[string]string{
+		"a@v1.0.0": "MIT",
+		"b@v1.0.0": "MIT",
+		"c@v1.0.0": "Apache-2.0",
+		"d@v1.0.0": "MPL-2.0",
+		"e@v1.0.0": UnknownLicense,
+		"f@v1.0.0": "Unlicense",
+	}}
+	got := string(RenderObligations(g))
+
+	for _, want := range []string{
+		"Public domain equivalent: Unlicense (1)\n  - No conditions on use or distribution\n",
+		"Permissive: MIT (2), Apache-2.0 (1)\n  - Attribution: ship the copyright notices",
+		"  - Patent clause (Apache-2.0): contributors grant a patent license",
+		"  - Apache-2.0: ship its NOTICE file, if it has one\n",
+		"Weak copyleft: MPL-2.0 (1)\n",
+		"  - Copyleft: modified files must be made available in source form under the same license\n",
+		"Needs review, license unknown or not in the knowledge base:\n  - e@v1.0.0 (Unknown)\n",
+		"5 of 6 module(s) with known license obligations\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected obligations to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "Permissive") > strings.Index(got, "Weak copyleft") {
+		t.Errorf("Expected families ordered by obligations, got:\n%s", got)
+	}
+}
@@ -0,0 +1,103 @@
+package deptree
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Options configures how an embedding application reaches the services
+// deptree fetches metadata from. The functions of this package that query
+// GitHub, GitLab, Bitbucket, OSV, pkg.go.dev, the OpenSSF Scorecard API or
+// the checksum database take an Options, and build their request URLs from
+// its base URLs; those that only talk to module proxies take the
+// *http.Client to send requests with, and the proxy from GOPROXY or their
+// arguments.
+type Options struct {
+	// HTTPClient sends every request, so embedders can supply an
+	// instrumented client, a record/replay transport for tests, or their
+	// own proxy and authentication policy. Nil means NewHTTPClient("").
+	HTTPClient *http.Client
+
+	// Base URLs replacing those of the public services, such as a mirror
+	// or a local test server. Empty fields keep the public service.
+	//
+	// GitHubAPIURL may also be a GitHub Enterprise API, such as
+	// "https://github.example.com/api/v3" or "https://api.example.ghe.com".
+	// Modules whose path is on its host, "github.example.com" or
+	// "example.ghe.com", are then looked up there as well as those on
+	// github.com.
+	GitHubAPIURL    string // api.github.com: descriptions, licenses, maintenance, advisories
+	GitLabURL       string // gitlab.com: descriptions
+	BitbucketAPIURL string // api.bitbucket.org: descriptions
+	OSVAPIURL       string // api.osv.dev: vulnerabilities
+	PkgsiteURL      string // pkg.go.dev: descriptions, licenses, importers
+	ScorecardAPIURL string // api.securityscorecards.dev: OpenSSF Scorecard
+	SumDBURL        string // sum.golang.org: go.sum verification
+}
+
+// Validate reports base URLs of o that are not absolute URLs.
+func (o Options) Validate() error {
+	for _, base := range []struct{ name, url string }{
+		{"GitHubAPIURL", o.GitHubAPIURL},
+		{"GitLabURL", o.GitLabURL},
+		{"BitbucketAPIURL", o.BitbucketAPIURL},
+		{"OSVAPIURL", o.OSVAPIURL},
+		{"PkgsiteURL", o.PkgsiteURL},
+		{"ScorecardAPIURL", o.ScorecardAPIURL},
+		{"SumDBURL", o.SumDBURL},
+	} {
+		if base.url == "" {
+			continue
+		}
+		if u, err := url.Parse(base.url); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s %q must be an absolute URL", base.name, base.url)
+		}
+	}
+	return nil
+}
+
+// defaultHTTPClient is the client of Options without an HTTPClient.
+var defaultHTTPClient = sync.OnceValue(func() *http.Client {
+	// Without a CA bundle to read, NewHTTPClient cannot fail
+	client, _ := NewHTTPClient("")
+	return client
+})
+
+// client returns the client to send o's requests with.
+func (o Options) client() *http.Client {
+	if o.HTTPClient != nil {
+		return o.HTTPClient
+	}
+	return defaultHTTPClient()
+}
+
+// baseURL returns base without a trailing slash, or def if base is empty.
+func baseURL(base, def string) string {
+	if base == "" {
+		return def
+	}
+	return strings.TrimSuffix(base, "/")
+}
+
+func (o Options) githubAPIURL() string    { return baseURL(o.GitHubAPIURL, githubAPIURL) }
+func (o Options) gitlabURL() string       { return baseURL(o.GitLabURL, gitlabAPIURL) }
+func (o Options) bitbucketAPIURL() string { return baseURL(o.BitbucketAPIURL, bitbucketAPIURL) }
+func (o Options) osvAPIURL() string       { return baseURL(o.OSVAPIURL, osvAPIURL) }
+func (o Options) pkgsiteURL() string      { return baseURL(o.PkgsiteURL, pkgsiteURL) }
+func (o Options) scorecardAPIURL() string { return baseURL(o.ScorecardAPIURL, scorecardAPIURL) }
+func (o Options) sumDBURL() string        { return baseURL(o.SumDBURL, sumdbURL) }
+
+// githubEnterpriseHost returns the host of the module paths a GitHub
+// Enterprise GitHubAPIURL serves besides github.com: the host of the URL
+// without the "api." of GitHub Enterprise Cloud API hosts, or "" without a
+// GitHubAPIURL.
+func (o Options) githubEnterpriseHost() string {
+	u, err := url.Parse(o.GitHubAPIURL)
+	if o.GitHubAPIURL == "" || err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "api.")
+}
@@ -0,0 +1,101 @@
+package deptree
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// countingTransport stands in for an embedder's instrumented transport.
+type countingTransport struct {
+	base http.RoundTripper
+	urls []string
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.urls = append(t.urls, req.URL.Host+req.URL.Path)
+	return t.base.RoundTrip(req)
+}
+
+func TestOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/scorecard/projects/github.com/spf13/cobra":
+			w.Write([]byte(`{"score": 6.8}`))
+		case "/api/v3/repos/spf13/cobra":
+			w.Write([]byte(`{"description": "A Commander for modern Go CLI interactions"}`))
+		case "/api/v3/repos/platform/auth":
+			w.Write([]byte(`{"description": "Internal authentication"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// Every host resolves to the test server, like a GitHub Enterprise
+	// host would to the appliance
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return new(net.Dialer).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	transport := &countingTransport{base: &http.Transport{DialContext: dial}}
+	opts := Options{
+		HTTPClient:      &http.Client{Transport: transport},
+		ScorecardAPIURL: "http://scorecard.example.com/scorecard/",
+		GitHubAPIURL:    "http://github.example.com/api/v3",
+	}
+	if err := opts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := FetchScorecard(opts, "spf13", "cobra")
+	if err != nil || sc.Score != 6.8 {
+		t.Errorf("FetchScorecard at the replaced base URL = %+v, %v", sc, err)
+	}
+	github := NewGitHubClient(opts, "")
+	for module, want := range map[string]string{
+		"github.com/spf13/cobra@v1.8.0":           "A Commander for modern Go CLI interactions",
+		"github.example.com/platform/auth@v1.2.0": "Internal authentication",
+	} {
+		repo, err := github.Repo(module)
+		if err != nil || repo.Description != want {
+			t.Errorf("Repo(%s) = %+v, %v; want %q", module, repo, err, want)
+		}
+	}
+	if _, err := github.Repo("gitlab.example.com/platform/auth@v1.2.0"); err == nil {
+		t.Error("Expected modules on other hosts not to be looked up on GitHub")
+	}
+
+	slices.Sort(transport.urls)
+	want := []string{
+		"github.example.com/api/v3/repos/platform/auth",
+		"github.example.com/api/v3/repos/spf13/cobra",
+		"scorecard.example.com/scorecard/projects/github.com/spf13/cobra",
+	}
+	if !slices.Equal(transport.urls, want) {
+		t.Errorf("Requests through the supplied client = %v, want %v", transport.urls, want)
+	}
+
+	if err := (Options{OSVAPIURL: "osv.internal"}).Validate(); err == nil {
+		t.Error("Expected an error for a relative base URL")
+	}
+}
+
+func TestOptionsGitHubEnterpriseHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"", ""},
+		{"https://github.example.com/api/v3", "github.example.com"},
+		{"https://api.example.ghe.com", "example.ghe.com"},
+		{"https://api.github.com/", "github.com"},
+	}
+	for _, tt := range tests {
+		if got := (Options{GitHubAPIURL: tt.url}).githubEnterpriseHost(); got != tt.want {
+			t.Errorf("githubEnterpriseHost(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
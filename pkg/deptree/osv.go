@@ -0,0 +1,467 @@
+package deptree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var osvAPIURL = "https://api.osv.dev"
+
+// osvBatchSize is the maximum number of queries OSV accepts per batch request.
+const osvBatchSize = 1000
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// QueryVulnerabilities returns the OSV advisory IDs affecting each of the
+// given path@version modules. Modules without known advisories are omitted.
+func QueryVulnerabilities(opts Options, modules []string) (map[string][]string, error) {
+	var queried []string
+	var queries []osvQuery
+	for _, name := range modules {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		queried = append(queried, name)
+		queries = append(queries, osvQuery{
+			Package: osvPackage{Name: path, Ecosystem: "Go"},
+			// OSV records Go versions without the "v" prefix
+			Version: strings.TrimPrefix(version, "v"),
+		})
+	}
+
+	vulns := make(map[string][]string)
+	for start := 0; start < len(queries); start += osvBatchSize {
+		end := min(start+osvBatchSize, len(queries))
+
+		resp, err := postOSVBatch(opts, queries[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Results) != end-start {
+			return nil, fmt.Errorf("OSV returned %d results for %d queries", len(resp.Results), end-start)
+		}
+
+		for i, result := range resp.Results {
+			for _, v := range result.Vulns {
+				name := queried[start+i]
+				vulns[name] = append(vulns[name], v.ID)
+			}
+		}
+	}
+
+	return vulns, nil
+}
+
+// Vulnerability is one OSV advisory affecting a module version.
+type Vulnerability struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary,omitempty"`
+	// Severity is taken from the advisory's CVSS v3 vector or, failing
+	// that, its database rating. Go advisories without either are rated
+	// through their GitHub (GHSA) alias.
+	Severity Severity `json:"severity"`
+	// Score is the CVSS v3 base score, or zero when the advisory has none.
+	Score float64 `json:"score,omitempty"`
+	// Fixed is the first version fixing the affected version, or empty if
+	// no fix has been released.
+	Fixed string `json:"fixed,omitempty"`
+	// Symbols are the affected functions and methods, qualified by package
+	// name, e.g. "language.Parse".
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// OSVEntry is an advisory in the OSV schema, reduced to the fields deptree
+// reads. Every VulnDB describes its advisories this way.
+type OSVEntry struct {
+	ID      string   `json:"id"`
+	Summary string   `json:"summary"`
+	Aliases []string `json:"aliases"`
+	// Published is when the advisory was first published; zero if the
+	// database does not say.
+	Published time.Time `json:"published"`
+	// Withdrawn is set when the advisory was retracted.
+	Withdrawn        string        `json:"withdrawn"`
+	Severity         []osvSeverity `json:"severity"`
+	Affected         []osvAffected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package osvPackage `json:"package"`
+	// Versions lists affected versions in addition to Ranges.
+	Versions          []string   `json:"versions"`
+	Ranges            []osvRange `json:"ranges"`
+	EcosystemSpecific struct {
+		Imports []struct {
+			Path    string   `json:"path"`
+			Symbols []string `json:"symbols"`
+		} `json:"imports"`
+	} `json:"ecosystem_specific"`
+}
+
+type osvRange struct {
+	Type   string              `json:"type"`
+	Events []map[string]string `json:"events"`
+}
+
+// advisory is a looked up OSV entry with its resolved severity.
+type advisory struct {
+	vuln     *OSVEntry
+	severity Severity
+	score    float64
+}
+
+// osvWorkers bounds the number of concurrent advisory lookups.
+const osvWorkers = 8
+
+// DetectVulnerabilities queries db for every module in g, looks up the
+// details of each advisory found, and keeps those rated minSeverity or
+// higher. Advisories whose severity is unknown are always kept. The IDs are
+// stored in g.Vulnerabilities, the details in g.VulnerabilityDetails, and
+// the affected tree nodes are annotated.
+func DetectVulnerabilities(g *Graph, db VulnDB, minSeverity Severity) error {
+	found, err := db.Query(g.Modules())
+	if err != nil {
+		return err
+	}
+
+	unique := make(map[string]bool)
+	for _, ids := range found {
+		for _, id := range ids {
+			unique[id] = true
+		}
+	}
+	advisories := fetchAdvisories(db, slices.Sorted(maps.Keys(unique)))
+
+	g.Vulnerabilities = make(map[string][]string)
+	g.VulnerabilityDetails = make(map[string][]Vulnerability)
+	for name, ids := range found {
+		sort.Strings(ids)
+		path, version := SplitModule(name)
+		for _, id := range ids {
+			v := Vulnerability{ID: id}
+			if a := advisories[id]; a != nil {
+				v.Summary = a.vuln.Summary
+				v.Severity = a.severity
+				v.Score = a.score
+				v.Fixed = a.vuln.fixedVersion(path, version)
+				v.Symbols = a.vuln.symbols(path)
+			}
+			if v.Severity != SeverityUnknown && v.Severity < minSeverity {
+				continue
+			}
+			g.Vulnerabilities[name] = append(g.Vulnerabilities[name], id)
+			g.VulnerabilityDetails[name] = append(g.VulnerabilityDetails[name], v)
+		}
+	}
+
+	g.Walk(func(node *Node) {
+		// Replace the markers of an earlier scan, e.g. of a loaded snapshot
+		node.Annotations = slices.DeleteFunc(node.Annotations, func(a string) bool {
+			return strings.HasPrefix(a, "(vulnerable: ")
+		})
+		if vulns := g.VulnerabilityDetails[node.Name]; len(vulns) > 0 {
+			node.Annotations = append(node.Annotations, vulnerableLabel(vulns))
+		}
+	})
+
+	return nil
+}
+
+// maxLabelSymbols caps the affected symbols listed inline per advisory.
+const maxLabelSymbols = 3
+
+// vulnerableLabel formats vulns as "(vulnerable: ID [details], ...)", where
+// the details are the severity, the fixed version and the affected symbols
+// as far as they are known.
+func vulnerableLabel(vulns []Vulnerability) string {
+	var entries []string
+	for _, v := range vulns {
+		var details []string
+		if v.Severity != SeverityUnknown {
+			if v.Score > 0 {
+				details = append(details, fmt.Sprintf("%s %.1f", v.Severity, v.Score))
+			} else {
+				details = append(details, v.Severity.String())
+			}
+		}
+		if v.Fixed != "" {
+			details = append(details, "fixed in "+v.Fixed)
+		}
+		if len(v.Symbols) > 0 {
+			symbols := strings.Join(v.Symbols[:min(len(v.Symbols), maxLabelSymbols)], ", ")
+			if extra := len(v.Symbols) - maxLabelSymbols; extra > 0 {
+				symbols += fmt.Sprintf(" +%d more", extra)
+			}
+			details = append(details, "affects "+symbols)
+		}
+
+		if len(details) > 0 {
+			entries = append(entries, fmt.Sprintf("%s [%s]", v.ID, strings.Join(details, ", ")))
+		} else {
+			entries = append(entries, v.ID)
+		}
+	}
+	return fmt.Sprintf("(vulnerable: %s)", strings.Join(entries, ", "))
+}
+
+// fetchAdvisories looks up the given advisory IDs concurrently. IDs that
+// cannot be looked up are left out, so that their findings are still
+// reported without details.
+func fetchAdvisories(db VulnDB, ids []string) map[string]*advisory {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, osvWorkers)
+	advisories := make(map[string]*advisory)
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			a, err := fetchAdvisory(db, id)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			advisories[id] = a
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	return advisories
+}
+
+// fetchAdvisory looks up one advisory and resolves its severity, consulting
+// its GHSA alias when the advisory itself is unrated.
+func fetchAdvisory(db VulnDB, id string) (*advisory, error) {
+	vuln, err := db.Lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &advisory{vuln: vuln}
+	a.severity, a.score = vuln.rating()
+	if a.severity != SeverityUnknown {
+		return a, nil
+	}
+	for _, alias := range vuln.Aliases {
+		if !strings.HasPrefix(alias, "GHSA-") {
+			continue
+		}
+		if ghsa, err := db.Lookup(alias); err == nil {
+			a.severity, a.score = ghsa.rating()
+		}
+		break
+	}
+	return a, nil
+}
+
+// rating returns the severity of v from its CVSS v3 vector, or from its
+// database rating when it has no usable vector.
+func (v *OSVEntry) rating() (Severity, float64) {
+	for _, s := range v.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, err := CVSS3BaseScore(s.Score); err == nil {
+			return severityForScore(score), score
+		}
+	}
+	severity, _ := ParseSeverity(v.DatabaseSpecific.Severity)
+	return severity, 0
+}
+
+// fixedVersion returns the version fixing the range that contains version of
+// module path, or "" if there is none.
+func (v *OSVEntry) fixedVersion(path, version string) string {
+	for _, affected := range v.Affected {
+		if affected.Package.Name != path || affected.Package.Ecosystem != "Go" {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			if r.Type != "SEMVER" {
+				continue
+			}
+			introduced := ""
+			for _, event := range r.Events {
+				if e, ok := event["introduced"]; ok {
+					introduced = osvVersion(e)
+				}
+				if e, ok := event["fixed"]; ok {
+					fixed := osvVersion(e)
+					if CompareVersions(version, introduced) >= 0 && CompareVersions(version, fixed) < 0 {
+						return fixed
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// affects reports whether version of module path is affected by v, either
+// listed explicitly or within one of its SEMVER ranges.
+func (v *OSVEntry) affects(path, version string) bool {
+	for _, affected := range v.Affected {
+		if affected.Package.Name != path || affected.Package.Ecosystem != "Go" {
+			continue
+		}
+		if slices.Contains(affected.Versions, strings.TrimPrefix(version, "v")) {
+			return true
+		}
+		for _, r := range affected.Ranges {
+			if r.Type == "SEMVER" && inOSVRange(version, r.Events) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inOSVRange reports whether version falls into one of the intervals that
+// a SEMVER range's events open with "introduced" and close with "fixed"
+// (exclusive) or "last_affected" (inclusive).
+func inOSVRange(version string, events []map[string]string) bool {
+	introduced, open := "", false
+	for _, event := range events {
+		if e, ok := event["introduced"]; ok {
+			introduced, open = osvVersion(e), true
+		}
+		if e, ok := event["fixed"]; ok && open {
+			if CompareVersions(version, introduced) >= 0 && CompareVersions(version, osvVersion(e)) < 0 {
+				return true
+			}
+			open = false
+		}
+		if e, ok := event["last_affected"]; ok && open {
+			if CompareVersions(version, introduced) >= 0 && CompareVersions(version, osvVersion(e)) <= 0 {
+				return true
+			}
+			open = false
+		}
+	}
+	return open && CompareVersions(version, introduced) >= 0
+}
+
+// symbols returns the affected symbols of module path qualified by the
+// name of their package, sorted and without duplicates.
+func (v *OSVEntry) symbols(path string) []string {
+	unique := make(map[string]bool)
+	for _, affected := range v.Affected {
+		if affected.Package.Name != path {
+			continue
+		}
+		for _, imp := range affected.EcosystemSpecific.Imports {
+			pkg := imp.Path[strings.LastIndex(imp.Path, "/")+1:]
+			for _, symbol := range imp.Symbols {
+				unique[pkg+"."+symbol] = true
+			}
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(unique))
+}
+
+// osvVersion converts an OSV Go version, which lacks the "v" prefix, to a
+// module version. The "0" introduced event, meaning all versions, maps to ""
+// which sorts before any version.
+func osvVersion(v string) string {
+	if v == "0" {
+		return ""
+	}
+	return "v" + v
+}
+
+func getOSVVuln(opts Options, id string) (*OSVEntry, error) {
+	req, err := http.NewRequest("GET", opts.osvAPIURL()+"/v1/vulns/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "deptree-cli")
+
+	resp, err := opts.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from OSV: %w", id, DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV API returned status %d for %s", resp.StatusCode, id)
+	}
+
+	var vuln OSVEntry
+	if err := json.NewDecoder(resp.Body).Decode(&vuln); err != nil {
+		return nil, fmt.Errorf("failed to parse OSV advisory %s: %w", id, err)
+	}
+	return &vuln, nil
+}
+
+func postOSVBatch(opts Options, queries []osvQuery) (*osvBatchResponse, error) {
+	body, err := json.Marshal(map[string][]osvQuery{"queries": queries})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", opts.osvAPIURL()+"/v1/querybatch", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "deptree-cli")
+
+	resp, err := opts.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OSV: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV API returned status %d", resp.StatusCode)
+	}
+
+	var batch osvBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to parse OSV response: %w", err)
+	}
+
+	return &batch, nil
+}
@@ -0,0 +1,178 @@
+package deptree
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestQueryVulnerabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/querybatch" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		var body struct {
+			Queries []osvQuery `json:"queries"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if len(body.Queries) != 2 {
+			t.Fatalf("Expected 2 queries (main module skipped), got %d", len(body.Queries))
+		}
+		if body.Queries[0].Version != "0.3.5" {
+			t.Errorf("Expected version without v prefix, got %q", body.Queries[0].Version)
+		}
+
+		w.Write([]byte(`{"results":[{"vulns":[{"id":"GO-2021-0113"}]},{}]}`))
+	}))
+	defer server.Close()
+
+	oldURL := osvAPIURL
+	osvAPIURL = server.URL
+	defer func() { osvAPIURL = oldURL }()
+
+	vulns, err := QueryVulnerabilities(Options{HTTPClient: server.Client()}, []string{"golang.org/x/text@v0.3.5", "mymodule", "safe@v1.0.0"})
+	if err != nil {
+		t.Fatalf("QueryVulnerabilities failed: %v", err)
+	}
+
+	if len(vulns) != 1 || vulns["golang.org/x/text@v0.3.5"][0] != "GO-2021-0113" {
+		t.Errorf("Unexpected result: %v", vulns)
+	}
+}
+
+func TestDetectVulnerabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"vulns":[{"id":"GO-2022-1059"},{"id":"GO-2021-0113"}]}]}`))
+	}))
+	defer server.Close()
+
+	oldURL := osvAPIURL
+	osvAPIURL = server.URL
+	defer func() { osvAPIURL = oldURL }()
+
+	root := NewNode("mymodule")
+	root.Children["golang.org/x/text@v0.3.5"] = NewNode("golang.org/x/text@v0.3.5")
+	g := &Graph{
+		Root: root,
+		Deps: map[string][]string{"mymodule": {"golang.org/x/text@v0.3.5"}},
+	}
+
+	if err := DetectVulnerabilities(g, NewOSVDB(Options{HTTPClient: server.Client()}), SeverityUnknown); err != nil {
+		t.Fatalf("DetectVulnerabilities failed: %v", err)
+	}
+
+	want := "(vulnerable: GO-2021-0113, GO-2022-1059)"
+	annotations := root.Children["golang.org/x/text@v0.3.5"].Annotations
+	if len(annotations) != 1 || annotations[0] != want {
+		t.Errorf("Expected annotation %q, got %v", want, annotations)
+	}
+	if len(root.Annotations) != 0 {
+		t.Errorf("Expected root to stay unannotated, got %v", root.Annotations)
+	}
+
+	out, err := exportRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(string(out), "golang.org/x/text@v0.3.5 "+want) {
+		t.Errorf("Expected export output to mark the vulnerable module, got:\n%s", out)
+	}
+}
+
+func TestDetectVulnerabilitiesDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			w.Write([]byte(`{"results":[{"vulns":[{"id":"GO-2021-0113"},{"id":"GO-2022-1059"}]}]}`))
+		case "/v1/vulns/GO-2021-0113":
+			w.Write([]byte(`{
+				"id": "GO-2021-0113",
+				"summary": "Out-of-bounds read in golang.org/x/text/language",
+				"aliases": ["CVE-2021-38561", "GHSA-ppp9-7jff-5vj2"],
+				"affected": [{
+					"package": {"name": "golang.org/x/text", "ecosystem": "Go"},
+					"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.7"}]}],
+					"ecosystem_specific": {"imports": [{"path": "golang.org/x/text/language", "symbols": ["Parse", "MatchStrings"]}]}
+				}]
+			}`))
+		case "/v1/vulns/GHSA-ppp9-7jff-5vj2":
+			w.Write([]byte(`{"id": "GHSA-ppp9-7jff-5vj2", "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}]}`))
+		case "/v1/vulns/GO-2022-1059":
+			w.Write([]byte(`{
+				"id": "GO-2022-1059",
+				"database_specific": {"severity": "MODERATE"},
+				"affected": [{
+					"package": {"name": "golang.org/x/text", "ecosystem": "Go"},
+					"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.8"}]}]
+				}]
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldURL := osvAPIURL
+	osvAPIURL = server.URL
+	defer func() { osvAPIURL = oldURL }()
+
+	newGraph := func() *Graph {
+		root := NewNode("mymodule")
+		root.Children["golang.org/x/text@v0.3.5"] = NewNode("golang.org/x/text@v0.3.5")
+		return &Graph{
+			Root: root,
+			Deps: map[string][]string{"mymodule": {"golang.org/x/text@v0.3.5"}},
+		}
+	}
+
+	g := newGraph()
+	if err := DetectVulnerabilities(g, NewOSVDB(Options{HTTPClient: server.Client()}), SeverityUnknown); err != nil {
+		t.Fatalf("DetectVulnerabilities failed: %v", err)
+	}
+	want := "(vulnerable: GO-2021-0113 [HIGH 7.5, fixed in v0.3.7, affects language.MatchStrings, language.Parse], GO-2022-1059 [MEDIUM, fixed in v0.3.8])"
+	if got := g.Root.Children["golang.org/x/text@v0.3.5"].Annotations; len(got) != 1 || got[0] != want {
+		t.Errorf("Expected annotation %q, got %v", want, got)
+	}
+
+	g = newGraph()
+	if err := DetectVulnerabilities(g, NewOSVDB(Options{HTTPClient: server.Client()}), SeverityHigh); err != nil {
+		t.Fatalf("DetectVulnerabilities failed: %v", err)
+	}
+	ids := g.Vulnerabilities["golang.org/x/text@v0.3.5"]
+	if len(ids) != 1 || ids[0] != "GO-2021-0113" {
+		t.Errorf("Expected only the high severity advisory, got %v", ids)
+	}
+}
+
+func TestFixedVersion(t *testing.T) {
+	var v OSVEntry
+	err := json.Unmarshal([]byte(`{"affected": [{
+		"package": {"name": "example.com/mod", "ecosystem": "Go"},
+		"ranges": [{"type": "SEMVER", "events": [
+			{"introduced": "0"}, {"fixed": "1.2.3"},
+			{"introduced": "1.5.0"}, {"fixed": "1.5.2"}
+		]}]
+	}]}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"v1.0.0", "v1.2.3"},
+		{"v1.5.1", "v1.5.2"},
+		{"v1.3.0", ""},
+	}
+	for _, tt := range tests {
+		if got := v.fixedVersion("example.com/mod", tt.version); got != tt.want {
+			t.Errorf("fixedVersion(%s) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
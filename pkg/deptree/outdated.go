@@ -0,0 +1,126 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// proxyWorkers bounds the number of concurrent module proxy requests.
+const proxyWorkers = 8
+
+// DetectOutdated looks up the latest version of every module in g on the
+// module proxy from GOPROXY, stores the newer ones in g.Outdated and
+// annotates the tree nodes of outdated modules. Private modules matching
+// GONOPROXY or GOPRIVATE are skipped without asking the proxy, as are
+// modules it does not know. Other failures are recorded in
+// g.OutdatedErrors and summarized in a WarnMetadata warning, since those
+// modules were not checked. Only the module's own major version is
+// considered, since a new major version has a different path. When GOPROXY
+// is "off", only the versions in the module cache are considered, and a
+// WarnOffline warning counts the modules it has no versions of.
+func DetectOutdated(g *Graph, client *http.Client) error {
+	proxy, err := ProxyURL()
+	if err != nil {
+		return err
+	}
+
+	latest := make(map[string]string)
+	failed := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, proxyWorkers)
+
+	noProxy := NoProxy()
+	for _, path := range g.modulePaths() {
+		if noProxy(path) {
+			continue
+		}
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			version, err := FetchLatestVersion(client, proxy, path)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, errProxyNotFound):
+			case err != nil:
+				failed[path] = err
+			default:
+				latest[path] = version
+			}
+		}(path)
+	}
+	wg.Wait()
+
+	g.OutdatedErrors = failed
+	g.warnFailures("latest version", failed)
+	if ProxyOff() {
+		if missing := len(g.modulePaths()) - len(latest); missing > 0 {
+			g.warn(WarnOffline, "", "GOPROXY=off: newer versions were looked up in the module cache only, which lists no versions of %d module(s); they were not checked", missing)
+		} else {
+			g.warn(WarnOffline, "", "GOPROXY=off: newer versions were looked up in the module cache only")
+		}
+	}
+
+	g.Outdated = make(map[string]string)
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if newer := latest[path]; version != "" && CompareVersions(newer, version) > 0 {
+			g.Outdated[name] = newer
+		}
+	}
+
+	g.Walk(func(node *Node) {
+		if newer, ok := g.Outdated[node.Name]; ok {
+			node.Annotations = append(node.Annotations, outdatedLabel(newer))
+		}
+	})
+
+	return nil
+}
+
+// modulePaths returns the distinct paths of the versioned modules in g.
+func (g *Graph) modulePaths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// outdatedLabel marks a module with the newer version available. The
+// current version is already part of the module's name.
+func outdatedLabel(latest string) string {
+	return fmt.Sprintf("(→ %s)", latest)
+}
+
+// writeOutdatedSummary appends the number of outdated modules to buf, if
+// outdated versions were checked, and that of the module paths whose
+// lookup failed.
+func writeOutdatedSummary(buf *bytes.Buffer, g *Graph) {
+	if g.Outdated == nil {
+		return
+	}
+	versioned := 0
+	for _, name := range g.Modules() {
+		if _, version := SplitModule(name); version != "" {
+			versioned++
+		}
+	}
+	fmt.Fprintf(buf, "\n%d of %d module(s) have a newer version", len(g.Outdated), versioned)
+	if len(g.OutdatedErrors) > 0 {
+		fmt.Fprintf(buf, "; %d module path(s) could not be checked", len(g.OutdatedErrors))
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,235 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProxyURL(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+		wantErr bool
+	}{
+		{"", "https://proxy.golang.org", false},
+		{"https://goproxy.example.com/,direct", "https://goproxy.example.com", false},
+		{"direct|https://proxy.example.com", "https://proxy.example.com", false},
+		{"direct", "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("GOPROXY", tt.setting)
+		got, err := ProxyURL()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("GOPROXY=%q: ProxyURL() = %q, %v; want %q", tt.setting, got, err, tt.want)
+		}
+	}
+}
+
+func TestProxySettingsFromGoEnvFile(t *testing.T) {
+	env := filepath.Join(t.TempDir(), "env")
+	if err := os.WriteFile(env, []byte("GOPROXY=https://goproxy.corp.example.com\nGOPRIVATE=corp.example.com,*.internal\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOENV", env)
+	t.Setenv("GOPROXY", "")
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GOPRIVATE", "")
+
+	if got, err := ProxyURL(); err != nil || got != "https://goproxy.corp.example.com" {
+		t.Errorf("ProxyURL() = %q, %v; want the GOPROXY set with 'go env -w'", got, err)
+	}
+	noProxy := NoProxy()
+	for path, want := range map[string]bool{
+		"corp.example.com/lib":      true,
+		"git.internal/team/lib":     true,
+		"github.com/spf13/cobra":    false,
+		"corp.example.com.evil/lib": false,
+	} {
+		if got := noProxy(path); got != want {
+			t.Errorf("NoProxy()(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	t.Setenv("GONOPROXY", "none.example.com")
+	if NoProxy()("corp.example.com/lib") {
+		t.Error("Expected GONOPROXY to take precedence over GOPRIVATE")
+	}
+}
+
+func TestProxyURLOff(t *testing.T) {
+	useModCache(t, "/home/user/go/pkg/mod")
+	t.Setenv("GOPROXY", "off")
+	got, err := ProxyURL()
+	if want := "file:///home/user/go/pkg/mod/cache/download"; err != nil || got != want {
+		t.Errorf("GOPROXY=off: ProxyURL() = %q, %v; want %q", got, err, want)
+	}
+}
+
+// useModCache points the module cache at dir for the duration of the test.
+func useModCache(t *testing.T, dir string) {
+	saved := goModCache
+	goModCache = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { goModCache = saved })
+}
+
+// writeCachedVersions fills the download directory of the module cache at
+// cache with versions of path, each with a .info file unless it is listed
+// in modOnly.
+func writeCachedVersions(t *testing.T, cache, path string, versions []string, modOnly ...string) {
+	dir := filepath.Join(cache, "cache", "download", escapeModulePath(path), "@v")
+	writeTestFile(t, filepath.Join(dir, "list"), strings.Join(versions, "\n")+"\n")
+	for _, v := range versions {
+		if !slices.Contains(modOnly, v) {
+			writeTestFile(t, filepath.Join(dir, v+".info"), fmt.Sprintf(`{"Version":%q,"Time":"2024-01-02T00:00:00Z"}`, v))
+		}
+	}
+}
+
+func TestDetectOutdatedProxyOff(t *testing.T) {
+	cache := t.TempDir()
+	useModCache(t, cache)
+	t.Setenv("GOPROXY", "off")
+	writeCachedVersions(t, cache, "golang.org/x/text", []string{"v0.3.5", "v0.14.0", "v0.15.0-rc.1"})
+	writeCachedVersions(t, cache, "github.com/BurntSushi/toml", []string{"v1.3.2", "v1.4.0"}, "v1.4.0")
+	// The go command writes no list when it only downloaded versions
+	// required by go.mod files
+	if err := os.Remove(filepath.Join(cache, "cache", "download", "github.com", "!burnt!sushi", "toml", "@v", "list")); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(cache, "cache", "download", "github.com", "!burnt!sushi", "toml", "@v", "v1.4.0.mod"), "module github.com/BurntSushi/toml\n")
+
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/BurntSushi/toml@v1.3.2", "golang.org/x/text@v0.3.5", "example.com/private@v1.0.0"},
+		},
+	}
+	g.Root = NewNode("example.com/app")
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+
+	if err := DetectOutdated(g, http.DefaultClient); err != nil {
+		t.Fatalf("DetectOutdated failed: %v", err)
+	}
+	want := map[string]string{"golang.org/x/text@v0.3.5": "v0.14.0", "github.com/BurntSushi/toml@v1.3.2": "v1.4.0"}
+	if !maps.Equal(g.Outdated, want) {
+		t.Errorf("Outdated = %v, want %v", g.Outdated, want)
+	}
+	if len(g.Warnings) != 1 || g.Warnings[0].Kind != WarnOffline || !strings.Contains(g.Warnings[0].Message, "no versions of 1 module(s)") {
+		t.Errorf("Warnings = %v, want one offline warning counting 1 unchecked module", g.Warnings)
+	}
+}
+
+func TestDetectOutdated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "private") {
+			t.Errorf("Private module looked up on the proxy: %s", r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/github.com/!burnt!sushi/toml/@latest":
+			w.Write([]byte(`{"Version":"v1.3.2","Time":"2023-06-08T06:04:01Z"}`))
+		case "/golang.org/x/text/@latest":
+			w.Write([]byte(`{"Version":"v0.14.0"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL+",direct")
+	t.Setenv("GOPRIVATE", "example.com/private")
+
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/BurntSushi/toml@v1.3.2", "golang.org/x/text@v0.3.5", "example.com/private@v1.0.0"},
+		},
+	}
+	g.Root = NewNode("example.com/app")
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+
+	if err := DetectOutdated(g, server.Client()); err != nil {
+		t.Fatalf("DetectOutdated failed: %v", err)
+	}
+
+	if len(g.Outdated) != 1 || g.Outdated["golang.org/x/text@v0.3.5"] != "v0.14.0" {
+		t.Errorf("Unexpected outdated modules: %v", g.Outdated)
+	}
+
+	out, err := Render("tree", g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		"golang.org/x/text@v0.3.5 (→ v0.14.0)\n",
+		"github.com/BurntSushi/toml@v1.3.2\n",
+		"\n1 of 3 module(s) have a newer version\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDetectOutdatedLookupFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/golang.org/x/text/@latest":
+			w.Write([]byte(`{"Version":"v0.14.0"}`))
+		case "/github.com/!burnt!sushi/toml/@latest":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+	t.Setenv("GOPRIVATE", "")
+
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/BurntSushi/toml@v1.3.2", "golang.org/x/text@v0.3.5", "example.com/gone@v1.0.0"},
+		},
+	}
+	g.Root = NewNode("example.com/app")
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+
+	if err := DetectOutdated(g, server.Client()); err != nil {
+		t.Fatalf("DetectOutdated failed: %v", err)
+	}
+	// Modules the proxy does not know are skipped, other failures recorded
+	if len(g.OutdatedErrors) != 1 || g.OutdatedErrors["github.com/BurntSushi/toml"] == nil {
+		t.Errorf("OutdatedErrors = %v, want the failed lookup of github.com/BurntSushi/toml", g.OutdatedErrors)
+	}
+	if len(g.Warnings) != 1 || g.Warnings[0].Kind != WarnMetadata || !strings.Contains(g.Warnings[0].Message, "latest version of 1 module(s)") {
+		t.Errorf("Warnings = %v, want one metadata warning for the failed lookup", g.Warnings)
+	}
+
+	var buf bytes.Buffer
+	writeOutdatedSummary(&buf, g)
+	if want := "\n1 of 3 module(s) have a newer version; 1 module path(s) could not be checked\n"; buf.String() != want {
+		t.Errorf("writeOutdatedSummary() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestLatestCachedVersion(t *testing.T) {
+	tests := []struct {
+		versions []string
+		want     string
+	}{
+		{[]string{"v1.2.0", "v1.10.0", "v1.11.0-rc.1"}, "v1.10.0"},
+		{[]string{"v1.0.0-alpha", "v1.0.0-beta"}, "v1.0.0-beta"},
+		{[]string{"v0.0.0-20191109021931-daa7c04131f5", "v0.0.0-20210101000000-0123456789ab"}, "v0.0.0-20210101000000-0123456789ab"},
+		{[]string{"v0.1.0", "v0.1.1-0.20230101000000-0123456789ab"}, "v0.1.0"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := latestCachedVersion(tt.versions); got != tt.want {
+			t.Errorf("latestCachedVersion(%q) = %q, want %q", tt.versions, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package deptree
+
+import (
+	"strings"
+)
+
+// forgeHosts are code hosting sites whose first path element is the owning
+// user or organization.
+var forgeHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"codeberg.org":  true,
+	"gitee.com":     true,
+	"sr.ht":         true,
+	"git.sr.ht":     true,
+}
+
+// ModuleOwner returns the user or organization a module belongs to: the
+// host plus owner on code forges (e.g. "github.com/spf13"), the backing
+// GitHub owner for gopkg.in, and the host for vanity import paths.
+func ModuleOwner(module string) string {
+	path, _ := SplitModule(module)
+	parts := strings.Split(path, "/")
+	host := parts[0]
+
+	switch {
+	case forgeHosts[host] && len(parts) >= 2:
+		return host + "/" + parts[1]
+	case host == "gopkg.in" && len(parts) == 2:
+		// gopkg.in/pkg.v3 is served from github.com/go-pkg/pkg
+		name, _, _ := strings.Cut(parts[1], ".")
+		return "github.com/go-" + name
+	case host == "gopkg.in" && len(parts) >= 3:
+		// gopkg.in/user/pkg.v3 is served from github.com/user/pkg
+		return "github.com/" + parts[1]
+	}
+
+	return host
+}
+
+// Owners groups the modules of g by ModuleOwner, leaving out the owner of the
+// root module itself.
+func (g *Graph) Owners() map[string][]string {
+	var rootOwner string
+	if g.Root != nil {
+		rootOwner = ModuleOwner(g.Root.Name)
+	}
+
+	owners := make(map[string][]string)
+	for _, name := range g.Modules() {
+		owner := ModuleOwner(name)
+		if owner == rootOwner {
+			continue
+		}
+		owners[owner] = append(owners[owner], name)
+	}
+	return owners
+}
@@ -0,0 +1,45 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModuleOwner(t *testing.T) {
+	tests := []struct {
+		module   string
+		expected string
+	}{
+		{"github.com/spf13/cobra@v1.8.0", "github.com/spf13"},
+		{"github.com/spf13/pflag@v1.0.5", "github.com/spf13"},
+		{"gitlab.com/group/project/v2@v2.0.0", "gitlab.com/group"},
+		{"gopkg.in/yaml.v3@v3.0.1", "github.com/go-yaml"},
+		{"gopkg.in/natefinch/lumberjack.v2@v2.2.1", "github.com/natefinch"},
+		{"golang.org/x/text@v0.14.0", "golang.org"},
+		{"go.uber.org/zap@v1.27.0", "go.uber.org"},
+	}
+
+	for _, tt := range tests {
+		if got := ModuleOwner(tt.module); got != tt.expected {
+			t.Errorf("ModuleOwner(%q) = %q, want %q", tt.module, got, tt.expected)
+		}
+	}
+}
+
+func TestGraphOwners(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("github.com/me/app"),
+		Deps: map[string][]string{
+			"github.com/me/app": {"github.com/me/lib@v1.0.0", "github.com/spf13/cobra@v1.8.0", "golang.org/x/text@v0.14.0"},
+		},
+	}
+
+	owners := g.Owners()
+	expected := map[string][]string{
+		"github.com/spf13": {"github.com/spf13/cobra@v1.8.0"},
+		"golang.org":       {"golang.org/x/text@v0.14.0"},
+	}
+	if !reflect.DeepEqual(owners, expected) {
+		t.Errorf("Owners() = %v, want %v", owners, expected)
+	}
+}
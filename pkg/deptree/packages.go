@@ -0,0 +1,121 @@
+package deptree
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// PackagesRoot is the synthetic module at the root of a graph combining
+// several separately analyzed packages, requiring the module of each.
+const PackagesRoot = "packages"
+
+// Views of the dependency sets of the packages below PackagesRoot, as
+// applied by ReduceToPackageSet.
+const (
+	// SetIntersection keeps the modules every package depends on.
+	SetIntersection = "intersection"
+	// SetUnion keeps the modules any package depends on.
+	SetUnion = "union"
+)
+
+// CombinePackages combines the graphs of packages analyzed on their own,
+// such as by Load in a temp module per package or by LoadFromProxy, into
+// one graph rooted at PackagesRoot with the root module of each graph below
+// it, so they can be compared side by side. Each package keeps the versions
+// its own analysis selected. Graphs without a root are left out.
+func CombinePackages(graphs []*Graph) *Graph {
+	g := &Graph{
+		Root:    NewNode(PackagesRoot),
+		Deps:    make(map[string][]string),
+		ModFile: &ModFile{},
+		Timings: &Timings{},
+	}
+	var roots []string
+	for _, member := range graphs {
+		if member.Root == nil {
+			continue
+		}
+		g.Merge(member)
+		if !slices.Contains(roots, member.Root.Name) {
+			roots = append(roots, member.Root.Name)
+		}
+		if member.Timings != nil {
+			for _, phase := range member.Timings.Phases() {
+				g.Timings.Add(phase.Name, phase.Duration)
+			}
+		}
+	}
+
+	g.Deps[PackagesRoot] = roots
+	g.expandRoot(PackagesRoot)
+	return g
+}
+
+// ReduceToPackageSet replaces the tree of a graph from CombinePackages with
+// the flat list of modules in set, SetIntersection or SetUnion, of the
+// dependency sets of its packages. Modules are compared by path, so a
+// module the packages require at different versions counts as shared; it
+// is listed at its highest version, annotated with the others. In a union,
+// each module is annotated with the packages depending on it.
+func (g *Graph) ReduceToPackageSet(set string) error {
+	if set != SetIntersection && set != SetUnion {
+		return fmt.Errorf("unknown package set %q (want %s or %s)", set, SetIntersection, SetUnion)
+	}
+	roots := g.Deps[PackagesRoot]
+	if len(roots) == 0 {
+		return fmt.Errorf("the graph does not combine several packages")
+	}
+
+	// versions maps module paths to the versions reached, and users to the
+	// packages reaching them
+	versions := make(map[string][]string)
+	users := make(map[string][]string)
+	for _, root := range roots {
+		rootPath, _ := SplitModule(root)
+		reached := make(map[string]bool)
+		for _, name := range g.Reachable(root) {
+			path, version := SplitModule(name)
+			if path == rootPath {
+				continue
+			}
+			if !slices.Contains(versions[path], version) {
+				versions[path] = append(versions[path], version)
+			}
+			if !reached[path] {
+				reached[path] = true
+				users[path] = append(users[path], rootPath)
+			}
+		}
+	}
+
+	var kept []string
+	root := NewNode(PackagesRoot)
+	for _, path := range slices.Sorted(maps.Keys(versions)) {
+		if set == SetIntersection && len(users[path]) < len(roots) {
+			continue
+		}
+		vs := versions[path]
+		slices.SortFunc(vs, func(a, b string) int { return CompareVersions(b, a) })
+		name := path
+		if vs[0] != "" {
+			name += "@" + vs[0]
+		}
+		node := NewNode(name)
+		if len(vs) > 1 {
+			node.Annotations = append(node.Annotations, "(also "+strings.Join(vs[1:], ", ")+")")
+		}
+		if set == SetUnion {
+			node.Annotations = append(node.Annotations, "("+strings.Join(users[path], ", ")+")")
+		}
+		kept = append(kept, name)
+		root.Children[name] = node
+	}
+
+	g.Deps = map[string][]string{PackagesRoot: kept}
+	g.Root = root
+	g.Unexpanded = 0
+	g.syncDescriptions()
+	return nil
+}
@@ -0,0 +1,61 @@
+package deptree
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCombinePackages(t *testing.T) {
+	cobra := &Graph{Deps: map[string][]string{
+		"temp":                          {"github.com/spf13/cobra@v1.8.0"},
+		"github.com/spf13/cobra@v1.8.0": {"github.com/spf13/pflag@v1.0.5", "golang.org/x/sys@v0.20.0"},
+	}}
+	cobra.expandRoot("github.com/spf13/cobra@v1.8.0")
+	cli := &Graph{Deps: map[string][]string{
+		"temp":                             {"github.com/urfave/cli/v2@v2.27.1"},
+		"github.com/urfave/cli/v2@v2.27.1": {"golang.org/x/sys@v0.18.0", "github.com/xrash/smetrics@v0.0.0-20201216005158-039620a65673"},
+	}}
+	cli.expandRoot("github.com/urfave/cli/v2@v2.27.1")
+
+	g := CombinePackages([]*Graph{cobra, cli, {Deps: map[string][]string{}}})
+	if g.Root.Name != PackagesRoot || len(g.Root.Children) != 2 {
+		t.Fatalf("root = %s with %d children, want %s with 2", g.Root.Name, len(g.Root.Children), PackagesRoot)
+	}
+	if slices.Contains(g.Modules(), PackagesRoot) {
+		t.Errorf("Modules() includes the synthetic %s", PackagesRoot)
+	}
+
+	intersection := CombinePackages([]*Graph{cobra, cli})
+	if err := intersection.ReduceToPackageSet(SetIntersection); err != nil {
+		t.Fatal(err)
+	}
+	if got := intersection.Modules(); !slices.Equal(got, []string{"golang.org/x/sys@v0.20.0"}) {
+		t.Errorf("intersection = %v, want golang.org/x/sys@v0.20.0", got)
+	}
+	if sys := intersection.Root.Children["golang.org/x/sys@v0.20.0"]; sys == nil || !slices.Equal(sys.Annotations, []string{"(also v0.18.0)"}) {
+		t.Errorf("golang.org/x/sys node = %+v, want it annotated with v0.18.0", sys)
+	}
+
+	union := CombinePackages([]*Graph{cobra, cli})
+	if err := union.ReduceToPackageSet(SetUnion); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Render("tree", union, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"github.com/spf13/pflag@v1.0.5 (github.com/spf13/cobra)\n",
+		"golang.org/x/sys@v0.20.0 (also v0.18.0) (github.com/spf13/cobra, github.com/urfave/cli/v2)\n",
+		"github.com/xrash/smetrics@v0.0.0-20201216005158-039620a65673 (github.com/urfave/cli/v2)\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("union output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := union.ReduceToPackageSet("difference"); err == nil {
+		t.Error("ReduceToPackageSet accepted an unknown set")
+	}
+}
@@ -0,0 +1,182 @@
+package deptree
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// pkgsiteURL is the base URL of pkg.go.dev, replaced in tests.
+var pkgsiteURL = "https://pkg.go.dev"
+
+// pkgsiteWorkers bounds concurrent pkg.go.dev requests.
+const pkgsiteWorkers = 8
+
+var (
+	pkgsiteDescriptionPattern = regexp.MustCompile(`(?is)<meta\s+name="description"\s+content="([^"]*)"`)
+	pkgsiteLicensePattern     = regexp.MustCompile(`(?is)data-test-id="UnitHeader-license"[^>]*>([^<]*)<`)
+	pkgsiteImportedByPattern  = regexp.MustCompile(`aria-label="Imported By: ([\d,]+)"`)
+)
+
+// PkgsiteInfo is what pkg.go.dev shows about a module version.
+type PkgsiteInfo struct {
+	// Synopsis is the first sentence of the documentation of the package
+	// at the module root, if there is one.
+	Synopsis string
+	// License lists the licenses pkg.go.dev detected, comma-separated.
+	License string
+	// ImportedBy is the number of packages importing the root package, or
+	// -1 if pkg.go.dev shows none.
+	ImportedBy int
+}
+
+// FetchPkgsiteInfo fetches the pkg.go.dev page of a module version. It
+// covers modules on every host and needs no token, but not private modules,
+// which pkg.go.dev cannot fetch.
+func FetchPkgsiteInfo(opts Options, module string) (PkgsiteInfo, error) {
+	path, version := SplitModule(module)
+	host, _, _ := strings.Cut(path, "/")
+	if version == "" || IsToolchainDep(module) || !strings.Contains(host, ".") {
+		return PkgsiteInfo{}, fmt.Errorf("not a remote module")
+	}
+
+	resp, err := opts.client().Get(fmt.Sprintf("%s/%s@%s", opts.pkgsiteURL(), path, version))
+	if errors.Is(err, ErrBudgetExceeded) {
+		return PkgsiteInfo{}, ErrBudgetExceeded
+	}
+	if err != nil {
+		return PkgsiteInfo{}, fmt.Errorf("failed to fetch from pkg.go.dev: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return PkgsiteInfo{}, fmt.Errorf("not on pkg.go.dev")
+	case resp.StatusCode != http.StatusOK:
+		return PkgsiteInfo{}, fmt.Errorf("pkg.go.dev returned status %d", resp.StatusCode)
+	}
+	page, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return PkgsiteInfo{}, fmt.Errorf("failed to read pkg.go.dev page: %w", err)
+	}
+	return parsePkgsitePage(page), nil
+}
+
+// parsePkgsitePage extracts the metadata of a pkg.go.dev unit page.
+func parsePkgsitePage(page []byte) PkgsiteInfo {
+	info := PkgsiteInfo{ImportedBy: -1}
+	if m := pkgsiteDescriptionPattern.FindSubmatch(page); m != nil {
+		info.Synopsis = strings.TrimSpace(html.UnescapeString(string(m[1])))
+	}
+
+	var licenses []string
+	for _, m := range pkgsiteLicensePattern.FindAllSubmatch(page, -1) {
+		if license := strings.TrimSpace(html.UnescapeString(string(m[1]))); license != "" {
+			licenses = append(licenses, license)
+		}
+	}
+	info.License = strings.Join(licenses, ", ")
+
+	if m := pkgsiteImportedByPattern.FindSubmatch(page); m != nil {
+		if n, err := strconv.Atoi(strings.ReplaceAll(string(m[1]), ",", "")); err == nil {
+			info.ImportedBy = n
+		}
+	}
+	return info
+}
+
+// FetchPkgsiteMetadata is the pkg.go.dev counterpart of FetchDescriptions
+// and DetectLicenses: it fetches the pkg.go.dev page of every versioned
+// module in g and stores the synopsis in g.Descriptions, the number of
+// importers in g.ImportedBy and, if licenses is set, the licenses in
+// g.Licenses. Failures and modules skipped because the client's time
+// budget ran out are recorded as by FetchDescriptions. progress is called
+// as by FetchDescriptionsProgress when not nil.
+func FetchPkgsiteMetadata(g *Graph, opts Options, licenses bool, progress func(done, total int)) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, pkgsiteWorkers)
+
+	modules := make(map[string]bool)
+	for _, name := range g.Modules() {
+		modules[name] = true
+	}
+	g.Walk(func(node *Node) {
+		modules[node.Name] = true
+	})
+
+	g.Descriptions = make(map[string]string)
+	g.DescriptionErrors = make(map[string]error)
+	g.ImportedBy = make(map[string]int)
+	if licenses {
+		g.Licenses = make(map[string]string)
+		g.LicenseErrors = make(map[string]error)
+	}
+	done := 0
+
+	for name := range modules {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			info, err := FetchPkgsiteInfo(opts, name)
+			if err == nil && info.Synopsis == "" {
+				err = errNoDescription
+			}
+
+			mu.Lock()
+			if errors.Is(err, ErrBudgetExceeded) {
+				g.markPartial(name)
+			}
+			if err != nil {
+				g.Descriptions[name] = fmt.Sprintf("(%s)", err.Error())
+				g.DescriptionErrors[name] = err
+			} else {
+				g.Descriptions[name] = info.Synopsis
+			}
+			if (err == nil || errors.Is(err, errNoDescription)) && info.ImportedBy >= 0 {
+				g.ImportedBy[name] = info.ImportedBy
+			}
+			if _, version := SplitModule(name); licenses && version != "" {
+				g.Licenses[name] = cmp.Or(info.License, UnknownLicense)
+				if err != nil && !errors.Is(err, errNoDescription) {
+					g.LicenseErrors[name] = err
+				}
+			}
+			done++
+			if progress != nil {
+				progress(done, len(modules))
+			}
+			mu.Unlock()
+		}(name)
+	}
+
+	wg.Wait()
+	failed := make(map[string]error)
+	for name, err := range g.DescriptionErrors {
+		if !errors.Is(err, errNoDescription) && !errors.Is(err, ErrBudgetExceeded) {
+			failed[name] = err
+		}
+	}
+	g.warnFailures("pkg.go.dev metadata", failed)
+	g.syncDescriptions()
+
+	g.Walk(func(node *Node) {
+		if n, ok := g.ImportedBy[node.Name]; ok {
+			node.Annotations = append(node.Annotations, importedByLabel(n))
+		}
+	})
+}
+
+// importedByLabel marks a module with the number of packages importing it.
+func importedByLabel(n int) string {
+	return fmt.Sprintf("(imported by %d)", n)
+}
@@ -0,0 +1,86 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const pkgsiteTestPage = `<!DOCTYPE html><html><head>
+<meta name="description" content="Package cobra is a commander for modern Go CLI interactions &amp; more.">
+</head><body>
+<span class="go-Main-headerDetailItem" data-test-id="UnitHeader-licenses">
+  License: <a href="/github.com/spf13/cobra?tab=licenses" data-test-id="UnitHeader-license">Apache-2.0</a>
+</span>
+<span class="go-Main-headerDetailItem" data-test-id="UnitHeader-imports">
+  <a href="/github.com/spf13/cobra?tab=importedby" aria-label="Imported By: 183,420" data-test-id="UnitHeader-importedby">Imported by: 183,420</a>
+</span>
+</body></html>`
+
+func TestParsePkgsitePage(t *testing.T) {
+	got := parsePkgsitePage([]byte(pkgsiteTestPage))
+	want := PkgsiteInfo{Synopsis: "Package cobra is a commander for modern Go CLI interactions & more.", License: "Apache-2.0", ImportedBy: 183420}
+	if got != want {
+		t.Errorf("parsePkgsitePage() = %+v, want %+v", got, want)
+	}
+
+	if got := parsePkgsitePage([]byte("<html></html>")); got != (PkgsiteInfo{ImportedBy: -1}) {
+		t.Errorf("Expected nothing from an empty page, got %+v", got)
+	}
+}
+
+func TestFetchPkgsiteMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/spf13/cobra@v1.8.0":
+			w.Write([]byte(pkgsiteTestPage))
+		case "/golang.org/x/tools@v0.1.0":
+			// A module without a root package
+			w.Write([]byte(`<a aria-label="Imported By: 0">`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldURL := pkgsiteURL
+	pkgsiteURL = server.URL
+	defer func() { pkgsiteURL = oldURL }()
+
+	root := NewNode("myapp")
+	for _, name := range []string{"github.com/spf13/cobra@v1.8.0", "golang.org/x/tools@v0.1.0", "corp.example.com/private@v1.0.0"} {
+		root.Children[name] = NewNode(name)
+	}
+	g := &Graph{
+		Root: root,
+		Deps: map[string][]string{"myapp": {"github.com/spf13/cobra@v1.8.0", "golang.org/x/tools@v0.1.0", "corp.example.com/private@v1.0.0"}},
+	}
+	FetchPkgsiteMetadata(g, Options{HTTPClient: server.Client()}, true, nil)
+
+	if desc := g.Descriptions["github.com/spf13/cobra@v1.8.0"]; !strings.HasPrefix(desc, "Package cobra is") {
+		t.Errorf("Expected the synopsis as description, got %q", desc)
+	}
+	if g.DescriptionErrors["golang.org/x/tools@v0.1.0"] == nil || g.DescriptionErrors["corp.example.com/private@v1.0.0"] == nil {
+		t.Errorf("Expected modules without a synopsis to be reported, got %v", g.DescriptionErrors)
+	}
+	wantImportedBy := map[string]int{"github.com/spf13/cobra@v1.8.0": 183420, "golang.org/x/tools@v0.1.0": 0}
+	if !reflect.DeepEqual(g.ImportedBy, wantImportedBy) {
+		t.Errorf("ImportedBy = %v, want %v", g.ImportedBy, wantImportedBy)
+	}
+	wantLicenses := map[string]string{
+		"github.com/spf13/cobra@v1.8.0":   "Apache-2.0",
+		"golang.org/x/tools@v0.1.0":       UnknownLicense,
+		"corp.example.com/private@v1.0.0": UnknownLicense,
+	}
+	if !reflect.DeepEqual(g.Licenses, wantLicenses) {
+		t.Errorf("Licenses = %v, want %v", g.Licenses, wantLicenses)
+	}
+	if got := root.Children["github.com/spf13/cobra@v1.8.0"].Annotations; !reflect.DeepEqual(got, []string{"(imported by 183420)"}) {
+		t.Errorf("Expected an importer annotation, got %v", got)
+	}
+	if len(g.Warnings) != 1 || !strings.Contains(g.Warnings[0].Message, "corp.example.com/private@v1.0.0: not on pkg.go.dev") {
+		t.Errorf("Expected one warning naming the private module, got %v", g.Warnings)
+	}
+}
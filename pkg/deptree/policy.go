@@ -0,0 +1,273 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Policy holds organizational limits a dependency graph must satisfy. Zero
+// values disable the corresponding check.
+type Policy struct {
+	// MaxOwners caps the number of distinct external owners (see
+	// ModuleOwner) in the graph.
+	MaxOwners int `json:"max-owners"`
+	// BannedModules are modules the graph must not contain, each a module
+	// path, path@version or path prefix ending in "/...".
+	BannedModules []string `json:"banned-modules"`
+	// BannedLicenses are SPDX identifiers no module may be licensed under.
+	// Checking them needs the licenses in g.Licenses (see DetectLicenses).
+	BannedLicenses []string `json:"banned-licenses"`
+	// MaxDepth caps the length of the shortest requirement chain from the
+	// root to any module.
+	MaxDepth int `json:"max-depth"`
+	// AllowedHosts, if not empty, are the only hosts (the first element of a
+	// module path) modules may come from.
+	AllowedHosts []string `json:"allowed-hosts"`
+}
+
+// NeedsLicenses reports whether checking p needs the licenses of the
+// modules.
+func (p Policy) NeedsLicenses() bool {
+	return len(p.BannedLicenses) > 0
+}
+
+// Violation is one policy rule the graph breaks.
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Check returns every violation of p in g.
+func (p Policy) Check(g *Graph) []Violation {
+	var violations []Violation
+
+	if p.MaxOwners > 0 {
+		owners := g.Owners()
+		if len(owners) > p.MaxOwners {
+			violations = append(violations, Violation{
+				Rule: "max-owners",
+				Message: fmt.Sprintf("%d distinct external owners exceed the maximum of %d (%s)",
+					len(owners), p.MaxOwners, strings.Join(slices.Sorted(maps.Keys(owners)), ", ")),
+			})
+		}
+	}
+
+	modules := g.policyModules()
+
+	for _, module := range modules {
+		for _, banned := range p.BannedModules {
+			if matchesBanned(module, banned) {
+				violations = append(violations, Violation{
+					Rule:    "banned-modules",
+					Message: fmt.Sprintf("%s is banned (%s)", module, banned),
+				})
+				break
+			}
+		}
+	}
+
+	for _, module := range modules {
+		if license := g.Licenses[module]; slices.Contains(p.BannedLicenses, license) {
+			violations = append(violations, Violation{
+				Rule:    "banned-licenses",
+				Message: fmt.Sprintf("%s is licensed under %s", module, license),
+			})
+		}
+	}
+
+	if p.MaxDepth > 0 && g.Root != nil {
+		for _, module := range modules {
+			if chain := g.shortestChain(module); len(chain)-1 > p.MaxDepth {
+				violations = append(violations, Violation{
+					Rule: "max-depth",
+					Message: fmt.Sprintf("%s is %d requirements deep, more than the maximum of %d (%s)",
+						module, len(chain)-1, p.MaxDepth, FormatChain(chain)),
+				})
+			}
+		}
+	}
+
+	if len(p.AllowedHosts) > 0 {
+		for _, module := range modules {
+			path, _ := SplitModule(module)
+			host, _, _ := strings.Cut(path, "/")
+			if !slices.Contains(p.AllowedHosts, host) {
+				violations = append(violations, Violation{
+					Rule:    "allowed-hosts",
+					Message: fmt.Sprintf("%s comes from %s, which is not an allowed host", module, host),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// policyModules returns the modules of g policies apply to, in order: every
+// module except the root, workspace members and toolchain entries.
+func (g *Graph) policyModules() []string {
+	var modules []string
+	for _, name := range g.Modules() {
+		if g.Root != nil && name == g.Root.Name {
+			continue
+		}
+		if _, member := g.Workspace[name]; member {
+			continue
+		}
+		modules = append(modules, name)
+	}
+	slices.Sort(modules)
+	return modules
+}
+
+// matchesBanned reports whether module is matched by a BannedModules
+// entry: a module path or path@version (see matchesModule), or a path prefix
+// ending in "/..." matching the path itself and everything below it.
+func matchesBanned(module, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		path, _ := SplitModule(module)
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+	return matchesModule(module, pattern)
+}
+
+// shortestChain returns the shortest requirement chain from the root of g to
+// module, or nil if module is unreachable.
+func (g *Graph) shortestChain(module string) []string {
+	parent := map[string]string{g.Root.Name: ""}
+	queue := []string{g.Root.Name}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if name == module {
+			var chain []string
+			for ; name != ""; name = parent[name] {
+				chain = append(chain, name)
+			}
+			slices.Reverse(chain)
+			return chain
+		}
+		for _, to := range g.Deps[name] {
+			if _, seen := parent[to]; !seen && !IsToolchainDep(to) {
+				parent[to] = name
+				queue = append(queue, to)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadPolicy reads a Policy from a JSON or YAML file with the keys
+// max-owners, banned-modules, banned-licenses, max-depth and allowed-hosts.
+// Only the YAML needed for these is supported: top-level keys with a number
+// or a list of strings, written as "- item" lines or as "[a, b]".
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	if trimmed := bytes.TrimSpace(data); !bytes.HasPrefix(trimmed, []byte("{")) {
+		fields, err := parsePolicyYAML(data)
+		if err != nil {
+			return Policy{}, fmt.Errorf("%s: %w", path, err)
+		}
+		// Going through JSON applies the same field names and types
+		if data, err = json.Marshal(fields); err != nil {
+			return Policy{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return Policy{}, fmt.Errorf("%s: invalid policy: %w", path, err)
+	}
+	return p, nil
+}
+
+// parsePolicyYAML parses the YAML subset LoadPolicy accepts into a map of
+// top-level keys to numbers, strings or lists of strings.
+func parsePolicyYAML(data []byte) (map[string]any, error) {
+	fields := make(map[string]any)
+	var list string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		indented := trimmed != strings.TrimRight(line, " \t")
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			if list == "" {
+				return nil, fmt.Errorf("line %d: list item outside a list", n)
+			}
+			fields[list] = append(fields[list].([]any), yamlScalar(item))
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || indented {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", n)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		list = ""
+
+		switch {
+		case value == "":
+			list = key
+			fields[key] = []any{}
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			items := []any{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, yamlScalar(item))
+				}
+			}
+			fields[key] = items
+		default:
+			fields[key] = yamlScalar(value)
+		}
+	}
+	return fields, scanner.Err()
+}
+
+// stripYAMLComment removes a "#" comment from line. Comments start at a
+// "#" at the beginning of the line or after a space.
+func stripYAMLComment(line string) string {
+	for i := range len(line) {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// yamlScalar converts a YAML scalar to an int if it is one, or to a string
+// with surrounding quotes removed.
+func yamlScalar(s string) any {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicyMaxOwners(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("github.com/me/app"),
+		Deps: map[string][]string{
+			"github.com/me/app": {"github.com/a/x@v1.0.0", "github.com/b/y@v1.0.0", "golang.org/x/text@v0.14.0"},
+		},
+	}
+
+	if violations := (Policy{MaxOwners: 3}).Check(g); len(violations) != 0 {
+		t.Errorf("Expected no violations at the limit, got %v", violations)
+	}
+
+	violations := Policy{MaxOwners: 2}.Check(g)
+	if len(violations) != 1 || violations[0].Rule != "max-owners" {
+		t.Fatalf("Expected one max-owners violation, got %v", violations)
+	}
+	if !strings.Contains(violations[0].Message, "3 distinct external owners") {
+		t.Errorf("Unexpected message %q", violations[0].Message)
+	}
+
+	if violations := (Policy{}).Check(g); len(violations) != 0 {
+		t.Errorf("Expected zero policy to be disabled, got %v", violations)
+	}
+}
+
+func TestPolicyRules(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("github.com/me/app"),
+		Deps: map[string][]string{
+			"github.com/me/app":         {"github.com/a/x@v1.0.0", "golang.org/x/text@v0.14.0", "go@1.21"},
+			"github.com/a/x@v1.0.0":     {"gopkg.in/yaml.v2@v2.4.0"},
+			"gopkg.in/yaml.v2@v2.4.0":   {"github.com/b/deep@v0.1.0"},
+			"golang.org/x/text@v0.14.0": {"golang.org/x/tools@v0.1.0"},
+			"golang.org/x/tools@v0.1.0": {},
+			"github.com/b/deep@v0.1.0":  {},
+		},
+		Licenses: map[string]string{
+			"github.com/a/x@v1.0.0":    "MIT",
+			"github.com/b/deep@v0.1.0": "AGPL-3.0",
+		},
+	}
+
+	policy := Policy{
+		BannedModules:  []string{"github.com/a/x@v0.9.0", "golang.org/x/..."},
+		BannedLicenses: []string{"AGPL-3.0", "GPL-3.0"},
+		MaxDepth:       2,
+		AllowedHosts:   []string{"github.com", "golang.org"},
+	}
+
+	var got []string
+	for _, v := range policy.Check(g) {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"banned-modules: golang.org/x/text@v0.14.0 is banned (golang.org/x/...)",
+		"banned-modules: golang.org/x/tools@v0.1.0 is banned (golang.org/x/...)",
+		"banned-licenses: github.com/b/deep@v0.1.0 is licensed under AGPL-3.0",
+		"max-depth: github.com/b/deep@v0.1.0 is 3 requirements deep, more than the maximum of 2 (github.com/me/app > github.com/a/x@v1.0.0 > gopkg.in/yaml.v2@v2.4.0 > github.com/b/deep@v0.1.0)",
+		"allowed-hosts: gopkg.in/yaml.v2@v2.4.0 comes from gopkg.in, which is not an allowed host",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	want := Policy{
+		MaxOwners:      10,
+		BannedModules:  []string{"github.com/pkg/errors", "golang.org/x/exp/..."},
+		BannedLicenses: []string{"AGPL-3.0", "GPL-3.0"},
+		MaxDepth:       6,
+		AllowedHosts:   []string{"github.com", "golang.org"},
+	}
+
+	files := map[string]string{
+		"policy.yaml": `# CI dependency policy
+max-owners: 10
+max-depth: 6   # keep chains short
+banned-modules:
+  - github.com/pkg/errors
+  - "golang.org/x/exp/..."
+banned-licenses: [AGPL-3.0, 'GPL-3.0']
+allowed-hosts:
+- github.com
+- golang.org
+`,
+		"policy.json": `{
+  "max-owners": 10,
+  "max-depth": 6,
+  "banned-modules": ["github.com/pkg/errors", "golang.org/x/exp/..."],
+  "banned-licenses": ["AGPL-3.0", "GPL-3.0"],
+  "allowed-hosts": ["github.com", "golang.org"]
+}`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadPolicy(path)
+		if err != nil {
+			t.Fatalf("LoadPolicy(%s): %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadPolicy(%s) = %+v, want %+v", name, got, want)
+		}
+	}
+
+	for name, content := range map[string]string{
+		"unknown.yaml":  "max-packages: 3\n",
+		"type.yaml":     "max-depth: deep\n",
+		"indented.yaml": "banned-modules:\n  nested: value\n",
+		"orphan.yaml":   "  - github.com/pkg/errors\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(path); err == nil {
+			t.Errorf("LoadPolicy(%s) succeeded, want an error", name)
+		}
+	}
+}
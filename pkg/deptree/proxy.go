@@ -0,0 +1,292 @@
+package deptree
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultGoProxy is the GOPROXY setting the go command uses when it is unset.
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// ProxyOff reports whether GOPROXY is "off", which forbids the go command
+// to download modules.
+func ProxyOff() bool {
+	return proxySetting() == "off"
+}
+
+// proxySetting returns GOPROXY as the go command sees it, including a value
+// set with 'go env -w'.
+func proxySetting() string {
+	return strings.TrimSpace(goSettings("GOPROXY")["GOPROXY"])
+}
+
+// ProxyURL returns the first module proxy listed in GOPROXY. When GOPROXY
+// is "off", it is a file:// URL of the download directory of the module
+// cache, which has the layout of a module proxy, so proxy lookups are
+// answered from what the cache holds. It fails when GOPROXY lists no proxy,
+// e.g. "direct", since deptree only speaks the proxy protocol.
+func ProxyURL() (string, error) {
+	setting := proxySetting()
+	if setting == "off" {
+		cache, err := goModCache()
+		if err != nil {
+			return "", err
+		}
+		return "file://" + filepath.ToSlash(filepath.Join(cache, "cache", "download")), nil
+	}
+	if setting == "" {
+		setting = defaultGoProxy
+	}
+	for _, entry := range strings.FieldsFunc(setting, func(r rune) bool { return r == ',' || r == '|' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "direct" || entry == "off" || entry == "" {
+			continue
+		}
+		return strings.TrimSuffix(entry, "/"), nil
+	}
+	return "", fmt.Errorf("GOPROXY=%s lists no module proxy", setting)
+}
+
+// NoProxy returns a function reporting whether a module path matches
+// GONOPROXY, or GOPRIVATE when it is unset. The go command fetches such
+// private modules directly, so deptree must not ask the module proxy about
+// them either. With GOPROXY=off, lookups go to the module cache, which
+// holds private modules too, and nothing matches.
+func NoProxy() func(path string) bool {
+	settings := goSettings("GOPROXY", "GONOPROXY", "GOPRIVATE")
+	if strings.TrimSpace(settings["GOPROXY"]) == "off" {
+		return func(string) bool { return false }
+	}
+	patterns := settings["GONOPROXY"]
+	if patterns == "" {
+		patterns = settings["GOPRIVATE"]
+	}
+	return func(path string) bool { return matchPrefixPatterns(patterns, path) }
+}
+
+// errNoProxy is the error for module paths NoProxy matches.
+func errNoProxy(path string) error {
+	return fmt.Errorf("%s is private (GONOPROXY or GOPRIVATE), so it is not looked up on the module proxy", path)
+}
+
+// ProxyInfo is the version metadata a module proxy serves from @latest and
+// @v/<version>.info.
+type ProxyInfo struct {
+	Version string
+	// Time is when the version was committed; zero if the proxy did not
+	// say.
+	Time time.Time
+}
+
+// FetchLatestVersion asks the module proxy at proxy for the latest version of
+// the module at path.
+func FetchLatestVersion(client *http.Client, proxy, path string) (string, error) {
+	info, err := FetchLatestInfo(client, proxy, path)
+	return info.Version, err
+}
+
+// FetchLatestInfo asks the module proxy at proxy for the latest version of
+// the module at path and when it was released.
+func FetchLatestInfo(client *http.Client, proxy, path string) (ProxyInfo, error) {
+	var info ProxyInfo
+	if err := fetchProxyJSON(client, proxy+"/"+escapeModulePath(path)+"/@latest", &info); err != nil {
+		return info, fmt.Errorf("failed to fetch latest version of %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// FetchVersionInfo resolves query, a version, branch or commit, to the
+// canonical version of the module at path.
+func FetchVersionInfo(client *http.Client, proxy, path, query string) (ProxyInfo, error) {
+	var info ProxyInfo
+	url := proxy + "/" + escapeModulePath(path) + "/@v/" + escapeModulePath(query) + ".info"
+	if err := fetchProxyJSON(client, url, &info); err != nil {
+		return info, fmt.Errorf("failed to resolve %s@%s: %w", path, query, err)
+	}
+	return info, nil
+}
+
+// FetchVersionList returns the tagged versions of the module at path that
+// the module proxy at proxy knows, newest first. Pseudo-versions are not
+// listed.
+func FetchVersionList(client *http.Client, proxy, path string) ([]string, error) {
+	data, err := fetchProxy(client, proxy+"/"+escapeModulePath(path)+"/@v/list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s: %w", path, err)
+	}
+	versions := strings.Fields(string(data))
+	slices.SortFunc(versions, func(a, b string) int { return CompareVersions(b, a) })
+	return slices.Compact(versions), nil
+}
+
+// FetchModFile downloads and parses the go.mod of path@version.
+func FetchModFile(client *http.Client, proxy, path, version string) (*ModFile, error) {
+	data, err := fetchProxy(client, proxy+"/"+escapeModulePath(path)+"/@v/"+escapeModulePath(version)+".mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch go.mod of %s@%s: %w", path, version, err)
+	}
+	mf, err := ParseModFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod of %s@%s: %w", path, version, err)
+	}
+	return mf, nil
+}
+
+// errProxyNotFound is returned for the 404 and 410 responses a proxy gives
+// for unknown modules and versions.
+var errProxyNotFound = errors.New("not found on module proxy")
+
+func fetchProxyJSON(client *http.Client, url string, v any) error {
+	data, err := fetchProxy(client, url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse module proxy response: %w", err)
+	}
+	return nil
+}
+
+func fetchProxy(client *http.Client, url string) ([]byte, error) {
+	if path, ok := strings.CutPrefix(url, "file://"); ok {
+		return readCacheProxy(path)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "deptree-cli")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, DescribeHTTPError(err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, errProxyNotFound
+	default:
+		return nil, fmt.Errorf("module proxy returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// readCacheProxy answers a module proxy request for path from a module
+// cache download directory. The go command only writes @v/list when it
+// listed the versions on a proxy, and the cache has no @latest endpoint,
+// so both are made up from the versions downloaded when missing.
+func readCacheProxy(path string) ([]byte, error) {
+	if base, ok := strings.CutSuffix(path, "/@v/list"); ok {
+		versions, err := cachedVersions(base)
+		if err != nil {
+			return nil, err
+		}
+		var tagged []string
+		for _, v := range versions {
+			if !pseudoVersion.MatchString(v) {
+				tagged = append(tagged, v)
+			}
+		}
+		return []byte(strings.Join(tagged, "\n")), nil
+	}
+
+	if base, ok := strings.CutSuffix(path, "/@latest"); ok {
+		versions, err := cachedVersions(base)
+		if err != nil {
+			return nil, err
+		}
+		latest := latestCachedVersion(versions)
+		if latest == "" {
+			return nil, errProxyNotFound
+		}
+		info, err := os.ReadFile(filepath.FromSlash(base + "/@v/" + escapeModulePath(latest) + ".info"))
+		if errors.Is(err, fs.ErrNotExist) {
+			// Only the go.mod of the version was downloaded
+			return json.Marshal(ProxyInfo{Version: latest})
+		}
+		return info, err
+	}
+
+	data, err := os.ReadFile(filepath.FromSlash(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errProxyNotFound
+	}
+	return data, err
+}
+
+// cachedVersions lists the versions of the module whose download
+// directory is base: those in @v/list along with every version whose .info
+// or .mod file was downloaded.
+func cachedVersions(base string) ([]string, error) {
+	dir := filepath.FromSlash(base + "/@v")
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errProxyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == "list" {
+			list, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			versions = append(versions, strings.Fields(string(list))...)
+			continue
+		}
+		if v, ok := strings.CutSuffix(name, ".info"); ok {
+			versions = append(versions, unescapeModulePath(v))
+		} else if v, ok := strings.CutSuffix(name, ".mod"); ok {
+			versions = append(versions, unescapeModulePath(v))
+		}
+	}
+	slices.SortFunc(versions, CompareVersions)
+	return slices.Compact(versions), nil
+}
+
+// pseudoVersion matches the pseudo-versions the go command gives untagged
+// commits, such as v0.0.0-20191109021931-daa7c04131f5.
+var pseudoVersion = regexp.MustCompile(`[-.]\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+
+// latestCachedVersion picks what @latest would resolve to among versions:
+// the highest release, or the highest prerelease if there is no release,
+// or the highest pseudo-version if nothing is tagged.
+func latestCachedVersion(versions []string) string {
+	var latest, latestPre, latestPseudo string
+	for _, v := range versions {
+		_, pre := splitVersion(v)
+		switch {
+		case pseudoVersion.MatchString(v):
+			if CompareVersions(v, latestPseudo) > 0 {
+				latestPseudo = v
+			}
+		case pre == "":
+			if CompareVersions(v, latest) > 0 {
+				latest = v
+			}
+		default:
+			if CompareVersions(v, latestPre) > 0 {
+				latestPre = v
+			}
+		}
+	}
+	return cmp.Or(latest, latestPre, latestPseudo)
+}
@@ -0,0 +1,217 @@
+package deptree
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// LoadFromProxy builds the dependency graph of a remote package from the
+// go.mod files served by the module proxy in GOPROXY, without the go command
+// or a temp module. packageName is a package or module path with an optional
+// @version, @latest, branch or commit query, as accepted by -package.
+//
+// The graph holds every version reachable through requirements, like 'go mod
+// graph' for modules without graph pruning. Replace and exclude directives
+// of the analyzed module are ignored, as they are when it is a dependency.
+// When GOPROXY is "off", the go.mod files are read from the module cache.
+// Modules matching GONOPROXY or GOPRIVATE are not asked for, so a graph
+// with private modules fails to load.
+func LoadFromProxy(client *http.Client, packageName string) (*Graph, error) {
+	proxy, err := ProxyURL()
+	if err != nil {
+		return nil, err
+	}
+
+	noProxy := NoProxy()
+	if path, _, _ := strings.Cut(packageName, "@"); noProxy(path) {
+		return nil, errNoProxy(path)
+	}
+
+	timings := &Timings{}
+	done := timings.Track("module resolution")
+	root, err := resolveProxyModule(client, proxy, packageName)
+	done()
+	if err != nil {
+		return nil, err
+	}
+
+	done = timings.Track("graph retrieval")
+	deps, modFiles, err := fetchProxyGraph(client, proxy, root.String(), noProxy)
+	done()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{
+		Deps:        deps,
+		Timings:     timings,
+		ModFile:     &ModFile{},
+		RootModFile: modFiles[root.String()],
+		ModFiles:    modFiles,
+	}
+	g.expandRoot(root.String())
+	if ProxyOff() {
+		g.warn(WarnOffline, "", "GOPROXY=off: the graph was read from the go.mod files in the module cache, and @latest is the newest version it holds")
+	}
+
+	return g, nil
+}
+
+// resolveProxyModule finds the module providing packageName and resolves the
+// requested version. Like 'go get', it tries the longest path prefix first.
+func resolveProxyModule(client *http.Client, proxy, packageName string) (ModVersion, error) {
+	path, query, _ := strings.Cut(packageName, "@")
+	if query == "" {
+		query = "latest"
+	}
+
+	for prefix := path; prefix != "" && prefix != "."; {
+		var version string
+		var err error
+		if query == "latest" {
+			version, err = FetchLatestVersion(client, proxy, prefix)
+		} else {
+			var info ProxyInfo
+			info, err = FetchVersionInfo(client, proxy, prefix, query)
+			version = info.Version
+		}
+		if err == nil {
+			return ModVersion{Path: prefix, Version: version}, nil
+		}
+		if !errors.Is(err, errProxyNotFound) {
+			return ModVersion{}, err
+		}
+
+		i := strings.LastIndex(prefix, "/")
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+
+	return ModVersion{}, fmt.Errorf("no module providing %s@%s found on %s", path, query, proxy)
+}
+
+// ListVersions finds the module providing packageName, a package or module
+// path without version, and lists its tagged versions newest first. Like
+// resolveProxyModule, it tries the longest path prefix first.
+func ListVersions(client *http.Client, packageName string) (string, []string, error) {
+	proxy, err := ProxyURL()
+	if err != nil {
+		return "", nil, err
+	}
+	if NoProxy()(packageName) {
+		return "", nil, errNoProxy(packageName)
+	}
+
+	for prefix := packageName; prefix != "" && prefix != "."; {
+		versions, err := FetchVersionList(client, proxy, prefix)
+		if err == nil {
+			if len(versions) == 0 {
+				return "", nil, fmt.Errorf("%s has no tagged versions on %s", prefix, proxy)
+			}
+			return prefix, versions, nil
+		}
+		if !errors.Is(err, errProxyNotFound) {
+			return "", nil, err
+		}
+
+		i := strings.LastIndex(prefix, "/")
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+
+	return "", nil, fmt.Errorf("no module providing %s found on %s", packageName, proxy)
+}
+
+// fetchProxyGraph fetches the go.mod of root and of every module version it
+// transitively requires, level by level, and returns the requirement edges
+// in the form of 'go mod graph' along with the parsed go.mod files. It
+// fails on private modules, which noProxy matches, rather than asking the
+// proxy about them.
+func fetchProxyGraph(client *http.Client, proxy, root string, noProxy func(path string) bool) (map[string][]string, map[string]*ModFile, error) {
+	deps := make(map[string][]string)
+	modFiles := make(map[string]*ModFile)
+	seen := map[string]bool{root: true}
+	frontier := []string{root}
+
+	for len(frontier) > 0 {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		var errs []error
+		sem := make(chan struct{}, proxyWorkers)
+
+		for _, name := range frontier {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				path, version := SplitModule(name)
+				var mf *ModFile
+				var err error
+				if noProxy(path) {
+					err = errNoProxy(path)
+				} else {
+					mf, err = FetchModFile(client, proxy, path, version)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+					return
+				}
+				modFiles[name] = mf
+			}(name)
+		}
+		wg.Wait()
+		if err := errors.Join(errs...); err != nil {
+			return nil, nil, err
+		}
+
+		var next []string
+		for _, name := range frontier {
+			for _, req := range modFiles[name].Require {
+				to := req.Path + "@" + req.Version
+				deps[name] = append(deps[name], to)
+				if !seen[to] {
+					seen[to] = true
+					next = append(next, to)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return deps, modFiles, nil
+}
+
+// SelectVersions applies minimal version selection to g: every module path
+// reachable from the root is selected at the highest version reachable. The
+// result is keyed by module path in the form ReadSelectedVersions returns,
+// with the root mapped to "" as the main module.
+func (g *Graph) SelectVersions() map[string]string {
+	selected := make(map[string]string)
+	if g.Root == nil {
+		return selected
+	}
+	rootPath, _ := SplitModule(g.Root.Name)
+	selected[rootPath] = ""
+
+	for _, name := range g.Reachable(g.Root.Name) {
+		path, version := SplitModule(name)
+		if path == rootPath {
+			continue
+		}
+		if current, ok := selected[path]; !ok || CompareVersions(version, current) > 0 {
+			selected[path] = version
+		}
+	}
+	return selected
+}
@@ -0,0 +1,101 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadFromProxy(t *testing.T) {
+	modFiles := map[string]string{
+		"/example.com/tool/@v/v1.1.0.mod":  "module example.com/tool\n\nrequire (\n\texample.com/lib v1.0.0\n\texample.com/extra v0.1.0 // indirect\n)\n",
+		"/example.com/lib/@v/v1.0.0.mod":   "module example.com/lib\n\nrequire example.com/extra v0.2.0\n",
+		"/example.com/extra/@v/v0.1.0.mod": "module example.com/extra\n",
+		"/example.com/extra/@v/v0.2.0.mod": "module example.com/extra\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/example.com/tool/@latest":
+			w.Write([]byte(`{"Version":"v1.1.0"}`))
+		case strings.HasSuffix(r.URL.Path, "/@latest"):
+			// example.com/tool/cmd/tool is a package, not a module
+			http.Error(w, "not found", http.StatusNotFound)
+		default:
+			content, ok := modFiles[r.URL.Path]
+			if !ok {
+				http.Error(w, "not found", http.StatusGone)
+				return
+			}
+			w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	g, err := LoadFromProxy(server.Client(), "example.com/tool/cmd/tool")
+	if err != nil {
+		t.Fatalf("LoadFromProxy failed: %v", err)
+	}
+
+	if g.Root.Name != "example.com/tool@v1.1.0" {
+		t.Errorf("Expected root example.com/tool@v1.1.0, got %s", g.Root.Name)
+	}
+	wantDeps := map[string][]string{
+		"example.com/tool@v1.1.0": {"example.com/lib@v1.0.0", "example.com/extra@v0.1.0"},
+		"example.com/lib@v1.0.0":  {"example.com/extra@v0.2.0"},
+	}
+	if !reflect.DeepEqual(g.Deps, wantDeps) {
+		t.Errorf("Deps = %v, want %v", g.Deps, wantDeps)
+	}
+	if direct := g.DirectDependencies(); !direct["example.com/lib@v1.0.0"] || direct["example.com/extra@v0.1.0"] {
+		t.Errorf("Expected only example.com/lib to be direct, got %v", direct)
+	}
+
+	wantSelected := map[string]string{"example.com/tool": "", "example.com/lib": "v1.0.0", "example.com/extra": "v0.2.0"}
+	if selected := g.SelectVersions(); !reflect.DeepEqual(selected, wantSelected) {
+		t.Errorf("SelectVersions() = %v, want %v", selected, wantSelected)
+	}
+
+	if _, err := LoadFromProxy(server.Client(), "example.org/missing@v1.0.0"); err == nil {
+		t.Error("Expected error for a module the proxy does not know")
+	}
+
+	// Private modules are not asked for, whether analyzed or required
+	for _, private := range []string{"example.com/tool", "example.com/extra"} {
+		t.Setenv("GOPRIVATE", private)
+		if _, err := LoadFromProxy(server.Client(), "example.com/tool/cmd/tool"); err == nil || !strings.Contains(err.Error(), "GOPRIVATE") {
+			t.Errorf("GOPRIVATE=%s: expected an error for the private module, got %v", private, err)
+		}
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/tool/@v/list":
+			w.Write([]byte("v1.0.0\nv1.10.0\nv1.2.0\nv1.10.0-rc.1\n"))
+		case "/example.com/empty/@v/list":
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	module, versions, err := ListVersions(server.Client(), "example.com/tool/cmd/tool")
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if want := []string{"v1.10.0", "v1.10.0-rc.1", "v1.2.0", "v1.0.0"}; module != "example.com/tool" || !reflect.DeepEqual(versions, want) {
+		t.Errorf("ListVersions = %s %v, want example.com/tool %v", module, versions, want)
+	}
+
+	if _, _, err := ListVersions(server.Client(), "example.com/empty/pkg"); err == nil || !strings.Contains(err.Error(), "no tagged versions") {
+		t.Errorf("Expected no tagged versions error, got %v", err)
+	}
+	if _, _, err := ListVersions(server.Client(), "example.com/missing"); err == nil {
+		t.Error("Expected error for an unknown module")
+	}
+}
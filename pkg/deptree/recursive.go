@@ -0,0 +1,129 @@
+package deptree
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FindModules returns the directories below dir, dir included, that hold a
+// go.mod file. Like the go command's ./... pattern, it skips vendor and
+// testdata directories and directories whose names start with "." or "_".
+func FindModules(dir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		if fileExists(filepath.Join(path, "go.mod")) {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find modules: %w", err)
+	}
+	return dirs, nil
+}
+
+// LoadRecursive reads the graph of every module below dir (see FindModules)
+// and combines them into one graph shaped like a workspace's: rooted at
+// WorkspaceRoot with each module below it and its go.mod in g.Workspace.
+// The modules are loaded concurrently, and since the combined graph lists
+// every dependency once, fetching metadata for it costs one request per
+// distinct module however many modules share it.
+func LoadRecursive(dir string) (*Graph, error) {
+	workPath, err := FindWorkFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if workPath != "" {
+		return nil, fmt.Errorf("%s is in the workspace of %s; analyze the workspace without recursing", dir, workPath)
+	}
+
+	dirs, err := FindModules(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no go.mod found below %s", dir)
+	}
+
+	timings := &Timings{}
+	done := timings.Track("graph retrieval")
+	graphs := make([]*Graph, len(dirs))
+	errs := make([]error, len(dirs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, moduleDir := range dirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			graphs[i], errs[i] = loadMember(moduleDir)
+		}()
+	}
+	wg.Wait()
+	done()
+
+	// A root keeps Merge from adopting the root of the first module
+	g := &Graph{
+		Root:      NewNode(WorkspaceRoot),
+		Deps:      make(map[string][]string),
+		Workspace: make(map[string]*ModFile),
+		ModFile:   &ModFile{},
+		Timings:   timings,
+	}
+	for i, member := range graphs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("%s: %w", dirs[i], errs[i])
+		}
+		if member.Workspace != nil {
+			return nil, fmt.Errorf("%s is in a go.work workspace; analyze the workspace without recursing", dirs[i])
+		}
+		name := member.ModFile.Module.Path
+		if _, ok := g.Workspace[name]; ok {
+			return nil, fmt.Errorf("module %s is defined more than once below %s", name, dir)
+		}
+		g.Merge(member)
+		if _, ok := g.Deps[name]; !ok {
+			g.Deps[name] = []string{}
+		}
+		g.Workspace[name] = member.ModFile
+		g.ModFile.Replace = append(g.ModFile.Replace, member.ModFile.Replace...)
+	}
+
+	g.Deps[WorkspaceRoot] = slices.Sorted(maps.Keys(g.Workspace))
+	defer timings.Track("tree building")()
+	g.expandRoot(WorkspaceRoot)
+	return g, nil
+}
+
+// loadMember loads the module in dir for LoadRecursive. Modules without
+// requirements have no graph, so their go.mod is read on its own.
+func loadMember(dir string) (*Graph, error) {
+	g, err := Load(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	if g.ModFile == nil {
+		if g.ModFile, err = ReadModFile(dir); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
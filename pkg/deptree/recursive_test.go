@@ -0,0 +1,70 @@
+package deptree
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadRecursive(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a", "go.mod"), "module example.com/a\n\ngo 1.21\n\nrequire example.com/shared v1.0.0\n\nreplace example.com/shared => ../shared\n")
+	writeTestFile(t, filepath.Join(dir, "services", "b", "go.mod"), "module example.com/b\n\ngo 1.21\n\nrequire example.com/shared v1.0.0\n\nreplace example.com/shared => ../../shared\n")
+	writeTestFile(t, filepath.Join(dir, "shared", "go.mod"), "module example.com/shared\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "a", "testdata", "go.mod"), "module example.com/fixture\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "_old", "go.mod"), "module example.com/old\n\ngo 1.21\n")
+
+	dirs, err := FindModules(dir)
+	if err != nil {
+		t.Fatalf("FindModules failed: %v", err)
+	}
+	want := []string{filepath.Join(dir, "a"), filepath.Join(dir, "services", "b"), filepath.Join(dir, "shared")}
+	if !slices.Equal(dirs, want) {
+		t.Errorf("FindModules = %v, want %v", dirs, want)
+	}
+
+	g, err := LoadRecursive(dir)
+	if err != nil {
+		t.Fatalf("LoadRecursive failed: %v", err)
+	}
+	if g.Root.Name != WorkspaceRoot {
+		t.Fatalf("Expected root %s, got %s", WorkspaceRoot, g.Root.Name)
+	}
+	members := []string{"example.com/a", "example.com/b", "example.com/shared"}
+	if names := slices.Sorted(maps.Keys(g.Root.Children)); !slices.Equal(names, members) {
+		t.Errorf("Expected every module as a top-level node, got %v", names)
+	}
+	if names := slices.Sorted(maps.Keys(g.Workspace)); !slices.Equal(names, members) {
+		t.Errorf("Expected every module in Workspace, got %v", names)
+	}
+
+	// The shared dependency is listed once for metadata fetching
+	if !slices.Contains(g.Modules(), "example.com/shared@v1.0.0") {
+		t.Errorf("Expected example.com/shared@v1.0.0 in %v", g.Modules())
+	}
+	if !g.DirectDependencies()["example.com/shared@v1.0.0"] {
+		t.Errorf("Expected example.com/shared@v1.0.0 to be direct")
+	}
+
+	if err := g.SelectWorkspaceModule("example.com/b"); err != nil {
+		t.Fatalf("SelectWorkspaceModule failed: %v", err)
+	}
+	if _, ok := g.Root.Children["example.com/shared@v1.0.0"]; g.Root.Name != "example.com/b" || !ok {
+		t.Errorf("Expected the tree rooted at example.com/b requiring example.com/shared, got %s with %v", g.Root.Name, slices.Sorted(maps.Keys(g.Root.Children)))
+	}
+}
+
+func TestLoadRecursiveDuplicateModule(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a", "go.mod"), "module example.com/a\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "copy", "go.mod"), "module example.com/a\n\ngo 1.21\n")
+
+	if _, err := LoadRecursive(dir); err == nil {
+		t.Error("Expected an error for a module path defined twice")
+	}
+}
@@ -0,0 +1,172 @@
+package deptree
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Renderer turns a dependency graph into the bytes of one output format.
+// Each format lives in its own render_<format>.go file and registers itself
+// from an init function.
+type Renderer interface {
+	Render(g *Graph, opts RenderOptions) ([]byte, error)
+}
+
+// WriterRenderer is implemented by renderers that can write their output
+// while walking the graph, so a huge graph starts showing at once and is
+// not held in memory a second time as rendered bytes.
+type WriterRenderer interface {
+	RenderTo(w io.Writer, g *Graph, opts RenderOptions) error
+}
+
+// RenderOptions carries the display settings shared by all renderers.
+// Renderers ignore the options that do not apply to their format.
+type RenderOptions struct {
+	ShowDesc bool
+	// ShowLicense displays each module's license from Graph.Licenses and
+	// a summary of license counts.
+	ShowLicense bool
+	MaxDepth    int
+	// TrimPrefix is stripped from module paths in human-readable formats.
+	// TrimPrefixAuto strips the longest path prefix shared by all modules.
+	TrimPrefix string
+	// Walk is the traversal order of the tree and ndjson formats, WalkDFS
+	// (the default) or WalkBFS.
+	Walk string
+	// Color styles the tree and export formats with ANSI escape sequences
+	// for display on a terminal.
+	Color bool
+	// Lang is the language of the headings and labels of the html, md-table
+	// and markdown reports, one of Languages. Empty means DefaultLang.
+	Lang string
+	// Export selects the flat form of formats that also have a nested one,
+	// such as the table of the markdown format.
+	Export bool
+	// ExpandAll writes the requirements of a module at every occurrence in
+	// the tree format and the markdown list, instead of at the first only,
+	// which later occurrences refer to with "(see above)".
+	ExpandAll bool
+	// Sort is the order of the requirements below each module in the tree
+	// format, one of SortOrders. Empty means SortName.
+	Sort string
+}
+
+// TrimPrefixAuto is the RenderOptions.TrimPrefix value that derives the
+// prefix from the graph.
+const TrimPrefixAuto = "auto"
+
+var renderers = make(map[string]Renderer)
+
+// RegisterRenderer makes a renderer available under the given format name.
+// It panics if the name is already taken.
+func RegisterRenderer(name string, r Renderer) {
+	if _, exists := renderers[name]; exists {
+		panic(fmt.Sprintf("renderer %q registered twice", name))
+	}
+	renderers[name] = r
+}
+
+// LookupRenderer returns the renderer registered for the format name.
+func LookupRenderer(name string) (Renderer, error) {
+	r, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (available: %s)", name, strings.Join(RendererNames(), ", "))
+	}
+	return r, nil
+}
+
+// Render renders g in the named format.
+func Render(format string, g *Graph, opts RenderOptions) ([]byte, error) {
+	r, err := LookupRenderer(format)
+	if err != nil {
+		return nil, err
+	}
+	return r.Render(g, opts)
+}
+
+// RenderTo renders g in the named format to w, incrementally if the
+// renderer implements WriterRenderer.
+func RenderTo(w io.Writer, format string, g *Graph, opts RenderOptions) error {
+	r, err := LookupRenderer(format)
+	if err != nil {
+		return err
+	}
+	if wr, ok := r.(WriterRenderer); ok {
+		return wr.RenderTo(w, g, opts)
+	}
+	output, err := r.Render(g, opts)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(output)
+	return err
+}
+
+// RendererNames returns the registered format names in sorted order.
+func RendererNames() []string {
+	var names []string
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedChildren returns the children of node ordered by name.
+func sortedChildren(node *Node) []*Node {
+	var childNames []string
+	for name := range node.Children {
+		childNames = append(childNames, name)
+	}
+	sort.Strings(childNames)
+
+	children := make([]*Node, len(childNames))
+	for i, name := range childNames {
+		children[i] = node.Children[name]
+	}
+	return children
+}
+
+// nameTrimmer returns the function human-readable renderers apply to module
+// names before display, according to opts.TrimPrefix.
+func nameTrimmer(g *Graph, opts RenderOptions) func(string) string {
+	prefix := opts.TrimPrefix
+	if prefix == TrimPrefixAuto {
+		names := g.Modules()
+		if g.Root != nil {
+			names = append(names, g.Root.Name)
+		}
+		prefix = commonPathPrefix(names)
+	}
+	if prefix == "" {
+		return func(name string) string { return name }
+	}
+	return func(name string) string {
+		if trimmed := strings.TrimPrefix(name, prefix); trimmed != "" {
+			return trimmed
+		}
+		return name
+	}
+}
+
+// commonPathPrefix returns the longest prefix ending in "/" that all names
+// share, or "" if there is none.
+func commonPathPrefix(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	prefix := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		return prefix[:i+1]
+	}
+	return ""
+}
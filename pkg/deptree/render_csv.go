@@ -0,0 +1,61 @@
+package deptree
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+)
+
+func init() {
+	RegisterRenderer("csv", csvRenderer{comma: ','})
+	RegisterRenderer("tsv", csvRenderer{comma: '\t'})
+}
+
+// csvRenderer lists every module once as a row of comma or tab separated
+// values, for auditing the dependency inventory in a spreadsheet. The
+// license, description and link columns are filled in when -license and
+// -desc fetched them.
+type csvRenderer struct {
+	comma rune
+}
+
+// csvHeader names the columns of the csv and tsv formats.
+var csvHeader = []string{"module", "version", "relation", "depth", "license", "description", "homepage", "clone_url"}
+
+func (r csvRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = r.comma
+	w.Write(csvHeader)
+
+	direct := g.DirectDependencies()
+	depths := g.depths()
+	for _, name := range g.Modules() {
+		if name == g.Root.Name || IsToolchainDep(name) {
+			continue
+		}
+		path, version := SplitModule(name)
+		relation := "transitive"
+		if direct[name] {
+			relation = "direct"
+		}
+		var depth, license, desc string
+		var links SourceLinks
+		if d, ok := depths[name]; ok {
+			depth = strconv.Itoa(d)
+		}
+		if opts.ShowLicense {
+			license = g.Licenses[name]
+		}
+		if opts.ShowDesc {
+			if g.DescriptionErrors[name] == nil {
+				desc = g.Descriptions[name]
+			}
+			links = g.Links[name]
+		}
+		w.Write([]string{path, version, relation, depth, license, desc, links.Homepage, links.CloneURL})
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,134 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func init() {
+	RegisterRenderer("cyclonedx", cyclonedxRenderer{})
+}
+
+// cyclonedxRenderer emits a CycloneDX 1.5 SBOM document in JSON.
+type cyclonedxRenderer struct{}
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type        string       `json:"type"`
+	BOMRef      string       `json:"bom-ref,omitempty"`
+	Name        string       `json:"name"`
+	Version     string       `json:"version,omitempty"`
+	Description string       `json:"description,omitempty"`
+	PURL        string       `json:"purl,omitempty"`
+	Hashes      []cdxHash    `json:"hashes,omitempty"`
+	Licenses    []cdxLicense `json:"licenses,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxLicense struct {
+	License cdxLicenseID `json:"license"`
+}
+
+type cdxLicenseID struct {
+	ID string `json:"id"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func (cyclonedxRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	created, err := sbomTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	modules := sbomModules(g)
+	digest := sbomDigest(modules)
+	// Shape the digest into an RFC 4122 UUID (version 5 layout)
+	digest[6] = digest[6]&0x0f | 0x50
+	digest[8] = digest[8]&0x3f | 0x80
+
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", digest[0:4], digest[4:6], digest[6:8], digest[8:10], digest[10:16]),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: created,
+			Tools: cdxTools{Components: []cdxComponent{{
+				Type: "application",
+				Name: "deptree",
+			}}},
+		},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+
+	deps := sbomDependencies(g)
+	for i, name := range modules {
+		component := cdxComponentFor(g, name, opts)
+		if i == 0 {
+			component.Type = "application"
+			bom.Metadata.Component = component
+		} else {
+			bom.Components = append(bom.Components, component)
+		}
+
+		dependency := cdxDependency{Ref: component.BOMRef, DependsOn: []string{}}
+		for _, to := range deps[name] {
+			dependency.DependsOn = append(dependency.DependsOn, PackageURL(to))
+		}
+		bom.Dependencies = append(bom.Dependencies, dependency)
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func cdxComponentFor(g *Graph, name string, opts RenderOptions) cdxComponent {
+	path, version := SplitModule(name)
+	component := cdxComponent{
+		Type:    "library",
+		BOMRef:  PackageURL(name),
+		Name:    path,
+		Version: version,
+		PURL:    PackageURL(name),
+	}
+	if sum := g.Sums.SHA256(name); sum != "" {
+		component.Hashes = []cdxHash{{Alg: "SHA-256", Content: sum}}
+	}
+	if license := g.Licenses[name]; license != "" && license != UnknownLicense {
+		component.Licenses = []cdxLicense{{License: cdxLicenseID{ID: license}}}
+	}
+	if opts.ShowDesc && g.DescriptionErrors[name] == nil {
+		component.Description = g.Descriptions[name]
+	}
+	return component
+}
@@ -0,0 +1,52 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func init() {
+	RegisterRenderer("dot", dotRenderer{})
+}
+
+// dotRenderer emits a Graphviz digraph. Nodes are named by NodeID and
+// labeled with the module name. Edge kinds are carried in a "kind"
+// attribute and drawn dashed (indirect) or dotted (transitive).
+type dotRenderer struct{}
+
+func (dotRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "digraph deptree {")
+	fmt.Fprintln(&buf, "  rankdir=LR;")
+	fmt.Fprintln(&buf, "  node [shape=box];")
+
+	for _, name := range g.Modules() {
+		if desc := g.Descriptions[name]; opts.ShowDesc && desc != "" {
+			fmt.Fprintf(&buf, "  %s [label=%q, tooltip=%q];\n", NodeID(name), name, desc)
+		} else {
+			fmt.Fprintf(&buf, "  %s [label=%q];\n", NodeID(name), name)
+		}
+	}
+
+	for _, edge := range g.Edges() {
+		attrs := fmt.Sprintf("kind=%q", edge.Kind)
+		switch edge.Kind {
+		case EdgeIndirect:
+			attrs += ", style=dashed"
+		case EdgeTransitive:
+			attrs += ", style=dotted"
+		case EdgeReplace:
+			attrs += ", style=dotted, color=blue"
+		case EdgeExclude:
+			attrs += ", style=dotted, color=orange"
+		}
+		if edge.Replace != "" {
+			attrs += fmt.Sprintf(", replace=%q, label=%q", edge.Replace, "=> "+edge.Replace)
+		}
+		fmt.Fprintf(&buf, "  %s -> %s [%s];\n", NodeID(edge.From), NodeID(edge.To), attrs)
+	}
+
+	fmt.Fprintln(&buf, "}")
+	return buf.Bytes(), nil
+}
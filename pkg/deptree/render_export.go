@@ -0,0 +1,75 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func init() {
+	RegisterRenderer("export", exportRenderer{})
+}
+
+// exportRenderer prints every module once as a flat, sorted list.
+type exportRenderer struct{}
+
+func (exportRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	trim := nameTrimmer(g, opts)
+	direct := g.DirectDependencies()
+	directives := g.directiveLabels()
+	painter := newPainter(g, opts, direct)
+
+	modules := g.Modules()
+	sortBySize(g, modules)
+
+	for _, dep := range modules {
+		label := trim(dep)
+		if direct[dep] {
+			label += " " + directTag
+		}
+		if directive, ok := directives[dep]; ok {
+			label += " " + directive
+		}
+		if g.TestOnly[dep] {
+			label += " " + testTag
+		}
+		if license, ok := g.Licenses[dep]; ok && opts.ShowLicense {
+			label += " [" + license + "]"
+		}
+		if vulns := g.VulnerabilityDetails[dep]; len(vulns) > 0 {
+			label += " " + vulnerableLabel(vulns)
+		}
+		if latest, ok := g.Outdated[dep]; ok {
+			label += " " + outdatedLabel(latest)
+		}
+		if size, ok := g.Sizes[dep]; ok {
+			label += " " + sizeLabel(size)
+		}
+		if status, ok := g.RepoStatus[dep]; ok {
+			label += " " + repoStatusLabel(status)
+		}
+		if sc, ok := g.Scorecards[dep]; ok {
+			label += " " + scorecardLabel(sc)
+		}
+		if n, ok := g.ImportedBy[dep]; ok {
+			label += " " + importedByLabel(n)
+		}
+
+		label = painter.paint(dep, label)
+
+		if desc, ok := g.Descriptions[dep]; ok && opts.ShowDesc {
+			fmt.Fprintf(&buf, "%s - %s\n", label, desc)
+		} else {
+			fmt.Fprintln(&buf, label)
+		}
+	}
+
+	if opts.ShowLicense {
+		writeLicenseSummary(&buf, g)
+	}
+	writeOutdatedSummary(&buf, g)
+	writeSizeSummary(&buf, g)
+	writeRepoStatusSummary(&buf, g)
+
+	return buf.Bytes(), nil
+}
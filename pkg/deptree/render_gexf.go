@@ -0,0 +1,113 @@
+package deptree
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+func init() {
+	RegisterRenderer("gexf", gexfRenderer{})
+}
+
+// gexfRenderer emits a GEXF 1.3 document for Gephi. Nodes are identified by
+// NodeID and labeled with the module name, edges are labeled with their
+// kind, and the other attributes are declared GEXF attributes.
+type gexfRenderer struct{}
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator string `xml:"creator"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue,omitempty"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue,omitempty"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gexfAttValuesOf converts attributes to attribute values, leaving out
+// the one carried as the label.
+func gexfAttValuesOf(attrs [][2]string, label string) []gexfAttValue {
+	var values []gexfAttValue
+	for _, attr := range attrs {
+		if attr[0] != label {
+			values = append(values, gexfAttValue{For: attr[0], Value: attr[1]})
+		}
+	}
+	return values
+}
+
+func (gexfRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	doc := gexfDocument{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Meta:    gexfMeta{Creator: "deptree"},
+		Graph:   gexfGraph{DefaultEdgeType: "directed"},
+	}
+	nodeClass := gexfAttributes{Class: "node"}
+	for _, key := range nodeAttributeKeys[1:] {
+		nodeClass.Attributes = append(nodeClass.Attributes, gexfAttribute{ID: key, Title: key, Type: "string"})
+	}
+	edgeClass := gexfAttributes{Class: "edge"}
+	for _, key := range edgeAttributeKeys[1:] {
+		edgeClass.Attributes = append(edgeClass.Attributes, gexfAttribute{ID: key, Title: key, Type: "string"})
+	}
+	doc.Graph.Attributes = []gexfAttributes{nodeClass, edgeClass}
+
+	for _, name := range g.Modules() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:        NodeID(name),
+			Label:     name,
+			AttValues: gexfAttValuesOf(nodeAttributes(g, name, opts), "label"),
+		})
+	}
+	for i, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:        strconv.Itoa(i),
+			Source:    NodeID(edge.From),
+			Target:    NodeID(edge.To),
+			Label:     string(edge.Kind),
+			AttValues: gexfAttValuesOf(edgeAttributes(edge), "kind"),
+		})
+	}
+
+	return marshalXML(doc)
+}
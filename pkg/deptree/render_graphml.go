@@ -0,0 +1,136 @@
+package deptree
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	RegisterRenderer("graphml", graphMLRenderer{})
+}
+
+// graphMLRenderer emits a GraphML document for graph tools such as yEd and
+// Cytoscape. Like the dot format, nodes are identified by NodeID; the module
+// name is their label and the other module and edge attributes are data
+// keys.
+type graphMLRenderer struct{}
+
+// nodeAttributeKeys and edgeAttributeKeys name the attributes the GraphML
+// and GEXF formats declare for modules and requirement edges.
+var (
+	nodeAttributeKeys = []string{"label", "path", "version", "description", "license"}
+	edgeAttributeKeys = []string{"kind", "replace"}
+)
+
+// nodeAttributes returns the attributes of a module in the order of
+// nodeAttributeKeys, leaving out those without a value.
+func nodeAttributes(g *Graph, name string, opts RenderOptions) [][2]string {
+	path, version := SplitModule(name)
+	var desc, license string
+	if opts.ShowDesc && g.DescriptionErrors[name] == nil {
+		desc = g.Descriptions[name]
+	}
+	if opts.ShowLicense {
+		license = g.Licenses[name]
+	}
+	return nonEmptyAttributes(nodeAttributeKeys, name, path, version, desc, license)
+}
+
+// edgeAttributes returns the attributes of an edge in the order of
+// edgeAttributeKeys, leaving out those without a value.
+func edgeAttributes(edge Edge) [][2]string {
+	return nonEmptyAttributes(edgeAttributeKeys, string(edge.Kind), edge.Replace)
+}
+
+func nonEmptyAttributes(keys []string, values ...string) [][2]string {
+	var attrs [][2]string
+	for i, value := range values {
+		if value != "" {
+			attrs = append(attrs, [2]string{keys[i], value})
+		}
+	}
+	return attrs
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func graphMLDataOf(attrs [][2]string) []graphMLData {
+	data := make([]graphMLData, len(attrs))
+	for i, attr := range attrs {
+		data[i] = graphMLData{Key: attr[0], Value: attr[1]}
+	}
+	return data
+}
+
+func (graphMLRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphMLGraph{ID: "deptree", EdgeDefault: "directed"},
+	}
+	for _, key := range nodeAttributeKeys {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: key, For: "node", Name: key, Type: "string"})
+	}
+	for _, key := range edgeAttributeKeys {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: key, For: "edge", Name: key, Type: "string"})
+	}
+
+	for _, name := range g.Modules() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: NodeID(name), Data: graphMLDataOf(nodeAttributes(g, name, opts))})
+	}
+	for i, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "e" + strconv.Itoa(i),
+			Source: NodeID(edge.From),
+			Target: NodeID(edge.To),
+			Data:   graphMLDataOf(edgeAttributes(edge)),
+		})
+	}
+
+	return marshalXML(doc)
+}
+
+// marshalXML renders doc as an indented XML document with a declaration.
+func marshalXML(doc any) ([]byte, error) {
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode XML: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
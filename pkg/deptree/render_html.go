@@ -0,0 +1,140 @@
+package deptree
+
+import (
+	"bytes"
+	"cmp"
+	"html/template"
+)
+
+func init() {
+	RegisterRenderer("html", htmlRenderer{})
+}
+
+// htmlRenderer writes a self-contained HTML report with a collapsible tree,
+// a search box and the metadata of each module.
+type htmlRenderer struct{}
+
+type htmlReport struct {
+	Lang     string
+	T        map[string]string
+	Root     *htmlNode
+	Modules  int
+	Licenses []licenseCount
+}
+
+type htmlNode struct {
+	Path        string
+	Version     string
+	Description string
+	License     string
+	Notes       []string
+	Children    []*htmlNode
+}
+
+type licenseCount struct {
+	License string
+	Count   int
+}
+
+func (htmlRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	report := htmlReport{
+		Lang:    cmp.Or(opts.Lang, DefaultLang),
+		T:       messagesFor(opts.Lang),
+		Root:    htmlTree(g, g.Root, opts),
+		Modules: len(g.Modules()),
+	}
+	if opts.ShowLicense {
+		counts := g.LicenseCounts()
+		for _, license := range sortedLicenses(counts) {
+			report.Licenses = append(report.Licenses, licenseCount{license, counts[license]})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, report); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func htmlTree(g *Graph, node *Node, opts RenderOptions) *htmlNode {
+	path, version := SplitModule(node.Name)
+	n := &htmlNode{Path: path, Version: version, Notes: node.Annotations}
+	if opts.ShowDesc && g.DescriptionErrors[node.Name] == nil {
+		n.Description = g.Descriptions[node.Name]
+	}
+	if opts.ShowLicense {
+		n.License = g.Licenses[node.Name]
+	}
+	for _, child := range sortedChildren(node) {
+		n.Children = append(n.Children, htmlTree(g, child, opts))
+	}
+	return n
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.T.dependencies_of}} {{.Root.Path}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+#search { width: 100%; max-width: 40em; padding: .4em; font-size: 1em; margin-bottom: 1em; }
+ul { list-style: none; padding-left: 1.4em; margin: 0; }
+details > summary { cursor: pointer; }
+.leaf { padding-left: 1.1em; }
+.path { font-family: ui-monospace, monospace; }
+.version, .license { font-size: .8em; border-radius: 3px; padding: 0 .3em; margin-left: .3em; }
+.version { background: #eef; }
+.license { background: #efe; }
+.note { color: #a40; margin-left: .3em; }
+.desc { color: #666; margin-left: .5em; }
+.hidden { display: none; }
+mark { background: #ff6; }
+table { border-collapse: collapse; margin-top: 2em; }
+td, th { border: 1px solid #ccc; padding: .2em .6em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.T.dependencies_of}} <span class="path">{{.Root.Path}}</span>{{with .Root.Version}} <span class="version">{{.}}</span>{{end}}</h1>
+<p>{{printf .T.module_count .Modules}}</p>
+<input id="search" type="search" placeholder="{{.T.search_placeholder}}">
+<ul id="tree">{{template "node" .Root}}</ul>
+{{- with .Licenses}}
+<table>
+<tr><th>{{$.T.license}}</th><th>{{$.T.modules}}</th></tr>
+{{- range .}}
+<tr><td>{{.License}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<script>
+document.getElementById("search").addEventListener("input", function (e) {
+  var query = e.target.value.toLowerCase();
+  var items = document.querySelectorAll("#tree li");
+  items.forEach(function (li) { li.classList.toggle("hidden", query !== ""); });
+  if (query === "") return;
+  items.forEach(function (li) {
+    if (li.dataset.name.toLowerCase().indexOf(query) === -1) return;
+    // Show the match, its subtree and the path to it
+    li.querySelectorAll("li").forEach(function (c) { c.classList.remove("hidden"); });
+    for (var el = li; el && el.id !== "tree"; el = el.parentElement) {
+      el.classList.remove("hidden");
+      if (el.tagName === "DETAILS") el.open = true;
+    }
+  });
+});
+</script>
+</body>
+</html>
+{{define "label"}}<span class="path">{{.Path}}</span>
+{{- with .Version}}<span class="version">{{.}}</span>{{end}}
+{{- with .License}}<span class="license">{{.}}</span>{{end}}
+{{- range .Notes}}<span class="note">{{.}}</span>{{end}}
+{{- with .Description}}<span class="desc">{{.}}</span>{{end}}{{end}}
+{{define "node"}}<li data-name="{{.Path}}@{{.Version}}">
+{{- if .Children}}<details open><summary>{{template "label" .}}</summary><ul>
+{{- range .Children}}{{template "node" .}}{{end}}</ul></details>
+{{- else}}<div class="leaf">{{template "label" .}}</div>{{end}}</li>
+{{end}}`))
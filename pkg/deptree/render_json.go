@@ -0,0 +1,129 @@
+package deptree
+
+import (
+	"encoding/json"
+)
+
+func init() {
+	RegisterRenderer("json", jsonRenderer{})
+}
+
+// jsonRenderer emits the modules and annotated requirement edges as JSON.
+type jsonRenderer struct{}
+
+type jsonGraph struct {
+	Root    string       `json:"root"`
+	Modules []jsonModule `json:"modules"`
+	Edges   []jsonEdge   `json:"edges"`
+	Pruned  []string     `json:"pruned,omitempty"`
+	Partial []string     `json:"partial,omitempty"`
+}
+
+type jsonModule struct {
+	// ID is the module's NodeID, stable across runs.
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+	License     string `json:"license,omitempty"`
+	// Homepage and CloneURL link to the module's repository, set with the
+	// description when descriptions were fetched.
+	Homepage string `json:"homepage,omitempty"`
+	CloneURL string `json:"cloneUrl,omitempty"`
+	// Latest is the newer version available, set when outdated versions
+	// were checked.
+	Latest string `json:"latest,omitempty"`
+	// Size is the size in bytes of the module's source in the module cache,
+	// set when sizes were measured.
+	Size int64 `json:"size,omitempty"`
+	// ImportedBy is the number of packages importing the module's root
+	// package, set when pkg.go.dev metadata was fetched.
+	ImportedBy *int `json:"importedBy,omitempty"`
+	// Test is set for modules only tests import, when test dependencies
+	// were marked.
+	Test bool `json:"test,omitempty"`
+	// Repo is the maintenance state of the module's GitHub repository,
+	// set when repository statuses were fetched.
+	Repo *RepoStatus `json:"repo,omitempty"`
+	// Scorecard is the OpenSSF Scorecard of the module's GitHub repository,
+	// set when scorecards were fetched.
+	Scorecard *Scorecard `json:"scorecard,omitempty"`
+	// Vulnerabilities are OSV advisory IDs, set when vulnerabilities were
+	// detected.
+	Vulnerabilities []string `json:"vulnerabilities,omitempty"`
+	// Advisories are the details of Vulnerabilities.
+	Advisories []Vulnerability `json:"advisories,omitempty"`
+	// GoMod summarizes the module's own go.mod, when it was read.
+	GoMod *jsonGoMod `json:"goMod,omitempty"`
+}
+
+// jsonGoMod is what a module declares about itself in its go.mod.
+type jsonGoMod struct {
+	Module     string       `json:"module"`
+	Go         string       `json:"go,omitempty"`
+	Deprecated string       `json:"deprecated,omitempty"`
+	Retract    []ModRetract `json:"retract,omitempty"`
+}
+
+type jsonEdge struct {
+	From    string   `json:"from"`
+	FromID  string   `json:"fromId"`
+	To      string   `json:"to"`
+	ToID    string   `json:"toId"`
+	Kind    EdgeKind `json:"kind"`
+	Replace string   `json:"replace,omitempty"`
+}
+
+func (jsonRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	out := jsonGraph{
+		Root:    g.Root.Name,
+		Modules: []jsonModule{},
+		Edges:   []jsonEdge{},
+		Pruned:  g.Pruned,
+		Partial: g.Partial,
+	}
+
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		module := jsonModule{ID: NodeID(name), Name: name, Path: path, Version: version, Vulnerabilities: g.Vulnerabilities[name], Advisories: g.VulnerabilityDetails[name], Latest: g.Outdated[name], Size: g.Sizes[name], Test: g.TestOnly[name]}
+		if status, ok := g.RepoStatus[name]; ok {
+			module.Repo = &status
+		}
+		if sc, ok := g.Scorecards[name]; ok {
+			module.Scorecard = &sc
+		}
+		if n, ok := g.ImportedBy[name]; ok {
+			module.ImportedBy = &n
+		}
+		if mf := g.ModFiles[name]; mf != nil {
+			module.GoMod = &jsonGoMod{Module: mf.Module.Path, Go: mf.Go, Deprecated: mf.Module.Deprecated, Retract: mf.Retract}
+		}
+		if opts.ShowDesc {
+			module.Description = g.Descriptions[name]
+			module.Homepage = g.Links[name].Homepage
+			module.CloneURL = g.Links[name].CloneURL
+		}
+		if opts.ShowLicense {
+			module.License = g.Licenses[name]
+		}
+		out.Modules = append(out.Modules, module)
+	}
+
+	for _, edge := range g.Edges() {
+		out.Edges = append(out.Edges, jsonEdge{
+			From:    edge.From,
+			FromID:  NodeID(edge.From),
+			To:      edge.To,
+			ToID:    NodeID(edge.To),
+			Kind:    edge.Kind,
+			Replace: edge.Replace,
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,137 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterRenderer("markdown", markdownRenderer{})
+}
+
+// markdownRenderer prints the dependency tree as a nested markdown bullet
+// list, or with RenderOptions.Export every module once as a table, for
+// pasting into documentation and pull request descriptions. Module
+// versions link to their documentation on pkg.go.dev.
+type markdownRenderer struct{}
+
+// pkgGoDevURL is the base URL of module documentation links.
+const pkgGoDevURL = "https://pkg.go.dev"
+
+var mdLineEscaper = strings.NewReplacer("\r\n", " ", "\n", " ")
+
+func (markdownRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	w := &markdownWriter{buf: &buf, g: g, opts: opts, name: nameTrimmer(g, opts), direct: g.DirectDependencies()}
+	if opts.Export {
+		w.writeTable()
+	} else {
+		w.expanded = expandedNodes(g.Root)
+		w.written = make(map[string]bool)
+		w.path = make(map[string]bool)
+		w.writeNode(g.Root, 0)
+	}
+	return buf.Bytes(), nil
+}
+
+// markdownWriter holds the state of one markdown rendering.
+type markdownWriter struct {
+	buf    *bytes.Buffer
+	g      *Graph
+	opts   RenderOptions
+	name   func(string) string
+	direct map[string]bool
+	// expanded, written and path track repeated modules like the fields
+	// of treeWriter.
+	expanded map[string]*Node
+	written  map[string]bool
+	path     map[string]bool
+}
+
+// link returns text linked to the documentation of a module, or text alone
+// for modules without any: those without a version, such as the main
+// module, and the go toolchain.
+func (w *markdownWriter) link(name, text string) string {
+	path, version := SplitModule(name)
+	if version == "" || IsToolchainDep(name) {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s/%s@%s)", text, pkgGoDevURL, path, version)
+}
+
+// description returns the fetched description of a module, if any was
+// fetched without error.
+func (w *markdownWriter) description(name string) string {
+	if !w.opts.ShowDesc || w.g.DescriptionErrors[name] != nil {
+		return ""
+	}
+	return w.g.Descriptions[name]
+}
+
+// writeNode writes node as an item of the bullet list indented to depth,
+// followed by its children up to RenderOptions.MaxDepth. Like the tree
+// format, it writes a module's requirements at its first occurrence and
+// marks later ones "(see above)", unless RenderOptions.ExpandAll is set.
+func (w *markdownWriter) writeNode(node *Node, depth int) {
+	full := node
+	if n, ok := w.expanded[node.Name]; ok {
+		full = n
+	}
+	parts := []string{w.link(node.Name, w.name(node.Name))}
+	if w.direct[node.Name] {
+		parts = append(parts, directTag)
+	}
+	parts = append(parts, node.Annotations...)
+	if license, ok := w.g.Licenses[node.Name]; ok && w.opts.ShowLicense {
+		parts = append(parts, "["+license+"]")
+	}
+	expand := len(full.Children) > 0
+	switch {
+	case !expand:
+	case w.written[node.Name] && !w.opts.ExpandAll:
+		parts = append(parts, seeAboveTag)
+		expand = false
+	case w.path[node.Name]:
+		parts = append(parts, cycleTag)
+		expand = false
+	case w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth:
+		parts = append(parts, fmt.Sprintf("(+%d more)", countDescendants(full)))
+		expand = false
+	}
+	line := strings.Join(parts, " ")
+	if desc := w.description(node.Name); desc != "" {
+		line += " - " + mdLineEscaper.Replace(desc)
+	}
+	fmt.Fprintf(w.buf, "%s- %s\n", strings.Repeat("  ", depth), line)
+
+	if expand {
+		w.written[node.Name] = true
+		w.path[node.Name] = true
+		for _, child := range sortedChildren(full) {
+			w.writeNode(child, depth+1)
+		}
+		delete(w.path, node.Name)
+	}
+}
+
+// writeTable writes the modules of the graph other than the main module as
+// a table with translated headings, like the md-table format.
+func (w *markdownWriter) writeTable() {
+	t := messagesFor(w.opts.Lang)
+	fmt.Fprintf(w.buf, "| %s | %s | %s | %s |\n", t["module"], t["version"], t["license"], t["description"])
+	w.buf.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, name := range w.g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" || (w.g.Root != nil && name == w.g.Root.Name) {
+			continue
+		}
+		var license string
+		if w.opts.ShowLicense {
+			license = w.g.Licenses[name]
+		}
+		fmt.Fprintf(w.buf, "| %s | %s | %s | %s |\n",
+			mdCellEscaper.Replace(w.link(name, w.name(path))), version, mdCellEscaper.Replace(license), mdCellEscaper.Replace(w.description(name)))
+	}
+}
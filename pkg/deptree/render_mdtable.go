@@ -0,0 +1,46 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterRenderer("md-table", mdTableRenderer{})
+}
+
+// mdTableRenderer prints the dependencies of the root module as a markdown
+// table for the third-party notices of project documentation. The License
+// and Description columns are filled in when -license and -desc fetched
+// them.
+type mdTableRenderer struct{}
+
+var mdCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+func (mdTableRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	t := messagesFor(opts.Lang)
+	fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n", t["module"], t["version"], t["license"], t["description"])
+	buf.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" || (g.Root != nil && name == g.Root.Name) {
+			continue
+		}
+
+		var license, desc string
+		if opts.ShowLicense {
+			license = g.Licenses[name]
+		}
+		if opts.ShowDesc && g.DescriptionErrors[name] == nil {
+			desc = g.Descriptions[name]
+		}
+
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n",
+			mdCellEscaper.Replace(path), version, mdCellEscaper.Replace(license), mdCellEscaper.Replace(desc))
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,47 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterRenderer("mermaid", mermaidRenderer{})
+}
+
+// mermaidRenderer emits a Mermaid flowchart that renders inline in GitHub,
+// GitLab and Notion markdown. Like the dot format, nodes are named by NodeID
+// and labeled with the module name. Indirect requirements are drawn dotted.
+type mermaidRenderer struct{}
+
+// mermaidEscaper replaces the characters that would end or break a quoted
+// Mermaid label with entity codes.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+func (mermaidRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "graph TD")
+
+	for _, name := range g.Modules() {
+		label := mermaidEscaper.Replace(name)
+		if desc := g.Descriptions[name]; opts.ShowDesc && desc != "" {
+			label += "<br/><small>" + mermaidEscaper.Replace(desc) + "</small>"
+		}
+		fmt.Fprintf(&buf, "  %s[\"%s\"]\n", NodeID(name), label)
+	}
+
+	for _, edge := range g.Edges() {
+		arrow := "-->"
+		if edge.Kind == EdgeIndirect {
+			arrow = "-.->"
+		}
+		if edge.Replace != "" {
+			arrow += `|"=> ` + mermaidEscaper.Replace(edge.Replace) + `"|`
+		}
+		fmt.Fprintf(&buf, "  %s %s %s\n", NodeID(edge.From), arrow, NodeID(edge.To))
+	}
+
+	return buf.Bytes(), nil
+}
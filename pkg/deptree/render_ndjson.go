@@ -0,0 +1,56 @@
+package deptree
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+func init() {
+	RegisterRenderer("ndjson", ndjsonRenderer{})
+}
+
+// ndjsonRenderer emits one JSON object per tree node, in the order chosen by
+// RenderOptions.Walk, so the output can be streamed through line-based
+// tools such as jq or grep.
+type ndjsonRenderer struct{}
+
+type ndjsonNode struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Version     string `json:"version,omitempty"`
+	Depth       int    `json:"depth"`
+	Parent      string `json:"parent,omitempty"`
+	Description string `json:"description,omitempty"`
+	License     string `json:"license,omitempty"`
+}
+
+func (ndjsonRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	var encodeErr error
+	err := g.WalkTree(opts.Walk, opts.MaxDepth, func(v TreeVisit) {
+		path, version := SplitModule(v.Node.Name)
+		node := ndjsonNode{Name: v.Node.Name, Path: path, Version: version, Depth: v.Depth}
+		if v.Parent != nil {
+			node.Parent = v.Parent.Name
+		}
+		if opts.ShowDesc {
+			node.Description = v.Node.Description
+		}
+		if opts.ShowLicense {
+			node.License = g.Licenses[v.Node.Name]
+		}
+		if err := enc.Encode(node); err != nil && encodeErr == nil {
+			encodeErr = err
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+
+	return buf.Bytes(), nil
+}
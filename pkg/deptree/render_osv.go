@@ -0,0 +1,67 @@
+package deptree
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+func init() {
+	RegisterRenderer("osv-lockfile", osvLockfileRenderer{})
+}
+
+// osvLockfileRenderer emits the versioned modules of the graph in the JSON
+// results format osv-scanner reads back as a lockfile
+// ('osv-scanner --lockfile osv-scanner:deps.json').
+type osvLockfileRenderer struct{}
+
+type osvLockfile struct {
+	Results []osvLockfileResult `json:"results"`
+}
+
+type osvLockfileResult struct {
+	Source   osvLockfileSource    `json:"source"`
+	Packages []osvLockfilePackage `json:"packages"`
+}
+
+type osvLockfileSource struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type osvLockfilePackage struct {
+	Package osvLockfilePackageInfo `json:"package"`
+}
+
+type osvLockfilePackageInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+func (osvLockfileRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	result := osvLockfileResult{
+		Source:   osvLockfileSource{Path: "go.mod", Type: "lockfile"},
+		Packages: []osvLockfilePackage{},
+	}
+
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		result.Packages = append(result.Packages, osvLockfilePackage{
+			Package: osvLockfilePackageInfo{
+				Name: path,
+				// osv-scanner records Go versions without the "v" prefix
+				Version:   strings.TrimPrefix(version, "v"),
+				Ecosystem: "Go",
+			},
+		})
+	}
+
+	data, err := json.MarshalIndent(osvLockfile{Results: []osvLockfileResult{result}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,142 @@
+package deptree
+
+import (
+	"encoding/hex"
+	"encoding/json"
+)
+
+func init() {
+	RegisterRenderer("spdx", spdxRenderer{})
+}
+
+// spdxRenderer emits an SPDX 2.3 SBOM document in JSON.
+type spdxRenderer struct{}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Description      string            `json:"description,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const spdxNoAssertion = "NOASSERTION"
+
+func (spdxRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	created, err := sbomTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	modules := sbomModules(g)
+	digest := sbomDigest(modules)
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              g.Root.Name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/deptree/" + hex.EncodeToString(digest[:16]),
+		CreationInfo: spdxCreationInfo{
+			Created:  created,
+			Creators: []string{"Tool: deptree"},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for _, name := range modules {
+		path, version := SplitModule(name)
+		pkg := spdxPackage{
+			Name:             path,
+			SPDXID:           spdxID(name),
+			VersionInfo:      version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  PackageURL(name),
+			}},
+		}
+		if version != "" {
+			pkg.DownloadLocation = "https://proxy.golang.org/" + escapeModulePath(path) + "/@v/" + escapeModulePath(version) + ".zip"
+		}
+		if sum := g.Sums.SHA256(name); sum != "" {
+			pkg.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: sum}}
+		}
+		if license := g.Licenses[name]; license != "" && license != UnknownLicense {
+			pkg.LicenseDeclared = license
+		}
+		if opts.ShowDesc && g.DescriptionErrors[name] == nil {
+			pkg.Description = g.Descriptions[name]
+		}
+		doc.Packages = append(doc.Packages, pkg)
+	}
+
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		SPDXElementID:      doc.SPDXID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: spdxID(g.Root.Name),
+	})
+	for _, edge := range g.Edges() {
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      spdxID(edge.From),
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: spdxID(edge.To),
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// spdxID turns a module name into an SPDX element identifier. It is built
+// from NodeID, since mapping the characters SPDX does not allow would make
+// paths like "a/b-c" and "a/b/c" collide.
+func spdxID(module string) string {
+	return "SPDXRef-Package-" + NodeID(module)
+}
@@ -0,0 +1,748 @@
+package deptree
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLookupRenderer(t *testing.T) {
+	for _, name := range []string{"tree", "export"} {
+		if _, err := LookupRenderer(name); err != nil {
+			t.Errorf("Expected %q renderer to be registered: %v", name, err)
+		}
+	}
+
+	if _, err := LookupRenderer("nope"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestExportRenderer(t *testing.T) {
+	deps := map[string][]string{
+		"mymodule":    {"dep1@v1.0.0", "dep2@v2.0.0"},
+		"dep1@v1.0.0": {"dep3@v1.5.0"},
+		"dep2@v2.0.0": {},
+		"dep3@v1.5.0": {},
+		"temp":        {"go@1.21.0"},
+		"go@1.21.0":   {},
+	}
+
+	out, err := exportRenderer{}.Render(&Graph{Deps: deps}, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	// Check that output contains expected dependencies
+	if !strings.Contains(output, "dep1@v1.0.0") {
+		t.Error("Expected output to contain dep1@v1.0.0")
+	}
+	if !strings.Contains(output, "dep2@v2.0.0") {
+		t.Error("Expected output to contain dep2@v2.0.0")
+	}
+	if !strings.Contains(output, "dep3@v1.5.0") {
+		t.Error("Expected output to contain dep3@v1.5.0")
+	}
+	if !strings.Contains(output, "mymodule") {
+		t.Error("Expected output to contain mymodule")
+	}
+
+	// Check that temp and toolchain deps are filtered out
+	if strings.Contains(output, "temp") {
+		t.Error("Expected output to not contain 'temp'")
+	}
+	if strings.Contains(output, "go@1.21.0") {
+		t.Error("Expected output to not contain toolchain dependency go@1.21.0")
+	}
+
+	// Check that output is sorted (each line should be >= previous)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] < lines[i-1] {
+			t.Errorf("Output is not sorted: %s comes before %s", lines[i-1], lines[i])
+		}
+	}
+}
+
+func TestTreeRenderer(t *testing.T) {
+	root := NewNode("root@v1.0.0")
+	child1 := NewNode("child1@v1.0.0")
+	child2 := NewNode("child2@v2.0.0")
+	root.Children["child1@v1.0.0"] = child1
+	root.Children["child2@v2.0.0"] = child2
+
+	out, err := treeRenderer{}.Render(&Graph{Root: root}, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	// Check that output contains the root and children
+	if !strings.Contains(output, "root@v1.0.0") {
+		t.Error("Expected output to contain root@v1.0.0")
+	}
+	if !strings.Contains(output, "child1@v1.0.0") {
+		t.Error("Expected output to contain child1@v1.0.0")
+	}
+	if !strings.Contains(output, "child2@v2.0.0") {
+		t.Error("Expected output to contain child2@v2.0.0")
+	}
+
+	// Check for tree characters
+	if !strings.Contains(output, "├──") && !strings.Contains(output, "└──") {
+		t.Error("Expected output to contain tree drawing characters")
+	}
+}
+
+func TestRenderTo(t *testing.T) {
+	root := NewNode("root@v1.0.0")
+	root.Children["child@v1.0.0"] = NewNode("child@v1.0.0")
+	g := &Graph{Root: root}
+
+	// Both the tree renderer, which writes as it walks, and renderers
+	// that only return bytes must match Render
+	for _, format := range []string{"tree", "json"} {
+		want, err := Render(format, g, RenderOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := RenderTo(&buf, format, g, RenderOptions{}); err != nil {
+			t.Fatalf("RenderTo %s failed: %v", format, err)
+		}
+		if buf.String() != string(want) {
+			t.Errorf("RenderTo %s wrote:\n%s\nwant:\n%s", format, buf.String(), want)
+		}
+	}
+
+	if err := RenderTo(&bytes.Buffer{}, "nope", g, RenderOptions{}); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestTreeRendererDepthLimit(t *testing.T) {
+	root := NewNode("root")
+	child := NewNode("child@v1.0.0")
+	grandchild := NewNode("grandchild@v1.0.0")
+	grandchild.Children["leaf@v1.0.0"] = NewNode("leaf@v1.0.0")
+	child.Children["grandchild@v1.0.0"] = grandchild
+	root.Children["child@v1.0.0"] = child
+
+	out, err := treeRenderer{}.Render(&Graph{Root: root}, RenderOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	if !strings.Contains(output, "child@v1.0.0 (+2 more)") {
+		t.Errorf("Expected truncated child with descendant count, got:\n%s", output)
+	}
+	if strings.Contains(output, "grandchild@v1.0.0") {
+		t.Error("Expected grandchild to be hidden beyond depth 1")
+	}
+}
+
+func TestTreeRendererRepeatedSubtrees(t *testing.T) {
+	deps := map[string][]string{
+		"app":      {"z@v1.0.0", "a@v1.0.0"},
+		"z@v1.0.0": {"c@v1.0.0"},
+		"a@v1.0.0": {"c@v1.0.0"},
+		"c@v1.0.0": {"d@v1.0.0"},
+		"d@v1.0.0": {"c@v1.0.0"},
+	}
+
+	// buildTree expands c below z, which comes after a in the tree
+	g := &Graph{Deps: deps, Root: NewNode("app")}
+	g.Root.Children["z@v1.0.0"] = NewNode("z@v1.0.0")
+	buildTree(g.Root.Children["z@v1.0.0"], deps, map[string]bool{"app": true})
+	g.Root.Children["a@v1.0.0"] = NewNode("a@v1.0.0")
+	g.Root.Children["a@v1.0.0"].Children["c@v1.0.0"] = NewNode("c@v1.0.0")
+
+	tests := []struct {
+		opts RenderOptions
+		want string
+	}{
+		{RenderOptions{}, `app
+├── a@v1.0.0
+│   └── c@v1.0.0
+│       └── d@v1.0.0
+│           └── c@v1.0.0 (see above)
+└── z@v1.0.0
+    └── c@v1.0.0 (see above)
+`},
+		{RenderOptions{ExpandAll: true}, `app
+├── a@v1.0.0
+│   └── c@v1.0.0
+│       └── d@v1.0.0
+│           └── c@v1.0.0 (cycle)
+└── z@v1.0.0
+    └── c@v1.0.0
+        └── d@v1.0.0
+            └── c@v1.0.0 (cycle)
+`},
+	}
+	for _, tt := range tests {
+		out, err := treeRenderer{}.Render(g, tt.opts)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("Render(%+v) =\n%s\nwant\n%s", tt.opts, out, tt.want)
+		}
+
+		var streamed bytes.Buffer
+		if err := StreamTree(&streamed, g, tt.opts); err != nil {
+			t.Fatalf("StreamTree failed: %v", err)
+		}
+		if streamed.String() != tt.want {
+			t.Errorf("StreamTree(%+v) =\n%s\nwant\n%s", tt.opts, streamed.String(), tt.want)
+		}
+	}
+
+	// The markdown list marks the same occurrences
+	for _, tt := range []struct {
+		opts RenderOptions
+		want string
+	}{
+		{RenderOptions{}, "- app\n  - a@v1.0.0\n    - c@v1.0.0\n      - d@v1.0.0\n        - c@v1.0.0 (see above)\n  - z@v1.0.0\n    - c@v1.0.0 (see above)\n"},
+		{RenderOptions{ExpandAll: true, MaxDepth: 3}, "- app\n  - a@v1.0.0\n    - c@v1.0.0\n      - d@v1.0.0 (+1 more)\n  - z@v1.0.0\n    - c@v1.0.0\n      - d@v1.0.0 (+1 more)\n"},
+	} {
+		out, err := markdownRenderer{}.Render(g, tt.opts)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		// Compare the link texts only
+		got := regexp.MustCompile(`\[([^]]*)\]\([^)]*\)`).ReplaceAllString(string(out), "$1")
+		if got != tt.want {
+			t.Errorf("markdown Render(%+v) =\n%s\nwant\n%s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestTreeRendererDepthTruncatedSubtree(t *testing.T) {
+	deps := map[string][]string{
+		"app":      {"a@v1.0.0", "z@v1.0.0"},
+		"a@v1.0.0": {"b@v1.0.0"},
+		"b@v1.0.0": {"x@v1.0.0"},
+		"x@v1.0.0": {"y@v1.0.0"},
+		"z@v1.0.0": {"x@v1.0.0"},
+	}
+	g := &Graph{Deps: deps, Root: NewNode("app")}
+	buildTree(g.Root, deps, make(map[string]bool))
+
+	// x is cut off below b, so its shallower occurrence below z is written
+	// out instead of being marked (see above)
+	want := `app
+├── a@v1.0.0
+│   └── b@v1.0.0
+│       └── x@v1.0.0 (+1 more)
+└── z@v1.0.0
+    └── x@v1.0.0
+        └── y@v1.0.0
+`
+	opts := RenderOptions{MaxDepth: 3}
+	out, err := treeRenderer{}.Render(g, opts)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if string(out) != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", out, want)
+	}
+
+	var streamed bytes.Buffer
+	if err := StreamTree(&streamed, g, opts); err != nil {
+		t.Fatalf("StreamTree failed: %v", err)
+	}
+	if streamed.String() != want {
+		t.Errorf("StreamTree() =\n%s\nwant\n%s", streamed.String(), want)
+	}
+
+	out, err = markdownRenderer{}.Render(g, opts)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	got := regexp.MustCompile(`\[([^]]*)\]\([^)]*\)`).ReplaceAllString(string(out), "$1")
+	if want := "- app\n  - a@v1.0.0\n    - b@v1.0.0\n      - x@v1.0.0 (+1 more)\n  - z@v1.0.0\n    - x@v1.0.0\n      - y@v1.0.0\n"; got != want {
+		t.Errorf("markdown Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule": {"dep1@v1.0.0"},
+		},
+		RootModFile: &ModFile{},
+	}
+
+	out, err := jsonRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var decoded jsonGraph
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if len(decoded.Modules) != 2 {
+		t.Errorf("Expected 2 modules, got %d", len(decoded.Modules))
+	}
+	if len(decoded.Edges) != 1 || decoded.Edges[0].Kind != EdgeDirect {
+		t.Errorf("Expected one direct edge, got %+v", decoded.Edges)
+	}
+}
+
+func TestDOTRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"dep1@v1.0.0"},
+			"dep1@v1.0.0": {"dep2@v1.0.0"},
+		},
+	}
+
+	out, err := dotRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	if !strings.HasPrefix(output, "digraph deptree {") {
+		t.Errorf("Expected digraph header, got:\n%s", output)
+	}
+	if !strings.Contains(output, NodeID("mymodule")+` [label="mymodule"];`) {
+		t.Errorf("Expected labeled node, got:\n%s", output)
+	}
+	if !strings.Contains(output, NodeID("mymodule")+" -> "+NodeID("dep1@v1.0.0")+` [kind="direct"];`) {
+		t.Errorf("Expected direct edge, got:\n%s", output)
+	}
+	if !strings.Contains(output, NodeID("dep1@v1.0.0")+" -> "+NodeID("dep2@v1.0.0")+` [kind="transitive", style=dotted];`) {
+		t.Errorf("Expected transitive edge, got:\n%s", output)
+	}
+}
+
+func TestMermaidRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"dep1@v1.0.0", "dep3@v1.0.0"},
+			"dep1@v1.0.0": {"dep2@v1.0.0"},
+		},
+		RootModFile: &ModFile{
+			Require: []ModRequire{{Path: "dep1", Version: "v1.0.0"}, {Path: "dep3", Version: "v1.0.0", Indirect: true}},
+		},
+		ModFile:      &ModFile{Replace: []ModReplace{{Old: ModVersion{Path: "dep2"}, New: ModVersion{Path: "../dep2"}}}},
+		Descriptions: map[string]string{"dep1@v1.0.0": `Says "hi" <loudly>`},
+	}
+
+	out, err := mermaidRenderer{}.Render(g, RenderOptions{ShowDesc: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	for _, want := range []string{
+		"graph TD\n",
+		"  " + NodeID("mymodule") + `["mymodule"]` + "\n",
+		"  " + NodeID("dep1@v1.0.0") + `["dep1@v1.0.0<br/><small>Says #quot;hi#quot; #lt;loudly#gt;</small>"]` + "\n",
+		"  " + NodeID("mymodule") + " --> " + NodeID("dep1@v1.0.0") + "\n",
+		"  " + NodeID("mymodule") + " -.-> " + NodeID("dep3@v1.0.0") + "\n",
+		"  " + NodeID("dep1@v1.0.0") + ` -->|"=> ../dep2"| ` + NodeID("dep2@v1.0.0") + "\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraphMLRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"dep1@v1.0.0"},
+			"dep1@v1.0.0": {"dep2@v1.0.0"},
+		},
+		Descriptions: map[string]string{"dep1@v1.0.0": "Says <hi>"},
+		Licenses:     map[string]string{"dep1@v1.0.0": "MIT"},
+	}
+
+	out, err := graphMLRenderer{}.Render(g, RenderOptions{ShowDesc: true, ShowLicense: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var doc graphMLDocument
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("Output is not valid XML: %v\n%s", err, out)
+	}
+
+	if len(doc.Keys) != len(nodeAttributeKeys)+len(edgeAttributeKeys) || doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("Expected declared keys of a directed graph, got %+v", doc)
+	}
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 2 {
+		t.Fatalf("Expected 3 nodes and 2 edges, got %+v", doc.Graph)
+	}
+	wantNode := graphMLNode{ID: NodeID("dep1@v1.0.0"), Data: []graphMLData{
+		{"label", "dep1@v1.0.0"}, {"path", "dep1"}, {"version", "v1.0.0"}, {"description", "Says <hi>"}, {"license", "MIT"},
+	}}
+	if !slices.ContainsFunc(doc.Graph.Nodes, func(n graphMLNode) bool { return reflect.DeepEqual(n, wantNode) }) {
+		t.Errorf("Expected node %+v, got %+v", wantNode, doc.Graph.Nodes)
+	}
+	wantEdge := graphMLEdge{ID: "e0", Source: NodeID("dep1@v1.0.0"), Target: NodeID("dep2@v1.0.0"), Data: []graphMLData{{"kind", "transitive"}}}
+	if !slices.ContainsFunc(doc.Graph.Edges, func(e graphMLEdge) bool { return reflect.DeepEqual(e, wantEdge) }) {
+		t.Errorf("Expected edge %+v, got %+v", wantEdge, doc.Graph.Edges)
+	}
+}
+
+func TestGEXFRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule": {"dep1@v1.0.0"},
+		},
+		ModFile: &ModFile{Replace: []ModReplace{{Old: ModVersion{Path: "dep1"}, New: ModVersion{Path: "../dep1"}}}},
+	}
+
+	out, err := gexfRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+	for _, want := range []string{
+		`<gexf xmlns="http://gexf.net/1.3" version="1.3">`,
+		`<graph defaultedgetype="directed">`,
+		`<attribute id="replace" title="replace" type="string"></attribute>`,
+		`<node id="` + NodeID("dep1@v1.0.0") + `" label="dep1@v1.0.0">`,
+		`<attvalue for="version" value="v1.0.0"></attvalue>`,
+		`<edge id="0" source="` + NodeID("mymodule") + `" target="` + NodeID("dep1@v1.0.0") + `" label="direct">`,
+		`<attvalue for="replace" value="../dep1"></attvalue>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, `for="label"`) || strings.Contains(output, `for="kind"`) {
+		t.Errorf("Expected labels as attributes only, got:\n%s", output)
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	root := NewNode("mymodule")
+	dep1 := NewNode("example.com/dep1@v1.0.0")
+	dep1.Children["example.com/dep2@v1.2.0"] = NewNode("example.com/dep2@v1.2.0")
+	root.Children["example.com/dep1@v1.0.0"] = dep1
+	g := &Graph{
+		Root: root,
+		Deps: map[string][]string{
+			"mymodule":                {"example.com/dep1@v1.0.0"},
+			"example.com/dep1@v1.0.0": {"example.com/dep2@v1.2.0"},
+		},
+		RootModFile:  &ModFile{Require: []ModRequire{{Path: "example.com/dep1", Version: "v1.0.0"}}},
+		Descriptions: map[string]string{"example.com/dep1@v1.0.0": "First | line\nsecond"},
+		Licenses:     map[string]string{"example.com/dep1@v1.0.0": "MIT"},
+	}
+
+	out, err := markdownRenderer{}.Render(g, RenderOptions{ShowDesc: true, ShowLicense: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	want := "- mymodule\n" +
+		"  - [example.com/dep1@v1.0.0](https://pkg.go.dev/example.com/dep1@v1.0.0) " + directTag + " [MIT] - First | line second\n" +
+		"    - [example.com/dep2@v1.2.0](https://pkg.go.dev/example.com/dep2@v1.2.0)\n"
+	if string(out) != want {
+		t.Errorf("Expected nested list:\n%s\ngot:\n%s", want, out)
+	}
+
+	out, err = markdownRenderer{}.Render(g, RenderOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(string(out), "(+1 more)") || strings.Contains(string(out), "dep2") {
+		t.Errorf("Expected the list cut at depth 1, got:\n%s", out)
+	}
+
+	out, err = markdownRenderer{}.Render(g, RenderOptions{ShowDesc: true, Export: true, Lang: "de"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		"| Modul | Version | Lizenz | Beschreibung |\n",
+		"| [example.com/dep1](https://pkg.go.dev/example.com/dep1@v1.0.0) | v1.0.0 |  | First \\| line second |\n",
+		"| [example.com/dep2](https://pkg.go.dev/example.com/dep2@v1.2.0) | v1.2.0 |  |  |\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %q in table:\n%s", want, out)
+		}
+	}
+	if strings.Contains(string(out), "mymodule") {
+		t.Errorf("Expected the main module left out of the table, got:\n%s", out)
+	}
+}
+
+func TestCSVRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":                {"example.com/dep1@v1.0.0", "go@1.21"},
+			"example.com/dep1@v1.0.0": {"example.com/dep2@v1.2.0"},
+		},
+		RootModFile:       &ModFile{Require: []ModRequire{{Path: "example.com/dep1", Version: "v1.0.0"}}},
+		Descriptions:      map[string]string{"example.com/dep1@v1.0.0": "Parses \"quoted\", text", "example.com/dep2@v1.2.0": "(not found)"},
+		DescriptionErrors: map[string]error{"example.com/dep2@v1.2.0": errNoDescription},
+		Licenses:          map[string]string{"example.com/dep1@v1.0.0": "MIT"},
+		Links:             map[string]SourceLinks{"example.com/dep1@v1.0.0": {Homepage: "https://dep1.example.com", CloneURL: "https://github.com/example/dep1"}},
+	}
+
+	out, err := csvRenderer{comma: ','}.Render(g, RenderOptions{ShowDesc: true, ShowLicense: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	want := "module,version,relation,depth,license,description,homepage,clone_url\n" +
+		`example.com/dep1,v1.0.0,direct,1,MIT,"Parses ""quoted"", text",https://dep1.example.com,https://github.com/example/dep1` + "\n" +
+		"example.com/dep2,v1.2.0,transitive,2,,,,\n"
+	if string(out) != want {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", want, out)
+	}
+
+	out, err = csvRenderer{comma: '\t'}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(string(out), "example.com/dep1\tv1.0.0\tdirect\t1\t\t\t\t\n") {
+		t.Errorf("Expected tab separated values without metadata, got:\n%s", out)
+	}
+}
+
+func TestRenderersDeterministic(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	// The same graph, with requirements and tree children inserted in the
+	// opposite order, must render to the same bytes in every format
+	graph := func(reversed bool) *Graph {
+		deps := []string{"example.com/a@v1.0.0", "example.com/b@v1.1.0", "example.com/c@v0.3.0"}
+		if reversed {
+			slices.Reverse(deps)
+		}
+		root := NewNode("mymodule")
+		for _, dep := range deps {
+			root.Children[dep] = NewNode(dep)
+		}
+		return &Graph{
+			Root: root,
+			Deps: map[string][]string{
+				"mymodule":             deps,
+				"example.com/a@v1.0.0": {"example.com/c@v0.3.0"},
+			},
+			RootModFile:  &ModFile{},
+			Descriptions: map[string]string{"example.com/a@v1.0.0": "A", "example.com/b@v1.1.0": "B"},
+			Licenses:     map[string]string{"example.com/a@v1.0.0": "MIT", "example.com/c@v0.3.0": "Apache-2.0"},
+			Pruned:       []string{"example.com/c@v0.2.0"},
+		}
+	}
+
+	opts := RenderOptions{ShowDesc: true, ShowLicense: true}
+	for _, format := range RendererNames() {
+		first, err := Render(format, graph(false), opts)
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+		second, err := Render(format, graph(true), opts)
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+		if !bytes.Equal(first, second) {
+			t.Errorf("%s output depends on input order:\n%s\nvs\n%s", format, first, second)
+		}
+	}
+}
+
+func TestTreeRendererTrimPrefix(t *testing.T) {
+	root := NewNode("github.com/me/app")
+	root.Children["github.com/spf13/cobra@v1.8.0"] = NewNode("github.com/spf13/cobra@v1.8.0")
+	root.Children["gopkg.in/yaml.v3@v3.0.1"] = NewNode("gopkg.in/yaml.v3@v3.0.1")
+
+	out, err := treeRenderer{}.Render(&Graph{Root: root}, RenderOptions{TrimPrefix: "github.com/"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	if !strings.HasPrefix(output, "me/app\n") {
+		t.Errorf("Expected trimmed root, got:\n%s", output)
+	}
+	if !strings.Contains(output, "── spf13/cobra@v1.8.0") || !strings.Contains(output, "── gopkg.in/yaml.v3@v3.0.1") {
+		t.Errorf("Expected only matching paths to be trimmed, got:\n%s", output)
+	}
+}
+
+func TestExportRendererTrimPrefixAuto(t *testing.T) {
+	deps := map[string][]string{
+		"github.com/me/app": {"github.com/me/lib@v1.0.0", "github.com/other/x@v1.0.0"},
+	}
+
+	out, err := exportRenderer{}.Render(&Graph{Deps: deps}, RenderOptions{TrimPrefix: TrimPrefixAuto})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := "me/app\nme/lib@v1.0.0\nother/x@v1.0.0\n"
+	if string(out) != expected {
+		t.Errorf("Render() = %q, want %q", out, expected)
+	}
+}
+
+func TestCommonPathPrefix(t *testing.T) {
+	tests := []struct {
+		names    []string
+		expected string
+	}{
+		{[]string{"github.com/a/b", "github.com/a/c"}, "github.com/a/"},
+		{[]string{"github.com/ab", "github.com/ac"}, "github.com/"},
+		{[]string{"github.com/a", "golang.org/x/text"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := commonPathPrefix(tt.names); got != tt.expected {
+			t.Errorf("commonPathPrefix(%v) = %q, want %q", tt.names, got, tt.expected)
+		}
+	}
+}
+
+func TestOSVLockfileRenderer(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"mymodule":                 {"golang.org/x/text@v0.3.5", "go@1.21.0"},
+			"golang.org/x/text@v0.3.5": {},
+		},
+	}
+
+	out, err := Render("osv-lockfile", g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var lockfile osvLockfile
+	if err := json.Unmarshal(out, &lockfile); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(lockfile.Results) != 1 {
+		t.Fatalf("Expected one result, got %d", len(lockfile.Results))
+	}
+
+	packages := lockfile.Results[0].Packages
+	want := osvLockfilePackageInfo{Name: "golang.org/x/text", Version: "0.3.5", Ecosystem: "Go"}
+	if len(packages) != 1 || packages[0].Package != want {
+		t.Errorf("Expected only %+v, got %+v", want, packages)
+	}
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	root := NewNode("mymodule")
+	child := NewNode("github.com/spf13/cobra@v1.8.0")
+	child.Children["github.com/spf13/pflag@v1.0.5"] = NewNode("github.com/spf13/pflag@v1.0.5")
+	root.Children[child.Name] = child
+
+	g := &Graph{
+		Root: root,
+		Deps: map[string][]string{
+			"mymodule":                      {"github.com/spf13/cobra@v1.8.0"},
+			"github.com/spf13/cobra@v1.8.0": {"github.com/spf13/pflag@v1.0.5"},
+		},
+		Descriptions: map[string]string{"github.com/spf13/cobra@v1.8.0": "A Commander <for> Go"},
+		Licenses:     map[string]string{"github.com/spf13/cobra@v1.8.0": "Apache-2.0"},
+	}
+
+	out, err := Render("html", g, RenderOptions{ShowDesc: true, ShowLicense: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	output := string(out)
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<li data-name="github.com/spf13/cobra@v1.8.0"><details open><summary>`,
+		`<span class="version">v1.8.0</span>`,
+		`<span class="license">Apache-2.0</span>`,
+		`<span class="desc">A Commander &lt;for&gt; Go</span>`,
+		`<div class="leaf"><span class="path">github.com/spf13/pflag</span>`,
+		`<input id="search"`,
+		// The query is lowercased, so mixed-case paths must be too
+		"li.dataset.name.toLowerCase().indexOf(query)",
+		"<tr><td>Apache-2.0</td><td>1</td></tr>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+}
+
+func TestTreeRendererBFS(t *testing.T) {
+	out, err := Render("tree", walkTestGraph(), RenderOptions{Walk: WalkBFS})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `depth 0: root
+depth 1: a@v1.0.0 (required by root)
+depth 1: b@v1.0.0 (required by root)
+depth 2: c@v1.0.0 (required by a@v1.0.0)
+`
+	if string(out) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+}
+
+func TestNDJSONRenderer(t *testing.T) {
+	out, err := Render("ndjson", walkTestGraph(), RenderOptions{Walk: WalkBFS})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d:\n%s", len(lines), out)
+	}
+
+	var last ndjsonNode
+	if err := json.Unmarshal([]byte(lines[3]), &last); err != nil {
+		t.Fatalf("Line is not valid JSON: %v", err)
+	}
+	want := ndjsonNode{Name: "c@v1.0.0", Path: "c", Version: "v1.0.0", Depth: 2, Parent: "a@v1.0.0"}
+	if last != want {
+		t.Errorf("Last line = %+v, want %+v", last, want)
+	}
+}
+
+func TestMarkdownTableRenderer(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":                      {"github.com/spf13/cobra@v1.8.0", "golang.org/x/text@v0.3.5"},
+			"github.com/spf13/cobra@v1.8.0": {},
+		},
+		Descriptions: map[string]string{"github.com/spf13/cobra@v1.8.0": "A Commander | CLI\nfor Go"},
+		Licenses:     map[string]string{"github.com/spf13/cobra@v1.8.0": "Apache-2.0", "golang.org/x/text@v0.3.5": "BSD-3-Clause"},
+	}
+
+	out, err := Render("md-table", g, RenderOptions{ShowDesc: true, ShowLicense: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `| Module | Version | License | Description |
+| --- | --- | --- | --- |
+| github.com/spf13/cobra | v1.8.0 | Apache-2.0 | A Commander \| CLI for Go |
+| golang.org/x/text | v0.3.5 | BSD-3-Clause |  |
+`
+	if string(out) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+}
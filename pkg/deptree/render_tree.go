@@ -0,0 +1,199 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	RegisterRenderer("tree", treeRenderer{})
+}
+
+// treeRenderer draws the dependency tree with box-drawing connectors.
+type treeRenderer struct{}
+
+// treeWriter holds the state of one tree rendering.
+type treeWriter struct {
+	w        *bufio.Writer
+	opts     RenderOptions
+	name     func(string) string
+	licenses map[string]string
+	direct   map[string]bool
+	// directives holds the replace and exclude annotations.
+	directives map[string]string
+	painter    painter
+	// expanded maps modules to the node holding their requirements; the
+	// tree holds them at one occurrence of each module only.
+	expanded map[string]*Node
+	// written holds the modules whose requirements have been written, and
+	// path those from the root down to the node being written.
+	written map[string]bool
+	path    map[string]bool
+	sorter  *childSorter
+}
+
+func (r treeRenderer) Render(g *Graph, opts RenderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.RenderTo(&buf, g, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderTo implements WriterRenderer, writing the tree to out while
+// walking it.
+func (treeRenderer) RenderTo(out io.Writer, g *Graph, opts RenderOptions) error {
+	w := &treeWriter{w: bufio.NewWriter(out), opts: opts, name: nameTrimmer(g, opts), licenses: g.Licenses, direct: g.DirectDependencies(), directives: g.directiveLabels()}
+	w.painter = newPainter(g, opts, w.direct)
+	w.sorter = newChildSorter(g, opts.Sort)
+	w.expanded = expandedNodes(g.Root)
+	w.written = map[string]bool{g.Root.Name: true}
+	w.path = map[string]bool{g.Root.Name: true}
+	if opts.Walk == WalkBFS {
+		if err := w.writeLevels(g); err != nil {
+			return err
+		}
+	} else {
+		node := g.Root
+		if opts.ShowDesc && node.Description != "" {
+			fmt.Fprintf(w.w, "%s - %s\n", w.label(node), node.Description)
+		} else {
+			fmt.Fprintln(w.w, w.label(node))
+		}
+		w.writeNode(node, "", 1)
+	}
+
+	var summary bytes.Buffer
+	if opts.ShowLicense {
+		writeLicenseSummary(&summary, g)
+	}
+	writeOutdatedSummary(&summary, g)
+	writeSizeSummary(&summary, g)
+	writeRepoStatusSummary(&summary, g)
+	w.w.Write(summary.Bytes())
+
+	return w.w.Flush()
+}
+
+// writeNode writes the children of node. A module's requirements are
+// written at its first occurrence, whichever node of the tree holds them,
+// and later occurrences are marked "(see above)", unless
+// RenderOptions.ExpandAll writes them at every occurrence; then a module
+// requiring one of the modules it is reached through is marked "(cycle)".
+func (w *treeWriter) writeNode(node *Node, prefix string, depth int) {
+	children := w.sorter.nodes(node)
+
+	for i, child := range children {
+		isLast := i == len(children)-1
+
+		var connector, childPrefix string
+		if isLast {
+			connector = "└── "
+			childPrefix = prefix + "    "
+		} else {
+			connector = "├── "
+			childPrefix = prefix + "│   "
+		}
+
+		full := child
+		if n, ok := w.expanded[child.Name]; ok {
+			full = n
+		}
+		label := w.label(child)
+		expand := len(full.Children) > 0
+		switch {
+		case !expand:
+		case w.written[child.Name] && !w.opts.ExpandAll:
+			label += " " + seeAboveTag
+			expand = false
+		case w.path[child.Name]:
+			label += " " + cycleTag
+			expand = false
+		case w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth:
+			label = fmt.Sprintf("%s (+%d more)", label, countDescendants(full))
+			expand = false
+		}
+
+		if w.opts.ShowDesc && child.Description != "" {
+			fmt.Fprintf(w.w, "%s%s%s - %s\n", prefix, connector, label, child.Description)
+		} else {
+			fmt.Fprintf(w.w, "%s%s%s\n", prefix, connector, label)
+		}
+
+		if expand {
+			w.written[child.Name] = true
+			w.path[child.Name] = true
+			w.writeNode(full, childPrefix, depth+1)
+			delete(w.path, child.Name)
+		}
+	}
+}
+
+// Markers of the tree format for occurrences of a module whose
+// requirements are not written there.
+const (
+	seeAboveTag = "(see above)"
+	cycleTag    = "(cycle)"
+)
+
+// expandedNodes maps the modules in the tree below root to the node that
+// holds their requirements, the first one with children in tree order.
+func expandedNodes(root *Node) map[string]*Node {
+	expanded := make(map[string]*Node)
+	var walk func(*Node)
+	walk = func(node *Node) {
+		for _, child := range sortedChildren(node) {
+			if _, ok := expanded[child.Name]; ok || len(child.Children) == 0 {
+				continue
+			}
+			expanded[child.Name] = child
+			walk(child)
+		}
+	}
+	walk(root)
+	return expanded
+}
+
+// writeLevels lists the tree breadth-first, one module per line prefixed
+// with its depth and followed by the module that requires it, so all
+// modules at one depth can be found with grep "^depth 2:".
+func (w *treeWriter) writeLevels(g *Graph) error {
+	return g.WalkTree(WalkBFS, w.opts.MaxDepth, func(v TreeVisit) {
+		line := fmt.Sprintf("depth %d: %s", v.Depth, w.label(v.Node))
+		if v.Parent != nil {
+			line += fmt.Sprintf(" (required by %s)", w.name(v.Parent.Name))
+		}
+		if w.opts.ShowDesc && v.Node.Description != "" {
+			line += " - " + v.Node.Description
+		}
+		fmt.Fprintln(w.w, line)
+	})
+}
+
+// label returns the displayed name of node followed by its annotations.
+func (w *treeWriter) label(node *Node) string {
+	parts := []string{w.name(node.Name)}
+	if w.direct[node.Name] {
+		parts = append(parts, directTag)
+	}
+	if label, ok := w.directives[node.Name]; ok {
+		parts = append(parts, label)
+	}
+	parts = append(parts, node.Annotations...)
+	if license, ok := w.licenses[node.Name]; ok && w.opts.ShowLicense {
+		parts = append(parts, "["+license+"]")
+	}
+	return w.painter.paint(node.Name, strings.Join(parts, " "))
+}
+
+// countDescendants returns the number of nodes below node in the tree.
+func countDescendants(node *Node) int {
+	count := 0
+	for _, child := range node.Children {
+		count += 1 + countDescendants(child)
+	}
+	return count
+}
@@ -0,0 +1,212 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ReplaceCollision is a set of replace directives the go command rejects
+// with confusing errors: several module paths replaced by the same target,
+// or one module replaced by different targets in different go.mod files.
+type ReplaceCollision struct {
+	// Target is set when several module paths are replaced by it.
+	Target string
+	// Module is set when it is replaced by different targets.
+	Module string
+	// Replacements are the colliding directives as
+	// "file-module: old => new".
+	Replacements []string
+}
+
+func (c ReplaceCollision) String() string {
+	if c.Target != "" {
+		return fmt.Sprintf("%s replaces %d different modules (%s)", c.Target, len(c.Replacements), strings.Join(c.Replacements, "; "))
+	}
+	return fmt.Sprintf("%s is replaced by different targets (%s)", c.Module, strings.Join(c.Replacements, "; "))
+}
+
+// warnReplaceCollisions records the replace collisions of g.ModFile in
+// g.Warnings.
+func (g *Graph) warnReplaceCollisions() {
+	for _, c := range FindReplaceCollisions(g.ModFile) {
+		g.warn(WarnReplaceCollision, c.Module, "replace collision: %s", c)
+	}
+}
+
+// FindReplaceCollisions checks the replace directives of the given go.mod
+// files, such as those of the modules in a workspace, for collisions. The
+// result is sorted by target, then module.
+func FindReplaceCollisions(files ...*ModFile) []ReplaceCollision {
+	// Directives by target and by replaced module
+	byTarget := make(map[string][]string)
+	targetPaths := make(map[string]map[string]bool)
+	byModule := make(map[string][]string)
+	moduleTargets := make(map[string]map[string]bool)
+
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		for _, r := range file.Replace {
+			directive := fmt.Sprintf("%s: %s => %s", file.Module.Path, r.Old, r.New)
+			target, old := r.New.String(), r.Old.String()
+
+			byTarget[target] = append(byTarget[target], directive)
+			if targetPaths[target] == nil {
+				targetPaths[target] = make(map[string]bool)
+			}
+			targetPaths[target][r.Old.Path] = true
+
+			byModule[old] = append(byModule[old], directive)
+			if moduleTargets[old] == nil {
+				moduleTargets[old] = make(map[string]bool)
+			}
+			moduleTargets[old][target] = true
+		}
+	}
+
+	var collisions []ReplaceCollision
+	for _, target := range slices.Sorted(maps.Keys(byTarget)) {
+		if len(targetPaths[target]) > 1 {
+			collisions = append(collisions, ReplaceCollision{Target: target, Replacements: byTarget[target]})
+		}
+	}
+	for _, old := range slices.Sorted(maps.Keys(byModule)) {
+		if len(moduleTargets[old]) > 1 {
+			collisions = append(collisions, ReplaceCollision{Module: old, Replacements: byModule[old]})
+		}
+	}
+
+	return collisions
+}
+
+// excludedTag marks modules an exclude directive of the main module names.
+const excludedTag = "(excluded)"
+
+// replacedLabel marks a module with the replacement its code comes from.
+func replacedLabel(rep ModVersion) string {
+	return "(replaced by " + rep.String() + ")"
+}
+
+// Replacement is a replace directive of the main module with the modules of
+// the graph it applies to.
+type Replacement struct {
+	ModReplace
+	// Modules are the module versions in the graph the directive replaces;
+	// empty if it replaces none.
+	Modules []string
+}
+
+// Local reports whether the replacement is a directory rather than a module
+// version.
+func (r Replacement) Local() bool {
+	return r.New.Version == ""
+}
+
+// Replacements returns the replace directives of the main module in go.mod
+// order, each with the modules of g it applies to.
+func (g *Graph) Replacements() []Replacement {
+	if g.ModFile == nil {
+		return nil
+	}
+	replacements := make([]Replacement, len(g.ModFile.Replace))
+	for i, rep := range g.ModFile.Replace {
+		replacements[i].ModReplace = rep
+	}
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		// Like FindReplace, the first matching directive applies
+		i := slices.IndexFunc(g.ModFile.Replace, func(rep ModReplace) bool {
+			return rep.Old.Path == path && (rep.Old.Version == "" || rep.Old.Version == version)
+		})
+		if i >= 0 {
+			replacements[i].Modules = append(replacements[i].Modules, name)
+		}
+	}
+	return replacements
+}
+
+// Excluded returns the modules of g an exclude directive of the main module
+// names, sorted by name.
+func (g *Graph) Excluded() []string {
+	if g.ModFile == nil || len(g.ModFile.Exclude) == 0 {
+		return nil
+	}
+	var excluded []string
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if slices.Contains(g.ModFile.Exclude, ModVersion{Path: path, Version: version}) {
+			excluded = append(excluded, name)
+		}
+	}
+	return excluded
+}
+
+// directiveLabels maps the modules of g affected by a replace or exclude
+// directive of the main module to their annotation.
+func (g *Graph) directiveLabels() map[string]string {
+	labels := make(map[string]string)
+	for _, r := range g.Replacements() {
+		for _, name := range r.Modules {
+			labels[name] = replacedLabel(r.New)
+		}
+	}
+	for _, name := range g.Excluded() {
+		labels[name] = excludedTag
+	}
+	return labels
+}
+
+// RenderReplaces summarizes the replace and exclude directives of the main
+// module: each replacement with the modules of the graph it applies to,
+// followed by the directives that apply to none, which are often left over.
+func RenderReplaces(g *Graph) []byte {
+	var buf bytes.Buffer
+	replacements := g.Replacements()
+	excluded := g.Excluded()
+	if len(replacements) == 0 && len(excluded) == 0 {
+		fmt.Fprintln(&buf, "No replace or exclude directives")
+		return buf.Bytes()
+	}
+
+	var active, inactive []Replacement
+	for _, r := range replacements {
+		if len(r.Modules) > 0 {
+			active = append(active, r)
+		} else {
+			inactive = append(inactive, r)
+		}
+	}
+
+	for _, r := range active {
+		line := fmt.Sprintf("%s => %s", r.Old, r.New)
+		if r.Local() {
+			line += " (local directory)"
+		}
+		fmt.Fprintln(&buf, line)
+		for _, name := range r.Modules {
+			fmt.Fprintf(&buf, "  %s\n", name)
+		}
+	}
+	if len(excluded) > 0 {
+		fmt.Fprintln(&buf, "Excluded:")
+		for _, name := range excluded {
+			fmt.Fprintf(&buf, "  %s\n", name)
+		}
+	}
+	if len(inactive) > 0 {
+		fmt.Fprintln(&buf, "Not applied to any module in the graph:")
+		for _, r := range inactive {
+			fmt.Fprintf(&buf, "  %s => %s\n", r.Old, r.New)
+		}
+	}
+
+	fmt.Fprintf(&buf, "\n%d active and %d unused replacement(s), %d excluded module(s) in the graph\n", len(active), len(inactive), len(excluded))
+	return buf.Bytes()
+}
@@ -0,0 +1,96 @@
+package deptree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindReplaceCollisions(t *testing.T) {
+	app := &ModFile{Replace: []ModReplace{
+		{Old: ModVersion{Path: "example.com/a"}, New: ModVersion{Path: "../fork"}},
+		{Old: ModVersion{Path: "example.com/b"}, New: ModVersion{Path: "../fork"}},
+		{Old: ModVersion{Path: "example.com/c", Version: "v1.0.0"}, New: ModVersion{Path: "example.com/c", Version: "v1.0.1"}},
+	}}
+	app.Module.Path = "example.com/app"
+
+	tool := &ModFile{Replace: []ModReplace{
+		{Old: ModVersion{Path: "example.com/c", Version: "v1.0.0"}, New: ModVersion{Path: "example.com/c", Version: "v1.0.2"}},
+	}}
+	tool.Module.Path = "example.com/tool"
+
+	collisions := FindReplaceCollisions(app, tool, nil)
+	if len(collisions) != 2 {
+		t.Fatalf("Expected 2 collisions, got %v", collisions)
+	}
+
+	if collisions[0].Target != "../fork" || len(collisions[0].Replacements) != 2 {
+		t.Errorf("Expected ../fork to collide, got %+v", collisions[0])
+	}
+	if collisions[1].Module != "example.com/c@v1.0.0" {
+		t.Errorf("Expected example.com/c@v1.0.0 to collide, got %+v", collisions[1])
+	}
+	if want := "example.com/tool: example.com/c@v1.0.0 => example.com/c@v1.0.2"; !strings.Contains(collisions[1].String(), want) {
+		t.Errorf("Expected %q in %q", want, collisions[1])
+	}
+
+	if got := FindReplaceCollisions(tool); len(got) != 0 {
+		t.Errorf("Expected no collisions in a single clean file, got %v", got)
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {"example.com/fork@v1.0.0", "example.com/pinned@v1.2.0", "example.com/bad@v1.1.0"},
+		},
+		ModFile: &ModFile{
+			Replace: []ModReplace{
+				{Old: ModVersion{Path: "example.com/fork"}, New: ModVersion{Path: "../local/fork"}},
+				{Old: ModVersion{Path: "example.com/pinned", Version: "v1.2.0"}, New: ModVersion{Path: "example.com/pinned", Version: "v1.2.1"}},
+				{Old: ModVersion{Path: "example.com/fork"}, New: ModVersion{Path: "../shadowed"}},
+				{Old: ModVersion{Path: "example.com/gone"}, New: ModVersion{Path: "../gone"}},
+			},
+			Exclude: []ModVersion{{Path: "example.com/bad", Version: "v1.1.0"}, {Path: "example.com/bad", Version: "v1.0.0"}},
+		},
+	}
+	g.expandRoot("example.com/app")
+
+	replacements := g.Replacements()
+	if len(replacements) != 4 || len(replacements[0].Modules) != 1 || !replacements[0].Local() || len(replacements[2].Modules) != 0 {
+		t.Errorf("Replacements = %+v", replacements)
+	}
+	if excluded := g.Excluded(); len(excluded) != 1 || excluded[0] != "example.com/bad@v1.1.0" {
+		t.Errorf("Excluded = %v", excluded)
+	}
+
+	out, err := Render("tree", g, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, label := range []string{
+		"example.com/fork@v1.0.0 (replaced by ../local/fork)\n",
+		"example.com/pinned@v1.2.0 (replaced by example.com/pinned@v1.2.1)\n",
+		"example.com/bad@v1.1.0 (excluded)\n",
+	} {
+		if !strings.Contains(string(out), label) {
+			t.Errorf("tree output missing %q:\n%s", label, out)
+		}
+	}
+
+	report := string(RenderReplaces(g))
+	expected := `example.com/fork => ../local/fork (local directory)
+  example.com/fork@v1.0.0
+example.com/pinned@v1.2.0 => example.com/pinned@v1.2.1
+  example.com/pinned@v1.2.0
+Excluded:
+  example.com/bad@v1.1.0
+Not applied to any module in the graph:
+  example.com/fork => ../shadowed
+  example.com/gone => ../gone
+
+2 active and 2 unused replacement(s), 1 excluded module(s) in the graph
+`
+	if report != expected {
+		t.Errorf("Expected report:\n%s\nGot:\n%s", expected, report)
+	}
+}
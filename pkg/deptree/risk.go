@@ -0,0 +1,234 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Risk signals combined by AssessRisk.
+const (
+	RiskSingleMaintainer = "single maintainer"
+	RiskArchived         = "archived"
+	RiskNoRecentRelease  = "no recent release"
+	RiskPreV1            = "pre-v1"
+	RiskReplaced         = "replaced"
+)
+
+// riskWeights is how much each signal adds to a module's risk score. An
+// archived repository will not get fixes at all, while a pre-v1 version
+// only says its API may still change.
+var riskWeights = map[string]int{
+	RiskArchived:         4,
+	RiskSingleMaintainer: 3,
+	RiskNoRecentRelease:  3,
+	RiskReplaced:         2,
+	RiskPreV1:            1,
+}
+
+// ModuleRisk is the risk score of one module and the signals behind it.
+type ModuleRisk struct {
+	Module  string
+	Score   int
+	Signals []string
+}
+
+// RiskReport is the result of AssessRisk.
+type RiskReport struct {
+	// Risks holds the modules with at least one signal, riskiest first.
+	Risks []ModuleRisk
+	// Modules is the number of modules assessed.
+	Modules int
+	// Unchecked lists the signals that could not be checked at all, such
+	// as RiskNoRecentRelease when GOPROXY is "off".
+	Unchecked []string
+	// Err collects the lookups that failed; their signals are missing
+	// from Risks.
+	Err error
+}
+
+// AssessRisk scores every dependency of g on how likely it is to be left
+// without maintenance: a GitHub repository that is archived or has a
+// single contributor, no release on the module proxy for longer than
+// staleAfter, a pre-v1 version and a replace directive each add to the
+// score. Each repository and module path is looked up once however many
+// versions g contains. When GOPROXY is "off", release dates are not
+// looked up, since the module cache only knows the versions already
+// downloaded, and RiskNoRecentRelease is reported as unchecked.
+func AssessRisk(g *Graph, opts Options, token string, staleAfter time.Duration) RiskReport {
+	return assessRisk(g, opts.client(), NewGitHubClient(opts, token), staleAfter, time.Now())
+}
+
+// repoRisk is what the GitHub API says about one repository.
+type repoRisk struct {
+	archived, singleMaintainer bool
+}
+
+func assessRisk(g *Graph, client *http.Client, github *GitHubClient, staleAfter time.Duration, now time.Time) RiskReport {
+	var modules []string
+	repos := make(map[string][]string)
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		modules = append(modules, name)
+		if owner, repo, ok := github.repoOf(path); ok {
+			repos[owner+"/"+repo] = append(repos[owner+"/"+repo], name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	repoRisks := make(map[string]repoRisk)
+	released := make(map[string]time.Time)
+
+	proxy, proxyErr := ProxyURL()
+
+	sem := make(chan struct{}, githubWorkers)
+	for repo, names := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var risk repoRisk
+			ghRepo, err := github.Repo(names[0])
+			if err == nil {
+				risk.archived = ghRepo.Archived
+				var contributors []GitHubContributor
+				contributors, err = github.Contributors(names[0], 2)
+				risk.singleMaintainer = len(contributors) == 1
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, ErrBudgetExceeded):
+				for _, name := range names {
+					g.markPartial(name)
+				}
+				return
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s: %w", repo, err))
+				return
+			}
+			repoRisks[repo] = risk
+		}()
+	}
+
+	offline := ProxyOff()
+	if proxyErr == nil && !offline {
+		proxySem := make(chan struct{}, proxyWorkers)
+		noProxy := NoProxy()
+		for _, path := range g.modulePaths() {
+			if noProxy(path) {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				proxySem <- struct{}{}
+				defer func() { <-proxySem }()
+
+				info, err := FetchLatestInfo(client, proxy, path)
+				if err != nil || info.Time.IsZero() {
+					// Private modules are unknown to the proxy
+					return
+				}
+				mu.Lock()
+				released[path] = info.Time
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+
+	report := RiskReport{Modules: len(modules)}
+	if offline {
+		report.Unchecked = append(report.Unchecked, RiskNoRecentRelease)
+	}
+	for _, name := range modules {
+		path, version := SplitModule(name)
+		var signals []string
+		if owner, repo, ok := github.repoOf(path); ok {
+			risk := repoRisks[owner+"/"+repo]
+			if risk.archived {
+				signals = append(signals, RiskArchived)
+			}
+			if risk.singleMaintainer {
+				signals = append(signals, RiskSingleMaintainer)
+			}
+		}
+		if t, ok := released[path]; ok && now.Sub(t) > staleAfter {
+			signals = append(signals, RiskNoRecentRelease)
+		}
+		if g.ModFile != nil {
+			if _, ok := g.ModFile.FindReplace(path, version); ok {
+				signals = append(signals, RiskReplaced)
+			}
+		}
+		if strings.HasPrefix(version, "v0.") {
+			signals = append(signals, RiskPreV1)
+		}
+		if len(signals) == 0 {
+			continue
+		}
+
+		score := 0
+		for _, s := range signals {
+			score += riskWeights[s]
+		}
+		report.Risks = append(report.Risks, ModuleRisk{Module: name, Score: score, Signals: signals})
+	}
+	slices.SortStableFunc(report.Risks, func(a, b ModuleRisk) int { return b.Score - a.Score })
+
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+		proxyErr = errors.Join(proxyErr, fmt.Errorf("failed to look up %d GitHub repository(ies): %w", len(errs), errors.Join(errs...)))
+	}
+	report.Err = proxyErr
+	return report
+}
+
+// RenderRisk formats a risk report as a table of modules, riskiest first,
+// followed by the weight of each signal.
+func RenderRisk(report RiskReport) []byte {
+	var buf bytes.Buffer
+	if len(report.Risks) == 0 {
+		fmt.Fprintf(&buf, "No risk signals in %d module(s)\n", report.Modules)
+		writeUncheckedSignals(&buf, report.Unchecked)
+		return buf.Bytes()
+	}
+
+	width := len("MODULE")
+	for _, r := range report.Risks {
+		width = max(width, len(r.Module))
+	}
+	fmt.Fprintf(&buf, "%5s  %-*s  %s\n", "SCORE", width, "MODULE", "SIGNALS")
+	for _, r := range report.Risks {
+		fmt.Fprintf(&buf, "%5d  %-*s  %s\n", r.Score, width, r.Module, strings.Join(r.Signals, ", "))
+	}
+
+	var weights []string
+	for _, s := range []string{RiskArchived, RiskSingleMaintainer, RiskNoRecentRelease, RiskReplaced, RiskPreV1} {
+		weights = append(weights, fmt.Sprintf("%s %d", s, riskWeights[s]))
+	}
+	fmt.Fprintf(&buf, "\n%d of %d module(s) with risk signals (weights: %s)\n", len(report.Risks), report.Modules, strings.Join(weights, ", "))
+	writeUncheckedSignals(&buf, report.Unchecked)
+	return buf.Bytes()
+}
+
+// writeUncheckedSignals notes the signals a risk report could not check.
+func writeUncheckedSignals(buf *bytes.Buffer, unchecked []string) {
+	if len(unchecked) > 0 {
+		fmt.Fprintf(buf, "Not checked with GOPROXY=off: %s\n", strings.Join(unchecked, ", "))
+	}
+}
@@ -0,0 +1,109 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssessRisk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/spf13/cobra":
+			w.Write([]byte(`{"stargazers_count": 38000}`))
+		case "/repos/spf13/cobra/contributors":
+			w.Write([]byte(`[{"login": "spf13"}, {"login": "marckhouzam"}]`))
+		case "/repos/pkg/errors":
+			w.Write([]byte(`{"archived": true}`))
+		case "/repos/pkg/errors/contributors":
+			w.Write([]byte(`[{"login": "davecheney"}]`))
+		case "/github.com/spf13/cobra/@latest":
+			w.Write([]byte(`{"Version": "v1.8.0", "Time": "2026-06-01T00:00:00Z"}`))
+		case "/github.com/pkg/errors/@latest":
+			w.Write([]byte(`{"Version": "v0.9.1", "Time": "2020-01-14T19:47:44Z"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	github, _ := testGitHubClient(t, server, now)
+
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/spf13/cobra@v1.8.0", "github.com/pkg/errors@v0.9.1", "example.com/fork@v1.2.0", "example.com/plain@v1.0.0"},
+		},
+		ModFile: &ModFile{Replace: []ModReplace{{Old: ModVersion{Path: "example.com/fork"}, New: ModVersion{Path: "../fork"}}}},
+	}
+
+	report := assessRisk(g, server.Client(), github, 2*365*24*time.Hour, now)
+	if report.Err != nil {
+		t.Fatalf("assessRisk failed: %v", report.Err)
+	}
+	if report.Modules != 4 {
+		t.Errorf("Assessed %d modules, want 4", report.Modules)
+	}
+	if len(report.Risks) != 2 {
+		t.Fatalf("Expected 2 risky modules, got %+v", report.Risks)
+	}
+
+	top := report.Risks[0]
+	wantSignals := []string{RiskArchived, RiskSingleMaintainer, RiskNoRecentRelease, RiskPreV1}
+	if top.Module != "github.com/pkg/errors@v0.9.1" || top.Score != 11 || !slices.Equal(top.Signals, wantSignals) {
+		t.Errorf("riskiest module = %+v, want github.com/pkg/errors@v0.9.1 scoring 11 with %v", top, wantSignals)
+	}
+	if fork := report.Risks[1]; fork.Module != "example.com/fork@v1.2.0" || fork.Score != 2 {
+		t.Errorf("second module = %+v, want example.com/fork@v1.2.0 scoring 2", fork)
+	}
+
+	out := string(RenderRisk(report))
+	for _, line := range []string{
+		"   11  github.com/pkg/errors@v0.9.1  archived, single maintainer, no recent release, pre-v1\n",
+		"\n2 of 4 module(s) with risk signals",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("output missing %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestAssessRiskProxyOff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/pkg/errors":
+			w.Write([]byte(`{"archived": true}`))
+		case "/repos/pkg/errors/contributors":
+			w.Write([]byte(`[{"login": "davecheney"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	cache := t.TempDir()
+	useModCache(t, cache)
+	t.Setenv("GOPROXY", "off")
+	writeCachedVersions(t, cache, "github.com/pkg/errors", []string{"v0.9.1"})
+
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	github, _ := testGitHubClient(t, server, now)
+	g := &Graph{Deps: map[string][]string{"example.com/app": {"github.com/pkg/errors@v0.9.1"}}}
+
+	report := assessRisk(g, server.Client(), github, 365*24*time.Hour, now)
+	if report.Err != nil {
+		t.Fatalf("assessRisk failed: %v", report.Err)
+	}
+	if len(report.Risks) != 1 || slices.Contains(report.Risks[0].Signals, RiskNoRecentRelease) {
+		t.Errorf("Risks = %+v, want the release date of the cached version unused", report.Risks)
+	}
+	if !slices.Equal(report.Unchecked, []string{RiskNoRecentRelease}) {
+		t.Errorf("Unchecked = %v, want [%s]", report.Unchecked, RiskNoRecentRelease)
+	}
+	if out := string(RenderRisk(report)); !strings.Contains(out, "Not checked with GOPROXY=off: no recent release\n") {
+		t.Errorf("output does not note the unchecked signal:\n%s", out)
+	}
+}
@@ -0,0 +1,119 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Weights of the parts of RiskDelta.Score. A new advisory outweighs
+// everything else a dependency update brings along; a new license or owner
+// widens what has to be trusted and reviewed, and every added module a
+// little.
+const (
+	riskDeltaVulnerability = 10
+	riskDeltaLicense       = 5
+	riskDeltaOwner         = 3
+	riskDeltaModule        = 1
+)
+
+// RiskDelta summarizes how the aggregate risk of a module graph changed
+// between a base and a head graph.
+type RiskDelta struct {
+	// NewVulnerabilities maps the advisories affecting the head graph but
+	// not the base graph to the head modules they affect.
+	NewVulnerabilities map[string][]string
+	// FixedVulnerabilities are the advisories affecting only the base
+	// graph, sorted.
+	FixedVulnerabilities []string
+	// NewLicenses are the licenses used in the head graph but not in the
+	// base graph, sorted.
+	NewLicenses []string
+	// NewOwners are the owners, as grouped by Owners, that the head graph
+	// depends on and the base graph did not, sorted.
+	NewOwners []string
+	// AddedModules is the number of modules the head graph has more than
+	// the base graph, negative if it has fewer.
+	AddedModules int
+	// Score weighs the above into a single number: positive when the
+	// change adds risk, negative when it takes risk away.
+	Score int
+}
+
+// DiffRisk compares the aggregate risk of the before and after graphs. It
+// uses the metadata already stored on them, so the vulnerabilities and
+// licenses of both must have been detected to be taken into account.
+func DiffRisk(before, after *Graph) RiskDelta {
+	var d RiskDelta
+
+	oldVulns := advisoryModules(before)
+	newVulns := advisoryModules(after)
+	d.NewVulnerabilities = make(map[string][]string)
+	for id, modules := range newVulns {
+		if _, ok := oldVulns[id]; !ok {
+			d.NewVulnerabilities[id] = modules
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(oldVulns)) {
+		if _, ok := newVulns[id]; !ok {
+			d.FixedVulnerabilities = append(d.FixedVulnerabilities, id)
+		}
+	}
+
+	oldLicenses := before.LicenseCounts()
+	for _, license := range slices.Sorted(maps.Keys(after.LicenseCounts())) {
+		if _, ok := oldLicenses[license]; !ok && license != UnknownLicense {
+			d.NewLicenses = append(d.NewLicenses, license)
+		}
+	}
+
+	oldOwners := before.Owners()
+	for _, owner := range slices.Sorted(maps.Keys(after.Owners())) {
+		if _, ok := oldOwners[owner]; !ok {
+			d.NewOwners = append(d.NewOwners, owner)
+		}
+	}
+
+	d.AddedModules = len(after.Modules()) - len(before.Modules())
+	d.Score = riskDeltaVulnerability*(len(d.NewVulnerabilities)-len(d.FixedVulnerabilities)) +
+		riskDeltaLicense*len(d.NewLicenses) +
+		riskDeltaOwner*len(d.NewOwners) +
+		riskDeltaModule*d.AddedModules
+	return d
+}
+
+// advisoryModules maps every advisory in g.Vulnerabilities to the modules
+// it affects, sorted.
+func advisoryModules(g *Graph) map[string][]string {
+	advisories := make(map[string][]string)
+	for _, name := range slices.Sorted(maps.Keys(g.Vulnerabilities)) {
+		for _, id := range g.Vulnerabilities[name] {
+			advisories[id] = append(advisories[id], name)
+		}
+	}
+	return advisories
+}
+
+// RenderRiskDelta formats d, ending with its score.
+func RenderRiskDelta(d RiskDelta) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "\nRisk:")
+	for _, id := range slices.Sorted(maps.Keys(d.NewVulnerabilities)) {
+		fmt.Fprintf(&buf, "  + vulnerability %s in %s\n", id, strings.Join(d.NewVulnerabilities[id], ", "))
+	}
+	for _, id := range d.FixedVulnerabilities {
+		fmt.Fprintf(&buf, "  - vulnerability %s\n", id)
+	}
+	for _, license := range d.NewLicenses {
+		fmt.Fprintf(&buf, "  + license %s\n", license)
+	}
+	for _, owner := range d.NewOwners {
+		fmt.Fprintf(&buf, "  + owner %s\n", owner)
+	}
+	fmt.Fprintf(&buf, "  %+d module(s)\n", d.AddedModules)
+	fmt.Fprintf(&buf, "\nRisk delta: %+d (%d new and %d fixed vulnerabilities, %d new license(s), %d new owner(s))\n",
+		d.Score, len(d.NewVulnerabilities), len(d.FixedVulnerabilities), len(d.NewLicenses), len(d.NewOwners))
+	return buf.Bytes()
+}
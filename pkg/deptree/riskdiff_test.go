@@ -0,0 +1,66 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffRisk(t *testing.T) {
+	before := &Graph{
+		Root: NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app": {"github.com/a/x@v1.0.0", "github.com/c/z@v1.0.0"},
+		},
+		Vulnerabilities: map[string][]string{"github.com/a/x@v1.0.0": {"GO-2023-0001"}},
+		Licenses:        map[string]string{"github.com/a/x@v1.0.0": "MIT", "github.com/c/z@v1.0.0": "MIT"},
+	}
+	after := &Graph{
+		Root: NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app":       {"github.com/a/x@v1.1.0", "github.com/c/z@v1.0.0"},
+			"github.com/a/x@v1.1.0": {"github.com/b/y@v0.1.0", "golang.org/x/text@v0.14.0"},
+		},
+		Vulnerabilities: map[string][]string{
+			"github.com/b/y@v0.1.0": {"GO-2024-0002"},
+			"github.com/c/z@v1.0.0": {"GO-2024-0002"},
+		},
+		Licenses: map[string]string{
+			"github.com/a/x@v1.1.0":     "MIT",
+			"github.com/b/y@v0.1.0":     "GPL-3.0",
+			"github.com/c/z@v1.0.0":     "MIT",
+			"golang.org/x/text@v0.14.0": UnknownLicense,
+		},
+	}
+
+	got := DiffRisk(before, after)
+	want := RiskDelta{
+		NewVulnerabilities:   map[string][]string{"GO-2024-0002": {"github.com/b/y@v0.1.0", "github.com/c/z@v1.0.0"}},
+		FixedVulnerabilities: []string{"GO-2023-0001"},
+		NewLicenses:          []string{"GPL-3.0"},
+		NewOwners:            []string{"github.com/b", "golang.org"},
+		AddedModules:         2,
+		Score:                10 - 10 + 5 + 2*3 + 2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffRisk() = %+v, want %+v", got, want)
+	}
+
+	out := string(RenderRiskDelta(got))
+	for _, line := range []string{
+		"  + vulnerability GO-2024-0002 in github.com/b/y@v0.1.0, github.com/c/z@v1.0.0\n",
+		"  - vulnerability GO-2023-0001\n",
+		"  + license GPL-3.0\n",
+		"  + owner golang.org\n",
+		"  +2 module(s)\n",
+		"Risk delta: +13 (1 new and 1 fixed vulnerabilities, 1 new license(s), 2 new owner(s))\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in:\n%s", line, out)
+		}
+	}
+
+	if d := DiffRisk(after, after); d.Score != 0 || len(d.NewVulnerabilities) != 0 || d.NewOwners != nil {
+		t.Errorf("Expected no risk change for identical graphs, got %+v", d)
+	}
+}
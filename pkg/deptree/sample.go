@@ -0,0 +1,91 @@
+package deptree
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// SamplePaths picks n leaf modules of g at random, those requiring nothing,
+// and returns a path from the root to each, choosing at random among the
+// shortest ones. The same seed picks the same paths. It also returns the
+// number of leaves there were to pick from; all of them are taken when n is
+// larger.
+func SamplePaths(g *Graph, n int, seed uint64) ([][]string, int) {
+	if g.Root == nil {
+		return nil, 0
+	}
+
+	// depth and parents describe the shortest paths from the root
+	depth := map[string]int{g.Root.Name: 0}
+	parents := make(map[string][]string)
+	queue := []string{g.Root.Name}
+	var leaves []string
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		leaf := true
+		for _, to := range uniqueChildren(g.Deps, name) {
+			if IsToolchainDep(to) {
+				continue
+			}
+			leaf = false
+			d, seen := depth[to]
+			if !seen {
+				depth[to] = depth[name] + 1
+				queue = append(queue, to)
+			} else if d != depth[name]+1 {
+				continue
+			}
+			parents[to] = append(parents[to], name)
+		}
+		if leaf && name != g.Root.Name {
+			leaves = append(leaves, name)
+		}
+	}
+	slices.Sort(leaves)
+
+	rng := rand.New(rand.NewPCG(seed, 0))
+	rng.Shuffle(len(leaves), func(i, j int) { leaves[i], leaves[j] = leaves[j], leaves[i] })
+
+	var paths [][]string
+	for _, leaf := range leaves[:min(n, len(leaves))] {
+		path := []string{leaf}
+		for name := leaf; name != g.Root.Name; {
+			name = parents[name][rng.IntN(len(parents[name]))]
+			path = append(path, name)
+		}
+		slices.Reverse(path)
+		paths = append(paths, path)
+	}
+	slices.SortFunc(paths, slices.Compare)
+	return paths, len(leaves)
+}
+
+// KeepPaths reduces g to the given paths from its root, as returned by
+// SamplePaths: the tree and the requirement edges hold only the modules on
+// them.
+func (g *Graph) KeepPaths(paths [][]string) {
+	if g.Root == nil {
+		return
+	}
+	deps := make(map[string][]string)
+	root := NewNode(g.Root.Name)
+	for _, path := range paths {
+		node := root
+		for i, name := range path[1:] {
+			if !slices.Contains(deps[path[i]], name) {
+				deps[path[i]] = append(deps[path[i]], name)
+			}
+			child, ok := node.Children[name]
+			if !ok {
+				child = NewNode(name)
+				node.Children[name] = child
+			}
+			node = child
+		}
+	}
+	g.Deps = deps
+	g.Root = root
+	g.Unexpanded = 0
+	g.syncDescriptions()
+}
@@ -0,0 +1,40 @@
+package deptree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSamplePaths(t *testing.T) {
+	g := streamTestGraph()
+	g.Deps["example.com/b@v1.0.0"] = append(g.Deps["example.com/b@v1.0.0"], "example.com/e@v1.0.0")
+	g.expandRoot("example.com/app")
+
+	paths, leaves := SamplePaths(g, 1, 42)
+	if leaves != 2 || len(paths) != 1 {
+		t.Fatalf("SamplePaths(1) = %d path(s) of %d leaves, want 1 of 2", len(paths), leaves)
+	}
+	if again, _ := SamplePaths(g, 1, 42); !slices.EqualFunc(paths, again, slices.Equal) {
+		t.Errorf("the same seed sampled %v, then %v", paths, again)
+	}
+
+	paths, _ = SamplePaths(g, 10, 1)
+	for _, path := range paths {
+		// d is reached at depth 2 through a, never through the longer c path
+		if path[len(path)-1] == "example.com/d@v1.0.0" && !slices.Equal(path, []string{"example.com/app", "example.com/a@v1.0.0", "example.com/d@v1.0.0"}) {
+			t.Errorf("path to d = %v, want the shortest one", path)
+		}
+	}
+	if len(paths) != 2 {
+		t.Fatalf("SamplePaths(10) = %v, want both leaves", paths)
+	}
+
+	g.KeepPaths(paths)
+	want := []string{"example.com/a@v1.0.0", "example.com/app", "example.com/b@v1.0.0", "example.com/d@v1.0.0", "example.com/e@v1.0.0"}
+	if got := g.Modules(); !slices.Equal(got, want) {
+		t.Errorf("Modules after KeepPaths = %v", got)
+	}
+	if len(g.Root.Children) != 2 || len(g.Root.Children["example.com/a@v1.0.0"].Children) != 1 {
+		t.Errorf("tree after KeepPaths has root children %d, want a with only d below it and b", len(g.Root.Children))
+	}
+}
@@ -0,0 +1,166 @@
+package deptree
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sbomNow stamps the creation time of generated SBOM documents.
+var sbomNow = time.Now
+
+// sbomTimestamp returns the creation time of an SBOM document. Following the
+// reproducible builds convention, SOURCE_DATE_EPOCH (seconds since the Unix
+// epoch) overrides the current time so that unchanged inputs produce
+// byte-identical documents.
+func sbomTimestamp() (string, error) {
+	created := sbomNow()
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: want seconds since the Unix epoch", epoch)
+		}
+		created = time.Unix(seconds, 0)
+	}
+	return created.UTC().Format(time.RFC3339), nil
+}
+
+// PackageURL returns the purl identifying a path@version module, e.g.
+// "pkg:golang/github.com/spf13/cobra@v1.8.0".
+func PackageURL(module string) string {
+	path, version := SplitModule(module)
+	if version == "" {
+		return "pkg:golang/" + path
+	}
+	return "pkg:golang/" + path + "@" + version
+}
+
+// sbomModules returns the root module followed by every other module of g.
+func sbomModules(g *Graph) []string {
+	modules := []string{g.Root.Name}
+	for _, name := range g.Modules() {
+		if name != g.Root.Name {
+			modules = append(modules, name)
+		}
+	}
+	return modules
+}
+
+// sbomDependencies groups the requirement edges of g by the requiring module.
+func sbomDependencies(g *Graph) map[string][]string {
+	deps := make(map[string][]string)
+	for _, edge := range g.Edges() {
+		deps[edge.From] = append(deps[edge.From], edge.To)
+	}
+	return deps
+}
+
+// sbomDigest derives a stable identifier for a set of modules so that the
+// same graph always produces the same document namespace and serial number.
+func sbomDigest(modules []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, name := range modules {
+		fmt.Fprintln(h, name)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// SBOMComponent is a Go module listed in an SBOM document.
+type SBOMComponent struct {
+	Path    string
+	Version string
+	// SHA256 is the hex-encoded digest the SBOM records, if any.
+	SHA256 string
+}
+
+// ReadSBOM parses the Go modules listed in an SPDX or CycloneDX JSON
+// document. Package URLs are preferred over names, so SBOMs produced by
+// other tools are understood as well; non-Go packages are skipped.
+func ReadSBOM(data []byte) ([]SBOMComponent, error) {
+	var header struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse SBOM: %w", err)
+	}
+
+	var components []SBOMComponent
+	switch {
+	case header.SPDXVersion != "":
+		var doc spdxDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse SPDX document: %w", err)
+		}
+		for _, pkg := range doc.Packages {
+			purl := ""
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					purl = ref.ReferenceLocator
+				}
+			}
+			component, ok := sbomComponent(purl, pkg.Name, pkg.VersionInfo)
+			if !ok {
+				continue
+			}
+			for _, sum := range pkg.Checksums {
+				if sum.Algorithm == "SHA256" {
+					component.SHA256 = sum.ChecksumValue
+				}
+			}
+			components = append(components, component)
+		}
+
+	case header.BOMFormat == "CycloneDX":
+		var bom cdxBOM
+		if err := json.Unmarshal(data, &bom); err != nil {
+			return nil, fmt.Errorf("failed to parse CycloneDX document: %w", err)
+		}
+		for _, c := range append([]cdxComponent{bom.Metadata.Component}, bom.Components...) {
+			component, ok := sbomComponent(c.PURL, c.Name, c.Version)
+			if !ok {
+				continue
+			}
+			for _, hash := range c.Hashes {
+				if hash.Alg == "SHA-256" {
+					component.SHA256 = hash.Content
+				}
+			}
+			components = append(components, component)
+		}
+
+	default:
+		return nil, fmt.Errorf("unrecognized SBOM format: expected an SPDX or CycloneDX JSON document")
+	}
+
+	return components, nil
+}
+
+// sbomComponent identifies a module from its package URL, falling back to
+// the name and version when the SBOM has no purl.
+func sbomComponent(purl, name, version string) (SBOMComponent, bool) {
+	if purl == "" {
+		return SBOMComponent{Path: name, Version: version}, name != ""
+	}
+
+	rest, ok := strings.CutPrefix(purl, "pkg:golang/")
+	if !ok {
+		return SBOMComponent{}, false
+	}
+	// Drop qualifiers and subpath
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	if unescaped, err := url.PathUnescape(rest); err == nil {
+		rest = unescaped
+	}
+	path, version := SplitModule(rest)
+	return SBOMComponent{Path: path, Version: version}, true
+}
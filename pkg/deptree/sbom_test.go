@@ -0,0 +1,247 @@
+package deptree
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGoSum = `github.com/spf13/pflag v1.0.5 h1:iy+VFUOCP1a+8yFto/drg2CJ5u0yRoB7fZw3DKv/JXA=
+github.com/spf13/pflag v1.0.5/go.mod h1:McXfInJRrz4CZXVZOBLb0bTZqETkiAhM9Iw0y3An2Bg=
+`
+
+func sbomTestGraph() *Graph {
+	root := NewNode("example.com/app")
+	root.Children["github.com/spf13/pflag@v1.0.5"] = NewNode("github.com/spf13/pflag@v1.0.5")
+	return &Graph{
+		Root: root,
+		Deps: map[string][]string{
+			"example.com/app":               {"github.com/spf13/pflag@v1.0.5"},
+			"github.com/spf13/pflag@v1.0.5": {},
+		},
+		Sums: GoSum{
+			"github.com/spf13/pflag@v1.0.5": "h1:iy+VFUOCP1a+8yFto/drg2CJ5u0yRoB7fZw3DKv/JXA=",
+		},
+		Licenses: map[string]string{"github.com/spf13/pflag@v1.0.5": "BSD-3-Clause"},
+	}
+}
+
+func TestReadGoSum(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(testGoSum), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := ReadGoSum(dir)
+	if err != nil {
+		t.Fatalf("ReadGoSum failed: %v", err)
+	}
+	if len(sums) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(sums))
+	}
+	if got := sums["github.com/spf13/pflag@v1.0.5/go.mod"]; got != "h1:McXfInJRrz4CZXVZOBLb0bTZqETkiAhM9Iw0y3An2Bg=" {
+		t.Errorf("Unexpected go.mod hash %q", got)
+	}
+
+	want := "8b2f951543823f56bef3216da3f76b836089e6ed3246807b7d9c370cabff2570"
+	if got := sums.SHA256("github.com/spf13/pflag@v1.0.5"); got != want {
+		t.Errorf("SHA256() = %q, want %q", got, want)
+	}
+	if got := sums.SHA256("missing@v1.0.0"); got != "" {
+		t.Errorf("Expected no hash for a missing module, got %q", got)
+	}
+
+	empty, err := ReadGoSum(t.TempDir())
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected empty GoSum without go.sum, got %v, %v", empty, err)
+	}
+}
+
+func TestSPDXRenderer(t *testing.T) {
+	sbomNow = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { sbomNow = time.Now }()
+
+	out, err := Render("spdx", sbomTestGraph(), RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" || doc.CreationInfo.Created != "2025-01-02T03:04:05Z" {
+		t.Errorf("Unexpected document header: %+v", doc)
+	}
+	if len(doc.Packages) != 2 {
+		t.Fatalf("Expected 2 packages, got %d", len(doc.Packages))
+	}
+
+	pflag := doc.Packages[1]
+	if pflag.SPDXID != spdxID("github.com/spf13/pflag@v1.0.5") || pflag.VersionInfo != "v1.0.5" {
+		t.Errorf("Unexpected package: %+v", pflag)
+	}
+	if len(pflag.Checksums) != 1 || pflag.Checksums[0].Algorithm != "SHA256" {
+		t.Errorf("Expected a SHA256 checksum, got %+v", pflag.Checksums)
+	}
+	if pflag.LicenseDeclared != "BSD-3-Clause" {
+		t.Errorf("Expected declared license BSD-3-Clause, got %q", pflag.LicenseDeclared)
+	}
+	if pflag.ExternalRefs[0].ReferenceLocator != "pkg:golang/github.com/spf13/pflag@v1.0.5" {
+		t.Errorf("Unexpected purl %q", pflag.ExternalRefs[0].ReferenceLocator)
+	}
+	if doc.Packages[0].Checksums != nil || doc.Packages[0].DownloadLocation != spdxNoAssertion {
+		t.Errorf("Expected the unversioned root to have no checksum or download location: %+v", doc.Packages[0])
+	}
+
+	want := []spdxRelationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", spdxID("example.com/app")},
+		{spdxID("example.com/app"), "DEPENDS_ON", spdxID("github.com/spf13/pflag@v1.0.5")},
+	}
+	if len(doc.Relationships) != len(want) {
+		t.Fatalf("Expected %d relationships, got %+v", len(want), doc.Relationships)
+	}
+	for i := range want {
+		if doc.Relationships[i] != want[i] {
+			t.Errorf("Relationship %d = %+v, want %+v", i, doc.Relationships[i], want[i])
+		}
+	}
+}
+
+func TestSPDXIDsDoNotCollide(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("example.com/app"),
+		Deps: map[string][]string{
+			"example.com/app":     {"github.com/a/b-c@v1", "github.com/a/b/c@v1"},
+			"github.com/a/b-c@v1": {},
+			"github.com/a/b/c@v1": {},
+		},
+	}
+	out, err := Render("spdx", g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	seen := make(map[string]string)
+	for _, pkg := range doc.Packages {
+		if other, ok := seen[pkg.SPDXID]; ok {
+			t.Errorf("Packages %q and %q share SPDXID %q", other, pkg.Name, pkg.SPDXID)
+		}
+		seen[pkg.SPDXID] = pkg.Name
+		if strings.Trim(strings.TrimPrefix(pkg.SPDXID, "SPDXRef-"), "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.-") != "" {
+			t.Errorf("SPDXID %q contains characters SPDX does not allow", pkg.SPDXID)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected 3 distinct packages, got %d", len(seen))
+	}
+}
+
+func TestCycloneDXRenderer(t *testing.T) {
+	out, err := Render("cyclonedx", sbomTestGraph(), RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(out, &bom); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" {
+		t.Errorf("Unexpected BOM header: %+v", bom)
+	}
+	if bom.Metadata.Component.Name != "example.com/app" || bom.Metadata.Component.Type != "application" {
+		t.Errorf("Unexpected metadata component: %+v", bom.Metadata.Component)
+	}
+	if len(bom.Components) != 1 {
+		t.Fatalf("Expected 1 component, got %d", len(bom.Components))
+	}
+
+	pflag := bom.Components[0]
+	if pflag.PURL != "pkg:golang/github.com/spf13/pflag@v1.0.5" {
+		t.Errorf("Unexpected purl %q", pflag.PURL)
+	}
+	if len(pflag.Hashes) != 1 || pflag.Hashes[0].Alg != "SHA-256" {
+		t.Errorf("Expected a SHA-256 hash, got %+v", pflag.Hashes)
+	}
+	if len(pflag.Licenses) != 1 || pflag.Licenses[0].License.ID != "BSD-3-Clause" {
+		t.Errorf("Expected BSD-3-Clause license, got %+v", pflag.Licenses)
+	}
+
+	if len(bom.Dependencies) != 2 || len(bom.Dependencies[0].DependsOn) != 1 || bom.Dependencies[0].DependsOn[0] != pflag.BOMRef {
+		t.Errorf("Unexpected dependencies: %+v", bom.Dependencies)
+	}
+
+	// The serial number is derived from the graph, so it is stable
+	again, _ := Render("cyclonedx", sbomTestGraph(), RenderOptions{})
+	var bom2 cdxBOM
+	json.Unmarshal(again, &bom2)
+	if bom.SerialNumber != bom2.SerialNumber {
+		t.Errorf("Serial number changed between renders: %s vs %s", bom.SerialNumber, bom2.SerialNumber)
+	}
+}
+
+func TestSBOMSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	for _, format := range []string{"spdx", "cyclonedx"} {
+		out, err := Render(format, sbomTestGraph(), RenderOptions{})
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+		if !strings.Contains(string(out), `"2023-11-14T22:13:20Z"`) {
+			t.Errorf("Expected the %s timestamp from SOURCE_DATE_EPOCH, got:\n%s", format, out)
+		}
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if _, err := Render("spdx", sbomTestGraph(), RenderOptions{}); err == nil || !strings.Contains(err.Error(), "SOURCE_DATE_EPOCH") {
+		t.Errorf("Expected an invalid SOURCE_DATE_EPOCH error, got %v", err)
+	}
+}
+
+func TestReadSBOM(t *testing.T) {
+	for _, format := range []string{"spdx", "cyclonedx"} {
+		out, err := Render(format, sbomTestGraph(), RenderOptions{})
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+
+		components, err := ReadSBOM(out)
+		if err != nil {
+			t.Fatalf("ReadSBOM %s failed: %v", format, err)
+		}
+		want := []SBOMComponent{
+			{Path: "example.com/app"},
+			{Path: "github.com/spf13/pflag", Version: "v1.0.5", SHA256: "8b2f951543823f56bef3216da3f76b836089e6ed3246807b7d9c370cabff2570"},
+		}
+		if !reflect.DeepEqual(components, want) {
+			t.Errorf("ReadSBOM(%s) = %+v, want %+v", format, components, want)
+		}
+	}
+
+	// Package URLs from other tools may carry qualifiers; non-Go packages are skipped
+	doc := `{"bomFormat": "CycloneDX", "components": [
+		{"name": "pflag", "purl": "pkg:golang/github.com/spf13/pflag@v1.0.5?type=module"},
+		{"name": "left-pad", "purl": "pkg:npm/left-pad@1.3.0"}
+	]}`
+	components, err := ReadSBOM([]byte(doc))
+	if err != nil {
+		t.Fatalf("ReadSBOM failed: %v", err)
+	}
+	if len(components) != 1 || components[0] != (SBOMComponent{Path: "github.com/spf13/pflag", Version: "v1.0.5"}) {
+		t.Errorf("Unexpected components: %+v", components)
+	}
+
+	if _, err := ReadSBOM([]byte(`{"foo": 1}`)); err == nil {
+		t.Error("Expected error for an unrecognized document")
+	}
+}
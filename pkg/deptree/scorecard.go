@@ -0,0 +1,134 @@
+package deptree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// scorecardAPIURL is the base URL of the OpenSSF Scorecard API, replaced in
+// tests.
+var scorecardAPIURL = "https://api.securityscorecards.dev"
+
+// scorecardWorkers bounds concurrent Scorecard API requests.
+const scorecardWorkers = 8
+
+// errNotScored reports a repository the Scorecard project has not scanned.
+var errNotScored = errors.New("not scored by OpenSSF Scorecard")
+
+// Scorecard is the OpenSSF Scorecard result of the GitHub repository of a
+// module.
+type Scorecard struct {
+	// Score is the aggregate score from 0 to 10, weighted over the checks.
+	Score float64 `json:"score"`
+	// Date is when the repository was last scanned.
+	Date string `json:"date,omitempty"`
+}
+
+// FetchScorecard fetches the latest OpenSSF Scorecard result of the GitHub
+// repository owner/repo.
+func FetchScorecard(opts Options, owner, repo string) (Scorecard, error) {
+	resp, err := opts.client().Get(fmt.Sprintf("%s/projects/github.com/%s/%s", opts.scorecardAPIURL(), owner, repo))
+	if errors.Is(err, ErrBudgetExceeded) {
+		return Scorecard{}, ErrBudgetExceeded
+	}
+	if err != nil {
+		return Scorecard{}, fmt.Errorf("failed to fetch scorecard: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Scorecard{}, errNotScored
+	case resp.StatusCode != http.StatusOK:
+		return Scorecard{}, fmt.Errorf("scorecard API returned status %d", resp.StatusCode)
+	}
+	var sc Scorecard
+	if err := json.NewDecoder(resp.Body).Decode(&sc); err != nil {
+		return Scorecard{}, fmt.Errorf("failed to parse scorecard: %w", err)
+	}
+	return sc, nil
+}
+
+// FetchScorecards fetches the OpenSSF Scorecard of the GitHub repository of
+// every module in g and stores it in g.Scorecards and on the tree nodes.
+// Each repository is fetched once however many of its modules and versions
+// g contains; modules hosted elsewhere or not scanned by the Scorecard
+// project are left out. Modules skipped because the client's time budget
+// ran out are listed in g.Partial, and other failures are returned
+// together.
+func FetchScorecards(g *Graph, opts Options) error {
+	repos := make(map[[2]string][]string)
+	for _, name := range g.Modules() {
+		if owner, repo, ok := extractGitHubRepo(name); ok {
+			key := [2]string{owner, repo}
+			repos[key] = append(repos[key], name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	sem := make(chan struct{}, scorecardWorkers)
+	g.Scorecards = make(map[string]Scorecard)
+
+	for key, modules := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			sc, err := FetchScorecard(opts, key[0], key[1])
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, ErrBudgetExceeded):
+				for _, name := range modules {
+					g.markPartial(name)
+				}
+				return
+			case errors.Is(err, errNotScored):
+				return
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s/%s: %w", key[0], key[1], err))
+				return
+			}
+			for _, name := range modules {
+				g.Scorecards[name] = sc
+			}
+		}()
+	}
+	wg.Wait()
+
+	g.Walk(func(node *Node) {
+		if sc, ok := g.Scorecards[node.Name]; ok {
+			node.Annotations = append(node.Annotations, scorecardLabel(sc))
+		}
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to fetch %d scorecard(s): %w", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// BelowScorecard returns the modules of g whose Scorecard score is below
+// threshold, sorted. Modules without a score are not included.
+func (g *Graph) BelowScorecard(threshold float64) []string {
+	var below []string
+	for _, name := range g.Modules() {
+		if sc, ok := g.Scorecards[name]; ok && sc.Score < threshold {
+			below = append(below, name)
+		}
+	}
+	return below
+}
+
+// scorecardLabel shows the aggregate Scorecard score of a module.
+func scorecardLabel(sc Scorecard) string {
+	return fmt.Sprintf("(scorecard %.1f)", sc.Score)
+}
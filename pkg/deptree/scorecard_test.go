@@ -0,0 +1,62 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchScorecards(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/projects/github.com/spf13/cobra":
+			w.Write([]byte(`{"date": "2024-05-06T00:00:00Z", "repo": {"name": "github.com/spf13/cobra"}, "score": 6.8, "checks": [{"name": "Maintained", "score": 10}]}`))
+		case "/projects/github.com/broken/repo":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	oldURL := scorecardAPIURL
+	scorecardAPIURL = server.URL
+	defer func() { scorecardAPIURL = oldURL }()
+
+	modules := []string{"github.com/spf13/cobra@v1.8.0", "github.com/spf13/cobra@v1.7.0", "github.com/unscanned/repo@v1.0.0", "github.com/broken/repo@v0.1.0", "golang.org/x/text@v0.14.0"}
+	root := NewNode("myapp")
+	for _, name := range modules {
+		root.Children[name] = NewNode(name)
+	}
+	g := &Graph{Root: root, Deps: map[string][]string{"myapp": modules}}
+
+	err := FetchScorecards(g, Options{HTTPClient: server.Client()})
+	if err == nil || !strings.Contains(err.Error(), "broken/repo: scorecard API returned status 500") {
+		t.Errorf("Expected the failed repository to be reported, got %v", err)
+	}
+	want := map[string]Scorecard{
+		"github.com/spf13/cobra@v1.8.0": {Score: 6.8, Date: "2024-05-06T00:00:00Z"},
+		"github.com/spf13/cobra@v1.7.0": {Score: 6.8, Date: "2024-05-06T00:00:00Z"},
+	}
+	if !reflect.DeepEqual(g.Scorecards, want) {
+		t.Errorf("Scorecards = %v, want %v", g.Scorecards, want)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Expected one request per GitHub repository, got %d", n)
+	}
+	if got := root.Children["github.com/spf13/cobra@v1.8.0"].Annotations; !reflect.DeepEqual(got, []string{"(scorecard 6.8)"}) {
+		t.Errorf("Unexpected annotations %v", got)
+	}
+
+	if below := g.BelowScorecard(7); !reflect.DeepEqual(below, []string{"github.com/spf13/cobra@v1.7.0", "github.com/spf13/cobra@v1.8.0"}) {
+		t.Errorf("BelowScorecard(7) = %v", below)
+	}
+	if below := g.BelowScorecard(6.5); len(below) != 0 {
+		t.Errorf("BelowScorecard(6.5) = %v, want none", below)
+	}
+}
@@ -0,0 +1,119 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ReadSelectedVersions runs 'go list -m all' in dir and returns the version
+// MVS selected for each module path. The main module maps to "".
+func ReadSelectedVersions(dir string) (map[string]string, error) {
+	output, err := goCommand(dir, "list", "-m", "all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'go list -m all': %w", err)
+	}
+
+	selected := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		// Lines are "path [version] [=> replacement]"
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 1 || fields[1] == "=>":
+			selected[fields[0]] = ""
+		default:
+			selected[fields[0]] = fields[1]
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading output: %w", err)
+	}
+
+	return selected, nil
+}
+
+// StartSelectedVersions runs ReadSelectedVersions in the background, so
+// 'go list -m all' can run while 'go mod graph' does, and returns a
+// function that waits for its result.
+func StartSelectedVersions(dir string) func() (map[string]string, error) {
+	var selected map[string]string
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		selected, err = ReadSelectedVersions(dir)
+	}()
+	return func() (map[string]string, error) {
+		<-done
+		return selected, err
+	}
+}
+
+// CollapseToSelected rewrites g so that every module appears only at the
+// version MVS selected. Requirements of versions that were not selected are
+// dropped, those versions are listed in g.Pruned, and tree nodes whose parent
+// required an older version are annotated with it.
+func (g *Graph) CollapseToSelected(selected map[string]string) {
+	resolve := func(name string) string {
+		path, version := SplitModule(name)
+		if sel, ok := selected[path]; ok && sel != "" && sel != version {
+			return path + "@" + sel
+		}
+		return name
+	}
+
+	collapsed := make(map[string][]string)
+	requested := make(map[[2]string]string)
+	pruned := make(map[string]bool)
+
+	for from, tos := range g.Deps {
+		if resolve(from) != from {
+			pruned[from] = true
+			continue
+		}
+		for _, to := range tos {
+			target := resolve(to)
+			if target != to {
+				pruned[to] = true
+				_, version := SplitModule(to)
+				requested[[2]string{from, target}] = version
+			}
+			if !slices.Contains(collapsed[from], target) {
+				collapsed[from] = append(collapsed[from], target)
+			}
+		}
+	}
+
+	g.Deps = collapsed
+	g.Pruned = make([]string, 0, len(pruned))
+	for name := range pruned {
+		g.Pruned = append(g.Pruned, name)
+	}
+	sort.Strings(g.Pruned)
+
+	if g.Root == nil {
+		return
+	}
+
+	root := NewNode(resolve(g.Root.Name))
+	buildTree(root, g.Deps, make(map[string]bool))
+	g.Root = root
+
+	var annotate func(*Node)
+	annotate = func(node *Node) {
+		for _, child := range node.Children {
+			if version, ok := requested[[2]string{node.Name, child.Name}]; ok {
+				child.Annotations = append(child.Annotations, fmt.Sprintf("(%s pruned)", version))
+			}
+			annotate(child)
+		}
+	}
+	annotate(g.Root)
+}
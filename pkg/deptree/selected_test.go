@@ -0,0 +1,78 @@
+package deptree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollapseToSelected(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"a@v1.0.0", "b@v1.2.0"},
+			"a@v1.0.0":    {"b@v1.1.0"},
+			"b@v1.1.0":    {"c@v0.1.0"},
+			"b@v1.2.0":    {"c@v0.2.0"},
+			"c@v0.1.0":    {},
+			"c@v0.2.0":    {},
+			"unused@v1.0": {},
+		},
+	}
+
+	g.CollapseToSelected(map[string]string{
+		"mymodule": "",
+		"a":        "v1.0.0",
+		"b":        "v1.2.0",
+		"c":        "v0.2.0",
+	})
+
+	if _, ok := g.Deps["b@v1.1.0"]; ok {
+		t.Error("Expected requirements of pruned b@v1.1.0 to be dropped")
+	}
+	if !reflect.DeepEqual(g.Deps["a@v1.0.0"], []string{"b@v1.2.0"}) {
+		t.Errorf("Expected a to require selected b, got %v", g.Deps["a@v1.0.0"])
+	}
+
+	expectedPruned := []string{"b@v1.1.0", "c@v0.1.0"}
+	if !reflect.DeepEqual(g.Pruned, expectedPruned) {
+		t.Errorf("Pruned = %v, want %v", g.Pruned, expectedPruned)
+	}
+
+	a := g.Root.Children["a@v1.0.0"]
+	if a == nil {
+		t.Fatal("Expected a@v1.0.0 under the root")
+	}
+	b := a.Children["b@v1.2.0"]
+	if b == nil || !reflect.DeepEqual(b.Annotations, []string{"(v1.1.0 pruned)"}) {
+		t.Errorf("Expected b under a to be annotated with the pruned version, got %+v", b)
+	}
+
+	out, err := treeRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(string(out), "b@v1.2.0 (v1.1.0 pruned)") {
+		t.Errorf("Expected annotation in tree output, got:\n%s", out)
+	}
+}
+
+func TestReadSelectedVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goModContent := []byte("module test\n\ngo 1.21\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), goModContent, 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	selected, err := ReadSelectedVersions(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadSelectedVersions failed: %v", err)
+	}
+
+	if version, ok := selected["test"]; !ok || version != "" {
+		t.Errorf("Expected main module with empty version, got %v", selected)
+	}
+}
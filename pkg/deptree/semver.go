@@ -0,0 +1,103 @@
+package deptree
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two module versions such as "v1.2.3" or
+// "v0.0.0-20240101000000-abcdef123456" by semantic version precedence,
+// returning -1, 0 or +1. Build metadata is ignored and an empty version
+// sorts before any other.
+func CompareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" || b == "" {
+		if a == "" {
+			return -1
+		}
+		return 1
+	}
+
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if c := compareNumeric(aCore[i], bCore[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A version without prerelease has higher precedence than one with
+	switch {
+	case aPre == "" && bPre == "":
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	aIDs := strings.Split(aPre, ".")
+	bIDs := strings.Split(bPre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		if c := comparePrereleaseID(aIDs[i], bIDs[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(aIDs), len(bIDs))
+}
+
+// splitVersion returns the major, minor and patch fields and the prerelease
+// suffix of a version. Missing fields are "0".
+func splitVersion(v string) (core [3]string, prerelease string) {
+	v = strings.TrimPrefix(v, "v")
+	v, _, _ = strings.Cut(v, "+")
+	v = strings.TrimSuffix(v, "+incompatible")
+	v, prerelease, _ = strings.Cut(v, "-")
+
+	fields := strings.SplitN(v, ".", 3)
+	for i := range core {
+		core[i] = "0"
+		if i < len(fields) && fields[i] != "" {
+			core[i] = fields[i]
+		}
+	}
+	return core, prerelease
+}
+
+func comparePrereleaseID(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(aNum, bNum)
+	case aErr == nil:
+		// Numeric identifiers sort before alphanumeric ones
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareNumeric compares decimal strings of arbitrary length.
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return compareInts(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package deptree
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.2", "v1.0.0-alpha.10", -1},
+		{"v1.0.0-alpha.beta", "v1.0.0-alpha.1", 1},
+		{"v0.0.0-20240101000000-abcdef123456", "v0.0.0-20230101000000-abcdef123456", 1},
+		{"v2.0.0+incompatible", "v2.0.0", 0},
+		{"", "v0.0.1", -1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
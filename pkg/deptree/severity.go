@@ -0,0 +1,162 @@
+package deptree
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Severity is the qualitative rating of a vulnerability, ordered from
+// SeverityUnknown to SeverityCritical.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return severityNames[SeverityUnknown]
+	}
+	return severityNames[s]
+}
+
+// MarshalText encodes s by name, e.g. "HIGH".
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity encoded by MarshalText.
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseSeverity parses a severity name case-insensitively. GitHub's
+// "moderate" is accepted as a synonym for medium and the empty string parses
+// as SeverityUnknown.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "", "unknown":
+		return SeverityUnknown, nil
+	case "low":
+		return SeverityLow, nil
+	case "medium", "moderate":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	}
+	return SeverityUnknown, fmt.Errorf("unknown severity %q (want low, medium, high or critical)", s)
+}
+
+// severityForScore rates a CVSS base score the way CVSS v3 does.
+func severityForScore(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityUnknown
+}
+
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSS3BaseScore computes the base score of a CVSS v3.x vector such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H". Temporal and environmental
+// metrics are ignored.
+func CVSS3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, fmt.Errorf("not a CVSS v3 vector: %q", vector)
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return 0, fmt.Errorf("malformed CVSS metric %q", part)
+		}
+		metrics[name] = value
+	}
+
+	changed := metrics["S"] == "C"
+	if s := metrics["S"]; s != "U" && s != "C" {
+		return 0, fmt.Errorf("CVSS vector %q has no valid scope", vector)
+	}
+
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if changed {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if changed {
+			pr = 0.5
+		}
+	default:
+		return 0, fmt.Errorf("CVSS vector %q has no valid PR metric", vector)
+	}
+
+	w := make(map[string]float64)
+	for name, weights := range cvss3Weights {
+		v, ok := weights[metrics[name]]
+		if !ok {
+			return 0, fmt.Errorf("CVSS vector %q has no valid %s metric", vector, name)
+		}
+		w[name] = v
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * w["AV"] * w["AC"] * pr * w["UI"]
+	if changed {
+		return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return cvssRoundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// cvssRoundUp rounds up to one decimal as specified by CVSS v3.1, avoiding
+// floating point artifacts such as 4.000000000000001 rounding to 4.1.
+func cvssRoundUp(x float64) float64 {
+	i := int(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
@@ -0,0 +1,53 @@
+package deptree
+
+import (
+	"testing"
+)
+
+func TestCVSS3BaseScore(t *testing.T) {
+	tests := []struct {
+		vector string
+		want   float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", 7.5},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0},
+		{"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:N/I:N/A:N", 0},
+	}
+	for _, tt := range tests {
+		got, err := CVSS3BaseScore(tt.vector)
+		if err != nil {
+			t.Errorf("CVSS3BaseScore(%s) failed: %v", tt.vector, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CVSS3BaseScore(%s) = %v, want %v", tt.vector, got, tt.want)
+		}
+	}
+
+	for _, vector := range []string{"CVSS:4.0/AV:N", "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "CVSS:3.1/AV:N"} {
+		if _, err := CVSS3BaseScore(vector); err == nil {
+			t.Errorf("Expected error for %s", vector)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := map[string]Severity{
+		"":         SeverityUnknown,
+		"low":      SeverityLow,
+		"MODERATE": SeverityMedium,
+		"medium":   SeverityMedium,
+		"High":     SeverityHigh,
+		"critical": SeverityCritical,
+	}
+	for in, want := range tests {
+		if got, err := ParseSeverity(in); err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseSeverity("severe"); err == nil {
+		t.Error("Expected error for unknown severity")
+	}
+}
@@ -0,0 +1,286 @@
+package deptree
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"html/template"
+)
+
+// SiteFile is one file of a static site, named by its slash-separated path
+// relative to the site's root directory.
+type SiteFile struct {
+	Name string
+	Data []byte
+}
+
+// SiteModulePage returns the path of a module's page relative to the site's
+// root directory.
+func SiteModulePage(name string) string {
+	return "modules/" + NodeID(name) + ".html"
+}
+
+// siteSearchEntry is one module of search-index.json.
+type siteSearchEntry struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+	License     string `json:"license,omitempty"`
+	URL         string `json:"url"`
+}
+
+type sitePage struct {
+	Lang    string
+	T       map[string]string
+	Root    *siteModule
+	Modules []*siteModule
+}
+
+type siteModule struct {
+	ID          string
+	Name        string
+	Path        string
+	Version     string
+	Description string
+	License     string
+	Homepage    string
+	CloneURL    string
+	Docs        string
+	Notes       []string
+	Vulns       []Vulnerability
+	Requires    []siteLink
+	RequiredBy  []siteLink
+}
+
+// siteLink is a requirement edge as shown on a module page.
+type siteLink struct {
+	Module *siteModule
+	Kind   EdgeKind
+}
+
+// RenderSite renders g as a static multi-page site for hosting on GitHub
+// Pages or any other web server: an index.html listing every module with a
+// search box, a page per module (see SiteModulePage) with its metadata and
+// the modules it requires and is required by, and a search-index.json of
+// the modules for the search box and other tools. Pages link to each other
+// relatively, so the site can be served from any directory.
+func RenderSite(g *Graph, opts RenderOptions) ([]SiteFile, error) {
+	direct := g.DirectDependencies()
+	modules := make(map[string]*siteModule)
+	var list []*siteModule
+	for _, name := range g.Modules() {
+		m := siteModuleOf(g, name, opts, direct)
+		modules[name] = m
+		list = append(list, m)
+	}
+	for _, edge := range g.Edges() {
+		from, to := modules[edge.From], modules[edge.To]
+		if from == nil || to == nil {
+			continue
+		}
+		from.Requires = append(from.Requires, siteLink{Module: to, Kind: edge.Kind})
+		to.RequiredBy = append(to.RequiredBy, siteLink{Module: from, Kind: edge.Kind})
+	}
+
+	page := sitePage{
+		Lang:    cmp.Or(opts.Lang, DefaultLang),
+		T:       messagesFor(opts.Lang),
+		Root:    modules[g.Root.Name],
+		Modules: list,
+	}
+	if page.Root == nil {
+		page.Root = siteModuleOf(g, g.Root.Name, opts, direct)
+	}
+
+	var files []SiteFile
+	render := func(name, tmpl string, page sitePage) error {
+		var buf bytes.Buffer
+		if err := siteTemplates.ExecuteTemplate(&buf, tmpl, page); err != nil {
+			return err
+		}
+		files = append(files, SiteFile{Name: name, Data: buf.Bytes()})
+		return nil
+	}
+
+	if err := render("index.html", "index", page); err != nil {
+		return nil, err
+	}
+	for _, m := range list {
+		modulePage := page
+		modulePage.Modules = []*siteModule{m}
+		if err := render(SiteModulePage(m.Name), "module", modulePage); err != nil {
+			return nil, err
+		}
+	}
+
+	index := []siteSearchEntry{}
+	for _, m := range list {
+		index = append(index, siteSearchEntry{
+			ID:          m.ID,
+			Name:        m.Name,
+			Path:        m.Path,
+			Version:     m.Version,
+			Description: m.Description,
+			License:     m.License,
+			URL:         SiteModulePage(m.Name),
+		})
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, SiteFile{Name: "search-index.json", Data: append(data, '\n')})
+
+	return files, nil
+}
+
+// siteModuleOf collects what a module page shows about the module name.
+func siteModuleOf(g *Graph, name string, opts RenderOptions, direct map[string]bool) *siteModule {
+	path, version := SplitModule(name)
+	m := &siteModule{ID: NodeID(name), Name: name, Path: path, Version: version, Vulns: g.VulnerabilityDetails[name]}
+	if version != "" && !IsToolchainDep(name) {
+		m.Docs = pkgGoDevURL + "/" + name
+	}
+	if opts.ShowDesc {
+		if g.DescriptionErrors[name] == nil {
+			m.Description = g.Descriptions[name]
+		}
+		m.Homepage = g.Links[name].Homepage
+		m.CloneURL = g.Links[name].CloneURL
+	}
+	if opts.ShowLicense {
+		m.License = g.Licenses[name]
+	}
+
+	if direct[name] {
+		m.Notes = append(m.Notes, directTag)
+	}
+	if g.TestOnly[name] {
+		m.Notes = append(m.Notes, testTag)
+	}
+	if latest, ok := g.Outdated[name]; ok {
+		m.Notes = append(m.Notes, outdatedLabel(latest))
+	}
+	if size, ok := g.Sizes[name]; ok {
+		m.Notes = append(m.Notes, sizeLabel(size))
+	}
+	if status, ok := g.RepoStatus[name]; ok {
+		m.Notes = append(m.Notes, repoStatusLabel(status))
+	}
+	if sc, ok := g.Scorecards[name]; ok {
+		m.Notes = append(m.Notes, scorecardLabel(sc))
+	}
+	if n, ok := g.ImportedBy[name]; ok {
+		m.Notes = append(m.Notes, importedByLabel(n))
+	}
+	return m
+}
+
+var siteTemplates = template.Must(template.New("site").Parse(`
+{{- define "head"}}<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.T.dependencies_of}} {{.Root.Path}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+h2 { font-size: 1.1em; margin-top: 1.5em; }
+a { color: #0550ae; }
+#search { width: 100%; max-width: 40em; padding: .4em; font-size: 1em; margin-bottom: 1em; }
+.path { font-family: ui-monospace, monospace; }
+.version, .license { font-size: .8em; border-radius: 3px; padding: 0 .3em; margin-left: .3em; }
+.version { background: #eef; }
+.license { background: #efe; }
+.note { color: #a40; margin-left: .3em; }
+.kind { color: #666; font-size: .8em; margin-left: .3em; }
+.hidden { display: none; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: .2em .6em; text-align: left; vertical-align: top; }
+dt { font-weight: bold; margin-top: .5em; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{- define "link"}}<a class="path" href="{{.ID}}.html">{{.Path}}</a>{{with .Version}}<span class="version">{{.}}</span>{{end}}{{end}}
+
+{{- define "index"}}{{template "head" .}}<h1>{{.T.dependencies_of}} <a class="path" href="modules/{{.Root.ID}}.html">{{.Root.Path}}</a>{{with .Root.Version}} <span class="version">{{.}}</span>{{end}}</h1>
+<p>{{printf .T.module_count (len .Modules)}}</p>
+<input id="search" type="search" placeholder="{{.T.search_placeholder}}">
+<table id="modules">
+<thead><tr><th>{{.T.module}}</th><th>{{.T.version}}</th><th>{{.T.license}}</th><th>{{.T.description}}</th></tr></thead>
+<tbody>
+{{- range .Modules}}{{if ne .Name $.Root.Name}}
+<tr id="{{.ID}}"><td><a class="path" href="modules/{{.ID}}.html">{{.Path}}</a>{{range .Notes}}<span class="note">{{.}}</span>{{end}}</td><td>{{.Version}}</td><td>{{.License}}</td><td>{{.Description}}</td></tr>
+{{- end}}{{end}}
+</tbody>
+</table>
+<script>
+var entries = null;
+// Search the metadata of search-index.json, or the table alone where it
+// cannot be fetched, such as from a file: URL
+fetch("search-index.json").then(function (r) { return r.json(); }).then(function (list) {
+  entries = {};
+  list.forEach(function (e) {
+    entries[e.id] = [e.name, e.description || "", e.license || ""].join(" ").toLowerCase();
+  });
+}).catch(function () {});
+document.getElementById("search").addEventListener("input", function (e) {
+  var query = e.target.value.toLowerCase();
+  document.querySelectorAll("#modules tbody tr").forEach(function (row) {
+    var text = entries && entries[row.id] !== undefined ? entries[row.id] : row.textContent.toLowerCase();
+    row.classList.toggle("hidden", text.indexOf(query) === -1);
+  });
+});
+</script>
+</body>
+</html>
+{{end}}
+
+{{- define "module"}}{{template "head" .}}{{with index .Modules 0}}<p><a href="../index.html">{{$.T.dependencies_of}} {{$.Root.Path}}</a></p>
+<h1><span class="path">{{.Path}}</span>{{with .Version}} <span class="version">{{.}}</span>{{end}}{{with .License}} <span class="license">{{.}}</span>{{end}}{{range .Notes}}<span class="note">{{.}}</span>{{end}}</h1>
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+<dl>
+{{- with .Docs}}
+<dt>pkg.go.dev</dt><dd><a href="{{.}}">{{.}}</a></dd>
+{{- end}}
+{{- with .Homepage}}
+<dt>{{$.T.homepage}}</dt><dd><a href="{{.}}">{{.}}</a></dd>
+{{- end}}
+{{- with .CloneURL}}
+<dt>{{$.T.repository}}</dt><dd><a href="{{.}}">{{.}}</a></dd>
+{{- end}}
+</dl>
+{{- with .Vulns}}
+<h2>{{$.T.vulnerabilities}}</h2>
+<ul>
+{{- range .}}
+<li><a href="https://osv.dev/vulnerability/{{.ID}}">{{.ID}}</a>{{with .Summary}}: {{.}}{{end}}{{with .Fixed}} ({{printf $.T.fixed_in .}}){{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Requires}}
+<h2>{{$.T.requires}}</h2>
+<ul>
+{{- range .}}
+<li>{{template "link" .Module}}<span class="kind">{{.Kind}}</span></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .RequiredBy}}
+<h2>{{$.T.required_by}}</h2>
+<ul>
+{{- range .}}
+<li>{{template "link" .Module}}<span class="kind">{{.Kind}}</span></li>
+{{- end}}
+</ul>
+{{- end}}
+{{end}}</body>
+</html>
+{{end}}`))
@@ -0,0 +1,66 @@
+package deptree
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderSite(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":                {"example.com/dep1@v1.0.0"},
+			"example.com/dep1@v1.0.0": {"example.com/dep2@v1.2.0"},
+		},
+		RootModFile:          &ModFile{Require: []ModRequire{{Path: "example.com/dep1", Version: "v1.0.0"}}},
+		Descriptions:         map[string]string{"example.com/dep1@v1.0.0": "Parses <things>"},
+		Licenses:             map[string]string{"example.com/dep1@v1.0.0": "MIT"},
+		Links:                map[string]SourceLinks{"example.com/dep1@v1.0.0": {Homepage: "https://dep1.example.com"}},
+		VulnerabilityDetails: map[string][]Vulnerability{"example.com/dep2@v1.2.0": {{ID: "GO-2024-0001", Fixed: "v1.2.1"}}},
+	}
+
+	files, err := RenderSite(g, RenderOptions{ShowDesc: true, ShowLicense: true})
+	if err != nil {
+		t.Fatalf("RenderSite failed: %v", err)
+	}
+	site := make(map[string]string)
+	for _, file := range files {
+		site[file.Name] = string(file.Data)
+	}
+	if len(site) != 5 {
+		t.Fatalf("Expected an index, a search index and 3 module pages, got %d files", len(files))
+	}
+
+	dep1 := SiteModulePage("example.com/dep1@v1.0.0")
+	index := site["index.html"]
+	for _, want := range []string{`<a class="path" href="` + dep1 + `">example.com/dep1</a>`, "<td>MIT</td>", "Parses &lt;things&gt;", "[direct]"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("Expected %q in index.html:\n%s", want, index)
+		}
+	}
+
+	page := site[dep1]
+	for _, want := range []string{
+		`<a href="https://dep1.example.com">`,
+		`<a href="https://pkg.go.dev/example.com/dep1@v1.0.0">`,
+		`<h2>Requires</h2>`,
+		`<a class="path" href="` + NodeID("example.com/dep2@v1.2.0") + `.html">example.com/dep2</a>`,
+		`<a class="path" href="` + NodeID("mymodule") + `.html">mymodule</a>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q in the page of dep1:\n%s", want, page)
+		}
+	}
+	if page := site[SiteModulePage("example.com/dep2@v1.2.0")]; !strings.Contains(page, "GO-2024-0001</a> (fixed in v1.2.1)") {
+		t.Errorf("Expected the vulnerability on the page of dep2:\n%s", page)
+	}
+
+	var entries []siteSearchEntry
+	if err := json.Unmarshal([]byte(site["search-index.json"]), &entries); err != nil {
+		t.Fatalf("search-index.json is not valid JSON: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Name != "example.com/dep1@v1.0.0" || entries[0].URL != dep1 || entries[0].License != "MIT" {
+		t.Errorf("Unexpected search index: %+v", entries)
+	}
+}
@@ -0,0 +1,133 @@
+package deptree
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+)
+
+// DetectSizes stores in g.Sizes the on-disk size of the source of every
+// module in g that is extracted in the module cache, and annotates the tree
+// nodes with it. 'go mod graph' only downloads go.mod files, so modules the
+// build never needed are usually missing; 'go mod download' fetches them.
+// A replaced module is measured by its replacement when that is another
+// module version.
+func DetectSizes(g *Graph) error {
+	return detectSizes(g, func(path, version string) (int64, error) {
+		dir, err := ModuleCacheDir(path, version)
+		if err != nil {
+			return 0, err
+		}
+		return dirSize(dir)
+	})
+}
+
+// detectSizes is DetectSizes with the measuring of a module version
+// injected, so tests need no module cache.
+func detectSizes(g *Graph, measure func(path, version string) (int64, error)) error {
+	g.Sizes = make(map[string]int64)
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		if g.ModFile != nil {
+			if rep, ok := g.ModFile.FindReplace(path, version); ok && rep.Version != "" {
+				path, version = rep.Path, rep.Version
+			}
+		}
+		size, err := measure(path, version)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		g.Sizes[name] = size
+	}
+
+	g.Walk(func(node *Node) {
+		if size, ok := g.Sizes[node.Name]; ok {
+			node.Annotations = append(node.Annotations, sizeLabel(size))
+		}
+	})
+	return nil
+}
+
+// dirSize returns the total size of the regular files below dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// sizeLabel marks a module with its size on disk.
+func sizeLabel(size int64) string {
+	return "(" + FormatSize(size) + ")"
+}
+
+// FormatSize formats a byte count with a binary unit, such as "1.5 MiB".
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// sortBySize orders modules by descending size in g.Sizes, keeping the
+// order of modules with the same size and putting unmeasured ones last.
+func sortBySize(g *Graph, modules []string) {
+	slices.SortStableFunc(modules, func(a, b string) int {
+		sa, oka := g.Sizes[a]
+		sb, okb := g.Sizes[b]
+		if oka != okb {
+			if oka {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(sb, sa)
+	})
+}
+
+// writeSizeSummary appends the total size of the measured modules to buf,
+// if sizes were detected.
+func writeSizeSummary(buf *bytes.Buffer, g *Graph) {
+	if g.Sizes == nil {
+		return
+	}
+	var total int64
+	for _, size := range g.Sizes {
+		total += size
+	}
+	versioned := 0
+	for _, name := range g.Modules() {
+		if _, version := SplitModule(name); version != "" {
+			versioned++
+		}
+	}
+	fmt.Fprintf(buf, "\n%d module(s) take %s in the module cache\n", len(g.Sizes), FormatSize(total))
+	if missing := versioned - len(g.Sizes); missing > 0 {
+		fmt.Fprintf(buf, "%d module(s) are not in the module cache and were not measured\n", missing)
+	}
+}
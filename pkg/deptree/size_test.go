@@ -0,0 +1,91 @@
+package deptree
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectSizes(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app": {"example.com/small@v1.0.0", "example.com/big@v1.0.0", "example.com/fork@v1.0.0", "example.com/gone@v1.0.0"},
+		},
+		ModFile: &ModFile{Replace: []ModReplace{
+			{Old: ModVersion{Path: "example.com/fork"}, New: ModVersion{Path: "github.com/me/fork", Version: "v1.0.1"}},
+		}},
+	}
+	g.Root = BuildDependencyTree(g.Deps, "")
+
+	sizes := map[string]int64{
+		"example.com/small@v1.0.0":  100,
+		"example.com/big@v1.0.0":    3 << 20,
+		"github.com/me/fork@v1.0.1": 2048,
+	}
+	err := detectSizes(g, func(path, version string) (int64, error) {
+		size, ok := sizes[path+"@"+version]
+		if !ok {
+			return 0, fs.ErrNotExist
+		}
+		return size, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := g.Sizes["example.com/fork@v1.0.0"]; got != 2048 {
+		t.Errorf("Expected the replacement to be measured, got size %d", got)
+	}
+	if _, ok := g.Sizes["example.com/gone@v1.0.0"]; ok {
+		t.Error("Expected no size for a module missing from the cache")
+	}
+
+	out, err := exportRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"example.com/big@v1.0.0 (3.0 MiB)",
+		"example.com/fork@v1.0.0 (replaced by github.com/me/fork@v1.0.1) (2.0 KiB)",
+		"example.com/small@v1.0.0 (100 B)",
+		"example.com/app",
+		"example.com/gone@v1.0.0",
+		"",
+		"3 module(s) take 3.0 MiB in the module cache",
+		"1 module(s) are not in the module cache and were not measured",
+		"",
+	}, "\n")
+	if string(out) != want {
+		t.Errorf("Export output:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = treeRenderer{}.Render(g, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "example.com/small@v1.0.0 (100 B)") {
+		t.Errorf("Expected size annotation in tree:\n%s", out)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"a.go": 10, "sub/b.go": 32} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size, err := dirSize(dir)
+	if err != nil || size != 42 {
+		t.Errorf("dirSize = %d, %v, want 42", size, err)
+	}
+	if _, err := dirSize(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package deptree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// snapshotVersion is bumped whenever the snapshot layout changes
+// incompatibly.
+const snapshotVersion = 1
+
+// ErrNotSnapshot is returned by LoadSnapshot for JSON that was not written by
+// SaveSnapshot, such as the output of the json format.
+var ErrNotSnapshot = errors.New("not a deptree snapshot")
+
+// snapshot is the on-disk form of a Graph. Unlike the json format it keeps
+// everything needed to render and analyze the graph again: the tree with its
+// annotations, the go.mod files that classify edges, and all fetched
+// metadata.
+type snapshot struct {
+	Version           int                        `json:"deptreeSnapshot"`
+	Tree              *snapshotNode              `json:"tree"`
+	Deps              map[string][]string        `json:"deps"`
+	Descriptions      map[string]string          `json:"descriptions,omitempty"`
+	DescriptionErrors map[string]string          `json:"descriptionErrors,omitempty"`
+	Links             map[string]SourceLinks     `json:"links,omitempty"`
+	ModFile           *ModFile                   `json:"modFile,omitempty"`
+	RootModFile       *ModFile                   `json:"rootModFile,omitempty"`
+	ModFiles          map[string]*ModFile        `json:"modFiles,omitempty"`
+	Workspace         map[string]*ModFile        `json:"workspace,omitempty"`
+	Sums              GoSum                      `json:"sums,omitempty"`
+	Licenses          map[string]string          `json:"licenses,omitempty"`
+	LicenseErrors     map[string]string          `json:"licenseErrors,omitempty"`
+	ImportedBy        map[string]int             `json:"importedBy,omitempty"`
+	Vulnerabilities   map[string][]Vulnerability `json:"vulnerabilities,omitempty"`
+	Outdated          map[string]string          `json:"outdated,omitempty"`
+	OutdatedErrors    map[string]string          `json:"outdatedErrors,omitempty"`
+	Sizes             map[string]int64           `json:"sizes,omitempty"`
+	TestOnly          map[string]bool            `json:"testOnly,omitempty"`
+	RepoStatus        map[string]RepoStatus      `json:"repoStatus,omitempty"`
+	Scorecards        map[string]Scorecard       `json:"scorecards,omitempty"`
+	Partial           []string                   `json:"partial,omitempty"`
+	Pruned            []string                   `json:"pruned,omitempty"`
+	Legacy            string                     `json:"legacy,omitempty"`
+}
+
+type snapshotNode struct {
+	Name        string          `json:"name"`
+	Annotations []string        `json:"annotations,omitempty"`
+	Children    []*snapshotNode `json:"children,omitempty"`
+}
+
+// SaveSnapshot writes g to w so that LoadSnapshot can restore it later, e.g.
+// to render it in another format or diff against it without access to the
+// module or the network.
+func SaveSnapshot(w io.Writer, g *Graph) error {
+	s := snapshot{
+		Version:         snapshotVersion,
+		Tree:            newSnapshotNode(g.Root),
+		Deps:            g.Deps,
+		Descriptions:    g.Descriptions,
+		Links:           g.Links,
+		ModFile:         g.ModFile,
+		RootModFile:     g.RootModFile,
+		ModFiles:        g.ModFiles,
+		Workspace:       g.Workspace,
+		Sums:            g.Sums,
+		Licenses:        g.Licenses,
+		ImportedBy:      g.ImportedBy,
+		Vulnerabilities: g.VulnerabilityDetails,
+		Outdated:        g.Outdated,
+		Sizes:           g.Sizes,
+		TestOnly:        g.TestOnly,
+		RepoStatus:      g.RepoStatus,
+		Scorecards:      g.Scorecards,
+		Partial:         g.Partial,
+		Pruned:          g.Pruned,
+		Legacy:          g.Legacy,
+	}
+	s.DescriptionErrors = errorMessages(g.DescriptionErrors)
+	s.LicenseErrors = errorMessages(g.LicenseErrors)
+	s.OutdatedErrors = errorMessages(g.OutdatedErrors)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot reads a graph written by SaveSnapshot. Description errors are
+// restored by message only.
+func LoadSnapshot(r io.Reader) (*Graph, error) {
+	var s snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	switch {
+	case s.Version == 0:
+		return nil, ErrNotSnapshot
+	case s.Version > snapshotVersion:
+		return nil, fmt.Errorf("snapshot version %d is newer than this deptree supports (%d)", s.Version, snapshotVersion)
+	case s.Tree == nil:
+		return nil, fmt.Errorf("snapshot has no tree")
+	}
+
+	g := &Graph{
+		Root:         s.Tree.node(),
+		Deps:         s.Deps,
+		Descriptions: s.Descriptions,
+		Links:        s.Links,
+		ModFile:      s.ModFile,
+		RootModFile:  s.RootModFile,
+		ModFiles:     s.ModFiles,
+		Workspace:    s.Workspace,
+		Sums:         s.Sums,
+		Licenses:     s.Licenses,
+		ImportedBy:   s.ImportedBy,
+		Outdated:     s.Outdated,
+		Sizes:        s.Sizes,
+		TestOnly:     s.TestOnly,
+		RepoStatus:   s.RepoStatus,
+		Scorecards:   s.Scorecards,
+		Partial:      s.Partial,
+		Pruned:       s.Pruned,
+		Legacy:       s.Legacy,
+	}
+	if g.Deps == nil {
+		g.Deps = make(map[string][]string)
+	}
+	if g.Descriptions == nil {
+		g.Descriptions = make(map[string]string)
+	}
+	g.DescriptionErrors = messageErrors(s.DescriptionErrors)
+	g.LicenseErrors = messageErrors(s.LicenseErrors)
+	g.OutdatedErrors = messageErrors(s.OutdatedErrors)
+	if len(s.Vulnerabilities) > 0 {
+		g.VulnerabilityDetails = s.Vulnerabilities
+		g.Vulnerabilities = make(map[string][]string, len(s.Vulnerabilities))
+		for name, vulns := range s.Vulnerabilities {
+			for _, v := range vulns {
+				g.Vulnerabilities[name] = append(g.Vulnerabilities[name], v.ID)
+			}
+		}
+	}
+	g.syncDescriptions()
+	if g.Legacy != "" {
+		g.warnLegacy()
+	}
+	g.warnReplaceCollisions()
+
+	return g, nil
+}
+
+func newSnapshotNode(node *Node) *snapshotNode {
+	if node == nil {
+		return nil
+	}
+	s := &snapshotNode{Name: node.Name, Annotations: node.Annotations}
+	for _, name := range slices.Sorted(maps.Keys(node.Children)) {
+		s.Children = append(s.Children, newSnapshotNode(node.Children[name]))
+	}
+	return s
+}
+
+func (s *snapshotNode) node() *Node {
+	node := NewNode(s.Name)
+	node.Annotations = s.Annotations
+	for _, child := range s.Children {
+		node.Children[child.Name] = child.node()
+	}
+	return node
+}
+
+// errorMessages converts the errors of a metadata error map to the messages
+// a snapshot stores, or nil if there are none.
+func errorMessages(errs map[string]error) map[string]string {
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make(map[string]string, len(errs))
+	for name, err := range errs {
+		messages[name] = err.Error()
+	}
+	return messages
+}
+
+// messageErrors restores the errors stored by errorMessages.
+func messageErrors(messages map[string]string) map[string]error {
+	if len(messages) == 0 {
+		return nil
+	}
+	errs := make(map[string]error, len(messages))
+	for name, msg := range messages {
+		errs[name] = errors.New(msg)
+	}
+	return errs
+}
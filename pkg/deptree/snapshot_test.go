@@ -0,0 +1,79 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app":         {"github.com/a/lib@v1.2.0", "golang.org/x/text@v0.3.5"},
+			"github.com/a/lib@v1.2.0": {"golang.org/x/text@v0.3.5"},
+		},
+		Descriptions:      map[string]string{"github.com/a/lib@v1.2.0": "A library"},
+		DescriptionErrors: map[string]error{"golang.org/x/text@v0.3.5": errors.New("not found")},
+		ModFile: &ModFile{
+			Require: []ModRequire{{Path: "golang.org/x/text", Version: "v0.3.5", Indirect: true}},
+		},
+		Licenses:        map[string]string{"github.com/a/lib@v1.2.0": "MIT", "golang.org/x/text@v0.3.5": UnknownLicense},
+		LicenseErrors:   map[string]error{"golang.org/x/text@v0.3.5": errors.New("rate limited")},
+		Vulnerabilities: map[string][]string{"golang.org/x/text@v0.3.5": {"GO-2021-0113"}},
+		VulnerabilityDetails: map[string][]Vulnerability{
+			"golang.org/x/text@v0.3.5": {{ID: "GO-2021-0113", Severity: SeverityHigh, Score: 7.5, Fixed: "v0.3.7"}},
+		},
+		Pruned: []string{"github.com/a/lib@v1.1.0"},
+	}
+	g.RootModFile = g.ModFile
+	g.Root = NewNode("example.com/app")
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+	g.Root.Children["golang.org/x/text@v0.3.5"].Annotations = []string{"(v0.3.0 pruned)"}
+	g.syncDescriptions()
+
+	var buf bytes.Buffer
+	if err := SaveSnapshot(&buf, g); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+	loaded, err := LoadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+
+	for _, format := range []string{"tree", "json"} {
+		renderer, _ := LookupRenderer(format)
+		opts := RenderOptions{ShowDesc: true, ShowLicense: true}
+		want, _ := renderer.Render(g, opts)
+		got, err := renderer.Render(loaded, opts)
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s output differs after round trip:\ngot:\n%s\nwant:\n%s", format, got, want)
+		}
+	}
+
+	if !reflect.DeepEqual(loaded.Vulnerabilities, map[string][]string{"golang.org/x/text@v0.3.5": {"GO-2021-0113"}}) {
+		t.Errorf("Unexpected vulnerabilities: %v", loaded.Vulnerabilities)
+	}
+	if err := loaded.DescriptionErrors["golang.org/x/text@v0.3.5"]; err == nil || err.Error() != "not found" {
+		t.Errorf("Expected description error to be restored, got %v", err)
+	}
+	if err := loaded.LicenseErrors["golang.org/x/text@v0.3.5"]; err == nil || err.Error() != "rate limited" {
+		t.Errorf("Expected license error to be restored, got %v", err)
+	}
+}
+
+func TestLoadSnapshotRejectsJSONFormat(t *testing.T) {
+	_, err := LoadSnapshot(strings.NewReader(`{"root":"example.com/app","modules":[],"edges":[]}`))
+	if !errors.Is(err, ErrNotSnapshot) {
+		t.Errorf("Expected ErrNotSnapshot, got %v", err)
+	}
+
+	_, err = LoadSnapshot(strings.NewReader(`{"deptreeSnapshot":99,"tree":{"name":"x"}}`))
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected version error, got %v", err)
+	}
+}
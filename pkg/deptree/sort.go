@@ -0,0 +1,111 @@
+package deptree
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// Orders of the requirements below each module in the tree format, for
+// RenderOptions.Sort. Ties are broken by name.
+const (
+	// SortName orders requirements by name. It is the default.
+	SortName = "name"
+	// SortDepth puts the requirements with the longest chain of
+	// requirements below them first.
+	SortDepth = "depth"
+	// SortChildren puts the requirements with the most requirements of
+	// their own first.
+	SortChildren = "children"
+	// SortSize puts the requirements with the largest source first, as
+	// measured by DetectSizes; modules without a size come last.
+	SortSize = "size"
+	// SortVersion orders requirements by version, oldest first.
+	SortVersion = "version"
+)
+
+// SortOrders lists the values of RenderOptions.Sort.
+var SortOrders = []string{SortName, SortDepth, SortChildren, SortSize, SortVersion}
+
+// childSorter orders the requirements of a module as RenderOptions.Sort
+// selects.
+type childSorter struct {
+	by      string
+	deps    map[string][]string
+	sizes   map[string]int64
+	heights map[string]int
+}
+
+func newChildSorter(g *Graph, by string) *childSorter {
+	s := &childSorter{by: by, deps: g.Deps, sizes: g.Sizes}
+	if by == SortDepth {
+		s.heights = requirementHeights(g.Deps)
+	}
+	return s
+}
+
+// sort orders names, which must be sorted by name already.
+func (s *childSorter) sort(names []string) {
+	switch s.by {
+	case SortDepth:
+		slices.SortStableFunc(names, func(a, b string) int { return cmp.Compare(s.heights[b], s.heights[a]) })
+	case SortChildren:
+		slices.SortStableFunc(names, func(a, b string) int {
+			return cmp.Compare(len(uniqueChildren(s.deps, b)), len(uniqueChildren(s.deps, a)))
+		})
+	case SortSize:
+		slices.SortStableFunc(names, func(a, b string) int {
+			sizeA, okA := s.sizes[a]
+			sizeB, okB := s.sizes[b]
+			if okA != okB {
+				if okA {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(sizeB, sizeA)
+		})
+	case SortVersion:
+		slices.SortStableFunc(names, func(a, b string) int {
+			_, versionA := SplitModule(a)
+			_, versionB := SplitModule(b)
+			return CompareVersions(versionA, versionB)
+		})
+	}
+}
+
+// nodes returns the children of node in order.
+func (s *childSorter) nodes(node *Node) []*Node {
+	names := slices.Sorted(maps.Keys(node.Children))
+	s.sort(names)
+	children := make([]*Node, len(names))
+	for i, name := range names {
+		children[i] = node.Children[name]
+	}
+	return children
+}
+
+// requirementHeights maps every module in deps to the length of the
+// longest chain of requirements below it. A requirement leading back into
+// the chain counts as a leaf, and modules are visited by name, so graphs
+// with cycles get the same heights on every run.
+func requirementHeights(deps map[string][]string) map[string]int {
+	heights := make(map[string]int)
+	var height func(name string) int
+	height = func(name string) int {
+		if h, ok := heights[name]; ok {
+			return h
+		}
+		heights[name] = 0
+		h := 0
+		for _, child := range uniqueChildren(deps, name) {
+			h = max(h, 1+height(child))
+		}
+		heights[name] = h
+		return h
+	}
+	for _, name := range slices.Sorted(maps.Keys(deps)) {
+		height(name)
+	}
+	return heights
+}
@@ -0,0 +1,65 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTreeRendererSort(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"app":          {"a@v1.2.0", "b@v0.1.0", "c@v2.0.0", "d@v1.0.0"},
+			"b@v0.1.0":     {"x@v1.0.0"},
+			"c@v2.0.0":     {"x@v1.0.0", "y@v1.0.0"},
+			"x@v1.0.0":     {"deep@v1.0.0"},
+			"y@v1.0.0":     {},
+			"deep@v1.0.0":  {},
+			"a@v1.2.0":     {},
+			"d@v1.0.0":     {},
+			"loop@v1.0.0":  {"loop2@v1.0.0"},
+			"loop2@v1.0.0": {"loop@v1.0.0"},
+		},
+		Sizes: map[string]int64{"a@v1.2.0": 10, "c@v2.0.0": 5, "d@v1.0.0": 20},
+	}
+	g.expandRoot("app")
+
+	// topLevel returns the modules directly below the root, in order
+	topLevel := func(opts RenderOptions) []string {
+		out, err := treeRenderer{}.Render(g, opts)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		var names []string
+		for _, line := range strings.Split(string(out), "\n") {
+			for _, connector := range []string{"├── ", "└── "} {
+				if name, ok := strings.CutPrefix(line, connector); ok {
+					names = append(names, strings.Fields(name)[0])
+				}
+			}
+		}
+		return names
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"", []string{"a@v1.2.0", "b@v0.1.0", "c@v2.0.0", "d@v1.0.0"}},
+		{SortName, []string{"a@v1.2.0", "b@v0.1.0", "c@v2.0.0", "d@v1.0.0"}},
+		{SortDepth, []string{"b@v0.1.0", "c@v2.0.0", "a@v1.2.0", "d@v1.0.0"}},
+		{SortChildren, []string{"c@v2.0.0", "b@v0.1.0", "a@v1.2.0", "d@v1.0.0"}},
+		{SortSize, []string{"d@v1.0.0", "a@v1.2.0", "c@v2.0.0", "b@v0.1.0"}},
+		{SortVersion, []string{"b@v0.1.0", "d@v1.0.0", "a@v1.2.0", "c@v2.0.0"}},
+	}
+	for _, tt := range tests {
+		if got := topLevel(RenderOptions{Sort: tt.sort}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Sort %q: got %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	heights := requirementHeights(g.Deps)
+	if heights["app"] != 3 || heights["b@v0.1.0"] != 2 || heights["loop@v1.0.0"] != 1 {
+		t.Errorf("Unexpected heights %v", heights)
+	}
+}
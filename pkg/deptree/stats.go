@@ -0,0 +1,157 @@
+package deptree
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// maxLargestSubtrees bounds the subtrees listed by Stats.
+const maxLargestSubtrees = 5
+
+// Stats are aggregate metrics of a dependency graph.
+type Stats struct {
+	// Modules counts the module versions the root depends on.
+	Modules int
+	// Direct counts the modules the root module's go.mod requires directly
+	// (see DirectDependencies); the other modules are transitive.
+	Direct     int
+	Transitive int
+	// MaxDepth is the length of the longest shortest requirement chain from
+	// the root to any module.
+	MaxDepth int
+	// Hosts counts modules by the first element of their path, most
+	// common first.
+	Hosts []Count
+	// LargestSubtrees are the modules reaching the most other modules,
+	// largest first.
+	LargestSubtrees []Count
+	// MultipleVersions maps module paths present at more than one version
+	// to those versions in ascending order.
+	MultipleVersions map[string][]string
+}
+
+// Count is a name with a number of modules.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// ComputeStats computes the Stats of g. Toolchain entries are not counted.
+func ComputeStats(g *Graph) Stats {
+	var s Stats
+	if g.Root == nil {
+		return s
+	}
+
+	direct := g.DirectDependencies()
+	hosts := make(map[string]int)
+	var subtrees []Count
+
+	for _, name := range g.Reachable(g.Root.Name) {
+		if _, member := g.Workspace[name]; member {
+			continue
+		}
+		s.Modules++
+		if direct[name] {
+			s.Direct++
+		}
+		path, _ := SplitModule(name)
+		host, _, _ := strings.Cut(path, "/")
+		hosts[host]++
+		if n := len(g.Reachable(name)); n > 0 {
+			subtrees = append(subtrees, Count{name, n})
+		}
+	}
+	s.Transitive = s.Modules - s.Direct
+
+	for host, n := range hosts {
+		s.Hosts = append(s.Hosts, Count{host, n})
+	}
+	slices.SortFunc(s.Hosts, compareCounts)
+
+	slices.SortFunc(subtrees, compareCounts)
+	s.LargestSubtrees = subtrees[:min(len(subtrees), maxLargestSubtrees)]
+
+	s.MultipleVersions = make(map[string][]string)
+	for _, d := range Duplicates(g) {
+		for _, v := range d.Versions {
+			s.MultipleVersions[d.Path] = append(s.MultipleVersions[d.Path], v.Version)
+		}
+	}
+
+	s.MaxDepth = g.maxDepth()
+	return s
+}
+
+// compareCounts orders counts by descending count, then by name.
+func compareCounts(a, b Count) int {
+	return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Name, b.Name))
+}
+
+// maxDepth returns the largest number of requirement edges on the shortest
+// chain from the root to a module.
+func (g *Graph) maxDepth() int {
+	deepest := 0
+	for _, d := range g.depths() {
+		deepest = max(deepest, d)
+	}
+	return deepest
+}
+
+// depths maps the root and every module reachable from it to the number of
+// requirement edges on the shortest chain from the root to it.
+func (g *Graph) depths() map[string]int {
+	depth := map[string]int{g.Root.Name: 0}
+	queue := []string{g.Root.Name}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, to := range g.Deps[name] {
+			if _, seen := depth[to]; seen || IsToolchainDep(to) {
+				continue
+			}
+			depth[to] = depth[name] + 1
+			queue = append(queue, to)
+		}
+	}
+	return depth
+}
+
+// RenderStats formats the ComputeStats of g as an aligned plain-text report.
+func RenderStats(g *Graph) []byte {
+	s := ComputeStats(g)
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "Modules:            %d\n", s.Modules)
+	fmt.Fprintf(&buf, "  direct:           %d\n", s.Direct)
+	fmt.Fprintf(&buf, "  transitive:       %d\n", s.Transitive)
+	fmt.Fprintf(&buf, "Max depth:          %d\n", s.MaxDepth)
+	fmt.Fprintf(&buf, "Multiple versions:  %d\n", len(s.MultipleVersions))
+	for _, path := range slices.Sorted(maps.Keys(s.MultipleVersions)) {
+		fmt.Fprintf(&buf, "  %s: %s\n", path, strings.Join(s.MultipleVersions[path], ", "))
+	}
+
+	writeCounts(&buf, "Modules per host", s.Hosts)
+	writeCounts(&buf, "Largest subtrees (modules reached)", s.LargestSubtrees)
+
+	return buf.Bytes()
+}
+
+func writeCounts(buf *bytes.Buffer, title string, counts []Count) {
+	if len(counts) == 0 {
+		return
+	}
+	width := 0
+	for _, c := range counts {
+		width = max(width, len(c.Name))
+	}
+	fmt.Fprintf(buf, "\n%s:\n", title)
+	for _, c := range counts {
+		fmt.Fprintf(buf, "  %-*s  %d\n", width, c.Name, c.Count)
+	}
+}
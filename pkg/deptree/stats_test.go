@@ -0,0 +1,50 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app":       {"github.com/a/x@v1.0.0", "golang.org/x/text@v0.14.0", "go@1.21"},
+			"github.com/a/x@v1.0.0": {"github.com/b/y@v1.0.0", "golang.org/x/text@v0.3.0"},
+			"github.com/b/y@v1.0.0": {"golang.org/x/sys@v0.5.0"},
+		},
+		RootModFile: &ModFile{Require: []ModRequire{
+			{Path: "github.com/a/x", Version: "v1.0.0"},
+			{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true},
+		}},
+	}
+	g.Root = BuildDependencyTree(g.Deps, "")
+
+	s := ComputeStats(g)
+
+	if s.Modules != 5 || s.Direct != 1 || s.Transitive != 4 {
+		t.Errorf("Modules %d, direct %d, transitive %d; want 5, 1, 4", s.Modules, s.Direct, s.Transitive)
+	}
+	if s.MaxDepth != 3 {
+		t.Errorf("MaxDepth = %d, want 3", s.MaxDepth)
+	}
+	wantHosts := []Count{{"golang.org", 3}, {"github.com", 2}}
+	if !reflect.DeepEqual(s.Hosts, wantHosts) {
+		t.Errorf("Hosts = %v, want %v", s.Hosts, wantHosts)
+	}
+	wantSubtrees := []Count{{"github.com/a/x@v1.0.0", 3}, {"github.com/b/y@v1.0.0", 1}}
+	if !reflect.DeepEqual(s.LargestSubtrees, wantSubtrees) {
+		t.Errorf("LargestSubtrees = %v, want %v", s.LargestSubtrees, wantSubtrees)
+	}
+	wantMulti := map[string][]string{"golang.org/x/text": {"v0.3.0", "v0.14.0"}}
+	if !reflect.DeepEqual(s.MultipleVersions, wantMulti) {
+		t.Errorf("MultipleVersions = %v, want %v", s.MultipleVersions, wantMulti)
+	}
+
+	out := string(RenderStats(g))
+	for _, want := range []string{"Modules:            5\n", "  golang.org/x/text: v0.3.0, v0.14.0\n", "\nModules per host:\n  golang.org  3\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+}
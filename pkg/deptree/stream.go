@@ -0,0 +1,210 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// MaxTreeNodes is the number of nodes above which Load and LoadFromProxy do
+// not expand the dependency tree, leaving Graph.Root a bare node and
+// recording the size in Graph.Unexpanded.
+var MaxTreeNodes = 500000
+
+// TreeSize returns the number of nodes the tree rooted at root would have,
+// counted the way buildTree expands it but without allocating any nodes.
+func TreeSize(deps map[string][]string, root string) int {
+	return treeSizeToDepth(deps, root, 0)
+}
+
+// treeSizeToDepth is TreeSize counting only the nodes down to maxDepth
+// levels below the root, or all of them when maxDepth is not positive.
+func treeSizeToDepth(deps map[string][]string, root string, maxDepth int) int {
+	visited := map[string]bool{root: true}
+	return 1 + countExpansion(deps, root, visited, 0, maxDepth)
+}
+
+// countExpansion counts the nodes below name, which is at depth, marking
+// every module it expands as visited. Modules are expanded down to the
+// leaves, as buildTree does, but nodes deeper than a positive maxDepth are
+// not counted.
+func countExpansion(deps map[string][]string, name string, visited map[string]bool, depth, maxDepth int) int {
+	count := 0
+	for _, child := range uniqueChildren(deps, name) {
+		if maxDepth <= 0 || depth < maxDepth {
+			count++
+		}
+		if !visited[child] {
+			visited[child] = true
+			count += countExpansion(deps, child, visited, depth+1, maxDepth)
+		}
+	}
+	return count
+}
+
+// uniqueChildren returns the requirements of name sorted and without
+// duplicates.
+func uniqueChildren(deps map[string][]string, name string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(deps[name])))
+}
+
+// expandRoot sets g.Root to the tree rooted at name, unless the tree would
+// exceed MaxTreeNodes.
+func (g *Graph) expandRoot(name string) {
+	if size := TreeSize(g.Deps, name); size > MaxTreeNodes {
+		g.Root = NewNode(name)
+		g.Unexpanded = size
+		return
+	}
+	g.Root = NewNode(name)
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+}
+
+// Expand builds the tree below g.Root when loading left it unexpanded,
+// however large it is.
+func (g *Graph) Expand() {
+	if g.Unexpanded == 0 {
+		return
+	}
+	g.Root = NewNode(g.Root.Name)
+	buildTree(g.Root, g.Deps, make(map[string]bool))
+	g.syncDescriptions()
+	g.Unexpanded = 0
+}
+
+// streamWriter holds the state of one StreamTree call.
+type streamWriter struct {
+	w      *bufio.Writer
+	g      *Graph
+	opts   RenderOptions
+	name   func(string) string
+	direct map[string]bool
+	// directives holds the replace and exclude annotations.
+	directives map[string]string
+	painter    painter
+	// visited holds the modules expanded or counted, as buildTree
+	// expands them, written those whose requirements have been written,
+	// and path those from the root down to the one being written.
+	visited map[string]bool
+	written map[string]bool
+	path    map[string]bool
+	sorter  *childSorter
+}
+
+// StreamTree writes the tree format for g to w while traversing g.Deps from
+// g.Root, so memory stays bounded by the module count however large the
+// expanded tree is. As in the tree renderer, each module is expanded at its
+// first occurrence only, and later ones are marked "(see above)", unless
+// opts.ExpandAll is set. Labels come from the graph's metadata maps rather than
+// node annotations, so the output matches the tree renderer when g.Root is
+// unexpanded.
+func StreamTree(w io.Writer, g *Graph, opts RenderOptions) error {
+	s := &streamWriter{
+		w:          bufio.NewWriter(w),
+		g:          g,
+		opts:       opts,
+		name:       nameTrimmer(g, opts),
+		direct:     g.DirectDependencies(),
+		directives: g.directiveLabels(),
+		visited:    map[string]bool{g.Root.Name: true},
+		written:    map[string]bool{g.Root.Name: true},
+		path:       map[string]bool{g.Root.Name: true},
+	}
+	s.painter = newPainter(g, opts, s.direct)
+	s.sorter = newChildSorter(g, opts.Sort)
+
+	s.writeLine("", s.label(g.Root.Name), g.Root.Name)
+	s.writeChildren(g.Root.Name, "", 1)
+
+	var summary bytes.Buffer
+	if opts.ShowLicense {
+		writeLicenseSummary(&summary, g)
+	}
+	writeOutdatedSummary(&summary, g)
+	writeSizeSummary(&summary, g)
+	writeRepoStatusSummary(&summary, g)
+	s.w.Write(summary.Bytes())
+
+	return s.w.Flush()
+}
+
+func (s *streamWriter) writeChildren(name, prefix string, depth int) {
+	children := uniqueChildren(s.g.Deps, name)
+	s.sorter.sort(children)
+
+	for i, child := range children {
+		connector, childPrefix := "├── ", prefix+"│   "
+		if i == len(children)-1 {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+
+		label := s.label(child)
+		expand := len(s.g.Deps[child]) > 0
+		switch {
+		case !expand:
+		case s.written[child] && !s.opts.ExpandAll:
+			label += " " + seeAboveTag
+			expand = false
+		case s.path[child]:
+			label += " " + cycleTag
+			expand = false
+		case s.opts.MaxDepth > 0 && depth >= s.opts.MaxDepth:
+			s.visited[child] = true
+			label = fmt.Sprintf("%s (+%d more)", label, countExpansion(s.g.Deps, child, s.visited, 0, 0))
+			expand = false
+		}
+		s.writeLine(prefix+connector, label, child)
+
+		if expand {
+			s.visited[child] = true
+			s.written[child] = true
+			s.path[child] = true
+			s.writeChildren(child, childPrefix, depth+1)
+			delete(s.path, child)
+		}
+	}
+}
+
+func (s *streamWriter) writeLine(prefix, label, name string) {
+	if desc := s.g.Descriptions[name]; s.opts.ShowDesc && desc != "" {
+		fmt.Fprintf(s.w, "%s%s - %s\n", prefix, label, desc)
+	} else {
+		fmt.Fprintf(s.w, "%s%s\n", prefix, label)
+	}
+}
+
+// label mirrors treeWriter.label, deriving the annotations from g.
+func (s *streamWriter) label(name string) string {
+	parts := []string{s.name(name)}
+	if s.direct[name] {
+		parts = append(parts, directTag)
+	}
+	if label, ok := s.directives[name]; ok {
+		parts = append(parts, label)
+	}
+	if s.g.TestOnly[name] {
+		parts = append(parts, testTag)
+	}
+	if vulns := s.g.VulnerabilityDetails[name]; len(vulns) > 0 {
+		parts = append(parts, vulnerableLabel(vulns))
+	}
+	if latest, ok := s.g.Outdated[name]; ok {
+		parts = append(parts, outdatedLabel(latest))
+	}
+	if size, ok := s.g.Sizes[name]; ok {
+		parts = append(parts, sizeLabel(size))
+	}
+	if status, ok := s.g.RepoStatus[name]; ok {
+		parts = append(parts, repoStatusLabel(status))
+	}
+	if sc, ok := s.g.Scorecards[name]; ok {
+		parts = append(parts, scorecardLabel(sc))
+	}
+	if license, ok := s.g.Licenses[name]; ok && s.opts.ShowLicense {
+		parts = append(parts, "["+license+"]")
+	}
+	return s.painter.paint(name, strings.Join(parts, " "))
+}
@@ -0,0 +1,79 @@
+package deptree
+
+import (
+	"bytes"
+	"testing"
+)
+
+func streamTestGraph() *Graph {
+	return &Graph{
+		Deps: map[string][]string{
+			"example.com/app":      {"example.com/a@v1.0.0", "example.com/b@v1.0.0"},
+			"example.com/a@v1.0.0": {"example.com/c@v1.0.0", "example.com/d@v1.0.0"},
+			"example.com/b@v1.0.0": {"example.com/c@v1.0.0"},
+			"example.com/c@v1.0.0": {"example.com/d@v1.0.0", "example.com/d@v1.0.0"},
+		},
+		Descriptions: map[string]string{"example.com/b@v1.0.0": "B things"},
+		Outdated:     map[string]string{"example.com/c@v1.0.0": "v1.2.0"},
+	}
+}
+
+func TestTreeSize(t *testing.T) {
+	g := streamTestGraph()
+	root := BuildDependencyTree(g.Deps, "")
+	if got, want := TreeSize(g.Deps, "example.com/app"), 1+countDescendants(root); got != want {
+		t.Errorf("TreeSize = %d, want %d", got, want)
+	}
+}
+
+func TestStreamTreeMatchesTreeRenderer(t *testing.T) {
+	for _, opts := range []RenderOptions{
+		{},
+		{ShowDesc: true},
+		{MaxDepth: 1},
+		{TrimPrefix: TrimPrefixAuto},
+		{ExpandAll: true},
+		{Sort: SortDepth},
+		{Sort: SortChildren, MaxDepth: 1},
+	} {
+		g := streamTestGraph()
+		g.expandRoot("example.com/app")
+		g.syncDescriptions()
+		g.Walk(func(node *Node) {
+			if latest, ok := g.Outdated[node.Name]; ok {
+				node.Annotations = append(node.Annotations, outdatedLabel(latest))
+			}
+		})
+		want, err := treeRenderer{}.Render(g, opts)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		var got bytes.Buffer
+		if err := StreamTree(&got, g, opts); err != nil {
+			t.Fatalf("StreamTree failed: %v", err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("StreamTree(%+v) =\n%s\nwant\n%s", opts, got.String(), want)
+		}
+	}
+}
+
+func TestExpandRootGuard(t *testing.T) {
+	defer func(max int) { MaxTreeNodes = max }(MaxTreeNodes)
+	MaxTreeNodes = 3
+
+	g := streamTestGraph()
+	g.expandRoot("example.com/app")
+	if g.Unexpanded != TreeSize(g.Deps, "example.com/app") || len(g.Root.Children) != 0 {
+		t.Fatalf("Expected an unexpanded root, got Unexpanded %d and %d children", g.Unexpanded, len(g.Root.Children))
+	}
+
+	g.Expand()
+	if g.Unexpanded != 0 || len(g.Root.Children) != 2 {
+		t.Errorf("Expand left Unexpanded %d and %d children", g.Unexpanded, len(g.Root.Children))
+	}
+	if desc := g.Root.Children["example.com/b@v1.0.0"].Description; desc != "B things" {
+		t.Errorf("Expanded node description = %q, want %q", desc, "B things")
+	}
+}
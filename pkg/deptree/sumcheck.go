@@ -0,0 +1,87 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SumCheck is the result of CheckSums: go.sum lines the module graph needs
+// but go.sum lacks, and go.sum lines no module in the graph accounts for.
+// Both hold go.sum keys ("path@version" or "path@version/go.mod"), sorted.
+type SumCheck struct {
+	Missing []string
+	Extra   []string
+}
+
+// OK reports whether go.sum matches the module graph.
+func (c SumCheck) OK() bool {
+	return len(c.Missing) == 0 && len(c.Extra) == 0
+}
+
+// CheckSums compares g.Sums against the modules in g. The go command needs
+// the go.mod hash of every module in the graph to load it, so each module
+// without one is missing; replaced modules are checked under their
+// replacement, and modules replaced by a local directory need no entry. An
+// entry is extra when its module is not in the graph at all, which is what
+// 'go mod tidy' would drop.
+func CheckSums(g *Graph) SumCheck {
+	needed := make(map[string]bool)
+	for _, name := range g.Modules() {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		if g.ModFile != nil {
+			if rep, ok := g.ModFile.FindReplace(path, version); ok {
+				if rep.Version == "" {
+					continue
+				}
+				name = rep.String()
+			}
+		}
+		needed[name] = true
+	}
+
+	var check SumCheck
+	for _, name := range slices.Sorted(maps.Keys(needed)) {
+		if _, ok := g.Sums[name+"/go.mod"]; !ok {
+			check.Missing = append(check.Missing, name+"/go.mod")
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(g.Sums)) {
+		if !needed[strings.TrimSuffix(key, "/go.mod")] {
+			check.Extra = append(check.Extra, key)
+		}
+	}
+	return check
+}
+
+// RenderSumCheck formats the result of CheckSums.
+func RenderSumCheck(c SumCheck) []byte {
+	var buf bytes.Buffer
+	if c.OK() {
+		fmt.Fprintln(&buf, "go.sum matches the module graph")
+		return buf.Bytes()
+	}
+
+	if len(c.Missing) > 0 {
+		fmt.Fprintln(&buf, "Missing from go.sum:")
+		for _, key := range c.Missing {
+			fmt.Fprintf(&buf, "  %s\n", key)
+		}
+	}
+	if len(c.Extra) > 0 {
+		if len(c.Missing) > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintln(&buf, "Not in the module graph:")
+		for _, key := range c.Extra {
+			fmt.Fprintf(&buf, "  %s\n", key)
+		}
+	}
+	fmt.Fprintf(&buf, "\n%d missing, %d extra go.sum entry(ies); run 'go mod tidy' to fix\n", len(c.Missing), len(c.Extra))
+	return buf.Bytes()
+}
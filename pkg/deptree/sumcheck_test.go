@@ -0,0 +1,52 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckSums(t *testing.T) {
+	g := &Graph{
+		Deps: map[string][]string{
+			"example.com/app":          {"go@1.21", "golang.org/x/text@v0.3.5", "example.com/fork@v1.0.0", "example.com/local@v1.0.0"},
+			"golang.org/x/text@v0.3.5": {"golang.org/x/tools@v0.1.0"},
+		},
+		ModFile: &ModFile{Replace: []ModReplace{
+			{Old: ModVersion{Path: "example.com/fork"}, New: ModVersion{Path: "github.com/me/fork", Version: "v1.0.1"}},
+			{Old: ModVersion{Path: "example.com/local"}, New: ModVersion{Path: "../local"}},
+		}},
+		Sums: GoSum{
+			"golang.org/x/text@v0.3.5":           "h1:a",
+			"golang.org/x/text@v0.3.5/go.mod":    "h1:b",
+			"github.com/me/fork@v1.0.1/go.mod":   "h1:c",
+			"golang.org/x/text@v0.3.0/go.mod":    "h1:d",
+			"github.com/stale/dep@v1.2.3":        "h1:e",
+			"github.com/stale/dep@v1.2.3/go.mod": "h1:f",
+		},
+	}
+
+	want := SumCheck{
+		Missing: []string{"golang.org/x/tools@v0.1.0/go.mod"},
+		Extra:   []string{"github.com/stale/dep@v1.2.3", "github.com/stale/dep@v1.2.3/go.mod", "golang.org/x/text@v0.3.0/go.mod"},
+	}
+	got := CheckSums(g)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CheckSums = %+v, want %+v", got, want)
+	}
+
+	out := string(RenderSumCheck(got))
+	for _, s := range []string{
+		"Missing from go.sum:\n  golang.org/x/tools@v0.1.0/go.mod\n",
+		"Not in the module graph:\n  github.com/stale/dep@v1.2.3\n",
+		"1 missing, 3 extra go.sum entry(ies)",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Expected %q in:\n%s", s, out)
+		}
+	}
+
+	if out := string(RenderSumCheck(SumCheck{})); out != "go.sum matches the module graph\n" {
+		t.Errorf("RenderSumCheck(OK) = %q", out)
+	}
+}
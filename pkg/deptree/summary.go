@@ -0,0 +1,76 @@
+package deptree
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// TreeNodes returns the number of nodes the tree of g prints down to
+// maxDepth levels below the root, or in full when maxDepth is not
+// positive. An unexpanded tree is counted from g.Deps without building it.
+func (g *Graph) TreeNodes(maxDepth int) int {
+	if g.Root == nil {
+		return 0
+	}
+	if g.Unexpanded > 0 {
+		return treeSizeToDepth(g.Deps, g.Root.Name, maxDepth)
+	}
+	nodes := 0
+	g.WalkTree(WalkDFS, maxDepth, func(TreeVisit) { nodes++ })
+	return nodes
+}
+
+// subtree is one direct dependency of the root in a tree summary.
+type subtree struct {
+	name  string
+	nodes int
+}
+
+// RenderTreeSummary summarizes a tree too large to print in full: its
+// size, and the direct dependencies of the root with the largest subtrees,
+// at most top of them, each with the number of nodes below it. In an
+// unexpanded tree, each subtree is counted as if it were the only one, so
+// modules shared between them count in each.
+func RenderTreeSummary(g *Graph, top int) []byte {
+	var buf bytes.Buffer
+	if g.Root == nil {
+		return buf.Bytes()
+	}
+
+	var subtrees []subtree
+	if g.Unexpanded > 0 {
+		for _, child := range uniqueChildren(g.Deps, g.Root.Name) {
+			subtrees = append(subtrees, subtree{child, TreeSize(g.Deps, child) - 1})
+		}
+	} else {
+		for _, child := range g.Root.Children {
+			nodes := -1
+			(&Graph{Root: child}).Walk(func(*Node) { nodes++ })
+			subtrees = append(subtrees, subtree{child.Name, nodes})
+		}
+	}
+	slices.SortFunc(subtrees, func(a, b subtree) int {
+		return cmp.Or(cmp.Compare(b.nodes, a.nodes), cmp.Compare(a.name, b.name))
+	})
+
+	fmt.Fprintf(&buf, "%s: %d nodes in the dependency tree, %d module(s)\n", g.Root.Name, g.TreeNodes(0), len(g.Modules()))
+	if len(subtrees) == 0 {
+		return buf.Bytes()
+	}
+	fmt.Fprintf(&buf, "\nLargest subtrees of %d direct dependencies:\n", len(subtrees))
+	shown := subtrees[:min(len(subtrees), top)]
+	width := len(fmt.Sprint(shown[0].nodes))
+	for _, s := range shown {
+		fmt.Fprintf(&buf, "  %*d  %s\n", width, s.nodes, s.name)
+	}
+	if rest := subtrees[len(shown):]; len(rest) > 0 {
+		nodes := 0
+		for _, s := range rest {
+			nodes += s.nodes
+		}
+		fmt.Fprintf(&buf, "  (+%d more with %d nodes below them)\n", len(rest), nodes)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,49 @@
+package deptree
+
+import "testing"
+
+func TestTreeNodes(t *testing.T) {
+	defer func(max int) { MaxTreeNodes = max }(MaxTreeNodes)
+
+	for _, max := range []int{500000, 3} {
+		MaxTreeNodes = max
+		g := streamTestGraph()
+		g.expandRoot("example.com/app")
+		if got := g.TreeNodes(0); got != 7 {
+			t.Errorf("MaxTreeNodes %d: TreeNodes(0) = %d, want 7", max, got)
+		}
+		if got := g.TreeNodes(1); got != 3 {
+			t.Errorf("MaxTreeNodes %d: TreeNodes(1) = %d, want 3", max, got)
+		}
+	}
+}
+
+func TestRenderTreeSummary(t *testing.T) {
+	defer func(max int) { MaxTreeNodes = max }(MaxTreeNodes)
+
+	g := streamTestGraph()
+	g.expandRoot("example.com/app")
+	want := `example.com/app: 7 nodes in the dependency tree, 5 module(s)
+
+Largest subtrees of 2 direct dependencies:
+  3  example.com/a@v1.0.0
+  (+1 more with 1 nodes below them)
+`
+	if got := string(RenderTreeSummary(g, 1)); got != want {
+		t.Errorf("RenderTreeSummary =\n%s\nwant\n%s", got, want)
+	}
+
+	// Unexpanded subtrees are counted on their own, so c counts below b too
+	MaxTreeNodes = 3
+	g = streamTestGraph()
+	g.expandRoot("example.com/app")
+	want = `example.com/app: 7 nodes in the dependency tree, 5 module(s)
+
+Largest subtrees of 2 direct dependencies:
+  3  example.com/a@v1.0.0
+  2  example.com/b@v1.0.0
+`
+	if got := string(RenderTreeSummary(g, 10)); got != want {
+		t.Errorf("RenderTreeSummary of an unexpanded tree =\n%s\nwant\n%s", got, want)
+	}
+}
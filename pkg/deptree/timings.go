@@ -0,0 +1,48 @@
+package deptree
+
+import (
+	"sync"
+	"time"
+)
+
+// Phase is the wall-clock duration of one step of an analysis.
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Timings records how long each phase of an analysis took, in the order the
+// phases finished. A nil *Timings records nothing.
+type Timings struct {
+	mu     sync.Mutex
+	phases []Phase
+}
+
+// Track starts timing the named phase; call the returned function when the
+// phase ends.
+func (t *Timings) Track(name string) func() {
+	start := time.Now()
+	return func() {
+		t.Add(name, time.Since(start))
+	}
+}
+
+// Add records a phase that took d.
+func (t *Timings) Add(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phases = append(t.phases, Phase{Name: name, Duration: d})
+}
+
+// Phases returns the recorded phases in order.
+func (t *Timings) Phases() []Phase {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Phase(nil), t.phases...)
+}
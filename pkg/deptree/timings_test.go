@@ -0,0 +1,27 @@
+package deptree
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimings(t *testing.T) {
+	timings := &Timings{}
+	timings.Add("first", time.Second)
+	timings.Track("second")()
+
+	phases := timings.Phases()
+	if len(phases) != 2 || phases[0].Name != "first" || phases[1].Name != "second" {
+		t.Fatalf("Unexpected phases: %+v", phases)
+	}
+	if phases[0].Duration != time.Second {
+		t.Errorf("Expected first phase to take 1s, got %s", phases[0].Duration)
+	}
+
+	// A nil *Timings records nothing
+	var none *Timings
+	none.Track("ignored")()
+	if len(none.Phases()) != 0 {
+		t.Error("Expected nil Timings to record nothing")
+	}
+}
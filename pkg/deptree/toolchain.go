@@ -0,0 +1,126 @@
+package deptree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ToolchainRequirement is a module whose go.mod asks for a newer Go than
+// the local toolchain provides.
+type ToolchainRequirement struct {
+	// Module is path@version for dependencies and the bare path for the
+	// main module.
+	Module string
+	// Go and Toolchain are the module's go and toolchain directives.
+	Go        string
+	Toolchain string
+}
+
+func (r ToolchainRequirement) String() string {
+	s := fmt.Sprintf("%s requires go %s", r.Module, r.Go)
+	if r.Toolchain != "" {
+		s += fmt.Sprintf(" (toolchain %s)", r.Toolchain)
+	}
+	return s
+}
+
+// LocalGoVersion returns the version of the go command on PATH, such as
+// "go1.22.1", or "" for a development build, which has no comparable
+// version.
+func LocalGoVersion() (string, error) {
+	output, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run 'go env GOVERSION': %w", err)
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(output)), " ")
+	if !strings.HasPrefix(version, "go") {
+		return "", nil
+	}
+	return version, nil
+}
+
+// CheckToolchains reads the go.mod of the module in dir and the go.mod
+// files of its requirements that are in the module cache, and returns those
+// whose go or toolchain directive is newer than local, a version as
+// returned by LocalGoVersion. 'go mod graph' fails or switches to a newer
+// toolchain for such modules, depending on GOTOOLCHAIN, so the check lets
+// that be reported before loading the graph. Requirements not yet
+// downloaded are not checked.
+func CheckToolchains(dir, local string) ([]ToolchainRequirement, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	mf, err := ParseModFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+	return toolchainRequirements(mf, local, readCachedModFileDirect), nil
+}
+
+// readCachedModFileDirect parses the go.mod of path@version from the module
+// cache without the go command, which might refuse to run for it.
+func readCachedModFileDirect(path, version string) (*ModFile, error) {
+	file, err := ModuleCacheFile(path, version, "mod")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseModFile(data)
+}
+
+// toolchainRequirements returns main and those of its requirements, as
+// read by readMod, that need a Go newer than local.
+func toolchainRequirements(main *ModFile, local string, readMod func(path, version string) (*ModFile, error)) []ToolchainRequirement {
+	var newer []ToolchainRequirement
+	check := func(module string, mf *ModFile) {
+		if CompareGoVersions(mf.Go, local) > 0 || CompareGoVersions(mf.Toolchain.Name, local) > 0 {
+			newer = append(newer, ToolchainRequirement{Module: module, Go: mf.Go, Toolchain: mf.Toolchain.Name})
+		}
+	}
+
+	check(main.Module.Path, main)
+	for _, req := range main.Require {
+		mf, err := readMod(req.Path, req.Version)
+		if err != nil {
+			continue
+		}
+		check(req.Path+"@"+req.Version, mf)
+	}
+	return newer
+}
+
+// CompareGoVersions compares Go release versions such as "1.21",
+// "1.21.3", "go1.22rc1" or "go1.22.1", returning -1, 0 or +1. Release
+// candidates and betas sort before the release, and an empty version
+// before any other.
+func CompareGoVersions(a, b string) int {
+	return CompareVersions(goSemver(a), goSemver(b))
+}
+
+// goSemver converts a Go version to a semantic version: "go1.22rc1"
+// becomes "v1.22.0-rc1" and "1.21" becomes "v1.21.0".
+func goSemver(v string) string {
+	v = strings.TrimPrefix(v, "go")
+	if v == "" {
+		return ""
+	}
+	core, pre := v, ""
+	if i := strings.IndexFunc(v, unicode.IsLetter); i >= 0 {
+		core, pre = v[:i], v[i:]
+	}
+	for strings.Count(core, ".") < 2 {
+		core += ".0"
+	}
+	if pre != "" {
+		return "v" + core + "-" + pre
+	}
+	return "v" + core
+}
@@ -0,0 +1,56 @@
+package deptree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.21", "go1.21.0", 0},
+		{"1.22", "go1.21.5", 1},
+		{"go1.22rc1", "go1.22.0", -1},
+		{"go1.22.1", "go1.22rc1", 1},
+		{"1.21.3", "1.21.10", -1},
+		{"", "go1.21.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareGoVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareGoVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestToolchainRequirements(t *testing.T) {
+	main, err := ParseModFile([]byte("module example.com/app\n\ngo 1.21\n\nrequire (\n\texample.com/new v1.0.0\n\texample.com/old v1.0.0\n\texample.com/missing v1.0.0\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mods := map[string]string{
+		"example.com/new@v1.0.0": "module example.com/new\n\ngo 1.21\n\ntoolchain go1.23.2\n",
+		"example.com/old@v1.0.0": "module example.com/old\n\ngo 1.18\n",
+	}
+	readMod := func(path, version string) (*ModFile, error) {
+		data, ok := mods[path+"@"+version]
+		if !ok {
+			return nil, fmt.Errorf("%s@%s not in the module cache", path, version)
+		}
+		return ParseModFile([]byte(data))
+	}
+
+	newer := toolchainRequirements(main, "go1.22.4", readMod)
+	if len(newer) != 1 {
+		t.Fatalf("Expected one requirement, got %v", newer)
+	}
+	if got, want := newer[0].String(), "example.com/new@v1.0.0 requires go 1.21 (toolchain go1.23.2)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	newer = toolchainRequirements(main, "go1.20.1", readMod)
+	if len(newer) != 2 || newer[0].Module != "example.com/app" {
+		t.Errorf("Expected the main module and example.com/new, got %v", newer)
+	}
+}
@@ -0,0 +1,84 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// UnusedRequirements returns the require directives of the root module's
+// go.mod whose module provides no package that its packages, their tests or
+// its tools import when built with cfg, as reported by 'go list -deps' in
+// dir. They are candidates for 'go mod tidy' or removal: tidy drops such
+// requirements unless a dependency needs them to select its version, which
+// 'go list' cannot tell.
+func (g *Graph) UnusedRequirements(dir string, cfg BuildConfig) ([]ModRequire, error) {
+	if g.Workspace != nil {
+		return nil, fmt.Errorf("unused requirements are found per module; select a workspace module with -module")
+	}
+	mf := g.RootModFile
+	if g.Root == nil || mf == nil {
+		return nil, nil
+	}
+
+	patterns := g.mainPackages()
+	for _, tool := range mf.Tool {
+		patterns = append(patterns, tool.Path)
+	}
+	output, err := g.listPackages(dir, cfg, true, patterns)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := decodeListedPackages(bytes.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	for _, pkg := range packages {
+		used[pkg.Module.Path] = true
+	}
+
+	var unused []ModRequire
+	for _, req := range mf.Require {
+		if !used[req.Path] {
+			unused = append(unused, req)
+		}
+	}
+	return unused, nil
+}
+
+// RenderUnused lists the requirements from UnusedRequirements as candidates
+// for 'go mod tidy', and names cfg, the build configuration they were found
+// with: a requirement only imported on another platform or with other build
+// tags is listed too, so none of them should be removed unchecked.
+func RenderUnused(unused []ModRequire, cfg BuildConfig) []byte {
+	var buf bytes.Buffer
+	if len(unused) == 0 {
+		fmt.Fprintf(&buf, "No unused requirements for %s\n", cfg)
+		return buf.Bytes()
+	}
+
+	var direct, indirect []string
+	for _, req := range unused {
+		line := "  " + req.Path + " " + req.Version
+		if req.Indirect {
+			indirect = append(indirect, line)
+		} else {
+			direct = append(direct, line)
+		}
+	}
+	if len(direct) > 0 {
+		fmt.Fprintln(&buf, "Required but not imported (candidate for 'go mod tidy'):")
+		fmt.Fprintln(&buf, strings.Join(direct, "\n"))
+	}
+	if len(indirect) > 0 {
+		if len(direct) > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintln(&buf, "Indirect requirements no package needs (candidate for 'go mod tidy'):")
+		fmt.Fprintln(&buf, strings.Join(indirect, "\n"))
+	}
+	fmt.Fprintf(&buf, "\n%d unused requirement(s) for %s\n", len(unused), cfg)
+	fmt.Fprintln(&buf, "Imports on other platforms or behind other build tags are not seen; check them with -GOOS, -GOARCH and -tags")
+	return buf.Bytes()
+}
@@ -0,0 +1,69 @@
+package deptree
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnusedRequirements(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app")
+	writeTestFile(t, filepath.Join(app, "go.mod"), `module example.com/app
+
+go 1.21
+
+require (
+	example.com/used v0.1.0
+	example.com/unused v0.1.0
+	example.com/testonly v0.1.0
+	example.com/stale v0.1.0 // indirect
+)
+
+replace (
+	example.com/used => ../used
+	example.com/unused => ../unused
+	example.com/testonly => ../testonly
+	example.com/stale => ../stale
+)
+`)
+	writeTestFile(t, filepath.Join(app, "main.go"), "package main\n\nimport _ \"example.com/used\"\n\nfunc main() {}\n")
+	writeTestFile(t, filepath.Join(app, "main_test.go"), "package main\n\nimport _ \"example.com/testonly\"\n")
+	for _, name := range []string{"used", "unused", "testonly", "stale"} {
+		writeTestFile(t, filepath.Join(dir, name, "go.mod"), "module example.com/"+name+"\n\ngo 1.21\n")
+		writeTestFile(t, filepath.Join(dir, name, name+".go"), "package "+name+"\n")
+	}
+
+	mf, err := ReadModFile(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Graph{Root: NewNode("example.com/app"), ModFile: mf, RootModFile: mf}
+
+	unused, err := g.UnusedRequirements(app, BuildConfig{})
+	if err != nil {
+		t.Fatalf("UnusedRequirements failed: %v", err)
+	}
+	want := []ModRequire{
+		{Path: "example.com/unused", Version: "v0.1.0"},
+		{Path: "example.com/stale", Version: "v0.1.0", Indirect: true},
+	}
+	if !reflect.DeepEqual(unused, want) {
+		t.Errorf("UnusedRequirements() = %+v, want %+v", unused, want)
+	}
+
+	out := string(RenderUnused(unused, BuildConfig{Tags: []string{"integration"}, GOOS: "linux", GOARCH: "amd64"}))
+	for _, line := range []string{
+		"Required but not imported (candidate for 'go mod tidy'):\n  example.com/unused v0.1.0\n",
+		"Indirect requirements no package needs (candidate for 'go mod tidy'):\n  example.com/stale v0.1.0\n",
+		"2 unused requirement(s) for linux/amd64 with tags integration\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in:\n%s", line, out)
+		}
+	}
+	if out := string(RenderUnused(nil, BuildConfig{GOOS: "windows", GOARCH: "arm64"})); out != "No unused requirements for windows/arm64 without build tags\n" {
+		t.Errorf("RenderUnused(nil) = %q", out)
+	}
+}
@@ -0,0 +1,187 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// VendoredModule is a module listed in vendor/modules.txt.
+type VendoredModule struct {
+	Path    string
+	Version string
+	// Replace is the replacement after "=>", if any, e.g. "../local".
+	Replace string
+	// Explicit is set for modules the main go.mod requires.
+	Explicit bool
+	// Packages are the vendored packages of the module.
+	Packages []string
+}
+
+// ReadVendorModules parses dir/vendor/modules.txt as written by
+// 'go mod vendor'. Lines for replacements no vendored module uses, which
+// have no version, are skipped.
+func ReadVendorModules(dir string) ([]VendoredModule, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "vendor", "modules.txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no vendor/modules.txt in %s; run 'go mod vendor' first", dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendor/modules.txt: %w", err)
+	}
+	return parseVendorModules(data), nil
+}
+
+func parseVendorModules(data []byte) []VendoredModule {
+	var modules []VendoredModule
+	var current *VendoredModule
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "## "):
+			// "## explicit; go 1.21" annotates the module above
+			if current != nil {
+				for _, note := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+					if strings.TrimSpace(note) == "explicit" {
+						current.Explicit = true
+					}
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			// "# path version [=> replacement [version]]"
+			current = nil
+			module, replace, _ := strings.Cut(strings.TrimPrefix(line, "# "), "=>")
+			fields := strings.Fields(module)
+			if len(fields) != 2 {
+				continue
+			}
+			modules = append(modules, VendoredModule{Path: fields[0], Version: fields[1], Replace: strings.TrimSpace(replace)})
+			current = &modules[len(modules)-1]
+		case line != "" && current != nil:
+			current.Packages = append(current.Packages, line)
+		}
+	}
+	return modules
+}
+
+// VendorMismatch is a module vendored at another version than the build
+// selects.
+type VendorMismatch struct {
+	Path     string
+	Vendored string
+	Selected string
+}
+
+// VendorCheck is the result of CheckVendor. Missing and Extra hold
+// path@version names, sorted.
+type VendorCheck struct {
+	Missing    []string
+	Extra      []string
+	Mismatched []VendorMismatch
+}
+
+// OK reports whether vendor/modules.txt matches the build.
+func (c VendorCheck) OK() bool {
+	return len(c.Missing) == 0 && len(c.Extra) == 0 && len(c.Mismatched) == 0
+}
+
+// VendorNeeds returns the modules 'go mod vendor' would vendor for the
+// project in dir: those providing packages to the main module's packages
+// and their tests, as reported by 'go list' while ignoring the vendor
+// directory, and the modules the main go.mod requires, at the version g
+// selects.
+func (g *Graph) VendorNeeds(dir string) (map[string]bool, error) {
+	output, err := g.listPackages(dir, BuildConfig{}, true, g.mainPackages(), "-mod=readonly")
+	if err != nil {
+		return nil, err
+	}
+	needed, err := parseListedModules(bytes.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+
+	if g.ModFile != nil {
+		selected := highestVersions(g)
+		for _, req := range g.ModFile.Require {
+			if version, ok := selected[req.Path]; ok {
+				needed[req.Path+"@"+version] = true
+			}
+		}
+	}
+	return needed, nil
+}
+
+// CheckVendor compares the vendored modules with the modules the build
+// needs, as returned by VendorNeeds. Needed modules without a version, the
+// main modules, are ignored.
+func CheckVendor(vendored []VendoredModule, needed map[string]bool) VendorCheck {
+	selected := make(map[string]string)
+	for name := range needed {
+		if path, version := SplitModule(name); version != "" {
+			selected[path] = version
+		}
+	}
+
+	var check VendorCheck
+	seen := make(map[string]bool)
+	for _, m := range vendored {
+		seen[m.Path] = true
+		version, ok := selected[m.Path]
+		switch {
+		case !ok:
+			check.Extra = append(check.Extra, m.Path+"@"+m.Version)
+		case version != m.Version:
+			check.Mismatched = append(check.Mismatched, VendorMismatch{Path: m.Path, Vendored: m.Version, Selected: version})
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(selected)) {
+		if !seen[path] {
+			check.Missing = append(check.Missing, path+"@"+selected[path])
+		}
+	}
+
+	slices.Sort(check.Extra)
+	slices.SortFunc(check.Mismatched, func(a, b VendorMismatch) int { return strings.Compare(a.Path, b.Path) })
+	return check
+}
+
+// RenderVendorCheck formats the result of CheckVendor.
+func RenderVendorCheck(c VendorCheck) []byte {
+	var buf bytes.Buffer
+	if c.OK() {
+		fmt.Fprintln(&buf, "vendor/modules.txt matches the build")
+		return buf.Bytes()
+	}
+
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintln(&buf, title)
+		for _, line := range lines {
+			fmt.Fprintf(&buf, "  %s\n", line)
+		}
+	}
+	section("Missing from vendor:", c.Missing)
+	section("Vendored but not needed:", c.Extra)
+	var mismatched []string
+	for _, m := range c.Mismatched {
+		mismatched = append(mismatched, fmt.Sprintf("%s: vendored %s, build selects %s", m.Path, m.Vendored, m.Selected))
+	}
+	section("Version mismatches:", mismatched)
+
+	fmt.Fprintf(&buf, "\n%d missing, %d extra, %d mismatched module(s); run 'go mod vendor' to fix\n", len(c.Missing), len(c.Extra), len(c.Mismatched))
+	return buf.Bytes()
+}
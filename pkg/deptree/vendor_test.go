@@ -0,0 +1,68 @@
+package deptree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVendorModules(t *testing.T) {
+	data := []byte(`# github.com/spf13/cobra v1.8.0
+## explicit; go 1.15
+github.com/spf13/cobra
+# github.com/spf13/pflag v1.0.5
+github.com/spf13/pflag
+# example.com/local v1.0.0 => ../local
+## explicit
+example.com/local/util
+# example.com/unused => ../unused
+`)
+	want := []VendoredModule{
+		{Path: "github.com/spf13/cobra", Version: "v1.8.0", Explicit: true, Packages: []string{"github.com/spf13/cobra"}},
+		{Path: "github.com/spf13/pflag", Version: "v1.0.5", Packages: []string{"github.com/spf13/pflag"}},
+		{Path: "example.com/local", Version: "v1.0.0", Replace: "../local", Explicit: true, Packages: []string{"example.com/local/util"}},
+	}
+	if got := parseVendorModules(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseVendorModules = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckVendor(t *testing.T) {
+	vendored := []VendoredModule{
+		{Path: "github.com/spf13/cobra", Version: "v1.7.0"},
+		{Path: "github.com/spf13/pflag", Version: "v1.0.5"},
+		{Path: "gopkg.in/yaml.v2", Version: "v2.4.0"},
+	}
+	needed := map[string]bool{
+		"example.com/app":               true,
+		"github.com/spf13/cobra@v1.8.0": true,
+		"github.com/spf13/pflag@v1.0.5": true,
+		"gopkg.in/yaml.v3@v3.0.1":       true,
+	}
+
+	check := CheckVendor(vendored, needed)
+	want := VendorCheck{
+		Missing:    []string{"gopkg.in/yaml.v3@v3.0.1"},
+		Extra:      []string{"gopkg.in/yaml.v2@v2.4.0"},
+		Mismatched: []VendorMismatch{{Path: "github.com/spf13/cobra", Vendored: "v1.7.0", Selected: "v1.8.0"}},
+	}
+	if !reflect.DeepEqual(check, want) {
+		t.Fatalf("CheckVendor = %+v, want %+v", check, want)
+	}
+
+	out := string(RenderVendorCheck(check))
+	for _, s := range []string{
+		"Missing from vendor:\n  gopkg.in/yaml.v3@v3.0.1\n",
+		"Vendored but not needed:\n  gopkg.in/yaml.v2@v2.4.0\n",
+		"github.com/spf13/cobra: vendored v1.7.0, build selects v1.8.0",
+		"1 missing, 1 extra, 1 mismatched module(s)",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Expected %q in:\n%s", s, out)
+		}
+	}
+
+	if !CheckVendor(vendored[1:2], map[string]bool{"github.com/spf13/pflag@v1.0.5": true}).OK() {
+		t.Error("Expected a matching vendor directory to pass")
+	}
+}
@@ -0,0 +1,314 @@
+package deptree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// sumdbURL is the base URL of sum.golang.org, replaced in tests.
+var sumdbURL = "https://sum.golang.org"
+
+// sumdbWorkers bounds concurrent checksum database lookups.
+const sumdbWorkers = 8
+
+// Sources VerifySums compares go.sum hashes with.
+const (
+	SourceSumDB = "checksum database"
+	SourceCache = "module cache"
+)
+
+// SumMismatch is a go.sum hash that differs from the hash a source has for
+// the same module zip or go.mod file.
+type SumMismatch struct {
+	// Key is the go.sum key, "path@version" or "path@version/go.mod".
+	Key    string
+	GoSum  string
+	Source string
+	Hash   string
+}
+
+// SumVerification is the result of VerifySums.
+type SumVerification struct {
+	// Missing are the go.sum keys the module graph needs but go.sum lacks,
+	// as reported by CheckSums.
+	Missing []string
+	// Mismatched are the go.sum hashes a source disagrees with, sorted by
+	// key.
+	Mismatched []SumMismatch
+	// Unverified maps the module versions not checked against the
+	// checksum database to the reason, such as GONOSUMDB.
+	Unverified map[string]string
+	// Verified is the number of go.sum hashes the checksum database
+	// confirmed.
+	Verified int
+}
+
+// OK reports whether no go.sum entry is missing or mismatched and the
+// checksum database confirmed at least one hash, unless there was nothing
+// to check. A go.sum none of whose modules could be looked up, e.g. with
+// GOSUMDB=off or the database unreachable, has not been verified.
+func (v SumVerification) OK() bool {
+	return len(v.Missing) == 0 && len(v.Mismatched) == 0 && (v.Verified > 0 || len(v.Unverified) == 0)
+}
+
+// VerifySums cross-checks the go.sum hashes in g.Sums against the checksum
+// database GOSUMDB names (sum.golang.org by default) and the module cache,
+// and reports the entries the module graph needs but go.sum lacks. Modules
+// matching GONOSUMDB (or GOPRIVATE when it is unset), and every module with
+// GOSUMDB=off, are only compared with the module cache. Modules not in the
+// cache are only compared with the database. These settings are read as the
+// go command resolves them, so those made with 'go env -w' apply too.
+//
+// The lookups are not checked against the database's signed tree, so this
+// detects go.sum files that were edited or went stale, not a compromised
+// database.
+func VerifySums(g *Graph, opts Options) SumVerification {
+	v := SumVerification{Missing: CheckSums(g).Missing, Unverified: make(map[string]string)}
+
+	// One lookup answers both hashes of a module version
+	modules := make(map[string][]string)
+	for key := range g.Sums {
+		name := strings.TrimSuffix(key, "/go.mod")
+		modules[name] = append(modules[name], key)
+	}
+
+	settings := goSettings("GOSUMDB", "GONOSUMDB", "GOPRIVATE")
+	db := sumDBURL(settings["GOSUMDB"], opts.sumDBURL())
+	private := settings["GONOSUMDB"]
+	if private == "" {
+		private = settings["GOPRIVATE"]
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, sumdbWorkers)
+
+	for name, keys := range modules {
+		modPath, version := SplitModule(name)
+
+		mu.Lock()
+		for _, key := range keys {
+			if hash, ok := cachedSum(modPath, version, key); ok && hash != g.Sums[key] {
+				v.Mismatched = append(v.Mismatched, SumMismatch{Key: key, GoSum: g.Sums[key], Source: SourceCache, Hash: hash})
+			}
+		}
+		skip := ""
+		switch {
+		case db == "":
+			skip = "GOSUMDB=off"
+		case matchPrefixPatterns(private, modPath):
+			skip = "private, matches GONOSUMDB or GOPRIVATE"
+		}
+		if skip != "" {
+			v.Unverified[name] = skip
+		}
+		mu.Unlock()
+		if skip != "" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			hashes, err := lookupSumDB(opts.client(), db, modPath, version)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ErrBudgetExceeded) {
+				g.markPartial(name)
+			}
+			if err != nil {
+				v.Unverified[name] = err.Error()
+				return
+			}
+			for _, key := range keys {
+				hash, ok := hashes[key]
+				switch {
+				case !ok:
+					v.Unverified[name] = "the checksum database has no hash of " + key
+				case hash != g.Sums[key]:
+					v.Mismatched = append(v.Mismatched, SumMismatch{Key: key, GoSum: g.Sums[key], Source: SourceSumDB, Hash: hash})
+				default:
+					v.Verified++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(v.Mismatched, func(a, b SumMismatch) int {
+		return strings.Compare(a.Key+" "+a.Source, b.Key+" "+b.Source)
+	})
+	return v
+}
+
+// sumDBURL returns the base URL of the checksum database a GOSUMDB setting
+// names, or "" if it is off. A setting is "name[+key] [url]"; sum.golang.org
+// is reached at def.
+func sumDBURL(gosumdb, def string) string {
+	fields := strings.Fields(gosumdb)
+	switch {
+	case len(fields) == 0:
+		return def
+	case fields[0] == "off":
+		return ""
+	case len(fields) > 1:
+		return strings.TrimSuffix(fields[1], "/")
+	}
+	name, _, _ := strings.Cut(fields[0], "+")
+	if name == "sum.golang.org" {
+		return def
+	}
+	return "https://" + name
+}
+
+// lookupSumDB fetches the go.sum hashes the checksum database at db records
+// for modPath@version, keyed like GoSum.
+func lookupSumDB(client *http.Client, db, modPath, version string) (map[string]string, error) {
+	resp, err := client.Get(fmt.Sprintf("%s/lookup/%s@%s", db, escapeModulePath(modPath), escapeModulePath(version)))
+	if errors.Is(err, ErrBudgetExceeded) {
+		return nil, ErrBudgetExceeded
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the checksum database: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksum database response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("not in the checksum database")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("checksum database returned status %d", resp.StatusCode)
+	}
+
+	// The record is the record number, go.sum lines and the signed tree
+	hashes := make(map[string]string)
+	for _, line := range strings.Split(string(body), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == modPath {
+			hashes[fields[0]+"@"+fields[1]] = fields[2]
+		}
+	}
+	return hashes, nil
+}
+
+// cachedSum returns the hash of the module zip or go.mod file a go.sum key
+// names as computed from the module cache, if the cache has the file.
+func cachedSum(modPath, version, key string) (string, bool) {
+	if strings.HasSuffix(key, "/go.mod") {
+		file, err := ModuleCacheFile(modPath, version, "mod")
+		if err != nil {
+			return "", false
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", false
+		}
+		return hashGoMod(data), true
+	}
+	file, err := ModuleCacheFile(modPath, version, "ziphash")
+	if err != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// hashGoMod computes the go.sum hash of a go.mod file: the "h1" directory
+// hash of a tree holding just that file.
+func hashGoMod(data []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%x  %s\n", sha256.Sum256(data), "go.mod")
+	return "h1:" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// matchPrefixPatterns reports whether a leading run of the path elements of
+// target matches any of the comma-separated glob patterns, as the go
+// command matches GOPRIVATE and GONOSUMDB.
+func matchPrefixPatterns(globs, target string) bool {
+	for _, glob := range strings.Split(globs, ",") {
+		glob = strings.TrimSuffix(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+		n := strings.Count(glob, "/")
+		prefix := target
+		for i := 0; i < len(target); i++ {
+			if target[i] == '/' {
+				if n == 0 {
+					prefix = target[:i]
+					break
+				}
+				n--
+			}
+		}
+		if n > 0 {
+			continue
+		}
+		if ok, _ := path.Match(glob, prefix); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderSumVerification formats the result of VerifySums.
+func RenderSumVerification(v SumVerification) []byte {
+	var buf bytes.Buffer
+	section := func(title string) {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintln(&buf, title)
+	}
+
+	if len(v.Mismatched) > 0 {
+		section("Mismatched hashes:")
+		for _, m := range v.Mismatched {
+			fmt.Fprintf(&buf, "  %s\n    go.sum:  %s\n    %s: %s\n", m.Key, m.GoSum, m.Source, m.Hash)
+		}
+	}
+	if len(v.Missing) > 0 {
+		section("Missing from go.sum:")
+		for _, key := range v.Missing {
+			fmt.Fprintf(&buf, "  %s\n", key)
+		}
+	}
+	if len(v.Unverified) > 0 {
+		section("Not checked against the checksum database:")
+		for _, name := range slices.Sorted(maps.Keys(v.Unverified)) {
+			fmt.Fprintf(&buf, "  %s (%s)\n", name, v.Unverified[name])
+		}
+	}
+
+	if buf.Len() > 0 {
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(&buf, "%d go.sum hash(es) verified, %d mismatched, %d missing, %d module(s) not checked against the checksum database\n",
+		v.Verified, len(v.Mismatched), len(v.Missing), len(v.Unverified))
+	if v.Verified == 0 && len(v.Unverified) > 0 {
+		fmt.Fprintln(&buf, "Nothing could be verified against the checksum database")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,133 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	pflagModHash = "h1:McXfInJRrz4CZXVZOBLb0bTZqETkiAhM9Iw0y3An2Bg="
+	pflagZipHash = "h1:iy+VFUOCP1a+8yFto/drg2CJ5u0yRoB7fZw3DKv/JXA="
+)
+
+func TestHashGoMod(t *testing.T) {
+	if got := hashGoMod([]byte("module github.com/spf13/pflag\n\ngo 1.12\n")); got != pflagModHash {
+		t.Errorf("hashGoMod() = %s, want %s", got, pflagModHash)
+	}
+}
+
+func TestVerifySums(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/lookup/github.com/spf13/pflag@v1.0.5":
+			w.Write([]byte("4567\ngithub.com/spf13/pflag v1.0.5 " + pflagZipHash + "\ngithub.com/spf13/pflag v1.0.5/go.mod " + pflagModHash + "\n\ngo.sum database tree\n"))
+		case "/lookup/github.com/!burnt!sushi/toml@v1.3.2":
+			w.Write([]byte("1234\ngithub.com/BurntSushi/toml v1.3.2/go.mod h1:CxXYINrC8qIiEnFrOxCa7Jy5BFHlXnUU2pbicEuybxQ=\n"))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	oldURL := sumdbURL
+	sumdbURL = server.URL
+	defer func() { sumdbURL = oldURL }()
+	t.Setenv("GOSUMDB", "")
+	t.Setenv("GONOSUMDB", "")
+	t.Setenv("GOPRIVATE", "corp.example.com")
+
+	cache := t.TempDir()
+	useModCache(t, cache)
+	dir := filepath.Join(cache, "cache", "download", "github.com", "spf13", "pflag", "@v")
+	writeTestFile(t, filepath.Join(dir, "v1.0.5.mod"), "module github.com/spf13/pflag\n\ngo 1.12\n")
+	writeTestFile(t, filepath.Join(dir, "v1.0.5.ziphash"), "h1:tampered=\n")
+
+	g := &Graph{
+		Root: NewNode("myapp"),
+		Deps: map[string][]string{"myapp": {"github.com/spf13/pflag@v1.0.5", "github.com/BurntSushi/toml@v1.3.2", "corp.example.com/lib@v1.0.0", "example.com/gone@v0.1.0"}},
+		Sums: GoSum{
+			"github.com/spf13/pflag@v1.0.5":            pflagZipHash,
+			"github.com/spf13/pflag@v1.0.5/go.mod":     pflagModHash,
+			"github.com/BurntSushi/toml@v1.3.2/go.mod": "h1:edited=",
+			"corp.example.com/lib@v1.0.0/go.mod":       "h1:private=",
+		},
+	}
+
+	v := VerifySums(g, Options{HTTPClient: server.Client()})
+	want := []SumMismatch{
+		{Key: "github.com/BurntSushi/toml@v1.3.2/go.mod", GoSum: "h1:edited=", Source: SourceSumDB, Hash: "h1:CxXYINrC8qIiEnFrOxCa7Jy5BFHlXnUU2pbicEuybxQ="},
+		{Key: "github.com/spf13/pflag@v1.0.5", GoSum: pflagZipHash, Source: SourceCache, Hash: "h1:tampered="},
+	}
+	if !reflect.DeepEqual(v.Mismatched, want) {
+		t.Errorf("Mismatched = %+v, want %+v", v.Mismatched, want)
+	}
+	if !reflect.DeepEqual(v.Missing, []string{"example.com/gone@v0.1.0/go.mod"}) {
+		t.Errorf("Missing = %v", v.Missing)
+	}
+	if v.Verified != 2 {
+		t.Errorf("Verified = %d, want 2", v.Verified)
+	}
+	if reason := v.Unverified["corp.example.com/lib@v1.0.0"]; !strings.Contains(reason, "GOPRIVATE") {
+		t.Errorf("Expected the private module to be skipped, got %v", v.Unverified)
+	}
+	if v.OK() {
+		t.Error("Expected the verification to fail")
+	}
+
+	out := string(RenderSumVerification(v))
+	for _, line := range []string{"Mismatched hashes:", "    checksum database: h1:CxXY", "Missing from go.sum:", "2 go.sum hash(es) verified, 2 mismatched, 1 missing, 1 module(s) not checked"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in:\n%s", line, out)
+		}
+	}
+
+	t.Setenv("GOSUMDB", "off")
+	if v := VerifySums(g, Options{HTTPClient: server.Client()}); v.Verified != 0 || len(v.Unverified) != 3 || len(v.Mismatched) != 1 {
+		t.Errorf("With GOSUMDB=off expected only the module cache check, got %+v", v)
+	}
+
+	// Nothing confirmed by the database is not a pass
+	g.Sums = GoSum{"github.com/spf13/pflag@v1.0.5/go.mod": pflagModHash}
+	g.Deps = map[string][]string{"myapp": {"github.com/spf13/pflag@v1.0.5"}}
+	v = VerifySums(g, Options{HTTPClient: server.Client()})
+	if v.OK() {
+		t.Errorf("With GOSUMDB=off expected no hash to count as verified, got %+v", v)
+	}
+	if out := string(RenderSumVerification(v)); !strings.Contains(out, "Nothing could be verified against the checksum database") {
+		t.Errorf("Expected a note that nothing was verified, got:\n%s", out)
+	}
+
+	// Settings made with 'go env -w' apply
+	env := filepath.Join(t.TempDir(), "env")
+	writeTestFile(t, env, "GOPRIVATE=github.com/spf13\n")
+	t.Setenv("GOENV", env)
+	t.Setenv("GOSUMDB", "")
+	t.Setenv("GOPRIVATE", "")
+	if v := VerifySums(g, Options{HTTPClient: server.Client()}); !strings.Contains(v.Unverified["github.com/spf13/pflag@v1.0.5"], "GOPRIVATE") {
+		t.Errorf("Expected GOPRIVATE from the go env file to apply, got %+v", v)
+	}
+}
+
+func TestMatchPrefixPatterns(t *testing.T) {
+	tests := []struct {
+		globs, target string
+		want          bool
+	}{
+		{"corp.example.com", "corp.example.com/lib", true},
+		{"corp.example.com", "corp.example.com", true},
+		{"*.corp.example.com", "git.corp.example.com/team/lib", true},
+		{"github.com/acme/*", "github.com/acme/lib/v2", true},
+		{"github.com/acme/*", "github.com/acme", false},
+		{"github.com/acme", "github.com/acmecorp/lib", false},
+		{"example.com/x, corp.example.com/", "corp.example.com/lib", true},
+		{"", "corp.example.com/lib", false},
+	}
+	for _, tt := range tests {
+		if got := matchPrefixPatterns(tt.globs, tt.target); got != tt.want {
+			t.Errorf("matchPrefixPatterns(%q, %q) = %v, want %v", tt.globs, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package deptree
+
+import (
+	"fmt"
+	"os"
+)
+
+// Names of the online vulnerability databases NewVulnDB accepts.
+const (
+	VulnDBOSV    = "osv"
+	VulnDBGitHub = "github"
+)
+
+// VulnDB is a source of vulnerability advisories for Go modules.
+type VulnDB interface {
+	// Query returns the IDs of the advisories affecting each of the given
+	// path@version modules. Modules without known advisories are omitted.
+	Query(modules []string) (map[string][]string, error)
+	// Lookup returns the advisory with the given ID.
+	Lookup(id string) (*OSVEntry, error)
+}
+
+// NewVulnDB returns the database named by spec: VulnDBOSV for the OSV API,
+// VulnDBGitHub for the GitHub Advisory Database, queried with token if it
+// is not empty, or otherwise the path of an offline bundle for
+// LoadOfflineVulnDB.
+func NewVulnDB(spec string, opts Options, token string) (VulnDB, error) {
+	switch spec {
+	case "", VulnDBOSV:
+		return NewOSVDB(opts), nil
+	case VulnDBGitHub:
+		return NewGitHubAdvisoryDB(opts, token), nil
+	}
+	if _, err := os.Stat(spec); err != nil {
+		return nil, fmt.Errorf("vulnerability database must be %s, %s or an offline bundle: %w", VulnDBOSV, VulnDBGitHub, err)
+	}
+	return LoadOfflineVulnDB(spec)
+}
+
+// OSVDB queries the OSV API at api.osv.dev, or at opts.OSVAPIURL.
+type OSVDB struct {
+	opts Options
+}
+
+// NewOSVDB returns an OSVDB sending requests to opts.OSVAPIURL through
+// opts.HTTPClient.
+func NewOSVDB(opts Options) *OSVDB {
+	return &OSVDB{opts: opts}
+}
+
+// Query implements VulnDB with OSV batch queries.
+func (db *OSVDB) Query(modules []string) (map[string][]string, error) {
+	return QueryVulnerabilities(db.opts, modules)
+}
+
+// Lookup implements VulnDB by fetching the advisory from OSV.
+func (db *OSVDB) Lookup(id string) (*OSVEntry, error) {
+	return getOSVVuln(db.opts, id)
+}
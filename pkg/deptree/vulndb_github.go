@@ -0,0 +1,201 @@
+package deptree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitHubAdvisoryDB queries the GitHub Advisory Database through the global
+// security advisories API, one request per module. Unauthenticated
+// requests are limited to 60 an hour, so large graphs need a token.
+type GitHubAdvisoryDB struct {
+	client *http.Client
+	token  string
+	apiURL string
+
+	mu sync.Mutex
+	// entries keeps the advisories returned by Query for Lookup.
+	entries map[string]*OSVEntry
+}
+
+// NewGitHubAdvisoryDB returns a GitHubAdvisoryDB sending requests to
+// opts.GitHubAPIURL through opts.HTTPClient, authenticated with token if it
+// is not empty.
+func NewGitHubAdvisoryDB(opts Options, token string) *GitHubAdvisoryDB {
+	return &GitHubAdvisoryDB{client: opts.client(), token: token, apiURL: opts.githubAPIURL(), entries: make(map[string]*OSVEntry)}
+}
+
+// githubAdvisory is the subset of a GitHub global security advisory
+// deptree reads.
+type githubAdvisory struct {
+	GHSAID   string `json:"ghsa_id"`
+	CVEID    string `json:"cve_id"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+	// PublishedAt is when GitHub published the advisory.
+	PublishedAt time.Time `json:"published_at"`
+	CVSS        struct {
+		VectorString string `json:"vector_string"`
+	} `json:"cvss"`
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+	} `json:"vulnerabilities"`
+}
+
+// Query implements VulnDB, asking for the advisories affecting each module
+// version concurrently.
+func (db *GitHubAdvisoryDB) Query(modules []string) (map[string][]string, error) {
+	vulns := make(map[string][]string)
+	var errs []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, githubWorkers)
+
+	for _, name := range modules {
+		if _, version := SplitModule(name); version == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			advisories, err := db.fetch(name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			for _, a := range advisories {
+				vulns[name] = append(vulns[name], a.GHSAID)
+			}
+			slices.Sort(vulns[name])
+		}(name)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return vulns, nil
+}
+
+// Lookup implements VulnDB for the advisories Query returned.
+func (db *GitHubAdvisoryDB) Lookup(id string) (*OSVEntry, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if entry, ok := db.entries[id]; ok {
+		return entry, nil
+	}
+	return nil, fmt.Errorf("advisory %s was not returned by the GitHub Advisory Database", id)
+}
+
+// fetch returns the reviewed advisories affecting the path@version module
+// name, remembering them for Lookup.
+func (db *GitHubAdvisoryDB) fetch(name string) ([]githubAdvisory, error) {
+	query := url.Values{"ecosystem": {"go"}, "affects": {name}, "per_page": {"100"}}
+	req, err := http.NewRequest("GET", db.apiURL+"/advisories?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "deptree-cli")
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if db.token != "" {
+		req.Header.Set("Authorization", "Bearer "+db.token)
+	}
+
+	resp, err := db.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the GitHub Advisory Database: %w", DescribeHTTPError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return nil, &RateLimitError{}
+		}
+		return nil, fmt.Errorf("GitHub API returned status %d for advisories of %s", resp.StatusCode, name)
+	}
+
+	var advisories []githubAdvisory
+	if err := json.NewDecoder(resp.Body).Decode(&advisories); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub advisories: %w", err)
+	}
+
+	db.mu.Lock()
+	for _, a := range advisories {
+		db.entries[a.GHSAID] = a.osvEntry()
+	}
+	db.mu.Unlock()
+	return advisories, nil
+}
+
+// osvEntry converts a to the OSV schema, translating the vulnerable version
+// ranges into SEMVER events.
+func (a githubAdvisory) osvEntry() *OSVEntry {
+	entry := &OSVEntry{ID: a.GHSAID, Summary: a.Summary, Published: a.PublishedAt}
+	if a.CVEID != "" {
+		entry.Aliases = []string{a.CVEID}
+	}
+	if strings.HasPrefix(a.CVSS.VectorString, "CVSS:3") {
+		entry.Severity = []osvSeverity{{Type: "CVSS_V3", Score: a.CVSS.VectorString}}
+	}
+	entry.DatabaseSpecific.Severity = a.Severity
+
+	for _, v := range a.Vulnerabilities {
+		if !strings.EqualFold(v.Package.Ecosystem, "go") {
+			continue
+		}
+		affected := osvAffected{Package: osvPackage{Name: v.Package.Name, Ecosystem: "Go"}}
+		events, versions := githubRangeEvents(v.VulnerableVersionRange)
+		affected.Versions = versions
+		if len(events) > 0 {
+			affected.Ranges = []osvRange{{Type: "SEMVER", Events: events}}
+		}
+		entry.Affected = append(entry.Affected, affected)
+	}
+	return entry
+}
+
+// githubRangeEvents translates a GitHub vulnerable version range such as
+// ">= 1.0.0, < 1.2.3" into OSV events, or an exact "= 1.0.0" into an
+// affected version. Versions are given without the "v" prefix, like in OSV.
+func githubRangeEvents(versionRange string) (events []map[string]string, versions []string) {
+	introduced := "0"
+	var closing map[string]string
+	for _, part := range strings.Split(versionRange, ",") {
+		op, version, ok := strings.Cut(strings.TrimSpace(part), " ")
+		if !ok {
+			continue
+		}
+		version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+		switch op {
+		case ">=":
+			introduced = version
+		case "<":
+			closing = map[string]string{"fixed": version}
+		case "<=":
+			closing = map[string]string{"last_affected": version}
+		case "=":
+			return nil, []string{version}
+		}
+	}
+	events = []map[string]string{{"introduced": introduced}}
+	if closing != nil {
+		events = append(events, closing)
+	}
+	return events, nil
+}
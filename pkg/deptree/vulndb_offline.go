@@ -0,0 +1,119 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OfflineVulnDB answers queries from a local copy of OSV advisories, so
+// vulnerabilities can be checked without network access against a mirrored
+// database.
+type OfflineVulnDB struct {
+	entries map[string]*OSVEntry
+	// byPath indexes the entries by the Go module paths they affect.
+	byPath map[string][]*OSVEntry
+}
+
+// LoadOfflineVulnDB reads OSV advisories from path: either a JSON file
+// holding an array of entries, or a directory of JSON files with one entry
+// each, as in the unpacked OSV bulk export for the Go ecosystem. Withdrawn
+// advisories are skipped.
+func LoadOfflineVulnDB(path string) (*OfflineVulnDB, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vulnerability database: %w", err)
+	}
+
+	var entries []*OSVEntry
+	if info.IsDir() {
+		files, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			var entry OSVEntry
+			if err := readJSONFile(file, &entry); err != nil {
+				return nil, err
+			}
+			entries = append(entries, &entry)
+		}
+	} else if err := readJSONFile(path, &entries); err != nil {
+		return nil, err
+	}
+
+	db := &OfflineVulnDB{entries: make(map[string]*OSVEntry), byPath: make(map[string][]*OSVEntry)}
+	for _, entry := range entries {
+		if entry.ID == "" || entry.Withdrawn != "" {
+			continue
+		}
+		db.entries[entry.ID] = entry
+		var paths []string
+		for _, affected := range entry.Affected {
+			if affected.Package.Ecosystem == "Go" && !slices.Contains(paths, affected.Package.Name) {
+				paths = append(paths, affected.Package.Name)
+			}
+		}
+		for _, p := range paths {
+			db.byPath[p] = append(db.byPath[p], entry)
+		}
+	}
+	return db, nil
+}
+
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read vulnerability database: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Len returns the number of advisories in db.
+func (db *OfflineVulnDB) Len() int {
+	return len(db.entries)
+}
+
+// Query implements VulnDB by matching the modules against the affected
+// versions and ranges of the advisories.
+func (db *OfflineVulnDB) Query(modules []string) (map[string][]string, error) {
+	vulns := make(map[string][]string)
+	for _, name := range modules {
+		path, version := SplitModule(name)
+		if version == "" {
+			continue
+		}
+		for _, entry := range db.byPath[path] {
+			if entry.affects(path, version) {
+				vulns[name] = append(vulns[name], entry.ID)
+			}
+		}
+		slices.Sort(vulns[name])
+	}
+	for name, ids := range vulns {
+		if len(ids) == 0 {
+			delete(vulns, name)
+		}
+	}
+	return vulns, nil
+}
+
+// Lookup implements VulnDB. Advisories can also be found by alias, such as
+// a GHSA or CVE ID.
+func (db *OfflineVulnDB) Lookup(id string) (*OSVEntry, error) {
+	if entry, ok := db.entries[id]; ok {
+		return entry, nil
+	}
+	for _, entry := range db.entries {
+		if slices.ContainsFunc(entry.Aliases, func(alias string) bool { return strings.EqualFold(alias, id) }) {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("advisory %s is not in the offline database", id)
+}
@@ -0,0 +1,170 @@
+package deptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const offlineAdvisories = `[
+	{
+		"id": "GO-2021-0113",
+		"summary": "Out-of-bounds read in golang.org/x/text/language",
+		"aliases": ["CVE-2021-38561", "GHSA-ppp9-7jff-5vj2"],
+		"database_specific": {"severity": "HIGH"},
+		"affected": [{
+			"package": {"name": "golang.org/x/text", "ecosystem": "Go"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.7"}]}]
+		}]
+	},
+	{
+		"id": "GO-2099-0001",
+		"affected": [{
+			"package": {"name": "example.com/lib", "ecosystem": "Go"},
+			"versions": ["1.0.0"],
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "2.0.0"}, {"last_affected": "2.1.0"}]}]
+		}]
+	},
+	{
+		"id": "GO-2099-0002",
+		"withdrawn": "2099-01-01T00:00:00Z",
+		"affected": [{
+			"package": {"name": "example.com/lib", "ecosystem": "Go"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+		}]
+	}
+]`
+
+func TestOfflineVulnDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vulns.json")
+	if err := os.WriteFile(path, []byte(offlineAdvisories), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewVulnDB(path, Options{}, "")
+	if err != nil {
+		t.Fatalf("NewVulnDB failed: %v", err)
+	}
+
+	got, err := db.Query([]string{
+		"app",
+		"golang.org/x/text@v0.3.5",
+		"golang.org/x/text@v0.3.7",
+		"example.com/lib@v1.0.0",
+		"example.com/lib@v1.5.0",
+		"example.com/lib@v2.1.0",
+		"example.com/lib@v2.2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"golang.org/x/text@v0.3.5": {"GO-2021-0113"},
+		"example.com/lib@v1.0.0":   {"GO-2099-0001"},
+		"example.com/lib@v2.1.0":   {"GO-2099-0001"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query = %v, want %v", got, want)
+	}
+
+	if entry, err := db.Lookup("GHSA-ppp9-7jff-5vj2"); err != nil || entry.ID != "GO-2021-0113" {
+		t.Errorf("Lookup by alias = %v, %v", entry, err)
+	}
+	if _, err := db.Lookup("GO-2099-0002"); err == nil {
+		t.Error("Expected withdrawn advisory to be skipped")
+	}
+
+	g := &Graph{Deps: map[string][]string{"app": {"golang.org/x/text@v0.3.5"}}}
+	g.Root = BuildDependencyTree(g.Deps, "")
+	if err := DetectVulnerabilities(g, db, SeverityUnknown); err != nil {
+		t.Fatal(err)
+	}
+	details := g.VulnerabilityDetails["golang.org/x/text@v0.3.5"]
+	if len(details) != 1 || details[0].Severity != SeverityHigh || details[0].Fixed != "v0.3.7" {
+		t.Errorf("Unexpected details: %+v", details)
+	}
+}
+
+func TestOfflineVulnDBDirectory(t *testing.T) {
+	dir := t.TempDir()
+	entry := `{"id": "GO-2099-0003", "affected": [{"package": {"name": "example.com/lib", "ecosystem": "Go"}, "versions": ["1.0.0"]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "GO-2099-0003.json"), []byte(entry), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := LoadOfflineVulnDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Len() != 1 {
+		t.Errorf("Expected 1 advisory, got %d", db.Len())
+	}
+
+	if _, err := NewVulnDB(filepath.Join(dir, "missing.json"), Options{}, ""); err == nil {
+		t.Error("Expected an error for a missing bundle")
+	}
+}
+
+func TestGitHubAdvisoryDB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/advisories" || r.URL.Query().Get("ecosystem") != "go" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Query().Get("affects") != "golang.org/x/text@v0.3.5" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{
+			"ghsa_id": "GHSA-ppp9-7jff-5vj2",
+			"cve_id": "CVE-2021-38561",
+			"summary": "Out-of-bounds read",
+			"severity": "high",
+			"cvss": {"vector_string": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"},
+			"vulnerabilities": [{
+				"package": {"ecosystem": "go", "name": "golang.org/x/text"},
+				"vulnerable_version_range": "< 0.3.7"
+			}]
+		}]`))
+	}))
+	defer server.Close()
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = oldURL }()
+
+	g := &Graph{Deps: map[string][]string{"app": {"golang.org/x/text@v0.3.5", "safe@v1.0.0"}}}
+	g.Root = BuildDependencyTree(g.Deps, "")
+	if err := DetectVulnerabilities(g, NewGitHubAdvisoryDB(Options{HTTPClient: server.Client()}, "secret"), SeverityUnknown); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]Vulnerability{
+		"golang.org/x/text@v0.3.5": {{ID: "GHSA-ppp9-7jff-5vj2", Summary: "Out-of-bounds read", Severity: SeverityHigh, Score: 7.5, Fixed: "v0.3.7"}},
+	}
+	if !reflect.DeepEqual(g.VulnerabilityDetails, want) {
+		t.Errorf("VulnerabilityDetails = %+v, want %+v", g.VulnerabilityDetails, want)
+	}
+}
+
+func TestGitHubRangeEvents(t *testing.T) {
+	tests := []struct {
+		in       string
+		events   []map[string]string
+		versions []string
+	}{
+		{"< 1.2.3", []map[string]string{{"introduced": "0"}, {"fixed": "1.2.3"}}, nil},
+		{">= 1.0.0, <= 1.4.0", []map[string]string{{"introduced": "1.0.0"}, {"last_affected": "1.4.0"}}, nil},
+		{">= v2.0.0", []map[string]string{{"introduced": "2.0.0"}}, nil},
+		{"= 0.1.0", nil, []string{"0.1.0"}},
+	}
+	for _, tt := range tests {
+		events, versions := githubRangeEvents(tt.in)
+		if !reflect.DeepEqual(events, tt.events) || !reflect.DeepEqual(versions, tt.versions) {
+			t.Errorf("githubRangeEvents(%q) = %v, %v, want %v, %v", tt.in, events, versions, tt.events, tt.versions)
+		}
+	}
+}
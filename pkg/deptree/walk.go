@@ -0,0 +1,64 @@
+package deptree
+
+import (
+	"fmt"
+)
+
+// Traversal orders for RenderOptions.Walk.
+const (
+	// WalkDFS visits the tree depth-first, each module followed by its
+	// dependencies. It is the default.
+	WalkDFS = "dfs"
+	// WalkBFS visits the tree breadth-first, level by level.
+	WalkBFS = "bfs"
+)
+
+// TreeVisit is one node reached by WalkTree, with its parent (nil for the
+// root) and depth (0 for the root).
+type TreeVisit struct {
+	Node   *Node
+	Parent *Node
+	Depth  int
+}
+
+// WalkTree calls fn for every node of the tree in the given order (WalkDFS
+// when empty), visiting children in name order. Nodes deeper than maxDepth
+// are skipped when maxDepth is positive.
+func (g *Graph) WalkTree(order string, maxDepth int, fn func(TreeVisit)) error {
+	if g.Root == nil {
+		return nil
+	}
+	within := func(depth int) bool { return maxDepth <= 0 || depth <= maxDepth }
+
+	switch order {
+	case "", WalkDFS:
+		var walk func(v TreeVisit)
+		walk = func(v TreeVisit) {
+			fn(v)
+			for _, child := range sortedChildren(v.Node) {
+				if within(v.Depth + 1) {
+					walk(TreeVisit{Node: child, Parent: v.Node, Depth: v.Depth + 1})
+				}
+			}
+		}
+		walk(TreeVisit{Node: g.Root})
+
+	case WalkBFS:
+		queue := []TreeVisit{{Node: g.Root}}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			fn(v)
+			for _, child := range sortedChildren(v.Node) {
+				if within(v.Depth + 1) {
+					queue = append(queue, TreeVisit{Node: child, Parent: v.Node, Depth: v.Depth + 1})
+				}
+			}
+		}
+
+	default:
+		return fmt.Errorf("unknown walk order %q (available: %s, %s)", order, WalkDFS, WalkBFS)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func walkTestGraph() *Graph {
+	root := NewNode("root")
+	a := NewNode("a@v1.0.0")
+	b := NewNode("b@v1.0.0")
+	a.Children["c@v1.0.0"] = NewNode("c@v1.0.0")
+	root.Children[a.Name] = a
+	root.Children[b.Name] = b
+	return &Graph{Root: root}
+}
+
+func TestWalkTree(t *testing.T) {
+	g := walkTestGraph()
+
+	for _, tc := range []struct {
+		order    string
+		maxDepth int
+		want     []string
+	}{
+		{"", 0, []string{"root", "a@v1.0.0", "c@v1.0.0", "b@v1.0.0"}},
+		{WalkBFS, 0, []string{"root", "a@v1.0.0", "b@v1.0.0", "c@v1.0.0"}},
+		{WalkBFS, 1, []string{"root", "a@v1.0.0", "b@v1.0.0"}},
+	} {
+		var got []string
+		if err := g.WalkTree(tc.order, tc.maxDepth, func(v TreeVisit) { got = append(got, v.Node.Name) }); err != nil {
+			t.Fatalf("WalkTree(%q) failed: %v", tc.order, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("WalkTree(%q, %d) = %v, want %v", tc.order, tc.maxDepth, got, tc.want)
+		}
+	}
+
+	if err := g.WalkTree("sideways", 0, func(TreeVisit) {}); err == nil {
+		t.Error("Expected error for unknown order")
+	}
+}
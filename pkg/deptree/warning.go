@@ -0,0 +1,85 @@
+package deptree
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// WarningKind classifies a Warning.
+type WarningKind string
+
+// Kinds of warnings an analysis records in Graph.Warnings.
+const (
+	// WarnReplaceCollision is a replace directive colliding with another
+	// (see FindReplaceCollisions).
+	WarnReplaceCollision WarningKind = "replace-collision"
+	// WarnGraphLine is a line of 'go mod graph' output that is not an
+	// edge between two modules and was skipped.
+	WarnGraphLine WarningKind = "graph-line"
+	// WarnLegacy is a project without go.mod read as a flat inventory.
+	WarnLegacy WarningKind = "legacy"
+	// WarnMetadata is metadata, such as descriptions or licenses, that
+	// could not be fetched for some modules.
+	WarnMetadata WarningKind = "metadata"
+	// WarnOffline is data that was looked up in the module cache only,
+	// because GOPROXY is "off", and is missing for modules not in it.
+	WarnOffline WarningKind = "offline"
+	// WarnRateLimit is the GitHub API rate limit running out, leaving
+	// metadata of some modules unfetched.
+	WarnRateLimit WarningKind = "rate-limit"
+)
+
+// Warning is a problem an analysis ran into without failing, for the
+// caller to present as it sees fit. Module is the module it concerns, if
+// any.
+type Warning struct {
+	Kind    WarningKind
+	Module  string
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// warn records a warning in g.Warnings. Callers fetching concurrently
+// must hold their lock or wait for their workers first.
+func (g *Graph) warn(kind WarningKind, module, format string, args ...any) {
+	g.Warnings = append(g.Warnings, Warning{Kind: kind, Module: module, Message: fmt.Sprintf(format, args...)})
+}
+
+// warnFailures records one WarnMetadata warning for the modules whose
+// lookup of what failed, naming the first of them with its error. Modules
+// skipped because the GitHub rate limit ran out get one WarnRateLimit
+// warning instead, saying until when.
+func (g *Graph) warnFailures(what string, failed map[string]error) {
+	others := make(map[string]error)
+	var limit *RateLimitError
+	limited := 0
+	for name, err := range failed {
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			others[name] = err
+			continue
+		}
+		limited++
+		if limit == nil || rateLimitErr.Reset.After(limit.Reset) {
+			limit = rateLimitErr
+		}
+	}
+
+	if limited > 0 {
+		advice := ""
+		if limit.Unauthenticated {
+			advice = "; without a GitHub token the limit is 60 requests per hour"
+		}
+		g.warn(WarnRateLimit, "", "%v, skipped the %s of %d module(s)%s", limit, what, limited, advice)
+	}
+	if len(others) == 0 {
+		return
+	}
+	first := slices.Min(slices.Collect(maps.Keys(others)))
+	g.warn(WarnMetadata, "", "failed to fetch the %s of %d module(s), such as %s: %v", what, len(others), first, others[first])
+}
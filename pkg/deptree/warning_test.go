@@ -0,0 +1,52 @@
+package deptree
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWarnFailures(t *testing.T) {
+	g := &Graph{}
+	g.warnFailures("license", nil)
+	if len(g.Warnings) != 0 {
+		t.Fatalf("Expected no warning without failures, got %v", g.Warnings)
+	}
+
+	g.warnFailures("license", map[string]error{
+		"example.com/b@v1.0.0": errors.New("status 500"),
+		"example.com/a@v1.0.0": errors.New("status 404"),
+	})
+	want := Warning{Kind: WarnMetadata, Message: "failed to fetch the license of 2 module(s), such as example.com/a@v1.0.0: status 404"}
+	if len(g.Warnings) != 1 || g.Warnings[0] != want {
+		t.Errorf("Warnings = %v, want %v", g.Warnings, want)
+	}
+}
+
+func TestLoadSnapshotWarnings(t *testing.T) {
+	g := &Graph{
+		Root:   NewNode("example.com/app"),
+		Deps:   map[string][]string{"example.com/app": {"example.com/a@v1.0.0"}},
+		Legacy: LegacyVendor,
+		ModFile: &ModFile{Replace: []ModReplace{
+			{Old: ModVersion{Path: "example.com/a"}, New: ModVersion{Path: "../fork"}},
+			{Old: ModVersion{Path: "example.com/b"}, New: ModVersion{Path: "../fork"}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := SaveSnapshot(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []WarningKind
+	for _, w := range loaded.Warnings {
+		kinds = append(kinds, w.Kind)
+	}
+	if len(kinds) != 2 || kinds[0] != WarnLegacy || kinds[1] != WarnReplaceCollision {
+		t.Errorf("Expected legacy and replace collision warnings, got %v", loaded.Warnings)
+	}
+}
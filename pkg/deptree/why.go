@@ -0,0 +1,200 @@
+package deptree
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MaxWhyPaths caps the paths RenderWhy and RenderWhyChains print. Graphs
+// with many diamonds have more paths to a module than anyone can read; the
+// rest are only counted.
+const MaxWhyPaths = 50
+
+// WhyPaths returns the first limit dependency paths, in sorted order, from
+// the root of g to a module matching target, which is either a module path
+// (any version) or an exact path@version, and how many paths there are in
+// all. Paths stop at the first match and never revisit a module. A limit of
+// zero or less returns every path.
+//
+// Only modules that can reach a match are walked, and the paths past limit
+// are counted rather than listed, so the work stays bounded by the size of
+// the graph even when the number of paths grows exponentially with it.
+func WhyPaths(g *Graph, target string, limit int) ([][]string, int) {
+	reaches := reachingModules(g, target)
+	if !reaches[g.Root.Name] {
+		return nil, 0
+	}
+
+	var paths [][]string
+	onPath := make(map[string]bool)
+	var current []string
+
+	var walk func(name string)
+	walk = func(name string) {
+		current = append(current, name)
+		onPath[name] = true
+		defer func() {
+			current = current[:len(current)-1]
+			onPath[name] = false
+		}()
+
+		if len(current) > 1 && matchesModule(name, target) {
+			paths = append(paths, append([]string(nil), current...))
+			return
+		}
+
+		for _, child := range whyChildren(g, name, reaches) {
+			if limit > 0 && len(paths) == limit {
+				return
+			}
+			if !onPath[child] {
+				walk(child)
+			}
+		}
+	}
+	walk(g.Root.Name)
+
+	return paths, max(countWhyPaths(g, target, reaches), len(paths))
+}
+
+// reachingModules returns the modules of g from which a module matching
+// target can be reached, found by a breadth-first search along reversed
+// edges from the matches.
+func reachingModules(g *Graph, target string) map[string]bool {
+	requiredBy := make(map[string][]string)
+	reaches := make(map[string]bool)
+	var queue []string
+	for name, deps := range g.Deps {
+		for _, dep := range deps {
+			if IsToolchainDep(dep) {
+				continue
+			}
+			requiredBy[dep] = append(requiredBy[dep], name)
+			if matchesModule(dep, target) && !reaches[dep] {
+				reaches[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, parent := range requiredBy[name] {
+			if !reaches[parent] {
+				reaches[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return reaches
+}
+
+// whyChildren returns the requirements of name that can reach a match, in
+// sorted order so that the paths come out sorted.
+func whyChildren(g *Graph, name string, reaches map[string]bool) []string {
+	var children []string
+	for _, child := range g.Deps[name] {
+		if reaches[child] && !IsToolchainDep(child) {
+			children = append(children, child)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+// countWhyPaths counts the paths WhyPaths would list without a limit,
+// memoizing the count below each module. Within a requirement cycle the
+// count is approximate, since a memoized count does not know which modules
+// were already on the path; it saturates instead of overflowing.
+func countWhyPaths(g *Graph, target string, reaches map[string]bool) int {
+	counts := make(map[string]int)
+	onPath := make(map[string]bool)
+
+	var count func(name string, depth int) int
+	count = func(name string, depth int) int {
+		if depth > 0 && matchesModule(name, target) {
+			return 1
+		}
+		if n, ok := counts[name]; ok {
+			return n
+		}
+		onPath[name] = true
+		n := 0
+		for _, child := range whyChildren(g, name, reaches) {
+			if !onPath[child] {
+				n = min(n+count(child, depth+1), math.MaxInt/2)
+			}
+		}
+		onPath[name] = false
+		counts[name] = n
+		return n
+	}
+	return count(g.Root.Name, 0)
+}
+
+// matchesModule reports whether the graph node name refers to target, given
+// either as a bare module path or as path@version.
+func matchesModule(name, target string) bool {
+	if name == target {
+		return true
+	}
+	path, _ := SplitModule(name)
+	return path == target
+}
+
+// RenderWhy formats the result of WhyPaths in the style of 'go mod why':
+// one block per path, one module per line.
+func RenderWhy(g *Graph, target string) []byte {
+	var buf bytes.Buffer
+	paths, total := WhyPaths(g, target, MaxWhyPaths)
+
+	fmt.Fprintf(&buf, "# %s\n", target)
+	if len(paths) == 0 {
+		fmt.Fprintf(&buf, "(%s does not depend on %s)\n", g.Root.Name, target)
+		return buf.Bytes()
+	}
+
+	for i, path := range paths {
+		if i > 0 {
+			fmt.Fprintln(&buf)
+		}
+		for _, module := range path {
+			fmt.Fprintln(&buf, module)
+		}
+	}
+	if more := total - len(paths); more > 0 {
+		fmt.Fprintf(&buf, "\n... %d more path(s) not shown\n", more)
+	}
+	fmt.Fprintf(&buf, "\n%d path(s) to %s\n", total, target)
+
+	return buf.Bytes()
+}
+
+// ChainSeparator joins the modules of a dependency chain on one line.
+const ChainSeparator = " > "
+
+// FormatChain formats a dependency path from the root to a module on one
+// line, e.g. "example.com/app > github.com/spf13/cobra@v1.8.0 >
+// github.com/spf13/pflag@v1.0.5", the form security tickets usually quote.
+func FormatChain(path []string) string {
+	return strings.Join(path, ChainSeparator)
+}
+
+// RenderWhyChains formats the result of WhyPaths with one chain per line,
+// as formatted by FormatChain, and a last line counting the paths past
+// MaxWhyPaths. Nothing is printed when there is no path.
+func RenderWhyChains(g *Graph, target string) []byte {
+	var buf bytes.Buffer
+	paths, total := WhyPaths(g, target, MaxWhyPaths)
+	for _, path := range paths {
+		fmt.Fprintln(&buf, FormatChain(path))
+	}
+	if more := total - len(paths); more > 0 {
+		fmt.Fprintf(&buf, "... %d more path(s) not shown\n", more)
+	}
+	return buf.Bytes()
+}
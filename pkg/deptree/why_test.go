@@ -0,0 +1,116 @@
+package deptree
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWhyPaths(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"dep1@v1.0.0", "dep2@v1.0.0"},
+			"dep1@v1.0.0": {"target@v1.0.0"},
+			"dep2@v1.0.0": {"dep3@v1.0.0", "target@v1.1.0"},
+			"dep3@v1.0.0": {"dep2@v1.0.0", "target@v1.0.0"}, // Cycle back to dep2
+		},
+	}
+
+	paths, total := WhyPaths(g, "target", 0)
+	if len(paths) != 3 || total != 3 {
+		t.Fatalf("Expected 3 paths, got %d of %d: %v", len(paths), total, paths)
+	}
+
+	expected := "mymodule dep1@v1.0.0 target@v1.0.0"
+	if got := strings.Join(paths[0], " "); got != expected {
+		t.Errorf("paths[0] = %q, want %q", got, expected)
+	}
+
+	exact, _ := WhyPaths(g, "target@v1.1.0", 0)
+	if len(exact) != 1 {
+		t.Errorf("Expected 1 path to exact version, got %d: %v", len(exact), exact)
+	}
+}
+
+func TestWhyPathsLayeredDiamonds(t *testing.T) {
+	// 40 layers of two modules that each require both modules of the next
+	// layer give 2^40 paths to the target below the last layer. A second
+	// stack of diamonds never reaches the target and must not be walked.
+	const layers = 40
+	g := &Graph{Root: NewNode("mymodule"), Deps: map[string][]string{}}
+	diamonds := func(prefixes string, leaf string) {
+		parents := []string{"mymodule"}
+		for layer := range layers {
+			modules := []string{fmt.Sprintf("%c%02d@v1.0.0", prefixes[0], layer), fmt.Sprintf("%c%02d@v1.0.0", prefixes[1], layer)}
+			for _, parent := range parents {
+				g.Deps[parent] = append(g.Deps[parent], modules...)
+			}
+			parents = modules
+		}
+		for _, parent := range parents {
+			g.Deps[parent] = []string{leaf}
+		}
+	}
+	diamonds("ab", "target@v1.0.0")
+	diamonds("cd", "unrelated@v1.0.0")
+
+	done := make(chan struct{})
+	var paths [][]string
+	var total int
+	go func() {
+		defer close(done)
+		paths, total = WhyPaths(g, "target", 10)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WhyPaths did not finish on a layered diamond graph")
+	}
+
+	if len(paths) != 10 {
+		t.Errorf("Expected 10 paths, got %d", len(paths))
+	}
+	if total != 1<<layers {
+		t.Errorf("Expected %d paths in total, got %d", 1<<layers, total)
+	}
+	if got := strings.Join(paths[0], " "); !strings.HasPrefix(got, "mymodule a00@v1.0.0 a01@v1.0.0") {
+		t.Errorf("paths[0] = %q, want the sorted first path", got)
+	}
+
+	output := string(RenderWhy(g, "target"))
+	if !strings.Contains(output, fmt.Sprintf("... %d more path(s) not shown", 1<<layers-MaxWhyPaths)) {
+		t.Errorf("Expected the paths past MaxWhyPaths to be counted, got:\n%s", output)
+	}
+}
+
+func TestRenderWhyNoPath(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{"mymodule": {"dep1@v1.0.0"}},
+	}
+
+	output := string(RenderWhy(g, "missing"))
+	if !strings.Contains(output, "does not depend on missing") {
+		t.Errorf("Expected explanation for missing module, got:\n%s", output)
+	}
+}
+
+func TestRenderWhyChains(t *testing.T) {
+	g := &Graph{
+		Root: NewNode("mymodule"),
+		Deps: map[string][]string{
+			"mymodule":    {"dep1@v1.0.0", "target@v1.0.0"},
+			"dep1@v1.0.0": {"target@v1.0.0"},
+		},
+	}
+
+	expected := "mymodule > dep1@v1.0.0 > target@v1.0.0\nmymodule > target@v1.0.0\n"
+	if got := string(RenderWhyChains(g, "target")); got != expected {
+		t.Errorf("RenderWhyChains = %q, want %q", got, expected)
+	}
+	if got := RenderWhyChains(g, "missing"); len(got) != 0 {
+		t.Errorf("Expected no output without a path, got %q", got)
+	}
+}
@@ -0,0 +1,132 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// WorkspaceRoot is the synthetic module at the root of a workspace graph,
+// requiring every module the go.work file uses.
+const WorkspaceRoot = "go.work"
+
+// WorkFile is the part of a go.work file deptree uses, as printed by 'go
+// work edit -json'.
+type WorkFile struct {
+	Go  string
+	Use []struct {
+		DiskPath string
+	}
+	Replace []ModReplace
+}
+
+// isSyntheticModule reports whether name is a module deptree adds to the
+// graph itself: the "temp" module of SetupPackage, WorkspaceRoot or
+// PackagesRoot.
+func isSyntheticModule(name string) bool {
+	return name == "temp" || name == WorkspaceRoot || name == PackagesRoot
+}
+
+// FindWorkFile returns the go.work file the go command uses in dir, or ""
+// outside a workspace.
+func FindWorkFile(dir string) (string, error) {
+	output, err := goCommand(dir, "env", "GOWORK").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run 'go env GOWORK': %w", err)
+	}
+	if path := strings.TrimSpace(string(output)); path != "off" {
+		return path, nil
+	}
+	return "", nil
+}
+
+// ReadWorkFile parses the go.work file at path.
+func ReadWorkFile(path string) (*WorkFile, error) {
+	output, err := exec.Command("go", "work", "edit", "-json", path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'go work edit -json': %w", err)
+	}
+
+	var wf WorkFile
+	if err := json.Unmarshal(output, &wf); err != nil {
+		return nil, fmt.Errorf("failed to parse go.work: %w", err)
+	}
+	return &wf, nil
+}
+
+// loadWorkspace roots g at WorkspaceRoot, with the modules used by the
+// go.work file at workPath as its children. Shared dependencies are
+// expanded under the first module requiring them only. The replace
+// directives of go.work and its modules all apply, go.work's first, and the
+// go.sum files of the modules are combined with go.work.sum.
+func (g *Graph) loadWorkspace(workPath string) error {
+	wf, err := ReadWorkFile(workPath)
+	if err != nil {
+		return err
+	}
+	workDir := filepath.Dir(workPath)
+
+	g.Workspace = make(map[string]*ModFile)
+	g.ModFile = &ModFile{Replace: slices.Clone(wf.Replace)}
+	if g.Sums, err = readSumFile(filepath.Join(workDir, "go.work.sum")); err != nil {
+		return err
+	}
+	for _, use := range wf.Use {
+		dir := use.DiskPath
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, dir)
+		}
+		mf, err := ReadModFile(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read go.mod of workspace module %s: %w", use.DiskPath, err)
+		}
+		sums, err := ReadGoSum(dir)
+		if err != nil {
+			return err
+		}
+		g.Workspace[mf.Module.Path] = mf
+		g.ModFile.Replace = append(g.ModFile.Replace, mf.Replace...)
+		maps.Copy(g.Sums, sums)
+	}
+
+	g.Deps[WorkspaceRoot] = slices.Sorted(maps.Keys(g.Workspace))
+	g.expandRoot(WorkspaceRoot)
+	return nil
+}
+
+// SelectWorkspaceModule reduces a workspace graph to the workspace module at
+// path and the modules it requires, as if it had been analyzed on its own.
+func (g *Graph) SelectWorkspaceModule(path string) error {
+	if g.Workspace == nil {
+		return fmt.Errorf("not a workspace; -module selects a module used by go.work")
+	}
+	mf, ok := g.Workspace[path]
+	if !ok {
+		return fmt.Errorf("%s is not a workspace module (have %s)", path, strings.Join(slices.Sorted(maps.Keys(g.Workspace)), ", "))
+	}
+
+	keep := map[string]bool{path: true}
+	for _, name := range g.Reachable(path) {
+		keep[name] = true
+	}
+	maps.DeleteFunc(g.Deps, func(from string, _ []string) bool { return !keep[from] })
+
+	g.Workspace = nil
+	g.RootModFile = mf
+	g.expandRoot(path)
+	g.syncDescriptions()
+	return nil
+}
+
+// mainModFile returns the go.mod of from when it is a main module: the root
+// module or a workspace module.
+func (g *Graph) mainModFile(from string) *ModFile {
+	if g.Root != nil && from == g.Root.Name {
+		return g.RootModFile
+	}
+	return g.Workspace[from]
+}
@@ -0,0 +1,66 @@
+package deptree
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTestWorkspace creates a workspace using modules a and b, which both
+// require example.com/shared, replaced by a local directory so that no
+// download is needed.
+func writeTestWorkspace(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.work"), "go 1.21\n\nuse (\n\t./a\n\t./b\n)\n\nreplace example.com/shared => ./shared\n")
+	writeTestFile(t, filepath.Join(dir, "a", "go.mod"), "module example.com/a\n\ngo 1.21\n\nrequire example.com/shared v1.0.0\n")
+	writeTestFile(t, filepath.Join(dir, "b", "go.mod"), "module example.com/b\n\ngo 1.21\n\nrequire example.com/shared v1.0.0 // indirect\n")
+	writeTestFile(t, filepath.Join(dir, "shared", "go.mod"), "module example.com/shared\n\ngo 1.21\n")
+	return dir
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	dir := writeTestWorkspace(t)
+
+	g, err := Load(dir, "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if g.Root.Name != WorkspaceRoot {
+		t.Fatalf("Expected root %s, got %s", WorkspaceRoot, g.Root.Name)
+	}
+	if names := slices.Sorted(maps.Keys(g.Root.Children)); !slices.Equal(names, []string{"example.com/a", "example.com/b"}) {
+		t.Errorf("Expected the workspace modules as top-level nodes, got %v", names)
+	}
+	if slices.Contains(g.Modules(), WorkspaceRoot) {
+		t.Errorf("Modules() lists the synthetic %s root", WorkspaceRoot)
+	}
+
+	const shared = "example.com/shared@v1.0.0"
+	if !g.DirectDependencies()[shared] {
+		t.Errorf("Expected %s to be direct through example.com/a", shared)
+	}
+	if kind := g.classifyEdge("example.com/b", shared).Kind; kind != EdgeIndirect {
+		t.Errorf("Expected the edge from example.com/b to be indirect, got %s", kind)
+	}
+	if rep, ok := g.ModFile.FindReplace("example.com/shared", "v1.0.0"); !ok || rep.Path != "./shared" {
+		t.Errorf("Expected the go.work replace to apply, got %v %v", rep, ok)
+	}
+
+	if err := g.SelectWorkspaceModule("example.com/b"); err != nil {
+		t.Fatalf("SelectWorkspaceModule failed: %v", err)
+	}
+	if g.Root.Name != "example.com/b" || len(g.Root.Children) == 0 {
+		t.Errorf("Expected the tree rooted at example.com/b, got %s with %d children", g.Root.Name, len(g.Root.Children))
+	}
+	if _, ok := g.Deps["example.com/a"]; ok {
+		t.Error("Expected example.com/a to be dropped")
+	}
+	if err := g.SelectWorkspaceModule("example.com/a"); err == nil {
+		t.Error("Expected error selecting a module from a graph that is no longer a workspace")
+	}
+}